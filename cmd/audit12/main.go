@@ -5,60 +5,19 @@ import (
 	"math"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
 	"mu-bmd-renderer/internal/bmd"
 	"mu-bmd-renderer/internal/filter"
+	"mu-bmd-renderer/internal/itemclass"
 	"mu-bmd-renderer/internal/itemlist"
 	"mu-bmd-renderer/internal/texture"
 	"mu-bmd-renderer/internal/trs"
 )
 
-// Item type classification by model file name
-var (
-	wingRE  = regexp.MustCompile(`(?i)^wing\d+|chaoswing|magic_wing|flamewing|conquerorwing|angel_devil_wing|wingsofpower|ManaBurstWing|SpiritualWorldWing`)
-	capeRE  = regexp.MustCompile(`(?i)^darklord|cape_of|cloak|jacquard_|PureWhite|Innocence|Sparkle|Resplendent|Limit_wing`)
-	petRE   = regexp.MustCompile(`(?i)_inven|_in[BR]\.|moru|petEgg|Apocal_stone|lightning_(?:stone|anvil)|Ghosthorse`)
-	gemRE   = regexp.MustCompile(`(?i)^gem\d+|^jewel|^attjewel|gemmix|SpiritDust|spellstone`)
-	seedRE  = regexp.MustCompile(`(?i)^s30_`)
-	pentaRE = regexp.MustCompile(`(?i)^penta`)
-	scrollRE = regexp.MustCompile(`(?i)scroll|rollofpaper`)
-	earRE   = regexp.MustCompile(`(?i)earring|so_neck`)
-	ringRE  = regexp.MustCompile(`(?i)^ring\d+`)
-	boxRE   = regexp.MustCompile(`(?i)box|gift`)
-	bookRE  = regexp.MustCompile(`(?i)^book\d+|strengscroll`)
-)
-
 func classifyModel(modelFile string) string {
-	stem := strings.TrimSuffix(modelFile, filepath.Ext(modelFile))
-	switch {
-	case wingRE.MatchString(stem):
-		return "wing"
-	case capeRE.MatchString(stem):
-		return "cape"
-	case petRE.MatchString(stem):
-		return "pet"
-	case gemRE.MatchString(stem):
-		return "gem"
-	case seedRE.MatchString(stem):
-		return "seed"
-	case pentaRE.MatchString(stem):
-		return "penta"
-	case scrollRE.MatchString(stem):
-		return "scroll"
-	case earRE.MatchString(stem):
-		return "earring"
-	case ringRE.MatchString(stem):
-		return "ring"
-	case boxRE.MatchString(stem):
-		return "box"
-	case bookRE.MatchString(stem):
-		return "book"
-	default:
-		return "misc"
-	}
+	return string(itemclass.Classify(modelFile))
 }
 
 func texStem(texPath string) string {
@@ -286,8 +245,8 @@ func main() {
 	fmt.Printf("Body meshes:      %d items\n", bodyMeshItems)
 
 	fmt.Printf("\n=== BY TYPE ===\n")
-	typeOrder := []string{"wing", "cape", "pet", "gem", "seed", "penta", "scroll", "earring", "ring", "box", "book", "misc"}
-	for _, t := range typeOrder {
+	for _, cat := range itemclass.Categories {
+		t := string(cat)
 		if c := typeCounts[t]; c > 0 {
 			fmt.Printf("  %-10s %3d items\n", t, c)
 		}
@@ -342,6 +301,6 @@ func main() {
 	if wingCapeNoOverride == 0 {
 		fmt.Println("  All wing/cape items have per-item TRS overrides.")
 	} else {
-		fmt.Printf("  %d wing/cape items using section default (may need wing12/cape12 preset)\n", wingCapeNoOverride)
+		fmt.Printf("  %d wing/cape items using section default (add wing/cape under \"categories\" in custom_trs.json)\n", wingCapeNoOverride)
 	}
 }
@@ -49,8 +49,8 @@ func main() {
 			}
 			texInfo = fmt.Sprintf("%dx%d, opaque=%.0f%% (%d/%d)", b.Dx(), b.Dy(), pct, opaque, visible)
 		}
-		fmt.Printf("  Mesh[%d]: verts=%d tris=%d tex=%q ext=%s isEffect=%v\n",
-			i, len(m.Verts), len(m.Tris), m.TexPath, ext, isEffect)
+		fmt.Printf("  Mesh[%d]: verts=%d tris=%d tex=%q texIndex=%d ext=%s isEffect=%v\n",
+			i, len(m.Verts), len(m.Tris), m.TexPath, m.TexIndex, ext, isEffect)
 		fmt.Printf("    Texture: %s\n", texInfo)
 
 		// Dump texture as PNG if requested with --dump flag
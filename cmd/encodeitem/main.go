@@ -0,0 +1,82 @@
+// cmd/encodeitem/main.go — Re-encode Data/Xml/ItemList.xml → item.bmd
+//
+// Usage:
+//
+//	go run ./cmd/encodeitem
+//	go run ./cmd/encodeitem [input.xml] [base.bmd] [output.bmd]
+//
+// The reverse of decodeitem: reads an (edited) ItemList.xml and writes its
+// records back over a base item.bmd, keeping any bytes the record layout
+// profile does not cover. Names are written as Windows-1252 unless -codepage is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"mu-bmd-renderer/internal/itembmd"
+)
+
+func main() {
+	cpName := flag.String("codepage", "auto", "Item name codepage (auto = Windows-1252 for changed names)")
+	profileName := flag.String("profile", "default", "Record layout profile (see itembmd.Profiles)")
+	flag.Parse()
+
+	xmlPath := "Data/Xml/ItemList.xml"
+	basePath := "Data/Local/item.bmd"
+	outputPath := "Data/Local/item.new.bmd"
+
+	if flag.NArg() > 0 {
+		xmlPath = flag.Arg(0)
+	}
+	if flag.NArg() > 1 {
+		basePath = flag.Arg(1)
+	}
+	if flag.NArg() > 2 {
+		outputPath = flag.Arg(2)
+	}
+
+	codepage, err := itembmd.LookupCodepage(*cpName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	base, err := os.ReadFile(basePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", basePath, err)
+		os.Exit(1)
+	}
+	layout, err := itembmd.ReadLayout(base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	profile, err := itembmd.LookupProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	records, err := itembmd.ParseXML(xmlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := itembmd.Encode(base, records, profile, codepage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(outputPath, out, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputPath, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Encoded %d items (base %d, profile %s) → %s\n",
+		len(records), layout.Count, profile.Name, outputPath)
+}
@@ -0,0 +1,129 @@
+// cmd/exporttrs/main.go — Dump the effective TRS entry of every item as JSON
+//
+// Usage:
+//
+//	go run ./cmd/exporttrs > trs.json
+//	go run ./cmd/exporttrs -section 12 -o trs-12.json
+//
+// Loads ItemTRSData.bmd, custom_trs.json and trs_overrides_dir exactly as
+// cmd/render does (rotation_offset included) and writes one JSON object
+// mapping "<section>_<index>" to the resolved entry, in section and index
+// order: what the renderer will use after every category, section, model
+// and item rule is merged. Entries use the custom_trs.json keys (see
+// trs.Entry.MarshalJSON) plus "source", so two configurations diff line by
+// line and any entry can be pasted back as an item override. -array writes
+// trs.Export's flat array instead, each entry with its "section" and "index".
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/trs"
+)
+
+func main() {
+	configFile := flag.String("config", "", "Path to config file (.json, .toml, .yaml)")
+	dataDir := flag.String("data", "", "Path to base directory (default: auto-detect)")
+	section := flag.Int("section", -1, "Export only this section")
+	out := flag.String("o", "", "Output file (default: stdout)")
+	array := flag.Bool("array", false, "Write a JSON array of entries with section and index keys (trs.Export) instead of an object")
+	flag.Parse()
+
+	var cfg config.Config
+	if *configFile != "" {
+		var err error
+		if cfg, err = config.Load(*configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cfg.Resolve(config.Flags{DataDir: *dataDir})
+
+	data, err := trs.LoadWith(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML, trs.Options{Strict: cfg.StrictTRS, OverrideDir: cfg.TRSOverrides})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: TRS load: %v\n", err)
+		os.Exit(1)
+	}
+	data.AddRotation(cfg.RotationOffset)
+
+	var doc []byte
+	var n int
+	if *array {
+		doc, n, err = exportData(data, *section)
+	} else {
+		doc, n, err = marshalData(data, *section)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(doc)
+	} else if err := os.WriteFile(*out, doc, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "%d entries\n", n)
+}
+
+// marshalData encodes data's entries (only section's, if >= 0) as an
+// indented JSON object in section, index order, and returns it with the
+// entry count. encoding/json would sort the keys as strings (10_0 before
+// 1_0), hence the hand-written object.
+func marshalData(data trs.Data, section int) ([]byte, int, error) {
+	keys := make([][2]int, 0, len(data))
+	for k := range data {
+		if section < 0 || k[0] == section {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, k := range keys {
+		entry, err := json.MarshalIndent(data[k], "  ", "  ")
+		if err != nil {
+			return nil, 0, fmt.Errorf("%d_%d: %w", k[0], k[1], err)
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, "\n  \"%d_%d\": %s", k[0], k[1], entry)
+	}
+	if len(keys) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), len(keys), nil
+}
+
+// exportData encodes data's entries (only section's, if >= 0) with
+// trs.Export, and returns the document with the entry count.
+func exportData(data trs.Data, section int) ([]byte, int, error) {
+	if section >= 0 {
+		only := make(trs.Data)
+		for k, e := range data {
+			if k[0] == section {
+				only[k] = e
+			}
+		}
+		data = only
+	}
+	var buf bytes.Buffer
+	if err := trs.Export(data, &buf); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), len(data), nil
+}
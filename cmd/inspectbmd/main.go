@@ -31,7 +31,7 @@ func main() {
 		printMeshes(meshes, cache)
 
 		// Apply bone transforms
-		skeleton.ApplyTransforms(meshes, bones, false)
+		skeleton.ApplyTransforms(meshes, bones, false, 0, 0)
 		fmt.Println("--- AFTER BONES ---")
 		printMeshes(meshes, cache)
 
@@ -106,8 +106,8 @@ func printMeshes(meshes []bmd.Mesh, cache *texture.Cache) {
 			sx := maxV[0] - minV[0]
 			sy := maxV[1] - minV[1]
 			sz := maxV[2] - minV[2]
-			fmt.Printf("  Mesh[%d]: v=%d t=%d tex=%q%s (%s) bright=%.0f bbox=(%.0f,%.0f,%.0f) min=(%.0f,%.0f,%.0f) max=(%.0f,%.0f,%.0f)%s\n",
-				i, len(m.Verts), len(m.Tris), stem, ext, texInfo, bright,
+			fmt.Printf("  Mesh[%d]: v=%d t=%d tex=%q%s #%d (%s) bright=%.0f bbox=(%.0f,%.0f,%.0f) min=(%.0f,%.0f,%.0f) max=(%.0f,%.0f,%.0f)%s\n",
+				i, len(m.Verts), len(m.Tris), stem, ext, m.TexIndex, texInfo, bright,
 				math.Abs(float64(sx)), math.Abs(float64(sy)), math.Abs(float64(sz)),
 				float64(minV[0]), float64(minV[1]), float64(minV[2]),
 				float64(maxV[0]), float64(maxV[1]), float64(maxV[2]),
@@ -0,0 +1,208 @@
+// cmd/itemquery/main.go — List (and optionally render) items matching decoded stats
+//
+// Usage:
+//
+//	go run ./cmd/itemquery "ReqLevel>300 and DarkKnight=1"
+//	go run ./cmd/itemquery -bmd Data/Local/item.bmd "Section=7 && Name~dragon"
+//	go run ./cmd/itemquery -render -output dk-catalog "DarkKnight=1"
+//
+// Filters item records by their numeric attributes (see itembmd.ParseQuery for
+// the syntax) and prints the matches with the fields the query mentions.
+// Records come from ItemList.xml, or from item.bmd with -bmd; either way they
+// are joined with ItemList.xml for model paths, so -render renders exactly the
+// matches through the same pipeline as cmd/render.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/itembmd"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/postprocess"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/texture"
+	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
+)
+
+func main() {
+	configFile := flag.String("config", "", "Path to config file (.json, .toml, .yaml)")
+	dataDir := flag.String("data", "", "Path to base directory (default: auto-detect)")
+	bmdPath := flag.String("bmd", "", "Read records from this item.bmd instead of ItemList.xml")
+	profileName := flag.String("profile", "default", "With -bmd, record layout profile (see itembmd.Profiles)")
+	cpName := flag.String("codepage", "auto", "With -bmd, item name codepage")
+	render := flag.Bool("render", false, "Render the matching items")
+	outputDir := flag.String("output", "", "With -render, output directory (default: Data/Item-renders)")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, `Usage: itemquery [flags] "Field op value [and ...]"`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	query, err := itembmd.ParseQuery(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var cfg config.Config
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cfg.Resolve(config.Flags{DataDir: *dataDir, OutputDir: *outputDir})
+	convention, err := viewmatrix.Convention(cfg.Convention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: coordinate_convention: %v\n", err)
+		os.Exit(1)
+	}
+	renderOpts := raster.Options{
+		MinTriangleArea:   cfg.MinTriangleArea,
+		NoOpaquePromotion: cfg.NoOpaquePromotion,
+		SmoothShading:     cfg.SmoothShading,
+		Convention:        convention,
+	}
+	if cfg.SSAO {
+		renderOpts.SSAORadius, renderOpts.SSAOIntensity = cfg.SSAORadius, cfg.SSAOIntensity
+	}
+	postOpts := postprocess.Options{
+		ContentAlpha:      cfg.ContentAlpha,
+		MaxImageDimension: cfg.MaxImageDimension,
+	}
+
+	records, err := loadRecords(cfg.ItemListXML, *bmdPath, *profileName, *cpName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Join with ItemList.xml: only items with a model can be rendered
+	items, err := itemlist.Parse(cfg.ItemListXML)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	byKey := make(map[[2]int]itemlist.ItemDef, len(items))
+	for _, it := range items {
+		byKey[[2]int{it.Section, it.Index}] = it
+	}
+
+	var matched []itemlist.ItemDef
+	noModel := 0
+	for _, r := range records {
+		if !query.Match(r) {
+			continue
+		}
+		var fields []string
+		for _, c := range query {
+			if c.Field != "Section" && c.Field != "Index" && c.Field != "Name" {
+				fields = append(fields, fmt.Sprintf("%s=%s", c.Field, fieldValue(r, c.Field)))
+			}
+		}
+		it, ok := byKey[[2]int{r.Section, r.Index}]
+		if !ok {
+			noModel++
+			printMatch(r, "(no model)", fields)
+			continue
+		}
+		matched = append(matched, it)
+		printMatch(r, it.ModelFile, fields)
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d items match", len(matched)+noModel, len(records))
+	if noModel > 0 {
+		fmt.Fprintf(os.Stderr, " (%d not in ItemList.xml with a model)", noModel)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if !*render || len(matched) == 0 {
+		return
+	}
+
+	trsData, _ := trs.LoadWith(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML, trs.Options{OverrideDir: cfg.TRSOverrides})
+	trsData.AddRotation(cfg.RotationOffset)
+	texIndex := texture.BuildIndex(cfg.ItemDir, filepath.Join(filepath.Dir(cfg.ItemDir), "Skill"))
+	texCache := texture.NewCache(texIndex)
+	texCache.SetMaxSize(cfg.MaxTextureSize)
+	results := batch.Run(batch.Config{
+		ItemDir:          cfg.ItemDir,
+		OutputDir:        cfg.OutputDir,
+		TexResolver:      texCache,
+		TRSData:          trsData,
+		RenderWidth:      cfg.RenderWidth,
+		RenderHeight:     cfg.RenderHeight,
+		WebPQuality:      cfg.WebPQuality,
+		Supersample:      cfg.Supersample,
+		DownsamplePasses: cfg.DownsamplePasses,
+		Render:           renderOpts,
+		Postprocess:      postOpts,
+		Workers:          cfg.Workers,
+		EncodeWorkers:    cfg.EncodeWorkers,
+		MinFeaturePixels: cfg.MinFeaturePixels,
+		OutputFormat:     cfg.OutputFormat,
+		Premultiply:      cfg.PremultipliedAlpha,
+		Retries:          cfg.Retries,
+		IconCrop:         cfg.IconCrop,
+	}, matched)
+
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+			fmt.Fprintf(os.Stderr, "  %d_%d %s: %s\n", r.Section, r.Index, r.Name, r.Error)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Rendered %d/%d → %s\n", len(results)-failed, len(results), cfg.OutputDir)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadRecords decodes item.bmd when bmdPath is set, else reads ItemList.xml.
+func loadRecords(xmlPath, bmdPath, profileName, cpName string) ([]itembmd.Record, error) {
+	if bmdPath == "" {
+		return itembmd.ParseXML(xmlPath)
+	}
+
+	codepage, err := itembmd.LookupCodepage(cpName)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(bmdPath)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := itembmd.LookupProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+	return itembmd.Decode(raw, profile, codepage)
+}
+
+// printMatch prints one tab-separated match line.
+func printMatch(r itembmd.Record, model string, fields []string) {
+	line := fmt.Sprintf("%d_%d\t%s\t%s", r.Section, r.Index, r.Name, model)
+	if len(fields) > 0 {
+		line += "\t" + strings.Join(fields, " ")
+	}
+	fmt.Println(line)
+}
+
+// fieldValue formats a record field for display.
+func fieldValue(r itembmd.Record, field string) string {
+	switch field {
+	case "ModelPath":
+		return r.ModelPath
+	case "ModelFile":
+		return r.ModelFile
+	}
+	return fmt.Sprint(r.Attrs[field])
+}
@@ -0,0 +1,160 @@
+// cmd/refcompare/main.go — Rank renders by similarity to in-game reference screenshots
+//
+// Usage:
+//
+//	go run ./cmd/refcompare -refs screenshots/
+//	go run ./cmd/refcompare -refs screenshots/ -renders out/ -o worst.csv -top 20
+//
+// Reference images are named <section>_<index>.<png|jpg|webp|bmp> and are
+// matched to renders at <renders>/<section>/<index>.webp. Each pair is
+// aligned on the item's content and scaled to a common size (see
+// imgdiff.Normalize), with the render composited over the screenshot's
+// background, then scored with SSIM. The CSV lists the pairs worst first, so
+// the items whose TRS most needs tuning come at the top.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/imgdiff"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+var refName = regexp.MustCompile(`(?i)^(\d+)_(\d+)\.(png|jpe?g|webp|bmp)$`)
+
+type match struct {
+	section, index int
+	name           string
+	ssim           float64
+	ref, render    string
+}
+
+func main() {
+	configFile := flag.String("config", "", "Path to config file (.json, .toml, .yaml)")
+	dataDir := flag.String("data", "", "Path to base directory (default: auto-detect)")
+	refDir := flag.String("refs", "", "Directory of reference screenshots named section_index.png")
+	renderDir := flag.String("renders", "", "Render output directory (default: Data/Item-renders)")
+	size := flag.Int("size", 128, "Side of the square both images are scaled to before comparing")
+	outPath := flag.String("o", "", "Write the ranked CSV here (default: stdout)")
+	top := flag.Int("top", 10, "Print this many worst matches to stderr")
+	flag.Parse()
+
+	if *refDir == "" {
+		fmt.Fprintln(os.Stderr, "Usage: refcompare -refs DIR [flags]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *size < 8 {
+		fmt.Fprintln(os.Stderr, "Error: -size must be at least 8")
+		os.Exit(1)
+	}
+
+	var cfg config.Config
+	if *configFile != "" {
+		var err error
+		if cfg, err = config.Load(*configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cfg.Resolve(config.Flags{DataDir: *dataDir, OutputDir: *renderDir})
+
+	// Names are only for the report; a missing ItemList.xml leaves them blank
+	names := make(map[[2]int]string)
+	if items, err := itemlist.Parse(cfg.ItemListXML); err == nil {
+		for _, it := range items {
+			names[[2]int{it.Section, it.Index}] = it.Name
+		}
+	}
+
+	files, err := os.ReadDir(*refDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var matches []match
+	missing := 0
+	for _, f := range files {
+		m := refName.FindStringSubmatch(f.Name())
+		if f.IsDir() || m == nil {
+			continue
+		}
+		sec, _ := strconv.Atoi(m[1])
+		idx, _ := strconv.Atoi(m[2])
+		refPath := filepath.Join(*refDir, f.Name())
+		renderPath := filepath.Join(cfg.OutputDir, m[1], m[2]+".webp")
+
+		ref, err := decode(refPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", refPath, err)
+			continue
+		}
+		render, err := decode(renderPath)
+		if err != nil {
+			missing++
+			continue
+		}
+
+		bg := imgdiff.BorderColor(ref)
+		score := imgdiff.SSIM(imgdiff.Normalize(ref, *size, bg), imgdiff.Normalize(render, *size, bg))
+		matches = append(matches, match{sec, idx, names[[2]int{sec, idx}], score, refPath, renderPath})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].ssim < matches[j].ssim })
+
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	w := csv.NewWriter(out)
+	w.Write([]string{"rank", "section", "index", "name", "ssim", "reference", "render"})
+	for i, m := range matches {
+		w.Write([]string{
+			strconv.Itoa(i + 1), strconv.Itoa(m.section), strconv.Itoa(m.index), m.name,
+			strconv.FormatFloat(m.ssim, 'f', 4, 64), m.ref, m.render,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Compared %d items", len(matches))
+	if missing > 0 {
+		fmt.Fprintf(os.Stderr, " (%d references without a render)", missing)
+	}
+	fmt.Fprintln(os.Stderr)
+	for _, m := range matches[:min(*top, len(matches))] {
+		fmt.Fprintf(os.Stderr, "  %.4f  %d_%d %s\n", m.ssim, m.section, m.index, m.name)
+	}
+}
+
+func decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
@@ -1,22 +1,32 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"image/color"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"mu-bmd-renderer/internal/batch"
 	"mu-bmd-renderer/internal/config"
 	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/logging"
+	"mu-bmd-renderer/internal/mmap"
+	"mu-bmd-renderer/internal/postprocess"
+	"mu-bmd-renderer/internal/raster"
 	"mu-bmd-renderer/internal/texture"
 	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
 )
 
 func main() {
 	// CLI flags
-	configFile := flag.String("config", "", "Path to config.json file")
+	configFile := flag.String("config", "", "Path to config file (.json, .toml, .yaml)")
 	testN := flag.Int("test", 0, "Render only first N items for testing")
 	section := flag.Int("section", -1, "Render only items from this section")
 	index := flag.Int("index", -1, "Render only item with this index (requires -section)")
@@ -24,6 +34,20 @@ func main() {
 	dataDir := flag.String("data", "", "Path to base directory (default: auto-detect)")
 	outputDir := flag.String("output", "", "Output directory (default: Data/Item-renders)")
 	quality := flag.Int("quality", 0, "WebP quality 1-100 (default: 90)")
+	format := flag.String("format", "", "Output image format: webp, png, or exr (overrides output_format)")
+	noWarm := flag.Bool("no-warm", false, "Decode each texture when the render first draws it instead of all of a model's at once after parsing it")
+	verbose := flag.Bool("verbose", false, "Print per-item mesh filtering, render passes, camera, coverage, and timing")
+	quiet := flag.Bool("quiet", false, "Print only warnings and errors (log_level warn)")
+	list := flag.Bool("list", false, "List sections (index, name, item count) and exit")
+	listJSON := flag.Bool("json", false, "With -list, print JSON instead of a table")
+	rerender := flag.String("rerender", "", "Re-render only the items flagged in this manifest.json from a previous run")
+	statuses := flag.String("status", "failed,near_empty,fallback_trs", "With -rerender, the manifest statuses to re-render (comma-separated)")
+	changedSince := flag.String("changed-since", "", "Render only items whose resolved TRS differs from custom_trs.json at this git ref")
+	skipExisting := flag.Bool("skip-existing", false, "Leave items whose image is already in the output directory (resume an interrupted or partial run)")
+	checkMtime := flag.Bool("check-mtime", false, "With -skip-existing, still re-render items whose BMD is newer than their image")
+	incremental := flag.Bool("incremental", false, "Re-render only items whose BMD, textures, TRS entry, or render settings changed since their last -incremental render")
+	validateTRS := flag.Bool("validate-trs", false, "Check custom_trs.json against ItemList.xml, print every problem, and exit (non-zero if any)")
+	order := flag.String("order", "", "Render order: itemlist, section, or cost (cheapest models first); overrides render_order")
 
 	flag.Parse()
 
@@ -43,14 +67,163 @@ func main() {
 		DataDir:   *dataDir,
 		OutputDir: *outputDir,
 		Quality:   *quality,
+		Format:    *format,
 		Workers:   *workers,
 	})
 
+	if cfg.OutputFormat != "webp" && cfg.OutputFormat != "png" && cfg.OutputFormat != "exr" {
+		fmt.Fprintf(os.Stderr, "Error: unknown output_format %q (use webp, png, or exr)\n", cfg.OutputFormat)
+		os.Exit(1)
+	}
+
+	if cfg.AlphaMatte != "" && cfg.AlphaMatte != "alongside" && cfg.AlphaMatte != "instead" {
+		fmt.Fprintf(os.Stderr, "Error: unknown alpha_matte %q (use alongside or instead)\n", cfg.AlphaMatte)
+		os.Exit(1)
+	}
+	if cfg.MatteQuality > 100 {
+		fmt.Fprintf(os.Stderr, "Error: matte_quality %d out of range (1-100)\n", cfg.MatteQuality)
+		os.Exit(1)
+	}
+	if cfg.ContentAlpha > 255 {
+		fmt.Fprintf(os.Stderr, "Error: content_alpha %d out of range (1-255)\n", cfg.ContentAlpha)
+		os.Exit(1)
+	}
+	if cfg.IconCrop != "" && cfg.IconCrop != "center" && cfg.IconCrop != "dense" {
+		fmt.Fprintf(os.Stderr, "Error: unknown icon_crop %q (use center or dense)\n", cfg.IconCrop)
+		os.Exit(1)
+	}
+	if cfg.TurntableFrames < 0 || cfg.TurntableFrames > 1000 {
+		fmt.Fprintf(os.Stderr, "Error: turntable_frames %d out of range (0-1000)\n", cfg.TurntableFrames)
+		os.Exit(1)
+	}
+	if cfg.DebugCanvas != "" && cfg.DebugCanvas != "fill" && cfg.DebugCanvas != "grid" {
+		fmt.Fprintf(os.Stderr, "Error: unknown debug_canvas %q (use fill or grid)\n", cfg.DebugCanvas)
+		os.Exit(1)
+	}
+	debugColor := postprocess.DefaultDebugColor
+	if cfg.DebugCanvasColor != "" {
+		var err error
+		if debugColor, err = postprocess.ParseHexColor(cfg.DebugCanvasColor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: debug_canvas_color: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var background *color.NRGBA
+	if cfg.BackgroundColor != "" {
+		bg, err := postprocess.ParseHexColor(cfg.BackgroundColor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: background_color: %v\n", err)
+			os.Exit(1)
+		}
+		bg.A = 255 // #RRGGBBAA is accepted but the background is always opaque
+		background = &bg
+	}
+	var outline *batch.Outline
+	if cfg.Outline < 0 {
+		fmt.Fprintf(os.Stderr, "Error: outline %d must not be negative\n", cfg.Outline)
+		os.Exit(1)
+	}
+	if cfg.Outline > 0 {
+		outlineColor := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		if cfg.OutlineColor != "" {
+			var err error
+			if outlineColor, err = postprocess.ParseHexColor(cfg.OutlineColor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: outline_color: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		outline = &batch.Outline{Thickness: cfg.Outline, Color: outlineColor}
+	}
+	var shadow *batch.DropShadow
+	if cfg.DropShadow {
+		shadowColor := color.NRGBA{A: 255}
+		if cfg.DropShadowColor != "" {
+			var err error
+			if shadowColor, err = postprocess.ParseHexColor(cfg.DropShadowColor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: drop_shadow_color: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		shadow = &batch.DropShadow{
+			OffsetX: cfg.DropShadowOffset[0],
+			OffsetY: cfg.DropShadowOffset[1],
+			Blur:    cfg.DropShadowBlur,
+			Color:   shadowColor,
+			Opacity: min(cfg.DropShadowOpacity, 1),
+		}
+	}
+	wireMode, ok := raster.WireframeModes[cfg.Wireframe]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown wireframe %q (use overlay or only)\n", cfg.Wireframe)
+		os.Exit(1)
+	}
+	wireColor := raster.DefaultWireframeColor
+	if cfg.WireframeColor != "" {
+		var err error
+		if wireColor, err = postprocess.ParseHexColor(cfg.WireframeColor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: wireframe_color: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: log_level: %v\n", err)
+		os.Exit(1)
+	}
+	if *quiet {
+		level = logging.LevelWarn
+	}
+	if *verbose {
+		level = logging.LevelDebug
+	}
+	log := logging.New(os.Stdout, os.Stderr, level)
+	logging.SetDefault(log)
+	profiles, err := buildProfiles(cfg.Profiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: profiles: %v\n", err)
+		os.Exit(1)
+	}
+
 	if cfg.BaseDir == "" {
 		fmt.Fprintln(os.Stderr, "Error: cannot find Data directory. Use -data flag or config.json.")
 		os.Exit(1)
 	}
 
+	if *validateTRS {
+		errs := trs.Validate(cfg.CustomTRS, cfg.ItemListXML)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		if len(errs) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: %d problem(s)\n", filepath.Base(cfg.CustomTRS), len(errs))
+			os.Exit(1)
+		}
+		fmt.Printf("%s: OK\n", filepath.Base(cfg.CustomTRS))
+		return
+	}
+
+	mmap.SetEnabled(cfg.Mmap)
+	convention, err := viewmatrix.Convention(cfg.Convention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: coordinate_convention: %v\n", err)
+		os.Exit(1)
+	}
+	renderOpts := raster.Options{
+		MinTriangleArea:   cfg.MinTriangleArea,
+		NoOpaquePromotion: cfg.NoOpaquePromotion,
+		SmoothShading:     cfg.SmoothShading,
+		Convention:        convention,
+		Wireframe:         wireMode,
+		WireframeColor:    wireColor,
+	}
+	if cfg.SSAO {
+		renderOpts.SSAORadius, renderOpts.SSAOIntensity = cfg.SSAORadius, cfg.SSAOIntensity
+	}
+	postOpts := postprocess.Options{
+		ContentAlpha:      cfg.ContentAlpha,
+		MaxImageDimension: cfg.MaxImageDimension,
+	}
+
 	// Load item list
 	items, err := itemlist.Parse(cfg.ItemListXML)
 	if err != nil {
@@ -58,6 +231,61 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *list {
+		listSections(items, *listJSON)
+		return
+	}
+
+	// Re-render only items flagged by a previous run
+	var prevManifest []batch.ManifestEntry
+	if *rerender != "" {
+		prevManifest, err = batch.ReadManifest(*rerender)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading manifest: %v\n", err)
+			os.Exit(1)
+		}
+		want := strings.Split(*statuses, ",")
+		for _, st := range want {
+			switch st {
+			case batch.StatusFailed, batch.StatusNearEmpty, batch.StatusFallbackTRS, batch.StatusOK:
+			default:
+				fmt.Fprintf(os.Stderr, "Error: unknown -status %q (use failed, near_empty, fallback_trs, ok)\n", st)
+				os.Exit(1)
+			}
+		}
+		items = batch.FlaggedItems(items, prevManifest, want)
+		log.Printf("Re-render: %d items flagged %s in %s\n", len(items), *statuses, *rerender)
+	}
+
+	// Render only items whose custom_trs.json overrides changed since a git ref
+	if *changedSince != "" {
+		changed, err := changedTRSKeys(cfg, *changedSince)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -changed-since: %v\n", err)
+			os.Exit(1)
+		}
+		var filtered []itemlist.ItemDef
+		for _, it := range items {
+			if changed[[2]int{it.Section, it.Index}] {
+				filtered = append(filtered, it)
+			}
+		}
+		items = filtered
+		log.Printf("Changed since %s: %d items\n", *changedSince, len(items))
+
+		// Update the last full run's manifest rather than replacing it
+		if prev, err := batch.ReadManifest(filepath.Join(cfg.OutputDir, "manifest.json")); err == nil && prevManifest == nil {
+			prevManifest = prev
+		}
+	}
+
+	// Resume: skipped items keep the last run's manifest entries
+	if (*skipExisting || *incremental) && prevManifest == nil {
+		if prev, err := batch.ReadManifest(filepath.Join(cfg.OutputDir, "manifest.json")); err == nil {
+			prevManifest = prev
+		}
+	}
+
 	// Filter by section/index
 	if *section >= 0 {
 		var filtered []itemlist.ItemDef
@@ -73,41 +301,75 @@ func main() {
 		items = filtered
 	}
 
+	// Known-broken items from config
+	items, skipped, err := batch.SkipItems(items, cfg.SkipItems)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Render order (before -test, so a quick run takes the first by priority)
+	if *order != "" {
+		cfg.RenderOrder = *order
+	}
+	if cfg.RenderOrder != "" || len(cfg.PriorityItems) > 0 {
+		if items, err = batch.OrderItems(items, cfg.RenderOrder, cfg.PriorityItems, cfg.ItemDir, cfg.Workers); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Limit for testing
 	if *testN > 0 && *testN < len(items) {
 		items = items[:*testN]
 	}
 
 	if len(items) == 0 {
-		fmt.Println("No items to render.")
+		log.Printf("No items to render.\n")
 		os.Exit(0)
 	}
 
 	// Load TRS data
-	trsData, err := trs.Load(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: TRS load: %v\n", err)
+	trsData, err := trs.LoadWith(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML, trs.Options{Strict: cfg.StrictTRS, OverrideDir: cfg.TRSOverrides})
+	if err != nil && cfg.StrictTRS {
+		fmt.Fprintf(os.Stderr, "Error: TRS load (strict_trs): %v\n", err)
+		os.Exit(1)
+	} else if err != nil {
+		log.Warnf("TRS load: %v", err)
 	}
-	fmt.Printf("TRS data: %d items loaded\n", len(trsData))
+	trsData.AddRotation(cfg.RotationOffset)
+	log.Printf("TRS data: %d items loaded\n", len(trsData))
 
 	// Build texture index (also scan Data/Skill for textures used by some items)
 	skillDir := filepath.Join(filepath.Dir(cfg.ItemDir), "Skill")
 	texIndex := texture.BuildIndex(cfg.ItemDir, skillDir)
-	texCache := texture.NewCache(texIndex)
-	fmt.Printf("Textures: %d indexed\n", texIndex.Len())
+	texCache := texture.NewCacheWithLimit(texIndex, cfg.TextureCacheMB<<20)
+	texCache.SetMaxSize(cfg.MaxTextureSize)
+	log.Printf("Textures: %d indexed\n", texIndex.Len())
 
 	// Print summary
 	mode := ""
-	if *section >= 0 {
+	if *rerender != "" {
+		mode = " (re-render)"
+	} else if *changedSince != "" {
+		mode = fmt.Sprintf(" (changed since %s)", *changedSince)
+	} else if *section >= 0 {
 		mode = fmt.Sprintf(" (Section %d)", *section)
 	} else if *testN > 0 {
 		mode = fmt.Sprintf(" (TEST: first %d)", *testN)
 	}
 
-	fmt.Printf("MU Online BMD 3D Renderer → WebP%s\n", mode)
-	fmt.Printf("Items: %d, Workers: %d\n", len(items), cfg.Workers)
-	fmt.Printf("Output: %s\n", cfg.OutputDir)
-	fmt.Println("------------------------------------------------------------")
+	log.Printf("MU Online BMD 3D Renderer → WebP%s\n", mode)
+	if cfg.EncodeWorkers > 0 {
+		log.Printf("Items: %d, Workers: %d (+%d encoding)\n", len(items), cfg.Workers, cfg.EncodeWorkers)
+	} else {
+		log.Printf("Items: %d, Workers: %d\n", len(items), cfg.Workers)
+	}
+	log.Printf("Output: %s\n", cfg.OutputDir)
+	for _, p := range profiles {
+		log.Printf("  profile %s: %s\n", p.Name, p.OutputDir)
+	}
+	log.Printf("------------------------------------------------------------\n")
 
 	start := time.Now()
 
@@ -116,25 +378,63 @@ func main() {
 		ItemDir:     cfg.ItemDir,
 		OutputDir:   cfg.OutputDir,
 		TexResolver: texCache,
+		WarmTextures: !*noWarm,
 		TRSData:     trsData,
 		RenderWidth:  cfg.RenderWidth,
 		RenderHeight: cfg.RenderHeight,
 		WebPQuality: cfg.WebPQuality,
 		Supersample: cfg.Supersample,
+		DownsamplePasses: cfg.DownsamplePasses,
+		Render:      renderOpts,
+		Postprocess: postOpts,
 		Workers:     cfg.Workers,
+		EncodeWorkers: cfg.EncodeWorkers,
+		MinFeaturePixels: cfg.MinFeaturePixels,
+		OutputFormat: cfg.OutputFormat,
+		Premultiply:  cfg.PremultipliedAlpha,
+		AlphaMatte:   cfg.AlphaMatte,
+		MatteQuality: cfg.MatteQuality,
+		MattePNG:     cfg.MattePNG,
+		Verbose:     level == logging.LevelDebug,
+		Log:         log,
+		Retries:     cfg.Retries,
+		IconCrop:    cfg.IconCrop,
+		Sidecar:     cfg.Sidecar,
+		TurntableFrames: cfg.TurntableFrames,
+		TurntableWebP:   cfg.TurntableWebP,
+		TurntableDelay:  cfg.TurntableDelay,
+		Background:  background,
+		Shadow:      shadow,
+		Outline:     outline,
+		DebugCanvas: cfg.DebugCanvas,
+		DebugColor:  debugColor,
+		Profiles:    profiles,
+		SkipExisting: *skipExisting,
+		CheckMtime:   *checkMtime,
+		Incremental:  *incremental,
+		Settings:     cfg.OutputKey(),
 	}
 
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: output directory: %v\n", err)
+		os.Exit(1)
+	}
 	results := batch.Run(batchCfg, items)
 
 	elapsed := time.Since(start)
-	fmt.Println("------------------------------------------------------------")
-	fmt.Printf("Done in %.1fs\n", elapsed.Seconds())
+	log.Printf("------------------------------------------------------------\n")
+	log.Printf("Done in %.1fs\n", elapsed.Seconds())
 
 	// Count results
-	success, failed := 0, 0
+	success, failed, retried, existing := 0, 0, 0, 0
 	var errors []Result
 	for _, r := range results {
-		if r.Success {
+		if r.Retries > 0 {
+			retried++
+		}
+		if r.Skipped {
+			existing++
+		} else if r.Success {
 			success++
 		} else {
 			failed++
@@ -142,26 +442,50 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Rendered: %d/%d\n", success, len(items))
+	log.Printf("Rendered: %d/%d\n", success, len(items)-existing)
+	if existing > 0 {
+		log.Printf("Skipped existing: %d\n", existing)
+	}
+	log.Printf("Texture cache: %.1f MiB\n", float64(texCache.MemoryUsage())/(1<<20))
+	if skipped > 0 {
+		log.Printf("Skipped by config: %d\n", skipped)
+	}
+	if retried > 0 {
+		log.Printf("Retried: %d items\n", retried)
+	}
 
 	if len(errors) > 0 {
-		fmt.Printf("\nFailed (%d):\n", failed)
+		byKind := make(map[batch.Failure]int)
+		for _, e := range errors {
+			byKind[e.Failure]++
+		}
+		var kinds []string
+		for k := batch.FailMissingBMD; k <= batch.FailOther; k++ {
+			if byKind[k] > 0 {
+				kinds = append(kinds, fmt.Sprintf("%s %d", k, byKind[k]))
+			}
+		}
+		log.Printf("\nFailed (%d): %s\n", failed, strings.Join(kinds, ", "))
 		limit := 20
 		if len(errors) < limit {
 			limit = len(errors)
 		}
 		for _, e := range errors[:limit] {
-			fmt.Printf("  %s: %s\n", e.Name, e.Error)
+			log.Printf("  %s: %s\n", e.Name, e.Error)
 		}
 	}
 
-	// Write manifest
+	// Write manifest; a re-render or -changed-since updates the previous run's entries in place
 	manifestPath := filepath.Join(cfg.OutputDir, "manifest.json")
-	os.MkdirAll(cfg.OutputDir, 0755)
-	if err := batch.WriteManifest(manifestPath, items); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: manifest write failed: %v\n", err)
+	entries := batch.BuildManifest(batchCfg, items, results)
+	printFlagged(log, entries)
+	if prevManifest != nil {
+		entries = batch.MergeManifest(prevManifest, entries)
+	}
+	if err := batch.WriteManifest(manifestPath, entries); err != nil {
+		log.Warnf("manifest write failed: %v", err)
 	} else {
-		fmt.Printf("Manifest: %s\n", manifestPath)
+		log.Printf("Manifest: %s\n", manifestPath)
 	}
 
 	if failed > 0 {
@@ -170,3 +494,105 @@ func main() {
 }
 
 type Result = batch.Result
+
+// changedTRSKeys resolves custom_trs.json as committed at ref and as it is
+// now, and returns the items whose resolved entry differs (see
+// trs.ChangedKeys), so an edited preset or section default selects every item
+// that inherits it.
+func changedTRSKeys(cfg config.Config, ref string) (map[[2]int]bool, error) {
+	cmd := exec.Command("git", "show", ref+":./"+filepath.Base(cfg.CustomTRS))
+	cmd.Dir = filepath.Dir(cfg.CustomTRS)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	old, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	tmp, err := os.CreateTemp("", "custom_trs-*"+filepath.Ext(cfg.CustomTRS))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(old); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	tmp.Close()
+
+	opts := trs.Options{OverrideDir: cfg.TRSOverrides}
+	before, err := trs.LoadWith(cfg.TRSBMD, tmp.Name(), cfg.ItemListXML, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s at %s: %w", filepath.Base(cfg.CustomTRS), ref, err)
+	}
+	after, err := trs.LoadWith(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[[2]int]bool)
+	for _, k := range trs.ChangedKeys(before, after) {
+		changed[k] = true
+	}
+	return changed, nil
+}
+
+// listSections prints each section with its item count, as a table or JSON.
+func listSections(items []itemlist.ItemDef, asJSON bool) {
+	sections := itemlist.Sections(items)
+	if asJSON {
+		out, _ := json.MarshalIndent(sections, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	fmt.Printf("%-8s %-32s %6s\n", "Section", "Name", "Items")
+	for _, s := range sections {
+		fmt.Printf("%-8d %-32s %6d\n", s.Index, s.Name, s.Items)
+	}
+	fmt.Printf("%d sections, %d items\n", len(sections), len(items))
+}
+
+// printFlagged prints how many rendered items the manifest flags for review
+// (near-empty or fallback TRS); failures are already reported above.
+func printFlagged(log logging.Logger, entries []batch.ManifestEntry) {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Status]++
+	}
+	var parts []string
+	for _, st := range []string{batch.StatusNearEmpty, batch.StatusFallbackTRS} {
+		if counts[st] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", st, counts[st]))
+		}
+	}
+	if len(parts) > 0 {
+		log.Printf("Flagged: %s (re-render with -rerender)\n", strings.Join(parts, ", "))
+	}
+}
+
+// buildProfiles converts the config's output profiles, checking that each
+// has a name and its own directory.
+func buildProfiles(cps []config.Profile) ([]batch.Profile, error) {
+	var profiles []batch.Profile
+	dirs := make(map[string]string)
+	for _, cp := range cps {
+		if cp.Name == "" {
+			return nil, fmt.Errorf("profile without a name")
+		}
+		if other, ok := dirs[cp.OutputDir]; ok {
+			return nil, fmt.Errorf("%s and %s write to the same directory %s", other, cp.Name, cp.OutputDir)
+		}
+		dirs[cp.OutputDir] = cp.Name
+
+		p := batch.Profile{Name: cp.Name, OutputDir: cp.OutputDir, Width: cp.RenderWidth, Height: cp.RenderHeight}
+		if cp.Background != "" {
+			bg, err := postprocess.ParseHexColor(cp.Background)
+			if err != nil {
+				return nil, fmt.Errorf("%s: background: %w", cp.Name, err)
+			}
+			p.Background = &bg
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
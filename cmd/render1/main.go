@@ -0,0 +1,96 @@
+// cmd/render1/main.go — Render one loose .bmd file to WebP (no ItemList.xml needed)
+//
+// Usage:
+//
+//	go run ./cmd/render1 <file.bmd> [out.webp]
+//	go run ./cmd/render1 -tex Data/Item -size 512 Data/Item/NewSword.bmd
+//	go run ./cmd/render1 -wireframe only Data/Item/NewSword.bmd
+//
+// Runs the same render + post-processing pipeline as cmd/render with default
+// TRS settings, for previewing a model before it is added to ItemList.xml.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemclass"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/texture"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+func main() {
+	texDir := flag.String("tex", "", "Item directory to index textures from (default: the BMD's directory)")
+	size := flag.Int("size", 256, "Output size in pixels (square)")
+	supersample := flag.Int("supersample", 2, "Supersampling multiplier")
+	wireframe := flag.String("wireframe", "", "Draw triangle edges: overlay (over the render) or only (edges alone)")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: render1 [flags] <file.bmd> [out.webp]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	wireMode, ok := raster.WireframeModes[*wireframe]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown -wireframe %q (use overlay or only)\n", *wireframe)
+		os.Exit(1)
+	}
+
+	bmdPath := flag.Arg(0)
+	stem := strings.TrimSuffix(filepath.Base(bmdPath), filepath.Ext(bmdPath))
+	outPath := stem + ".webp"
+	if flag.NArg() > 1 {
+		outPath = flag.Arg(1)
+	}
+
+	dir := filepath.Dir(bmdPath)
+	if *texDir == "" {
+		*texDir = dir
+	}
+	texIndex := texture.BuildIndex(*texDir, filepath.Join(filepath.Dir(*texDir), "Skill"))
+	fmt.Fprintf(os.Stderr, "Textures: %d indexed from %s\n", texIndex.Len(), *texDir)
+
+	cfg := batch.Config{
+		ItemDir:      dir,
+		TexResolver:  texture.NewCache(texIndex),
+		RenderWidth:  *size,
+		RenderHeight: *size,
+		Supersample:  *supersample,
+		Render:       raster.Options{Wireframe: wireMode},
+	}
+	// Synthetic item: section -1 never matches a TRS entry, so defaults apply
+	item := itemlist.ItemDef{
+		Section:   -1,
+		Index:     -1,
+		Name:      stem,
+		ModelFile: filepath.Base(bmdPath),
+		Category:  itemclass.Classify(bmdPath),
+	}
+
+	img, err := batch.RenderItem(cfg, item)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	f, err := os.Create(outPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	if err := nativewebp.Encode(f, img, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: WebP encode: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s → %s\n", bmdPath, outPath)
+}
@@ -0,0 +1,285 @@
+// cmd/stitch/main.go — Combine rendered item families into labeled strips
+//
+// Usage:
+//
+//	go run ./cmd/stitch
+//	go run ./cmd/stitch -by name -section 14 -cell 96 -o docs/families
+//
+// Groups items into families and writes one PNG per family with the
+// family's renders side by side, each labeled with the item's name and
+// section_index. Nothing is rendered: the images come from a previous
+// render run's output tree (<renders>/<section>/<index>.webp).
+//
+// Families (-by):
+//
+//	model     items that share a model file (e.g. every Box of Kundun level)
+//	name      items whose names match once a trailing tier marker is dropped
+//	          ("+3", "Lv.2", "(5)", "IV", "7")
+//	category  items of one section with the same itemclass category
+//
+// Families wider than -cols wrap onto more rows.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/webp"
+
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/postprocess"
+)
+
+// tierSuffix matches one trailing tier marker of an item name.
+var tierSuffix = regexp.MustCompile(`(?i)\s*(\+\d+|lv\.?\s*\d+|\(\d+\)|\b[ivx]+|\d+)$`)
+
+const (
+	labelH = 16 // height of a label row (basicfont is 13px tall)
+	pad    = 4
+)
+
+type family struct {
+	key   string
+	label string
+	items []itemlist.ItemDef
+}
+
+func main() {
+	configFile := flag.String("config", "", "Path to config file (.json, .toml, .yaml)")
+	dataDir := flag.String("data", "", "Path to base directory (default: auto-detect)")
+	renderDir := flag.String("renders", "", "Render output directory (default: Data/Item-renders)")
+	outDir := flag.String("o", "", "Directory for the strips (default: <renders>/families)")
+	by := flag.String("by", "model", "Group items by: model, name, or category")
+	section := flag.Int("section", -1, "Only items from this section")
+	minSize := flag.Int("min", 2, "Skip families with fewer rendered items than this")
+	cell := flag.Int("cell", 128, "Side of each item's square cell in pixels")
+	cols := flag.Int("cols", 8, "Items per row before a family wraps")
+	bgHex := flag.String("bg", "#202020", "Background color (#RRGGBB[AA]; #00000000 = transparent)")
+	flag.Parse()
+
+	if *by != "model" && *by != "name" && *by != "category" {
+		fmt.Fprintf(os.Stderr, "Error: unknown -by %q (use model, name, or category)\n", *by)
+		os.Exit(1)
+	}
+	if *cell < 16 || *cols < 1 {
+		fmt.Fprintln(os.Stderr, "Error: -cell must be at least 16 and -cols at least 1")
+		os.Exit(1)
+	}
+	bg, err := postprocess.ParseHexColor(*bgHex)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -bg: %v\n", err)
+		os.Exit(1)
+	}
+
+	var cfg config.Config
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cfg.Resolve(config.Flags{DataDir: *dataDir, OutputDir: *renderDir})
+	if *outDir == "" {
+		*outDir = filepath.Join(cfg.OutputDir, "families")
+	}
+
+	items, err := itemlist.Parse(cfg.ItemListXML)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading ItemList.xml: %v\n", err)
+		os.Exit(1)
+	}
+	if *section >= 0 {
+		var filtered []itemlist.ItemDef
+		for _, it := range items {
+			if it.Section == *section {
+				filtered = append(filtered, it)
+			}
+		}
+		items = filtered
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	written := 0
+	used := make(map[string]bool)
+	for _, fam := range groupFamilies(items, *by) {
+		var cells []image.Image
+		var members []itemlist.ItemDef
+		for _, it := range fam.items {
+			img, err := decodeWebP(filepath.Join(cfg.OutputDir, fmt.Sprint(it.Section), fmt.Sprintf("%d.webp", it.Index)))
+			if err != nil {
+				continue // not rendered (or failed); the family goes on without it
+			}
+			cells = append(cells, img)
+			members = append(members, it)
+		}
+		if len(cells) == 0 || len(cells) < *minSize {
+			continue
+		}
+
+		name := fileName(fam.key, used)
+		path := filepath.Join(*outDir, name+".png")
+		if err := writePNG(path, stitch(fam.label, members, cells, *cell, *cols, bg)); err != nil {
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("  %-40s %d items\n", name+".png", len(cells))
+		written++
+	}
+	fmt.Printf("Wrote %d family strips to %s\n", written, *outDir)
+}
+
+// groupFamilies groups items by the family key of mode, keeping ItemList
+// order within a family and ordering families by their first item.
+func groupFamilies(items []itemlist.ItemDef, mode string) []family {
+	var fams []family
+	byKey := make(map[string]int)
+	for _, it := range items {
+		key, label := familyKey(it, mode)
+		i, ok := byKey[key]
+		if !ok {
+			i = len(fams)
+			byKey[key] = i
+			fams = append(fams, family{key: key, label: label})
+		}
+		fams[i].items = append(fams[i].items, it)
+	}
+	sort.SliceStable(fams, func(a, b int) bool {
+		fa, fb := fams[a].items[0], fams[b].items[0]
+		if fa.Section != fb.Section {
+			return fa.Section < fb.Section
+		}
+		return fa.Index < fb.Index
+	})
+	return fams
+}
+
+// familyKey returns the grouping key of it under mode and the family's
+// title.
+func familyKey(it itemlist.ItemDef, mode string) (key, label string) {
+	switch mode {
+	case "name":
+		base := it.Name
+		for {
+			trimmed := strings.TrimSpace(tierSuffix.ReplaceAllString(base, ""))
+			if trimmed == base || trimmed == "" {
+				break
+			}
+			base = trimmed
+		}
+		return strings.ToLower(base), base
+	case "category":
+		return fmt.Sprintf("%d_%s", it.Section, it.Category), fmt.Sprintf("%s - %s", it.SectionName, it.Category)
+	default:
+		model := filepath.ToSlash(filepath.Join(it.SubDir, it.ModelFile))
+		return strings.ToLower(strings.TrimSuffix(model, filepath.Ext(model))), it.ModelFile
+	}
+}
+
+// stitch lays cells out in rows of cols under a title row, each cell with
+// its item's label beneath.
+func stitch(title string, items []itemlist.ItemDef, cells []image.Image, size, cols int, bg color.NRGBA) *image.NRGBA {
+	n := len(cells)
+	perRow := min(n, cols)
+	rows := (n + cols - 1) / cols
+	cellW, cellH := size+pad, size+labelH+pad
+	w := max(pad+perRow*cellW, pad+len([]rune(title))*basicfont.Face7x13.Advance+pad)
+	h := labelH + pad + rows*cellH
+
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	fg := labelColor(bg)
+	drawText(out, pad, labelH-3, title, w-2*pad, fg)
+
+	for i, img := range cells {
+		x0 := pad + (i%cols)*cellW
+		y0 := labelH + pad + (i/cols)*cellH
+
+		// Aspect-fit the render into its square
+		b := img.Bounds()
+		scale := float64(size) / float64(max(b.Dx(), b.Dy()))
+		dw, dh := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+		dst := image.Rect(x0+(size-dw)/2, y0+(size-dh)/2, x0+(size-dw)/2+dw, y0+(size-dh)/2+dh)
+		xdraw.CatmullRom.Scale(out, dst, img, b, xdraw.Over, nil)
+
+		it := items[i]
+		drawText(out, x0, y0+size+labelH-3, fmt.Sprintf("%d_%d %s", it.Section, it.Index, it.Name), size, fg)
+	}
+	return out
+}
+
+// drawText draws s with its baseline at (x, y), cut to maxW pixels.
+func drawText(dst *image.NRGBA, x, y int, s string, maxW int, c color.Color) {
+	face := basicfont.Face7x13
+	if maxChars := maxW / face.Advance; len([]rune(s)) > maxChars {
+		s = string([]rune(s)[:max(maxChars-3, 0)]) + "..."
+	}
+	d := font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// labelColor picks white or black text, whichever reads better on bg
+// (white on a transparent background).
+func labelColor(bg color.NRGBA) color.Color {
+	if bg.A < 128 || 0.299*float64(bg.R)+0.587*float64(bg.G)+0.114*float64(bg.B) < 128 {
+		return color.White
+	}
+	return color.Black
+}
+
+// fileName turns a family key into a unique file name stem.
+func fileName(key string, used map[string]bool) string {
+	stem := strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(key)), "_")
+	if stem == "" {
+		stem = "family"
+	}
+	name := stem
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", stem, i)
+	}
+	used[name] = true
+	return name
+}
+
+func decodeWebP(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return webp.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -47,9 +47,25 @@ func dumpTexture(base string, f texFile) error {
 	}
 	fmt.Printf("OK  %s -> %s  (%d-byte header skipped, %d bytes %s written)\n",
 		f.srcPath, f.dstName, headerSize, len(payload), dataType)
+	if f.format == "ozt" && len(payload) >= 18 {
+		fmt.Printf("    TGA type %d, %d bpp, origin %s\n", payload[2], payload[16], tgaOrigin(payload[17]))
+	}
 	return nil
 }
 
+// tgaOrigin describes the image descriptor's origin bits (bit 5 = top,
+// bit 4 = right). The texture loader normalizes every origin to top-left.
+func tgaOrigin(desc byte) string {
+	v, h := "bottom", "left"
+	if desc&0x20 != 0 {
+		v = "top"
+	}
+	if desc&0x10 != 0 {
+		h = "right"
+	}
+	return v + "-" + h
+}
+
 func main() {
 	base := "."
 	if len(os.Args) > 1 {
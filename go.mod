@@ -3,9 +3,11 @@ module mu-bmd-renderer
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/HugoSmits86/nativewebp v1.2.1
 	github.com/ftrvxmtrx/tga v0.0.0-20150524081124-bd8e8d5be13a
 	golang.org/x/image v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/text v0.34.0 // indirect
+require golang.org/x/text v0.34.0
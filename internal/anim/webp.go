@@ -0,0 +1,64 @@
+// Package anim assembles rendered frames into animated images.
+package anim
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// Canvas returns frames on one canvas size, the largest width and height
+// among them, each centered on it (transparent around). Frames already at
+// that size are returned as they are.
+func Canvas(frames []*image.NRGBA) []*image.NRGBA {
+	w, h := 0, 0
+	for _, f := range frames {
+		w, h = max(w, f.Bounds().Dx()), max(h, f.Bounds().Dy())
+	}
+	out := make([]*image.NRGBA, len(frames))
+	for i, f := range frames {
+		b := f.Bounds()
+		if b.Dx() == w && b.Dy() == h && b.Min == (image.Point{}) {
+			out[i] = f
+			continue
+		}
+		c := image.NewNRGBA(image.Rect(0, 0, w, h))
+		at := image.Pt((w-b.Dx())/2, (h-b.Dy())/2)
+		draw.Draw(c, b.Sub(b.Min).Add(at), f, b.Min, draw.Src)
+		out[i] = c
+	}
+	return out
+}
+
+// EncodeWebP writes frames to w as a lossless animated WebP that loops
+// forever, showing each frame for delayMS milliseconds. Frames of different
+// sizes are centered on a common canvas first (see Canvas), and each is
+// cleared before the next, so transparent parts don't show earlier frames.
+// A nil or empty frame is an error: it has no size to center.
+func EncodeWebP(w io.Writer, frames []*image.NRGBA, delayMS int) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames")
+	}
+	for i, f := range frames {
+		if f == nil || f.Bounds().Empty() {
+			return fmt.Errorf("frame %d is empty", i)
+		}
+	}
+	if delayMS <= 0 {
+		return fmt.Errorf("frame delay %d ms must be positive", delayMS)
+	}
+	ani := &nativewebp.Animation{
+		Images:    make([]image.Image, len(frames)),
+		Durations: make([]uint, len(frames)),
+		Disposals: make([]uint, len(frames)),
+	}
+	for i, f := range Canvas(frames) {
+		ani.Images[i] = f
+		ani.Durations[i] = uint(delayMS)
+		ani.Disposals[i] = 1 // clear to the (transparent) background
+	}
+	return nativewebp.EncodeAll(w, ani, nil)
+}
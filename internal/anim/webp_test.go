@@ -0,0 +1,121 @@
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// webpFrame is one ANMF chunk of an animated WebP: the frame's rectangle on
+// the canvas and its duration.
+type webpFrame struct {
+	rect     image.Rectangle
+	duration int
+}
+
+// parseAnimation walks the RIFF chunks of an animated WebP and returns its
+// canvas size (VP8X) and frames (ANMF).
+func parseAnimation(t *testing.T, data []byte) (image.Point, []webpFrame) {
+	t.Helper()
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		t.Fatalf("not a WebP file: % x", data[:min(len(data), 12)])
+	}
+	u24 := func(b []byte) int { return int(b[0]) | int(b[1])<<8 | int(b[2])<<16 }
+	var canvas image.Point
+	var frames []webpFrame
+	for p := 12; p+8 <= len(data); {
+		id, size := string(data[p:p+4]), int(binary.LittleEndian.Uint32(data[p+4:]))
+		body := data[p+8 : min(p+8+size, len(data))]
+		switch id {
+		case "VP8X":
+			canvas = image.Pt(u24(body[4:])+1, u24(body[7:])+1)
+		case "ANMF":
+			x, y := 2*u24(body), 2*u24(body[3:])
+			w, h := u24(body[6:])+1, u24(body[9:])+1
+			frames = append(frames, webpFrame{image.Rect(x, y, x+w, y+h), u24(body[12:])})
+		}
+		p += 8 + size + size&1
+	}
+	return canvas, frames
+}
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+// TestEncodeWebP encodes three frames and reads the file's chunks back: one
+// frame each, every one shown for the delay, on the canvas of the largest.
+func TestEncodeWebP(t *testing.T) {
+	frames := []*image.NRGBA{
+		solid(32, 24, color.NRGBA{255, 0, 0, 255}),
+		solid(32, 24, color.NRGBA{0, 255, 0, 128}),
+		solid(32, 24, color.NRGBA{0, 0, 255, 255}),
+	}
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, frames, 80); err != nil {
+		t.Fatal(err)
+	}
+	canvas, got := parseAnimation(t, buf.Bytes())
+	if canvas != image.Pt(32, 24) {
+		t.Errorf("canvas %v, want 32x24", canvas)
+	}
+	if len(got) != len(frames) {
+		t.Fatalf("%d frames, want %d", len(got), len(frames))
+	}
+	for i, f := range got {
+		if f.duration != 80 {
+			t.Errorf("frame %d: %d ms, want 80", i, f.duration)
+		}
+	}
+}
+
+// TestEncodeWebPSizes checks frames of differing sizes come out on one
+// canvas, the largest width and height among them, each centered.
+func TestEncodeWebPSizes(t *testing.T) {
+	frames := []*image.NRGBA{solid(40, 20, color.NRGBA{255, 0, 0, 255}), solid(20, 30, color.NRGBA{0, 0, 255, 255})}
+	c := Canvas(frames)
+	for i, f := range c {
+		if f.Bounds() != image.Rect(0, 0, 40, 30) {
+			t.Errorf("frame %d: canvas %v, want 40x30", i, f.Bounds())
+		}
+	}
+	if c[0].NRGBAAt(0, 0).A != 0 || c[0].NRGBAAt(20, 15).R != 255 || c[1].NRGBAAt(9, 15).A != 0 || c[1].NRGBAAt(10, 0).B != 255 {
+		t.Error("frames not centered on the canvas")
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, frames, 100); err != nil {
+		t.Fatal(err)
+	}
+	if canvas, got := parseAnimation(t, buf.Bytes()); canvas != image.Pt(40, 30) || len(got) != 2 {
+		t.Errorf("canvas %v with %d frames, want 40x30 with 2", canvas, len(got))
+	}
+}
+
+func TestEncodeWebPErrors(t *testing.T) {
+	frame := solid(8, 8, color.NRGBA{255, 255, 255, 255})
+	for _, c := range []struct {
+		name   string
+		frames []*image.NRGBA
+		delay  int
+	}{
+		{"no frames", nil, 100},
+		{"nil frame", []*image.NRGBA{frame, nil}, 100},
+		{"empty frame", []*image.NRGBA{frame, image.NewNRGBA(image.Rect(0, 0, 0, 8))}, 100},
+		{"zero delay", []*image.NRGBA{frame}, 0},
+	} {
+		var buf bytes.Buffer
+		if err := EncodeWebP(&buf, c.frames, c.delay); err == nil {
+			t.Errorf("%s: no error", c.name)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: %d bytes written", c.name, buf.Len())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package batch_test
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+)
+
+// readTree returns the files under dir by slash-separated relative path.
+func readTree(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		rel, _ := filepath.Rel(dir, p)
+		files[filepath.ToSlash(rel)] = data
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// TestEncodeWorkers runs the whole fixture with two render workers and
+// with encode pools of several sizes: results and written files must match
+// the run where each worker writes its own items. Run with -race, it also
+// checks the hand-off between the pools.
+func TestEncodeWorkers(t *testing.T) {
+	// Errors name the fixture's temporary directory, which differs per run
+	type outcome struct {
+		Section, Index int
+		Failure        batch.Failure
+		Coverage       float64
+		Output         batch.OutputInfo
+	}
+	run := func(encoders int) ([]outcome, map[string][]byte) {
+		cfg, items := newFixture(t)
+		cfg.Workers, cfg.EncodeWorkers = 2, encoders
+		cfg.Sidecar = true
+		cfg.Profiles = []batch.Profile{
+			{Name: "large", OutputDir: filepath.Join(cfg.OutputDir, "large")},
+			{Name: "small", OutputDir: filepath.Join(cfg.OutputDir, "small"), Width: 64, Height: 64},
+		}
+		var results []outcome
+		for _, r := range batch.Run(cfg, items) {
+			o := outcome{Section: r.Section, Index: r.Index, Failure: r.Failure, Coverage: r.Coverage}
+			if r.Output != nil {
+				o.Output = *r.Output
+			}
+			results = append(results, o)
+		}
+		return results, readTree(t, cfg.OutputDir)
+	}
+
+	wantResults, wantFiles := run(0)
+	if len(wantFiles) == 0 {
+		t.Fatal("nothing written")
+	}
+	for _, encoders := range []int{1, 3} {
+		results, files := run(encoders)
+		if !reflect.DeepEqual(results, wantResults) {
+			t.Errorf("encode_workers %d: results %+v, want %+v", encoders, results, wantResults)
+		}
+		if len(files) != len(wantFiles) {
+			t.Errorf("encode_workers %d: %d files written, want %d", encoders, len(files), len(wantFiles))
+		}
+		for name, want := range wantFiles {
+			if filepath.Ext(name) == ".json" {
+				continue // sidecars hold timings
+			}
+			if got, ok := files[name]; !ok || !bytes.Equal(got, want) {
+				t.Errorf("encode_workers %d: %s differs or is missing", encoders, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// ErrNoMeshes is returned for a BMD that parses but contains no meshes.
+var ErrNoMeshes = errors.New("no meshes in BMD")
+
+// EncodeError wraps a failure while encoding or writing an output image.
+type EncodeError struct {
+	Format string // "webp", "png", "exr", or "matte"
+	Err    error
+}
+
+func (e *EncodeError) Error() string {
+	return fmt.Sprintf("%s encode: %v", e.Format, e.Err)
+}
+
+func (e *EncodeError) Unwrap() error { return e.Err }
+
+// Failure categorizes why an item failed.
+type Failure int
+
+const (
+	FailNone       Failure = iota // item succeeded
+	FailMissingBMD                // model file not found
+	FailParse                     // malformed BMD
+	FailNoMeshes                  // BMD has no meshes
+	FailIO                        // file read/write error (retryable)
+	FailEncode                    // image encoder error
+	FailOther
+)
+
+var failureNames = [...]string{"ok", "missing BMD", "parse error", "no meshes", "I/O error", "encode error", "other"}
+
+func (f Failure) String() string {
+	if f < 0 || int(f) >= len(failureNames) {
+		return "unknown"
+	}
+	return failureNames[f]
+}
+
+// classify maps an item error to its Failure category. File-system errors
+// win over EncodeError, so a disk-full write inside the encoder counts as
+// I/O (and is retried) rather than as an encoder bug.
+func classify(err error) Failure {
+	var pe *bmd.ParseError
+	var fsErr *fs.PathError
+	var ee *EncodeError
+	switch {
+	case err == nil:
+		return FailNone
+	case errors.Is(err, bmd.ErrNotFound):
+		return FailMissingBMD
+	case errors.As(err, &pe):
+		return FailParse
+	case errors.Is(err, ErrNoMeshes):
+		return FailNoMeshes
+	case errors.As(err, &fsErr):
+		return FailIO
+	case errors.As(err, &ee):
+		return FailEncode
+	}
+	return FailOther
+}
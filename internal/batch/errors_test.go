@@ -0,0 +1,64 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+func TestClassify(t *testing.T) {
+	pathErr := &fs.PathError{Op: "write", Path: "out/0/1.webp", Err: errors.New("no space left on device")}
+	for _, c := range []struct {
+		err  error
+		want Failure
+	}{
+		{nil, FailNone},
+		{fmt.Errorf("bmd: read x.bmd: %w", bmd.ErrNotFound), FailMissingBMD},
+		{&bmd.ParseError{Path: "x.bmd", Reason: "invalid header"}, FailParse},
+		{fmt.Errorf("item 0_1: %w", ErrNoMeshes), FailNoMeshes},
+		{pathErr, FailIO},
+		{&EncodeError{Format: "webp", Err: pathErr}, FailIO}, // a full disk is not an encoder bug
+		{&EncodeError{Format: "png", Err: errors.New("bad size")}, FailEncode},
+		{errors.New("something else"), FailOther},
+	} {
+		if got := classify(c.err); got != c.want {
+			t.Errorf("classify(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestFailureString(t *testing.T) {
+	if got := FailIO.String(); got != "I/O error" {
+		t.Errorf("FailIO = %q", got)
+	}
+	for f := FailNone; f <= FailOther; f++ {
+		if f.String() == "unknown" {
+			t.Errorf("Failure %d has no name", int(f))
+		}
+	}
+	if got := Failure(-1).String(); got != "unknown" {
+		t.Errorf("Failure(-1) = %q, want unknown", got)
+	}
+}
+
+func TestParseErrorTypes(t *testing.T) {
+	dir := t.TempDir()
+	_, _, err := bmd.Parse(filepath.Join(dir, "Missing.bmd"))
+	if !errors.Is(err, bmd.ErrNotFound) || classify(err) != FailMissingBMD {
+		t.Errorf("missing file: %v, want bmd.ErrNotFound", err)
+	}
+	path := filepath.Join(dir, "Bad.bmd")
+	if err := os.WriteFile(path, []byte("OZJ\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = bmd.Parse(path)
+	var pe *bmd.ParseError
+	if !errors.As(err, &pe) || pe.Offset != 0 || pe.Path != path {
+		t.Errorf("bad header: %v, want a *bmd.ParseError at offset 0 naming %s", err, path)
+	}
+}
@@ -0,0 +1,340 @@
+package batch_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/crypto"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/texture"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// fixtureItem is one item of the fixture tree and what its render must
+// look like.
+type fixtureItem struct {
+	section, index int
+	name           string
+	modelPath      string // ItemList ModelPath
+	modelFile      string
+	render         bool       // false: the model is missing and the item must fail
+	suggest        string     // missing model: the "did you mean" expected for it ("" = none)
+	width, height  int        // expected output size
+	trsSource      string     // expected TRS entry source: "binary" or "custom"
+	meshes         int        // meshes expected to be drawn, all in the opaque pass
+	tint           color.RGBA // the dominant channel of its opaque pixels comes from this texture color
+}
+
+// Texture colors, one clearly dominant channel each so a render shows which
+// texture it sampled
+var (
+	swordColor = color.RGBA{200, 40, 30, 255}
+	hiltColor  = color.RGBA{210, 170, 40, 255}
+	jewelColor = color.RGBA{30, 60, 210, 255}
+)
+
+// fixtureItems covers the wiring between modules:
+//
+//	0_0   v10 model in Data/Item, binary TRS entry, OZJ + OZT textures
+//	0_1   same model, no binary entry; custom_trs.json gives it its own
+//	      output size
+//	14_0  v12 (XOR) model in a ModelPath subdirectory, texture in that
+//	      subdirectory's Texture/ folder
+//	14_4  v15 (LEA-256) model beside it, same texture
+//	14_1  model missing (wrong ModelPath), 14_2 missing (misspelled), 14_3
+//	      missing (nothing like it): each must fail as a missing BMD, write
+//	      nothing, and suggest the right model where there is one
+var fixtureItems = []fixtureItem{
+	{0, 0, "Check Sword", `Data\Item\`, "Sword01.bmd", true, "", 256, 256, "binary", 3, swordColor},
+	{0, 1, "Check Sword +1", `Data\Item\`, "Sword01.bmd", true, "", 128, 64, "custom", 3, swordColor},
+	{14, 0, "Check Jewel", `Data\Item\Jewel\`, "Jewel01.bmd", true, "", 256, 256, "binary", 1, jewelColor},
+	{14, 4, "Check Jewel v15", `Data\Item\Jewel\`, "Jewel02.bmd", true, "", 256, 256, "binary", 1, jewelColor},
+	{14, 1, "Check Misplaced", `Data\Item\`, "Jewel01.bmd", false, "Jewel/Jewel01.bmd", 0, 0, "", 0, color.RGBA{}},
+	{14, 2, "Check Misspelled", `Data\Item\Jewel\`, "Jewl01.bmd", false, "Jewel/Jewel01.bmd", 0, 0, "", 0, color.RGBA{}},
+	{14, 3, "Check Missing", `Data\Item\`, "Helm77.bmd", false, "", 0, 0, "", 0, color.RGBA{}},
+}
+
+// writeFixture lays out a small MU Data tree under base.
+func writeFixture(base string) error {
+	itemDir := filepath.Join(base, "Data", "Item")
+
+	blade := box([3]float32{-10, -4, 0}, [3]float32{10, 4, 120}, 4)
+	hilt := box([3]float32{-28, -6, -8}, [3]float32{28, 6, 0}, 1)
+	grip := box([3]float32{-5, -5, -40}, [3]float32{5, 5, -8}, 5) // not the blade's vertex count: that reads as a duplicate-geometry overlay
+	sword := encodeBMD("Sword01", []fixtureMesh{
+		blade.textured("sword01.jpg"),
+		hilt.textured("hilt01.tga"),
+		grip.textured("sword01.jpg"),
+	})
+	gem := box([3]float32{-20, -20, -30}, [3]float32{20, 20, 30}, 1)
+	jewel := encryptBMD(encodeBMD("Jewel01", []fixtureMesh{gem.textured("jewel01.jpg")}))
+	jewelLEA := encryptLEABMD(encodeBMD("Jewel02", []fixtureMesh{gem.textured("jewel01.jpg")}))
+
+	files := map[string][]byte{
+		filepath.Join(itemDir, "Sword01.bmd"):                     sword,
+		filepath.Join(itemDir, "Jewel", "Jewel01.bmd"):            jewel,
+		filepath.Join(itemDir, "Jewel", "Jewel02.bmd"):            jewelLEA,
+		filepath.Join(itemDir, "texture", "sword01.ozj"):          ozj(swordColor),
+		filepath.Join(itemDir, "texture", "hilt01.ozt"):           ozt(hiltColor),
+		filepath.Join(itemDir, "Jewel", "Texture", "jewel01.ozj"): ozj(jewelColor),
+		filepath.Join(base, "Data", "Local", "itemtrsdata.bmd"):   itemTRS(),
+		filepath.Join(base, "Data", "Xml", "ItemList.xml"):        itemListXML(),
+		filepath.Join(base, "custom_trs.json"): []byte(`{
+  "items": {
+    "0_1": {"render_width": 128, "render_height": 64}
+  }
+}
+`),
+	}
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func itemListXML() []byte {
+	var b bytes.Buffer
+	b.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<ItemList>\n")
+	sections := []struct {
+		index int
+		name  string
+	}{{0, "Swords"}, {14, "Jewels"}}
+	for _, s := range sections {
+		fmt.Fprintf(&b, "  <Section Index=\"%d\" Name=\"%s\">\n", s.index, s.name)
+		for _, it := range fixtureItems {
+			if it.section == s.index {
+				fmt.Fprintf(&b, "    <Item Index=\"%d\" Name=\"%s\" ModelPath=\"%s\" ModelFile=\"%s\"/>\n",
+					it.index, it.name, it.modelPath, it.modelFile)
+			}
+		}
+		b.WriteString("  </Section>\n")
+	}
+	b.WriteString("</ItemList>\n")
+	return b.Bytes()
+}
+
+// itemTRS encodes ItemTRSData.bmd with entries for 0_0, 14_0 and 14_4 (0_1's
+// entry comes from custom_trs.json alone).
+func itemTRS() []byte {
+	entries := []struct {
+		section, index   int
+		rotX, rotY, rotZ float32
+		scale            float32
+	}{
+		{0, 0, 270, 0, 0, 1},
+		{14, 0, 270, 0, 45, 1},
+		{14, 4, 270, 0, 45, 1},
+	}
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(entries)))
+	for _, e := range entries {
+		rec := binary.LittleEndian.AppendUint32(nil, uint32(e.section*512+e.index))
+		for _, f := range []float32{0, 0, 0, e.rotX, e.rotY, e.rotZ, e.scale} {
+			rec = binary.LittleEndian.AppendUint32(rec, math.Float32bits(f))
+		}
+		out = append(out, crypto.DecryptTRS(rec)...) // XOR: its own inverse
+	}
+	return out
+}
+
+// fixtureMesh is one mesh of a fixture model.
+type fixtureMesh struct {
+	verts   [][3]float32
+	quads   [][4]int16
+	texture string
+}
+
+// box returns an axis-aligned box cut into segments along z. Real body
+// meshes have more than 16 vertices; a JPEG mesh smaller than that is taken
+// for a glow billboard and drawn additively.
+func box(lo, hi [3]float32, segments int) fixtureMesh {
+	var m fixtureMesh
+	ring := [4][2]float32{{lo[0], lo[1]}, {hi[0], lo[1]}, {hi[0], hi[1]}, {lo[0], hi[1]}}
+	for k := 0; k <= segments; k++ {
+		z := lo[2] + (hi[2]-lo[2])*float32(k)/float32(segments)
+		for _, c := range ring {
+			m.verts = append(m.verts, [3]float32{c[0], c[1], z})
+		}
+	}
+	for k := 0; k < segments; k++ {
+		for c := 0; c < 4; c++ {
+			a, b := int16(k*4+c), int16(k*4+(c+1)%4)
+			m.quads = append(m.quads, [4]int16{a, b, b + 4, a + 4})
+		}
+	}
+	top := int16(segments * 4)
+	m.quads = append(m.quads, [4]int16{3, 2, 1, 0}, [4]int16{top, top + 1, top + 2, top + 3})
+	return m
+}
+
+func (m fixtureMesh) textured(name string) fixtureMesh {
+	m.texture = name
+	return m
+}
+
+// encodeBMD encodes meshes as an unencrypted (version 10) BMD with one
+// root bone and one single-key action.
+func encodeBMD(name string, meshes []fixtureMesh) []byte {
+	var b bytes.Buffer
+	le := func(v any) { binary.Write(&b, binary.LittleEndian, v) }
+	str := func(s string) {
+		var buf [32]byte
+		copy(buf[:], s)
+		b.Write(buf[:])
+	}
+
+	b.WriteString("BMD\x0a")
+	str(name)
+	le([3]uint16{uint16(len(meshes)), 1, 1})
+	for _, m := range meshes {
+		nv := int16(len(m.verts))
+		le([5]int16{nv, nv, 4, int16(2 * len(m.quads)), 0})
+		for _, v := range m.verts {
+			le([2]int16{0, 0})
+			le(v)
+		}
+		for _, v := range m.verts {
+			l := float32(math.Sqrt(float64(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])))
+			le([2]int16{0, 0})
+			le([3]float32{v[0] / l, v[1] / l, v[2] / l})
+			le([2]int16{0, 0})
+		}
+		le([4][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}})
+		for _, q := range m.quads {
+			for _, corners := range [2][3]int{{0, 1, 2}, {0, 2, 3}} {
+				var rec [64]byte
+				rec[0] = 3
+				for k, c := range corners {
+					binary.LittleEndian.PutUint16(rec[2+k*2:], uint16(q[c]))  // vertex
+					binary.LittleEndian.PutUint16(rec[10+k*2:], uint16(q[c])) // normal
+					binary.LittleEndian.PutUint16(rec[18+k*2:], uint16(c))    // UV: the quad's corner
+				}
+				b.Write(rec[:])
+			}
+		}
+		str(m.texture)
+	}
+
+	le(int16(1)) // action 0: one key, no locked positions
+	b.WriteByte(0)
+	b.WriteByte(0) // bone 0: not a dummy
+	str("Bone01")
+	le(int16(-1))
+	le([6]float32{}) // key 0: position, rotation
+	return b.Bytes()
+}
+
+// encryptBMD turns a version 10 BMD into version 12, the inverse of
+// crypto.DecryptXOR.
+func encryptBMD(v10 []byte) []byte {
+	plain := v10[4:]
+	out := append([]byte("BMD\x0c"), binary.LittleEndian.AppendUint32(nil, uint32(len(plain)))...)
+	chain := byte(0x5E)
+	for i, p := range plain {
+		c := (p + chain) ^ crypto.XORKey[i&15]
+		out = append(out, c)
+		chain = c + 0x3D
+	}
+	return out
+}
+
+// encryptLEABMD turns a version 10 BMD into version 15: the body zero-padded
+// to whole 16-byte blocks and encrypted with crypto.EncryptLEA.
+func encryptLEABMD(v10 []byte) []byte {
+	plain := append([]byte(nil), v10[4:]...)
+	plain = append(plain, make([]byte, -len(plain)&15)...)
+	out := append([]byte("BMD\x0f"), binary.LittleEndian.AppendUint32(nil, uint32(len(plain)))...)
+	return append(out, crypto.EncryptLEA(plain, crypto.LEAKey)...)
+}
+
+// ozj encodes a 32×32 OZJ texture of c with a darker checker so lighting
+// and filtering have something to work on.
+func ozj(c color.RGBA) []byte {
+	var b bytes.Buffer
+	b.Write(make([]byte, 24))
+	jpeg.Encode(&b, checker(c), &jpeg.Options{Quality: 95})
+	return b.Bytes()
+}
+
+// ozt encodes a 32×32 OZT texture (uncompressed 32-bit TGA, top-left
+// origin) of c, fully opaque.
+func ozt(c color.RGBA) []byte {
+	img := checker(c)
+	out := make([]byte, 4, 4+18+32*32*4)
+	hdr := [18]byte{2: 2, 12: 32, 14: 32, 16: 32, 17: 0x28}
+	out = append(out, hdr[:]...)
+	for i := 0; i < len(img.Pix); i += 4 {
+		p := img.Pix[i : i+4]
+		out = append(out, p[2], p[1], p[0], p[3])
+	}
+	return out
+}
+
+func checker(c color.RGBA) *image.RGBA {
+	dark := color.RGBA{c.R * 3 / 4, c.G * 3 / 4, c.B * 3 / 4, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			if (x/8+y/8)%2 == 0 {
+				img.SetRGBA(x, y, c)
+			} else {
+				img.SetRGBA(x, y, dark)
+			}
+		}
+	}
+	return img
+}
+
+// newFixture writes the fixture tree into a temporary directory and returns
+// a one-worker Config over it, writing into its "out" directory, and the
+// parsed item list.
+func newFixture(t *testing.T) (batch.Config, []itemlist.ItemDef) {
+	t.Helper()
+	base := t.TempDir()
+	if err := writeFixture(base); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	var cfg config.Config
+	cfg.Resolve(config.Flags{DataDir: base, OutputDir: filepath.Join(base, "out"), Workers: 1})
+	items, err := itemlist.Parse(cfg.ItemListXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trsData, err := trs.Load(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return batch.Config{
+		ItemDir:          cfg.ItemDir,
+		OutputDir:        cfg.OutputDir,
+		TexResolver:      texture.NewCache(fixtureTextures(cfg.ItemDir)),
+		TRSData:          trsData,
+		RenderWidth:      cfg.RenderWidth,
+		RenderHeight:     cfg.RenderHeight,
+		WebPQuality:      cfg.WebPQuality,
+		Supersample:      cfg.Supersample,
+		DownsamplePasses: cfg.DownsamplePasses,
+		MinFeaturePixels: cfg.MinFeaturePixels,
+		Workers:          cfg.Workers,
+		OutputFormat:     cfg.OutputFormat,
+	}, items
+}
+
+// fixtureTextures indexes the fixture's textures under itemDir.
+func fixtureTextures(itemDir string) *texture.Index {
+	return texture.BuildIndex(itemDir, filepath.Join(filepath.Dir(itemDir), "Skill"))
+}
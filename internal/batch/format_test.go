@@ -0,0 +1,93 @@
+package batch_test
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+
+	"golang.org/x/image/webp"
+)
+
+// decodeFile decodes the image at path with decode, as NRGBA.
+func decodeFile(t *testing.T, path string, decode func(f *os.File) (image.Image, error)) *image.NRGBA {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := decode(f)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	b := img.Bounds()
+	out := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.SetNRGBA(x, y, color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA))
+		}
+	}
+	return out
+}
+
+// TestOutputFormat renders the fixture sword with the default format and
+// with "png": each run writes only its own file, and the PNG holds the
+// render itself, losslessly: its coverage is the one Run reports, and its
+// pixels match the lossy WebP's up to compression.
+func TestOutputFormat(t *testing.T) {
+	run := func(format string) (batch.Config, batch.Result) {
+		cfg, items := newFixture(t)
+		cfg.OutputFormat = format
+		r := batch.Run(cfg, fixtureItem0(t, items))[0]
+		if !r.Success {
+			t.Fatalf("%q: %s", format, r.Error)
+		}
+		return cfg, r
+	}
+
+	wcfg, _ := run("")
+	webpPath := filepath.Join(wcfg.OutputDir, "0", "0.webp")
+	if !exists(webpPath) || exists(filepath.Join(wcfg.OutputDir, "0", "0.png")) {
+		t.Fatal("default format: want 0.webp only")
+	}
+	pcfg, r := run("png")
+	pngPath := filepath.Join(pcfg.OutputDir, "0", "0.png")
+	if !exists(pngPath) || exists(filepath.Join(pcfg.OutputDir, "0", "0.webp")) {
+		t.Fatal(`"png": want 0.png only`)
+	}
+
+	pi := decodeFile(t, pngPath, func(f *os.File) (image.Image, error) { return png.Decode(f) })
+	wi := decodeFile(t, webpPath, func(f *os.File) (image.Image, error) { return webp.Decode(f) })
+	if pi.Bounds() != wi.Bounds() {
+		t.Fatalf("png %v, webp %v", pi.Bounds(), wi.Bounds())
+	}
+	n, diff := 0, 0
+	for i := 0; i < len(pi.Pix); i += 4 {
+		if pi.Pix[i+3] > 0 {
+			n++
+		}
+		if pi.Pix[i+3] == 255 && wi.Pix[i+3] == 255 {
+			for c := 0; c < 3; c++ {
+				diff = max(diff, abs(int(pi.Pix[i+c])-int(wi.Pix[i+c])))
+			}
+		}
+	}
+	if got := float64(n) / float64(len(pi.Pix)/4); n == 0 || got != r.Coverage {
+		t.Errorf("png coverage %.4f, Run reported %.4f", got, r.Coverage)
+	}
+	if diff > 16 {
+		t.Errorf("png and webp differ by up to %d per channel", diff)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
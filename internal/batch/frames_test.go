@@ -0,0 +1,72 @@
+package batch_test
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+
+	"golang.org/x/image/webp"
+)
+
+// opaqueBounds returns the bounds of img's pixels with any alpha.
+func opaqueBounds(img *image.NRGBA) image.Rectangle {
+	var b image.Rectangle
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.NRGBAAt(x, y).A > 0 {
+				b = b.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return b
+}
+
+// TestTurntableFrames turns the fixture sword, tipped to point at the
+// camera, through 4 frames: 0_000 … 0_003 are written and nothing past
+// them. Frames a half turn apart show the same silhouette size, and the
+// end-on frames stay small next to the side-on ones, as they do at one
+// shared scale (fitting each frame alone would blow them up). The item's
+// TRS entry is left as it was.
+func TestTurntableFrames(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.TurntableFrames = 4
+	e := *cfg.TRSData[[2]int{0, 0}]
+	e.RotX -= 90
+	cfg.TRSData[[2]int{0, 0}] = &e
+	before := e
+	if r := batch.Run(cfg, fixtureItem0(t, items))[0]; !r.Success {
+		t.Fatal(r.Error)
+	}
+	if got := *cfg.TRSData[[2]int{0, 0}]; !reflect.DeepEqual(got, before) {
+		t.Errorf("entry changed to %+v", got)
+	}
+
+	var sizes []image.Point
+	for i := 0; i < 4; i++ {
+		f, err := os.Open(filepath.Join(cfg.OutputDir, "0", fmt.Sprintf("0_%03d.webp", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := webp.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		sizes = append(sizes, opaqueBounds(img.(*image.NRGBA)).Size())
+	}
+	if exists(filepath.Join(cfg.OutputDir, "0", "0_004.webp")) {
+		t.Error("frame 0_004 written")
+	}
+	if sizes[0] != sizes[2] || sizes[1] != sizes[3] {
+		t.Errorf("silhouettes %v: frames a half turn apart differ", sizes)
+	}
+	long := func(p image.Point) int { return max(p.X, p.Y) }
+	if long(sizes[1]) < 2*long(sizes[0]) {
+		t.Errorf("silhouettes %v: end-on frame not kept small beside the side-on one", sizes)
+	}
+}
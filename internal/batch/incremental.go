@@ -0,0 +1,104 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/texture"
+)
+
+// itemMeta is <section>/<index>.meta, written next to an item's image (the
+// first profile's) by an Incremental run: what the item was rendered from.
+// The next Incremental run leaves the item alone while all of it still
+// matches and its images exist.
+type itemMeta struct {
+	BMD      fileStamp            `json:"bmd"`
+	Textures map[string]fileStamp `json:"textures"` // texture reference → file it resolved to (Path "" = not found)
+	Entry    string               `json:"entry"`    // trs.Entry.Hash of the item's TRS entry ("" = none)
+	Settings string               `json:"settings"` // Config.Settings: the run's config.Config.OutputKey
+}
+
+// fileStamp identifies a version of a file by path, size, and modification
+// time. A missing file has only its path.
+type fileStamp struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size,omitempty"`
+	ModTime int64  `json:"mtime,omitempty"` // Unix nanoseconds
+}
+
+func stampFile(path string) fileStamp {
+	s := fileStamp{Path: path}
+	if info, err := os.Stat(path); err == nil {
+		s.Size, s.ModTime = info.Size(), info.ModTime().UnixNano()
+	}
+	return s
+}
+
+// metaPath returns item's .meta file, next to its first profile's image.
+func metaPath(cfg Config, item itemlist.ItemDef) string {
+	dir := outputProfiles(cfg)[0].OutputDir
+	return filepath.Join(dir, fmt.Sprintf("%d", item.Section), fmt.Sprintf("%d.meta", item.Index))
+}
+
+// buildMeta records item's current inputs. textures are the model's
+// texture references; their files are stamped only if cfg.TexResolver can
+// name them (texture.Cache can), otherwise texture edits go unnoticed.
+func buildMeta(cfg Config, item itemlist.ItemDef, textures []string) itemMeta {
+	m := itemMeta{
+		BMD:      stampFile(filepath.Join(cfg.ItemDir, item.SubDir, item.ModelFile)),
+		Entry:    cfg.TRSData[[2]int{item.Section, item.Index}].Hash(),
+		Settings: cfg.Settings,
+	}
+	if pr, ok := cfg.TexResolver.(texture.PathResolver); ok {
+		m.Textures = make(map[string]fileStamp, len(textures))
+		for _, name := range textures {
+			s := fileStamp{}
+			if path, ok := pr.ResolvePath(name); ok {
+				s = stampFile(path)
+			}
+			m.Textures[name] = s
+		}
+	}
+	return m
+}
+
+// writeMeta writes item's .meta file for the textures its model uses.
+func writeMeta(cfg Config, item itemlist.ItemDef, textures []string) error {
+	data, err := json.MarshalIndent(buildMeta(cfg, item, textures), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaPath(cfg, item), data, 0644)
+}
+
+// metaCurrent reports whether item's images exist and its .meta file still
+// matches its BMD, the textures recorded there (resolved again, so a newly
+// added or removed texture file counts too), its TRS entry, and the run's
+// settings. A missing or unreadable .meta file means render.
+func metaCurrent(cfg Config, item itemlist.ItemDef) bool {
+	data, err := os.ReadFile(metaPath(cfg, item))
+	if err != nil {
+		return false
+	}
+	var old itemMeta
+	if json.Unmarshal(data, &old) != nil || !upToDate(cfg, item, false) {
+		return false
+	}
+	names := make([]string, 0, len(old.Textures))
+	for name := range old.Textures {
+		names = append(names, name)
+	}
+	now := buildMeta(cfg, item, names)
+	if now.BMD != old.BMD || now.Entry != old.Entry || now.Settings != old.Settings || len(now.Textures) != len(old.Textures) {
+		return false
+	}
+	for name, s := range old.Textures {
+		if now.Textures[name] != s {
+			return false
+		}
+	}
+	return true
+}
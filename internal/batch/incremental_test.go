@@ -0,0 +1,82 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mu-bmd-renderer/internal/batch"
+)
+
+// TestIncremental renders the fixture sword with Incremental, then runs
+// again after changing one of the inputs its .meta file records: nothing
+// changed leaves the image alone, any change renders it again.
+func TestIncremental(t *testing.T) {
+	touch := func(path string) error {
+		later := time.Now().Add(time.Hour)
+		return os.Chtimes(path, later, later)
+	}
+	for _, c := range []struct {
+		name   string
+		change func(cfg *batch.Config) error
+		render bool
+	}{
+		{"unchanged", func(*batch.Config) error { return nil }, false},
+		{"bmd mtime", func(cfg *batch.Config) error {
+			return touch(filepath.Join(cfg.ItemDir, "Sword01.bmd"))
+		}, true},
+		{"bmd size", func(cfg *batch.Config) error {
+			// Same mtime, so only the size tells
+			path := filepath.Join(cfg.ItemDir, "Sword01.bmd")
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, append(data, make([]byte, 16)...), 0644); err != nil {
+				return err
+			}
+			return os.Chtimes(path, info.ModTime(), info.ModTime())
+		}, true},
+		{"texture", func(cfg *batch.Config) error {
+			return touch(filepath.Join(cfg.ItemDir, "texture", "sword01.ozj"))
+		}, true},
+		{"trs entry", func(cfg *batch.Config) error {
+			e := *cfg.TRSData[[2]int{0, 0}]
+			e.RotY += 15
+			cfg.TRSData[[2]int{0, 0}] = &e
+			return nil
+		}, true},
+		{"settings", func(cfg *batch.Config) error {
+			cfg.Settings = "quality=80"
+			return nil
+		}, true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, items := newFixture(t)
+			cfg.Incremental = true
+			cfg.Settings = "quality=90"
+			items = fixtureItem0(t, items)
+			if r := batch.Run(cfg, items)[0]; !r.Success || r.Skipped {
+				t.Fatalf("first run: success %v, skipped %v: %s", r.Success, r.Skipped, r.Error)
+			}
+			if !exists(filepath.Join(cfg.OutputDir, "0", "0.meta")) {
+				t.Fatal("no .meta file written")
+			}
+			if err := c.change(&cfg); err != nil {
+				t.Fatal(err)
+			}
+			r := batch.Run(cfg, items)[0]
+			if r.Skipped == c.render {
+				t.Errorf("skipped %v, want %v", r.Skipped, !c.render)
+			}
+			if c.render && !r.Success {
+				t.Errorf("render again: %s", r.Error)
+			}
+		})
+	}
+}
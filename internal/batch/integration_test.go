@@ -0,0 +1,228 @@
+//go:build integration
+
+// The integration test writes a small, self-contained MU Data layout into a
+// temporary directory (BMD models in plain, XOR- and LEA-encrypted form,
+// OZJ/OZT textures, ItemList.xml, an encrypted itemtrsdata.bmd, and
+// custom_trs.json; see fixtureItems), then goes config → itemlist → trs →
+// texture → batch.Run exactly as cmd/render does and checks every item's
+// outcome: the expected success or failure category (and, for a missing
+// model, the suggested one), the image and sidecar on disk, the output size,
+// a non-trivial opaque coverage, the TRS entry's source, and that the pixels
+// come from the item's own texture. The per-package logic has its own
+// tests; this guards the wiring between packages (paths, subdirectories,
+// entry lookup, texture resolution), which only breaks end to end.
+//
+// Needs no game data:
+//
+//	go test -tags integration ./internal/batch
+package batch_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/webp"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/config"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/postprocess"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/texture"
+	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
+)
+
+// minCoverage is the least opaque-pixel fraction a fixture render may have;
+// the fixture models fill far more than this once framed.
+const minCoverage = 0.05
+
+func TestIntegration(t *testing.T) {
+	// Workers writing their own items, and a separate encoder pool
+	for _, encodeWorkers := range []int{0, 1} {
+		t.Run(fmt.Sprintf("encode_workers=%d", encodeWorkers), func(t *testing.T) {
+			base := t.TempDir()
+			if err := writeFixture(base); err != nil {
+				t.Fatalf("writing fixture: %v", err)
+			}
+			runFixture(t, base, 2, encodeWorkers)
+		})
+	}
+}
+
+// runFixture renders the fixture tree at base and reports what doesn't
+// match fixtureItems.
+func runFixture(t *testing.T, base string, workers, encodeWorkers int) {
+	var cfg config.Config
+	cfg.Sidecar = true
+	cfg.EncodeWorkers = encodeWorkers
+	cfg.Resolve(config.Flags{DataDir: base, Workers: workers})
+	convention, err := viewmatrix.Convention(cfg.Convention)
+	if err != nil {
+		t.Fatalf("coordinate_convention: %v", err)
+	}
+	renderOpts := raster.Options{
+		MinTriangleArea:   cfg.MinTriangleArea,
+		NoOpaquePromotion: cfg.NoOpaquePromotion,
+		SmoothShading:     cfg.SmoothShading,
+		Convention:        convention,
+	}
+	if cfg.SSAO {
+		renderOpts.SSAORadius, renderOpts.SSAOIntensity = cfg.SSAORadius, cfg.SSAOIntensity
+	}
+	postOpts := postprocess.Options{
+		ContentAlpha:      cfg.ContentAlpha,
+		MaxImageDimension: cfg.MaxImageDimension,
+	}
+
+	items, err := itemlist.Parse(cfg.ItemListXML)
+	if err != nil {
+		t.Fatalf("ItemList.xml: %v", err)
+	}
+	if len(items) != len(fixtureItems) {
+		t.Errorf("ItemList.xml: parsed %d items, want %d", len(items), len(fixtureItems))
+	}
+	trsData, err := trs.Load(cfg.TRSBMD, cfg.CustomTRS, cfg.ItemListXML)
+	if err != nil {
+		t.Fatalf("TRS: %v", err)
+	}
+	trsData.AddRotation(cfg.RotationOffset)
+	texIndex := texture.BuildIndex(cfg.ItemDir, filepath.Join(filepath.Dir(cfg.ItemDir), "Skill"))
+	if texIndex.Len() != 3 {
+		t.Errorf("texture index: %d textures, want 3", texIndex.Len())
+	}
+	texCache := texture.NewCache(texIndex)
+
+	results := batch.Run(batch.Config{
+		ItemDir:          cfg.ItemDir,
+		OutputDir:        cfg.OutputDir,
+		TexResolver:      texCache,
+		WarmTextures:     true,
+		TRSData:          trsData,
+		RenderWidth:      cfg.RenderWidth,
+		RenderHeight:     cfg.RenderHeight,
+		WebPQuality:      cfg.WebPQuality,
+		Supersample:      cfg.Supersample,
+		DownsamplePasses: cfg.DownsamplePasses,
+		Render:           renderOpts,
+		Postprocess:      postOpts,
+		Workers:          cfg.Workers,
+		EncodeWorkers:    cfg.EncodeWorkers,
+		MinFeaturePixels: cfg.MinFeaturePixels,
+		OutputFormat:     cfg.OutputFormat,
+		Sidecar:          cfg.Sidecar,
+	}, items)
+
+	byKey := make(map[[2]int]batch.Result)
+	for _, r := range results {
+		byKey[[2]int{r.Section, r.Index}] = r
+	}
+	for _, want := range fixtureItems {
+		key := fmt.Sprintf("%d_%d", want.section, want.index)
+		r, ok := byKey[[2]int{want.section, want.index}]
+		if !ok {
+			t.Errorf("%s: no result", key)
+			continue
+		}
+		stem := filepath.Join(cfg.OutputDir, fmt.Sprint(want.section), fmt.Sprint(want.index))
+
+		if !want.render {
+			if r.Success || r.Failure != batch.FailMissingBMD {
+				t.Errorf("%s: got %v (%s), want a missing BMD failure", key, r.Failure, r.Error)
+			}
+			if _, err := os.Stat(stem + ".webp"); err == nil {
+				t.Errorf("%s: failed item left %s.webp behind", key, stem)
+			}
+			if r.Suggestion != want.suggest {
+				t.Errorf("%s: suggested %q for %s, want %q", key, r.Suggestion, want.modelFile, want.suggest)
+			}
+			continue
+		}
+
+		if !r.Success {
+			t.Errorf("%s: %v: %s", key, r.Failure, r.Error)
+			continue
+		}
+		img, err := decodeWebP(stem + ".webp")
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+			continue
+		}
+		if b := img.Bounds(); b.Dx() != want.width || b.Dy() != want.height {
+			t.Errorf("%s: %dx%d image, want %dx%d", key, b.Dx(), b.Dy(), want.width, want.height)
+		}
+		coverage, rgb := opaqueStats(img)
+		if coverage < minCoverage {
+			t.Errorf("%s: coverage %.3f, want at least %.2f", key, coverage, minCoverage)
+		} else if dominant(rgb) != dominant([3]float64{float64(want.tint.R), float64(want.tint.G), float64(want.tint.B)}) {
+			t.Errorf("%s: mean color %.0f, not from its texture %v", key, rgb, want.tint)
+		}
+
+		var sc batch.Sidecar
+		if raw, err := os.ReadFile(stem + ".json"); err != nil {
+			t.Errorf("%s: sidecar: %v", key, err)
+		} else if err := json.Unmarshal(raw, &sc); err != nil {
+			t.Errorf("%s: sidecar: %v", key, err)
+		} else {
+			if sc.TRS == nil || sc.TRS.Source != want.trsSource {
+				t.Errorf("%s: TRS entry %+v, want source %q", key, sc.TRS, want.trsSource)
+			}
+			if len(sc.Rendered) != want.meshes {
+				t.Errorf("%s: %d meshes rendered, want %d (filtered: %+v)", key, len(sc.Rendered), want.meshes, sc.Filtered)
+			}
+			for _, m := range sc.Rendered {
+				if m.Pass != "opaque" {
+					t.Errorf("%s: %s drawn in the %s pass, want opaque", key, m.TexPath, m.Pass)
+				}
+			}
+		}
+	}
+}
+
+// opaqueStats returns the fraction of img's pixels at full alpha and their
+// mean RGB.
+func opaqueStats(img image.Image) (float64, [3]float64) {
+	b := img.Bounds()
+	var sum [3]float64
+	n := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a != 0xffff {
+				continue
+			}
+			sum[0] += float64(r >> 8)
+			sum[1] += float64(g >> 8)
+			sum[2] += float64(bl >> 8)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, sum
+	}
+	return float64(n) / float64(b.Dx()*b.Dy()), [3]float64{sum[0] / float64(n), sum[1] / float64(n), sum[2] / float64(n)}
+}
+
+// dominant returns the index of rgb's largest channel.
+func dominant(rgb [3]float64) int {
+	i := 0
+	for c := 1; c < 3; c++ {
+		if rgb[c] > rgb[i] {
+			i = c
+		}
+	}
+	return i
+}
+
+func decodeWebP(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return webp.Decode(f)
+}
@@ -0,0 +1,37 @@
+package batch_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/logging"
+)
+
+// TestRunLog runs the fixture verbose through a capturing Config.Log: the
+// per-item lines are debug output, so info level prints none of them and
+// debug level one per rendered item, and nothing reaches the default logger.
+func TestRunLog(t *testing.T) {
+	var leaked bytes.Buffer
+	prev := logging.Default()
+	logging.SetDefault(logging.New(&leaked, &leaked, logging.LevelDebug))
+	defer logging.SetDefault(prev)
+
+	for _, c := range []struct {
+		level logging.Level
+		items int
+	}{{logging.LevelInfo, 0}, {logging.LevelDebug, 4}} {
+		cfg, items := newFixture(t)
+		cfg.Verbose = true
+		var out bytes.Buffer
+		cfg.Log = logging.New(&out, &out, c.level)
+		batch.Run(cfg, items)
+		if n := strings.Count(out.String(), "meshes\n"); n != c.items {
+			t.Errorf("%v: %d item lines, want %d:\n%s", c.level, n, c.items, out.String())
+		}
+	}
+	if leaked.Len() != 0 {
+		t.Errorf("default logger got output:\n%s", leaked.String())
+	}
+}
@@ -4,22 +4,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"mu-bmd-renderer/internal/itemlist"
 )
 
+// Manifest statuses. An item gets the first that applies, in this order.
+const (
+	StatusFailed      = "failed"       // render or encode failed; no image
+	StatusNearEmpty   = "near_empty"   // rendered, but under nearEmptyCoverage of the canvas
+	StatusFallbackTRS = "fallback_trs" // no TRS entry, or one that routes to VIEW_FALLBACK
+	StatusOK          = "ok"
+	StatusSkipped     = "skipped" // left as an earlier run wrote it (SkipExisting); see MergeManifest
+)
+
+// nearEmptyCoverage is the opaque-pixel fraction below which a rendered item
+// is flagged near-empty (typically everything but a stray effect was filtered).
+const nearEmptyCoverage = 0.01
+
 // ManifestEntry represents one item in the output manifest.
 type ManifestEntry struct {
-	Section     int    `json:"section"`
-	SectionName string `json:"section_name"`
-	Index       int    `json:"index"`
-	Name        string `json:"name"`
-	ModelFile   string `json:"model_file"`
-	Image       string `json:"image"`
+	Section     int         `json:"section"`
+	SectionName string      `json:"section_name"`
+	Index       int         `json:"index"`
+	Name        string      `json:"name"`
+	ModelFile   string      `json:"model_file"`
+	Image       string      `json:"image"`
+	Alpha       string      `json:"alpha,omitempty"` // alpha matte PNG when alpha_matte is set
+	Status      string      `json:"status"`
+	Error       string      `json:"error,omitempty"`
+	Coverage    float64     `json:"coverage"` // opaque-pixel fraction of the final image
+	Render      *OutputInfo `json:"render"`   // the written image; null when failed or skipped
 }
 
-// WriteManifest writes manifest.json to the output directory.
-func WriteManifest(path string, items []itemlist.ItemDef) error {
+// BuildManifest builds the manifest entries for items and their results
+// (results[i] belongs to items[i], as returned by Run). Image paths follow
+// cfg.OutputFormat and cfg.AlphaMatte ("instead" points image at the matte's
+// RGB file). With cfg.Profiles, paths point at the first profile's files.
+func BuildManifest(cfg Config, items []itemlist.ItemDef, results []Result) []ManifestEntry {
+	ext := OutputExt(cfg.OutputFormat)
+	dir := ""
+	if len(cfg.Profiles) > 0 {
+		if rel, err := filepath.Rel(cfg.OutputDir, cfg.Profiles[0].OutputDir); err == nil {
+			dir = filepath.ToSlash(rel) + "/"
+		}
+	}
 	entries := make([]ManifestEntry, len(items))
 	for i, it := range items {
 		entries[i] = ManifestEntry{
@@ -28,13 +57,116 @@ func WriteManifest(path string, items []itemlist.ItemDef) error {
 			Index:       it.Index,
 			Name:        it.Name,
 			ModelFile:   it.ModelFile,
-			Image:       fmt.Sprintf("%d/%d.webp", it.Section, it.Index),
+			Image:       dir + fmt.Sprintf("%d/%d.%s", it.Section, it.Index, ext),
+		}
+		if cfg.AlphaMatte != "" {
+			entries[i].Alpha = dir + fmt.Sprintf("%d/%d_alpha.png", it.Section, it.Index)
+		}
+		if cfg.AlphaMatte == "instead" {
+			entries[i].Image = dir + fmt.Sprintf("%d/%d_rgb.%s", it.Section, it.Index, MatteRGBExt(cfg.MattePNG))
+		}
+
+		r := results[i]
+		entries[i].Coverage = r.Coverage
+		entries[i].Render = r.Output
+		switch {
+		case r.Skipped:
+			entries[i].Status = StatusSkipped
+		case !r.Success:
+			entries[i].Status = StatusFailed
+			entries[i].Error = r.Error
+		case r.Coverage < nearEmptyCoverage:
+			entries[i].Status = StatusNearEmpty
+		case r.FallbackTRS:
+			entries[i].Status = StatusFallbackTRS
+		default:
+			entries[i].Status = StatusOK
 		}
 	}
+	return entries
+}
 
+// WriteManifest writes manifest entries to path as indented JSON.
+func WriteManifest(path string, entries []ManifestEntry) error {
 	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return err
 	}
 	return os.WriteFile(path, data, 0644)
 }
+
+// ReadManifest loads a manifest.json written by a previous run.
+func ReadManifest(path string) ([]ManifestEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []ManifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("manifest %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// FlaggedItems returns the items whose manifest entry has one of statuses, in
+// items order. Items come from the current item list rather than the manifest,
+// so a model file fixed in ItemList.xml since the run is picked up.
+func FlaggedItems(items []itemlist.ItemDef, entries []ManifestEntry, statuses []string) []itemlist.ItemDef {
+	want := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		want[s] = true
+	}
+	flagged := make(map[[2]int]bool)
+	for _, e := range entries {
+		if want[e.Status] {
+			flagged[[2]int{e.Section, e.Index}] = true
+		}
+	}
+
+	var out []itemlist.ItemDef
+	for _, it := range items {
+		if flagged[[2]int{it.Section, it.Index}] {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// MergeManifest replaces entries in old with updated ones for the same item,
+// keeping old's order; updated entries for items not in old are appended.
+// A skipped item keeps its old entry, which still describes its image.
+func MergeManifest(old, updated []ManifestEntry) []ManifestEntry {
+	pos := make(map[[2]int]int, len(old))
+	merged := make([]ManifestEntry, len(old))
+	for i, e := range old {
+		merged[i] = e
+		pos[[2]int{e.Section, e.Index}] = i
+	}
+	for _, e := range updated {
+		if i, ok := pos[[2]int{e.Section, e.Index}]; ok {
+			if e.Status != StatusSkipped {
+				merged[i] = e
+			}
+		} else {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// OutputExt returns the image file extension for an output format
+// ("png", "exr"; anything else is WebP).
+func OutputExt(format string) string {
+	if format == "png" || format == "exr" {
+		return format
+	}
+	return "webp"
+}
+
+// MatteRGBExt returns the extension of the alpha matte's RGB image.
+func MatteRGBExt(png bool) string {
+	if png {
+		return "png"
+	}
+	return "jpg"
+}
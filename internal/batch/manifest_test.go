@@ -0,0 +1,145 @@
+package batch_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// manifestItems returns items 0_0 to 0_n-1.
+func manifestItems(n int) []itemlist.ItemDef {
+	items := make([]itemlist.ItemDef, n)
+	for i := range items {
+		items[i] = itemlist.ItemDef{Section: 0, Index: i, ModelFile: "Sword.bmd"}
+	}
+	return items
+}
+
+func TestBuildManifestStatus(t *testing.T) {
+	results := []batch.Result{
+		{Success: true, Coverage: 0.3},
+		{Success: false, Error: "bmd: not found"},
+		{Success: true, Coverage: 0.005, FallbackTRS: true}, // near-empty outranks fallback
+		{Success: true, Coverage: 0.3, FallbackTRS: true},
+		{Skipped: true},
+	}
+	want := []string{batch.StatusOK, batch.StatusFailed, batch.StatusNearEmpty, batch.StatusFallbackTRS, batch.StatusSkipped}
+	entries := batch.BuildManifest(batch.Config{}, manifestItems(len(results)), results)
+	for i, e := range entries {
+		if e.Status != want[i] {
+			t.Errorf("0_%d: status %q, want %q", i, e.Status, want[i])
+		}
+	}
+	if entries[1].Error != "bmd: not found" || entries[0].Image != "0/0.webp" {
+		t.Errorf("entries %+v", entries[:2])
+	}
+}
+
+func TestRerenderFlaggedItems(t *testing.T) {
+	items := manifestItems(4)
+	results := []batch.Result{
+		{Success: true, Coverage: 0.3},
+		{Success: false, Error: "parse"},
+		{Success: true, Coverage: 0},
+		{Success: true, Coverage: 0.3},
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := batch.WriteManifest(path, batch.BuildManifest(batch.Config{}, items, results)); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := batch.ReadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prev, batch.BuildManifest(batch.Config{}, items, results)) {
+		t.Fatalf("manifest read back as %+v", prev)
+	}
+
+	// The current item list decides model files and order; the manifest only
+	// which items are flagged
+	current := manifestItems(4)
+	current[2].ModelFile = "Fixed.bmd"
+	current[1], current[2] = current[2], current[1]
+	flagged := batch.FlaggedItems(current, prev, []string{batch.StatusFailed, batch.StatusNearEmpty})
+	if len(flagged) != 2 || flagged[0].Index != 2 || flagged[0].ModelFile != "Fixed.bmd" || flagged[1].Index != 1 {
+		t.Fatalf("flagged %+v, want 0_2 (Fixed.bmd) then 0_1", flagged)
+	}
+
+	// Re-rendering them updates their entries in place; a skipped item
+	// keeps its old entry and an item new to the list is appended
+	rerun := batch.BuildManifest(batch.Config{}, []itemlist.ItemDef{flagged[0], flagged[1], {Section: 1, Index: 0}},
+		[]batch.Result{{Success: true, Coverage: 0.2}, {Skipped: true}, {Success: true, Coverage: 0.4}})
+	merged := batch.MergeManifest(prev, rerun)
+	var got []string
+	for _, e := range merged {
+		got = append(got, e.Status)
+	}
+	if want := []string{"ok", "failed", "ok", "ok", "ok"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged statuses %v, want %v", got, want)
+	}
+	if merged[2].ModelFile != "Fixed.bmd" || merged[4].Section != 1 {
+		t.Errorf("merged %+v", merged)
+	}
+}
+
+// TestManifestRender runs the whole fixture as PNG and checks each entry's
+// render fields against the file its image path names: the decoded
+// dimensions (0_1 has its own size), the byte size, and the SHA-256 of the
+// bytes. Failed items name no render.
+func TestManifestRender(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.OutputFormat = "png"
+	path := filepath.Join(cfg.OutputDir, "manifest.json")
+	if err := batch.WriteManifest(path, batch.BuildManifest(cfg, items, batch.Run(cfg, items))); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := batch.ReadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := map[string][2]int{"0_0": {256, 256}, "0_1": {128, 64}, "14_0": {256, 256}, "14_4": {256, 256}}
+	for i, e := range entries {
+		key := itemKeys(items)[i]
+		want, ok := sizes[key]
+		if !ok {
+			if e.Status != batch.StatusFailed || e.Render != nil {
+				t.Errorf("%s: status %q, render %+v; want failed, none", key, e.Status, e.Render)
+			}
+			continue
+		}
+		if e.Render == nil {
+			t.Errorf("%s: no render", key)
+			continue
+		}
+		if e.Image != fmt.Sprintf("%d/%d.png", e.Section, e.Index) {
+			t.Errorf("%s: image %q", key, e.Image)
+		}
+		data, err := os.ReadFile(filepath.Join(cfg.OutputDir, filepath.FromSlash(e.Image)))
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		conf, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		sum := sha256.Sum256(data)
+		if e.Render.Width != want[0] || e.Render.Height != want[1] || conf.Width != want[0] || conf.Height != want[1] {
+			t.Errorf("%s: manifest %dx%d, file %dx%d, want %dx%d", key, e.Render.Width, e.Render.Height, conf.Width, conf.Height, want[0], want[1])
+		}
+		if e.Render.Bytes != int64(len(data)) {
+			t.Errorf("%s: bytes %d, file has %d", key, e.Render.Bytes, len(data))
+		}
+		if e.Render.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("%s: sha256 %s, file hashes to %x", key, e.Render.SHA256, sum)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// fixtureItem0 returns the fixture's plain sword, 0_0.
+func fixtureItem0(t *testing.T, items []itemlist.ItemDef) []itemlist.ItemDef {
+	t.Helper()
+	for _, it := range items {
+		if it.Section == 0 && it.Index == 0 {
+			return []itemlist.ItemDef{it}
+		}
+	}
+	t.Fatal("fixture has no 0_0")
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestAlphaMatteFiles(t *testing.T) {
+	for _, c := range []struct {
+		mode string
+		png  bool // matte_png
+		webp bool
+	}{
+		{"", false, true},
+		{"alongside", false, true},
+		{"instead", false, false},
+		{"instead", true, false},
+	} {
+		cfg, items := newFixture(t)
+		cfg.AlphaMatte, cfg.MatteQuality, cfg.MattePNG = c.mode, 90, c.png
+		for _, r := range batch.Run(cfg, fixtureItem0(t, items)) {
+			if !r.Success {
+				t.Fatalf("%q: %s", c.mode, r.Error)
+			}
+		}
+		want := "0/0_rgb.jpg"
+		if c.png {
+			want = "0/0_rgb.png"
+		}
+		entries := batch.BuildManifest(cfg, fixtureItem0(t, items), []batch.Result{{Success: true}})
+		if c.mode == "instead" && entries[0].Image != want {
+			t.Errorf("matte_png %v: manifest image %q, want %q", c.png, entries[0].Image, want)
+		}
+		dir := filepath.Join(cfg.OutputDir, "0")
+		matte := c.mode != ""
+		for _, f := range []struct {
+			name string
+			want bool
+		}{
+			{"0.webp", c.webp},
+			{"0_rgb.jpg", matte && !c.png},
+			{"0_rgb.png", matte && c.png},
+			{"0_alpha.png", matte},
+		} {
+			if got := exists(filepath.Join(dir, f.name)); got != f.want {
+				t.Errorf("alpha_matte %q, matte_png %v: %s written = %v, want %v", c.mode, c.png, f.name, got, f.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package batch_test
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/mmap"
+	"mu-bmd-renderer/internal/texture"
+)
+
+// TestMappedReads renders every fixture item with plain and memory-mapped
+// file reads: the pixels must match, so nothing the parsers or decoders
+// return still points into a mapping after it is released.
+func TestMappedReads(t *testing.T) {
+	cfg, items := newFixture(t)
+	t.Cleanup(func() { mmap.SetEnabled(false) })
+
+	render := func(mapped bool) map[[2]int][]byte {
+		mmap.SetEnabled(mapped)
+		cfg.TexResolver = texture.NewCache(fixtureTextures(cfg.ItemDir))
+		out := map[[2]int][]byte{}
+		for _, it := range items {
+			if img, err := batch.RenderItem(cfg, it); err == nil {
+				out[[2]int{it.Section, it.Index}] = img.Pix
+			}
+		}
+		return out
+	}
+	plain, mapped := render(false), render(true)
+	if len(plain) == 0 || len(mapped) != len(plain) {
+		t.Fatalf("rendered %d items with plain reads, %d mapped", len(plain), len(mapped))
+	}
+	for key, pix := range plain {
+		if !bytes.Equal(mapped[key], pix) {
+			t.Errorf("%d_%d: mapped render differs", key[0], key[1])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package batch
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// OrderItems returns items in the order to render them. Items matching one
+// of the priority keys ("section_index" or "section_start-end") come first,
+// in key order; the rest follow in order:
+//
+//	itemlist  as listed (the default)
+//	section   by section number, as listed within a section
+//	cost      cheapest model first, by vertex count (models are parsed on
+//	          workers goroutines; one that doesn't parse counts as 0, so it
+//	          fails early)
+//
+// Sorting is stable, and results come back from Run in the same order, so
+// BuildManifest and the failure report follow the render order too.
+func OrderItems(items []itemlist.ItemDef, order string, priority []string, itemDir string, workers int) ([]itemlist.ItemDef, error) {
+	rank := make(map[[2]int]int)
+	for i, k := range priority {
+		pairs := trs.ParseItemKeys(k)
+		if pairs == nil {
+			return nil, fmt.Errorf("priority_items: bad key %q (want section_index or section_start-end)", k)
+		}
+		for _, p := range pairs {
+			if _, ok := rank[p]; !ok {
+				rank[p] = i
+			}
+		}
+	}
+
+	var less func(a, b itemlist.ItemDef) bool
+	switch order {
+	case "", "itemlist":
+	case "section":
+		less = func(a, b itemlist.ItemDef) bool { return a.Section < b.Section }
+	case "cost":
+		cost := modelVertexCounts(itemDir, items, workers)
+		path := func(it itemlist.ItemDef) string { return filepath.Join(itemDir, it.SubDir, it.ModelFile) }
+		less = func(a, b itemlist.ItemDef) bool { return cost[path(a)] < cost[path(b)] }
+	default:
+		return nil, fmt.Errorf("unknown render_order %q (use itemlist, section, or cost)", order)
+	}
+
+	out := append([]itemlist.ItemDef(nil), items...)
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, pi := rank[[2]int{out[i].Section, out[i].Index}]
+		rj, pj := rank[[2]int{out[j].Section, out[j].Index}]
+		if pi || pj {
+			return pi && (!pj || ri < rj)
+		}
+		return less != nil && less(out[i], out[j])
+	})
+	return out, nil
+}
+
+// modelVertexCounts returns the total vertex count of each of items' model
+// files, keyed by path.
+func modelVertexCounts(itemDir string, items []itemlist.ItemDef, workers int) map[string]int {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	forEachModel(itemDir, items, workers, func(path string, meshes []bmd.Mesh) {
+		n := 0
+		for _, m := range meshes {
+			n += len(m.Verts)
+		}
+		mu.Lock()
+		counts[path] = n
+		mu.Unlock()
+	})
+	return counts
+}
@@ -0,0 +1,79 @@
+package batch_test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+func itemKeys(items []itemlist.ItemDef) []string {
+	keys := make([]string, len(items))
+	for i, it := range items {
+		keys[i] = fmt.Sprintf("%d_%d", it.Section, it.Index)
+	}
+	return keys
+}
+
+func TestOrderItems(t *testing.T) {
+	cfg, items := newFixture(t)
+	if got := itemKeys(items); !reflect.DeepEqual(got, []string{"0_0", "0_1", "14_0", "14_4", "14_1", "14_2", "14_3"}) {
+		t.Fatalf("fixture lists %v", got)
+	}
+	reversed := slices.Clone(items)
+	slices.Reverse(reversed)
+
+	for _, c := range []struct {
+		items    []itemlist.ItemDef
+		order    string
+		priority []string
+		want     []string
+	}{
+		{items, "", []string{"14_4", "0_1"}, []string{"14_4", "0_1", "0_0", "14_0", "14_1", "14_2", "14_3"}},
+		{items, "itemlist", []string{"14_1-2", "0_1", "14_1"}, []string{"14_1", "14_2", "0_1", "0_0", "14_0", "14_4", "14_3"}},
+		{reversed, "section", nil, []string{"0_1", "0_0", "14_3", "14_2", "14_1", "14_4", "14_0"}},
+		{reversed, "section", []string{"14_0"}, []string{"14_0", "0_1", "0_0", "14_3", "14_2", "14_1", "14_4"}},
+		// Missing models count 0 vertices; both jewels are one box, the
+		// swords three
+		{items, "cost", nil, []string{"14_1", "14_2", "14_3", "14_0", "14_4", "0_0", "0_1"}},
+	} {
+		got, err := batch.OrderItems(c.items, c.order, c.priority, cfg.ItemDir, 2)
+		if err != nil {
+			t.Fatalf("%q %v: %v", c.order, c.priority, err)
+		}
+		if keys := itemKeys(got); !reflect.DeepEqual(keys, c.want) {
+			t.Errorf("%q %v: %v, want %v", c.order, c.priority, keys, c.want)
+		}
+	}
+	if itemKeys(items)[0] != "0_0" {
+		t.Error("OrderItems reordered its input")
+	}
+
+	if _, err := batch.OrderItems(items, "size", nil, cfg.ItemDir, 1); err == nil {
+		t.Error("unknown render_order: no error")
+	}
+	if _, err := batch.OrderItems(items, "", []string{"sword"}, cfg.ItemDir, 1); err == nil {
+		t.Error("bad priority key: no error")
+	}
+}
+
+// TestRunKeepsOrder checks that Run reports results in the order it was
+// given, so a priority list reorders the run as a whole.
+func TestRunKeepsOrder(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.Workers = 3
+	ordered, err := batch.OrderItems(items, "", []string{"14_4", "0_1"}, cfg.ItemDir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range batch.Run(cfg, ordered) {
+		got = append(got, fmt.Sprintf("%d_%d", r.Section, r.Index))
+	}
+	if want := itemKeys(ordered); !reflect.DeepEqual(got, want) {
+		t.Errorf("results in order %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package batch_test
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+
+	"golang.org/x/image/webp"
+)
+
+// edgeBrightness decodes the WebP at path and returns the mean blue channel
+// of its opaque pixels and of its partially transparent edge pixels.
+func edgeBrightness(t *testing.T, path string) (opaque, edge float64) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := webp.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, ok := img.(*image.NRGBA)
+	if !ok {
+		t.Fatalf("decoded %T, want *image.NRGBA", img)
+	}
+	var so, se float64
+	var no, ne int
+	for i := 0; i < len(n.Pix); i += 4 {
+		switch a := n.Pix[i+3]; {
+		case a == 255:
+			so += float64(n.Pix[i+2])
+			no++
+		case a >= 32 && a <= 160:
+			se += float64(n.Pix[i+2])
+			ne++
+		}
+	}
+	if no == 0 || ne == 0 {
+		t.Fatalf("%d opaque and %d edge pixels, want both", no, ne)
+	}
+	return so / float64(no), se / float64(ne)
+}
+
+// TestStraightAlphaOutput renders the single-texture jewel and decodes the
+// WebP: its anti-aliased edge keeps the jewel's blue (straight alpha, no dark
+// halo), while premultiplied_alpha darkens it by design.
+func TestStraightAlphaOutput(t *testing.T) {
+	var jewel []itemlist.ItemDef
+	for _, premultiply := range []bool{false, true} {
+		cfg, items := newFixture(t)
+		for _, it := range items {
+			if it.Section == 14 && it.Index == 0 {
+				jewel = []itemlist.ItemDef{it}
+			}
+		}
+		cfg.Premultiply = premultiply
+		for _, r := range batch.Run(cfg, jewel) {
+			if !r.Success {
+				t.Fatal(r.Error)
+			}
+		}
+		opaque, edge := edgeBrightness(t, filepath.Join(cfg.OutputDir, "14", "0.webp"))
+		if !premultiply && edge < 0.75*opaque {
+			t.Errorf("straight alpha: edge blue %.0f against opaque %.0f, a dark halo", edge, opaque)
+		}
+		if premultiply && edge > 0.6*opaque {
+			t.Errorf("premultiplied_alpha: edge blue %.0f against opaque %.0f, want it scaled by alpha", edge, opaque)
+		}
+	}
+}
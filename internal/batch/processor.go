@@ -1,19 +1,30 @@
 package batch
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"image"
+	"image/png"
+	"image/color"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"mu-bmd-renderer/internal/anim"
 	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/export"
 	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/logging"
 	"mu-bmd-renderer/internal/postprocess"
 	"mu-bmd-renderer/internal/raster"
 	"mu-bmd-renderer/internal/texture"
 	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
 
 	"github.com/HugoSmits86/nativewebp"
 )
@@ -23,23 +34,99 @@ type Config struct {
 	ItemDir     string
 	OutputDir   string
 	TexResolver texture.Resolver
+	WarmTextures bool // decode each model's textures concurrently right after parsing it (needs a texture.PathResolver)
 	TRSData     trs.Data
 	RenderWidth  int
 	RenderHeight int
 	WebPQuality int
 	Supersample int
+	DownsamplePasses int // see postprocess.DownsamplePasses (0/1 = single stage)
+	Render      raster.Options // settings for every model's render (see raster.Options)
+	Postprocess postprocess.Options // settings for every image's framing and cleanup (see postprocess.Options)
 	Workers     int
+	EncodeWorkers int // goroutines that write rendered items while the workers render the next (0 = each worker writes its own)
+	MinFeaturePixels int  // cluster cleanup threshold in px at 256×256 (0 = ratio-based)
+	OutputFormat string // "webp" (default), "png" (WebPQuality ignored), or "exr"
+	Premultiply  bool   // store WebP RGB premultiplied by alpha (see postprocess.Premultiply)
+	AlphaMatte   string // "" (off), "alongside", or "instead": also/only write <index>_rgb.jpg + <index>_alpha.png
+	MatteQuality int    // JPEG quality of the matte's RGB image
+	MattePNG     bool   // write the matte's RGB image as <index>_rgb.png (lossless) instead
+	Verbose     bool // print per-item mesh classification, camera, coverage, timing (at Log's debug level)
+	Log         logging.Logger // progress and per-item output (nil = logging.Default)
+	Retries     int  // extra attempts for items that fail with an I/O error (0 = no retry)
+	IconCrop    string // "" (off), "center", or "dense": final square crop (see postprocess.Options.CenterSquareCrop)
+	Sidecar     bool   // also write <index>.json with the item's render metadata (see Sidecar)
+	Background  *color.NRGBA // flattens written images whose profile sets none; alpha is ignored (nil = transparent)
+	Shadow      *DropShadow  // drawn beneath the item in written images, under any background (nil = none)
+	Outline     *Outline     // stroke around the item in written images; framing leaves room for it (nil = none)
+	DebugCanvas string      // "" (off), "fill", or "grid": written images show the canvas (see postprocess.DebugCanvas)
+	DebugColor  color.NRGBA // DebugCanvas background and marker color
+	Profiles    []Profile   // if set, each item is written once per profile instead of to OutputDir
+	SkipExisting bool // leave items whose image is already written (see upToDate) instead of rendering them
+	CheckMtime   bool // with SkipExisting, still render items whose BMD is newer than their image
+	Incremental  bool   // leave items whose BMD, textures, TRS entry and Settings match their .meta file (see itemMeta)
+	Settings     string // with Incremental, the run's output settings key (config.Config.OutputKey); a change renders every item
+	TurntableFrames int // > 1: also write <index>_000 … turntable frames (see renderTurntable)
+	TurntableWebP   bool // with TurntableFrames, also write the frames as <index>_turntable.webp (animated)
+	TurntableDelay  int  // TurntableWebP frame delay in ms
+}
+
+// DropShadow is the item's shadow in written images (see
+// postprocess.DropShadow); sizes are in output px.
+type DropShadow struct {
+	OffsetX, OffsetY int
+	Blur             int
+	Color            color.NRGBA
+	Opacity          float64
+}
+
+// Outline is the stroke around the item in written images (see
+// postprocess.Outline); Thickness is in output px.
+type Outline struct {
+	Thickness int
+	Color     color.NRGBA
+}
+
+// Profile is one output variant of a run: its own directory, size, and
+// background, from the same parsed (and, at equal sizes, rasterized) item.
+type Profile struct {
+	Name       string
+	OutputDir  string       // replaces Config.OutputDir for this profile's files
+	Width      int          // 0 = Config.RenderWidth or the item's TRS override
+	Height     int          // 0 = Config.RenderHeight or the item's TRS override
+	Background *color.NRGBA // composited behind the item (nil = transparent)
 }
 
 // Result holds the outcome of processing one item.
 type Result struct {
-	Name    string
-	Section int
-	Index   int
-	Success bool
-	Error   string
+	Name        string
+	Section     int
+	Index       int
+	Success     bool
+	Error       string
+	Failure     Failure // why the item failed (FailNone on success)
+	Retries     int     // attempts made after the first one
+	Coverage    float64 // opaque-pixel fraction of the final image (0 on failure)
+	FallbackTRS bool    // rendered without a TRS entry or via VIEW_FALLBACK
+	Suggestion  string  // missing BMD: the closest existing model, relative to ItemDir ("" = none close)
+	Skipped     bool    // SkipExisting or Incremental left the earlier image in place; nothing else is set
+	Output      *OutputInfo // the main image written (the first profile's); nil unless Success
+
+	err error // underlying error, for retry classification
+}
+
+// OutputInfo describes an item's written main image: the file the manifest's
+// "image" names.
+type OutputInfo struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"` // hex digest of the file's bytes, e.g. for cache-busting
 }
 
+// retryBackoff is the wait before the first retry; it doubles per attempt.
+var retryBackoff = 250 * time.Millisecond
+
 // Run processes all items using a worker pool.
 func Run(cfg Config, items []itemlist.ItemDef) []Result {
 	total := len(items)
@@ -62,12 +149,34 @@ func Run(cfg Config, items []itemlist.ItemDef) []Result {
 				if p > 0 {
 					elapsed := time.Since(start).Seconds()
 					rate := float64(p) / elapsed
-					fmt.Printf("  [%d/%d] %.1f items/sec\n", p, total, rate)
+					logging.Or(cfg.Log).Printf("  [%d/%d] %.1f items/sec\n", p, total, rate)
 				}
 			}
 		}
 	}()
 
+	// Encoder pool: rendered items queue here (at most 2 per encoder, which
+	// bounds the images held in memory) while their workers move on
+	type encodeJob struct {
+		idx int
+		p   *pendingItem
+	}
+	var encChan chan encodeJob
+	var encWG sync.WaitGroup
+	if cfg.EncodeWorkers > 0 {
+		encChan = make(chan encodeJob, cfg.EncodeWorkers*2)
+		for w := 0; w < cfg.EncodeWorkers; w++ {
+			encWG.Add(1)
+			go func() {
+				defer encWG.Done()
+				for job := range encChan {
+					results[job.idx] = writeWithRetry(cfg, job.p)
+					processed.Add(1)
+				}
+			}()
+		}
+	}
+
 	// Worker pool
 	itemChan := make(chan int, cfg.Workers*2)
 	var wg sync.WaitGroup
@@ -77,7 +186,21 @@ func Run(cfg Config, items []itemlist.ItemDef) []Result {
 		go func() {
 			defer wg.Done()
 			for idx := range itemChan {
-				results[idx] = processItem(cfg, items[idx])
+				if (cfg.SkipExisting && upToDate(cfg, items[idx], cfg.CheckMtime)) || (cfg.Incremental && metaCurrent(cfg, items[idx])) {
+					it := items[idx]
+					results[idx] = Result{Name: it.Name, Section: it.Section, Index: it.Index, Skipped: true}
+					processed.Add(1)
+					continue
+				}
+				p, res := renderWithRetry(cfg, items[idx])
+				if p != nil && encChan != nil {
+					encChan <- encodeJob{idx, p}
+					continue
+				}
+				if p != nil {
+					res = writeWithRetry(cfg, p)
+				}
+				results[idx] = res
 				processed.Add(1)
 			}
 		}()
@@ -90,43 +213,375 @@ func Run(cfg Config, items []itemlist.ItemDef) []Result {
 	close(itemChan)
 
 	wg.Wait()
+	if encChan != nil {
+		close(encChan)
+		encWG.Wait()
+	}
 	close(done)
 
+	suggestModels(cfg.ItemDir, items, results)
 	return results
 }
 
-func processItem(cfg Config, item itemlist.ItemDef) Result {
-	bmdPath := filepath.Join(cfg.ItemDir, item.SubDir, item.ModelFile)
-	if _, err := os.Stat(bmdPath); os.IsNotExist(err) {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   fmt.Sprintf("BMD not found: %s", item.ModelFile),
+// pendingItem is an item rendered for every profile and not yet written.
+type pendingItem struct {
+	item     itemlist.ItemDef
+	profiles []Profile
+	renders  []renderedItem
+	t        itemTimings
+	retries  int         // render attempts made after the first
+	output   *OutputInfo // the first profile's main image, once written
+}
+
+// renderWithRetry runs prepareItem, retrying with backoff up to cfg.Retries
+// times while the failure is an I/O error. A missing BMD, bad data, or an
+// empty model fails the same way every time and isn't retried. On failure
+// the pendingItem is nil and the Result says why.
+func renderWithRetry(cfg Config, item itemlist.ItemDef) (*pendingItem, Result) {
+	var p *pendingItem
+	retries, err := withRetry(cfg, func() error {
+		var err error
+		p, err = prepareItem(cfg, item)
+		return err
+	})
+	if err != nil {
+		return nil, failResult(item, err, retries)
+	}
+	p.retries = retries
+	return p, Result{}
+}
+
+// writeWithRetry writes p's files, retrying the writes alone (the images
+// are already rendered) like renderWithRetry, and returns the item's Result.
+func writeWithRetry(cfg Config, p *pendingItem) Result {
+	retries, err := withRetry(cfg, func() error { return p.write(cfg) })
+	if err != nil {
+		return failResult(p.item, err, p.retries+retries)
+	}
+	res := p.result(cfg)
+	res.Retries = p.retries + retries
+	return res
+}
+
+// withRetry calls fn until it succeeds, fails with something other than an
+// I/O error, or has been retried cfg.Retries times, doubling the wait from
+// retryBackoff each time. It returns the retries made and fn's last error.
+func withRetry(cfg Config, fn func() error) (int, error) {
+	err := fn()
+	wait := retryBackoff
+	attempt := 0
+	for ; attempt < cfg.Retries && classify(err) == FailIO; attempt++ {
+		time.Sleep(wait)
+		wait *= 2
+		err = fn()
+	}
+	return attempt, err
+}
+
+func failResult(item itemlist.ItemDef, err error, retries int) Result {
+	return Result{
+		Name:    item.Name,
+		Section: item.Section,
+		Index:   item.Index,
+		Error:   err.Error(),
+		Failure: classify(err),
+		Retries: retries,
+		err:     err,
+	}
+}
+
+// prepareItem parses and renders item once per profile (cfg.Profiles, or
+// just cfg.OutputDir).
+func prepareItem(cfg Config, item itemlist.ItemDef) (*pendingItem, error) {
+	profiles := outputProfiles(cfg)
+	p := &pendingItem{item: item, profiles: profiles}
+	renders, err := renderProfiles(cfg, item, profiles, &p.t)
+	if err != nil {
+		return nil, err
+	}
+	p.renders = renders
+	return p, nil
+}
+
+// write encodes and writes p's images and, with cfg.Sidecar, its sidecars.
+func (p *pendingItem) write(cfg Config) error {
+	t0 := time.Now()
+	for i, prof := range p.profiles {
+		out, err := writeImages(cfg, prof, p.item, p.renders[i])
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			p.output = out
+		}
+	}
+	p.t.encode = time.Since(t0)
+
+	if cfg.Sidecar {
+		for i, prof := range p.profiles {
+			jsonPath := filepath.Join(prof.OutputDir, fmt.Sprintf("%d", p.item.Section), fmt.Sprintf("%d.json", p.item.Index))
+			if err := writeSidecar(jsonPath, buildSidecar(p.item, p.renders[i], p.t)); err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.Incremental {
+		return writeMeta(cfg, p.item, p.renders[0].textures)
+	}
+	return nil
+}
+
+// result prints the verbose log (if on) and returns the success Result; the
+// log and result describe the first profile.
+func (p *pendingItem) result(cfg Config) Result {
+	r := p.renders[0]
+	if cfg.Verbose {
+		logging.Or(cfg.Log).Debugf("%s", formatItemLog(p.item, r.entry, r.meshCount, r.stats, r.img, p.t))
+	}
+
+	return Result{
+		Name:        p.item.Name,
+		Section:     p.item.Section,
+		Index:       p.item.Index,
+		Success:     true,
+		Coverage:    coverage(r.img),
+		FallbackTRS: r.entry == nil || viewmatrix.IsFallbackPath(r.entry),
+		Output:      p.output,
+	}
+}
+
+// writeImages writes r's image (and alpha matte) for profile p to
+// p.OutputDir/<section>/ and describes the main one (see imagePath).
+func writeImages(cfg Config, p Profile, item itemlist.ItemDef, r renderedItem) (*OutputInfo, error) {
+	// Outline, shadow, background and debug canvas go on the written images
+	// only; coverage and the sidecar still describe the item itself
+	decorate := func(img *image.NRGBA) *image.NRGBA {
+		b := img.Bounds()
+		if o := cfg.Outline; o != nil {
+			img = postprocess.Outline(img, o.Thickness, o.Color)
+		}
+		if s := cfg.Shadow; s != nil {
+			img = postprocess.DropShadow(img, s.OffsetX, s.OffsetY, s.Blur, s.Color, s.Opacity)
+		}
+		if img.Bounds() != b {
+			// Outline and shadow grow the canvas; written images keep the
+			// render's size (framing left the outline a margin)
+			img = postprocess.OverColor(img.SubImage(b).(*image.NRGBA), color.NRGBA{})
+		}
+		bg := p.Background
+		if bg == nil && cfg.Background != nil {
+			// Flattening always gives an opaque image, whatever alpha the
+			// color was given with
+			opaque := *cfg.Background
+			opaque.A = 255
+			bg = &opaque
+		}
+		if bg != nil {
+			img = postprocess.OverColor(img, *bg)
+		}
+		if cfg.DebugCanvas != "" {
+			fillRatio := trs.DefaultFillRatio
+			if r.entry != nil && r.entry.FillRatio > 0 {
+				fillRatio = r.entry.FillRatio
+			}
+			img = postprocess.DebugCanvas(img, cfg.DebugColor, cfg.DebugCanvas == "grid", fillRatio)
+		}
+		return img
+	}
+	img := decorate(r.img)
+
+	// Save (WebP by default, or PNG or EXR)
+	ext := OutputExt(cfg.OutputFormat)
+	secDir := filepath.Join(p.OutputDir, fmt.Sprintf("%d", item.Section))
+	if err := os.MkdirAll(secDir, 0755); err != nil {
+		return nil, err
+	}
+
+	// WebP may store premultiplied RGB; the matte's files never do
+	premultiply := func(img *image.NRGBA) *image.NRGBA {
+		if cfg.Premultiply && ext == "webp" {
+			return postprocess.Premultiply(img)
+		}
+		return img
+	}
+
+	var out *OutputInfo
+	if cfg.AlphaMatte != "instead" {
+		outPath := filepath.Join(secDir, fmt.Sprintf("%d.%s", item.Index, ext))
+		var err error
+		if out, err = writeImage(outPath, premultiply(img), ext); err != nil {
+			return nil, err
+		}
+	}
+	frames := make([]*image.NRGBA, len(r.frames))
+	for i, frame := range r.frames {
+		frames[i] = decorate(frame)
+		framePath := filepath.Join(secDir, fmt.Sprintf("%d_%03d.%s", item.Index, i, ext))
+		if _, err := writeImage(framePath, premultiply(frames[i]), ext); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.TurntableWebP && len(frames) > 0 {
+		if cfg.Premultiply {
+			for i, f := range frames {
+				frames[i] = postprocess.Premultiply(f)
+			}
+		}
+		var buf bytes.Buffer
+		if err := anim.EncodeWebP(&buf, frames, cfg.TurntableDelay); err != nil {
+			return nil, &EncodeError{Format: "webp", Err: err}
+		}
+		animPath := filepath.Join(secDir, fmt.Sprintf("%d_turntable.webp", item.Index))
+		if err := os.WriteFile(animPath, buf.Bytes(), 0644); err != nil {
+			return nil, err
 		}
 	}
+	if cfg.AlphaMatte != "" {
+		rgbPath := filepath.Join(secDir, fmt.Sprintf("%d_rgb.%s", item.Index, MatteRGBExt(cfg.MattePNG)))
+		alphaPath := filepath.Join(secDir, fmt.Sprintf("%d_alpha.png", item.Index))
+		if err := export.WriteMatte(rgbPath, alphaPath, img, cfg.MatteQuality); err != nil {
+			return nil, &EncodeError{Format: "matte", Err: err}
+		}
+		if out == nil {
+			// The matte's RGB image is the main one; WriteMatte encodes
+			// straight to the file, so hash it from there
+			data, err := os.ReadFile(rgbPath)
+			if err != nil {
+				return nil, err
+			}
+			out = outputInfo(img, data)
+		}
+	}
+	return out, nil
+}
 
+// outputInfo describes an image file of img's size holding data.
+func outputInfo(img *image.NRGBA, data []byte) *OutputInfo {
+	sum := sha256.Sum256(data)
+	return &OutputInfo{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+		Bytes:  int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
+
+// writeImage encodes img to path in the given output format (see OutputExt).
+// WebP gets img's pixels as they are: straight alpha unless the caller
+// premultiplied them. PNG is always straight alpha, EXR always premultiplied.
+// The image is encoded in memory, then written and described in one go.
+func writeImage(path string, img *image.NRGBA, ext string) (*OutputInfo, error) {
+	var buf bytes.Buffer
+	switch ext {
+	case "exr":
+		if err := export.WriteEXR(&buf, img); err != nil {
+			return nil, &EncodeError{Format: "exr", Err: err}
+		}
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, &EncodeError{Format: "png", Err: err}
+		}
+	default:
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, &EncodeError{Format: "webp", Err: err}
+		}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	return outputInfo(img, buf.Bytes()), nil
+}
+
+// RenderItem runs the full pipeline for one item — parse, render, and
+// post-processing — and returns the final image without encoding it.
+// The model is read from cfg.ItemDir/item.SubDir/item.ModelFile and the TRS
+// entry is looked up in cfg.TRSData by (Section, Index); a missing entry
+// renders with defaults, so a synthetic ItemDef works for loose BMD files.
+func RenderItem(cfg Config, item itemlist.ItemDef) (*image.NRGBA, error) {
+	var t itemTimings
+	rs, err := renderProfiles(cfg, item, []Profile{{}}, &t)
+	if err != nil {
+		return nil, err
+	}
+	return rs[0].img, nil
+}
+
+// renderedItem is one final image of renderProfiles plus the details
+// verbose logging and sidecars report.
+type renderedItem struct {
+	img       *image.NRGBA
+	entry     *trs.Entry
+	meshCount int
+	textures  []string // the model's distinct texture references, lowercase (with cfg.Incremental or cfg.WarmTextures)
+	frames    []*image.NRGBA // turntable frames (with cfg.TurntableFrames)
+	stats     *raster.RenderStats // nil unless cfg.Verbose or cfg.Sidecar
+}
+
+// renderProfiles runs the pipeline for item and returns one final image per
+// profile, in profile order. The BMD is parsed once; profiles that come out
+// at the same size also share the rasterization and post-processing, so an
+// extra variant costs little more than its encoding.
+func renderProfiles(cfg Config, item itemlist.ItemDef, profiles []Profile, t *itemTimings) ([]renderedItem, error) {
+	bmdPath := filepath.Join(cfg.ItemDir, item.SubDir, item.ModelFile)
+
+	t0 := time.Now()
 	meshes, bones, err := bmd.Parse(bmdPath)
+	t.parse = time.Since(t0)
 	if err != nil {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   err.Error(),
-		}
+		return nil, err
 	}
 
 	if len(meshes) == 0 {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   "No meshes in BMD",
-		}
+		return nil, ErrNoMeshes
 	}
 
 	entry := cfg.TRSData[[2]int{item.Section, item.Index}]
+	var textures []string
+	if cfg.Incremental || cfg.WarmTextures {
+		textures = textureRefs(meshes)
+	}
+	if pr, ok := cfg.TexResolver.(texture.PathResolver); ok && cfg.WarmTextures {
+		t0 := time.Now()
+		texture.Warm(pr, textures, runtime.GOMAXPROCS(0))
+		t.render += time.Since(t0)
+	}
+
+	sizes := make([][2]int, len(profiles))
+	distinct := make(map[[2]int]bool)
+	for i, p := range profiles {
+		sizes[i] = profileSize(cfg, p, entry)
+		distinct[sizes[i]] = true
+	}
+
+	out := make([]renderedItem, len(profiles))
+	bySize := make(map[[2]int]renderedItem)
+	for i, size := range sizes {
+		r, ok := bySize[size]
+		if !ok {
+			var frames []*image.NRGBA
+			if cfg.TurntableFrames > 1 {
+				frames = renderTurntable(cfg, meshes, bones, entry, size[0], size[1], t)
+			}
+			// Bone transforms move vertices in place: every pass but the
+			// last renders a copy
+			m := meshes
+			if len(bySize) < len(distinct)-1 {
+				m = bmd.CloneMeshes(meshes)
+			}
+			r = renderAt(cfg, m, bones, entry, size[0], size[1], t)
+			r.meshCount = len(meshes)
+			r.textures = textures
+			r.frames = frames
+			bySize[size] = r
+		}
+		out[i] = r
+	}
+	return out, nil
+}
 
+// profileSize returns the output size for p: the profile's own size, else
+// the entry's render_width/render_height, else cfg's.
+func profileSize(cfg Config, p Profile, entry *trs.Entry) [2]int {
 	// Per-item render dimensions override global config
 	renderW, renderH := cfg.RenderWidth, cfg.RenderHeight
 	if entry != nil && entry.RenderWidth > 0 {
@@ -135,38 +590,89 @@ func processItem(cfg Config, item itemlist.ItemDef) Result {
 	if entry != nil && entry.RenderHeight > 0 {
 		renderH = entry.RenderHeight
 	}
+	if p.Width > 0 {
+		renderW = p.Width
+	}
+	if p.Height > 0 {
+		renderH = p.Height
+	}
+	return [2]int{renderW, renderH}
+}
 
-	img := raster.RenderBMD(meshes, bones, entry, cfg.TexResolver, renderW, renderH, cfg.Supersample)
+// renderAt rasterizes meshes at renderW×renderH and post-processes the
+// result into the final image.
+func renderAt(cfg Config, meshes []bmd.Mesh, bones []bmd.Bone, entry *trs.Entry, renderW, renderH int, t *itemTimings) renderedItem {
+	t0 := time.Now()
+	var img *image.NRGBA
+	var stats *raster.RenderStats
+	if cfg.Verbose || cfg.Sidecar {
+		img, stats = raster.RenderBMDWithStats(meshes, bones, entry, cfg.TexResolver, renderW, renderH, cfg.Supersample, cfg.Render)
+	} else {
+		img = raster.RenderBMD(meshes, bones, entry, cfg.TexResolver, renderW, renderH, cfg.Supersample, cfg.Render)
+	}
+	t.render += time.Since(t0)
+	t0 = time.Now()
 
 	// Post-processing: supersample downsample
 	if cfg.Supersample > 1 {
-		img = postprocess.Downsample(img, renderW, renderH)
+		img = postprocess.DownsamplePasses(img, renderW, renderH, cfg.DownsamplePasses)
 	}
 
 	// Remove small clusters
-	img = postprocess.RemoveSmallClusters(img, 0.02)
+	if cfg.MinFeaturePixels > 0 {
+		img = cfg.Postprocess.RemoveClustersBelowArea(img, cfg.MinFeaturePixels)
+	} else {
+		img = cfg.Postprocess.RemoveSmallClusters(img, 0.02)
+	}
+	if entry != nil && entry.KeepComponents > 0 {
+		img = cfg.Postprocess.KeepLargestN(img, entry.KeepComponents)
+	}
+
+	// An outline is drawn at write time; frame the item to leave room for it
+	reserve := 0
+	if cfg.Outline != nil {
+		reserve = cfg.Outline.Thickness
+	}
+	frame := func(fillRatio float64) float64 {
+		return postprocess.OutlineMargin(fillRatio, renderW, renderH, reserve)
+	}
 
 	// Standardize (PCA rotation + scale + center)
 	doStandardize := true
 	if entry != nil && entry.Standardize != nil && !*entry.Standardize {
 		doStandardize = false
 	}
-	if doStandardize {
+	absolute := entry != nil && entry.AbsoluteScale
+	if absolute {
+		// Rendered at a fixed scale: only re-center, never rescale. A pivot
+		// already put its point at the center, so it stays there.
+		if entry.Pivot == "" {
+			img = cfg.Postprocess.CenterContent(img, renderW, renderH)
+		}
+	} else if entry != nil && entry.PostRotate2D != nil {
+		// Explicit 2D rotation replaces PCA alignment entirely
+		img = cfg.Postprocess.RotateAndCenter(img, renderW, renderH, *entry.PostRotate2D, frame(entry.FillRatio))
+	} else if doStandardize {
 		displayAngle := trs.DefaultDisplayAngle
 		fillRatio := trs.DefaultFillRatio
 		forceFlip := false
+		autoFlip := true
 		if entry != nil {
 			displayAngle = entry.DisplayAngle
 			fillRatio = entry.FillRatio
 			forceFlip = entry.Flip
+			autoFlip = entry.NoAutoFlip == nil || !*entry.NoAutoFlip
+			if entry.AutoDisplayAngle {
+				displayAngle = cfg.Postprocess.AutoDisplayAngle(img, displayAngle)
+			}
 		}
-		img = postprocess.StandardizeImage(img, renderW, renderH, displayAngle, fillRatio, forceFlip)
+		img = cfg.Postprocess.StandardizeImage(img, renderW, renderH, displayAngle, frame(fillRatio), forceFlip, autoFlip)
 	} else {
 		fillRatio := trs.DefaultFillRatio
 		if entry != nil {
 			fillRatio = entry.FillRatio
 		}
-		img = postprocess.CropAndCenter(img, renderW, renderH, fillRatio)
+		img = cfg.Postprocess.CropAndCenter(img, renderW, renderH, frame(fillRatio))
 	}
 
 	// Mirror pair: duplicate + mirror to create a pair (e.g. single boot → pair)
@@ -175,7 +681,7 @@ func processItem(cfg Config, item itemlist.ItemDef) Result {
 		if entry.FillRatio > 0 {
 			fillRatio = entry.FillRatio
 		}
-		img = postprocess.MirrorPair(img, renderW, renderH, fillRatio)
+		img = cfg.Postprocess.MirrorPair(img, renderW, renderH, frame(fillRatio))
 	}
 
 	// Horizontal canvas flip
@@ -184,43 +690,16 @@ func processItem(cfg Config, item itemlist.ItemDef) Result {
 	}
 
 	// Final trim: crop transparent borders and scale to fill canvas
-	img = postprocess.TrimToContent(img, renderW, renderH, 4)
-
-	// Save as WebP
-	outPath := filepath.Join(cfg.OutputDir, fmt.Sprintf("%d", item.Section), fmt.Sprintf("%d.webp", item.Index))
-	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   err.Error(),
-		}
+	if !absolute {
+		img = cfg.Postprocess.TrimToContent(img, renderW, renderH, 4+reserve)
 	}
 
-	f, err := os.Create(outPath)
-	if err != nil {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   err.Error(),
-		}
+	// Square icon: crop the item's middle instead of showing it whole
+	if cfg.IconCrop != "" {
+		img = cfg.Postprocess.CenterSquareCrop(img, min(renderW, renderH), cfg.IconCrop == "dense")
 	}
-	defer f.Close()
 
-	if err := nativewebp.Encode(f, img, nil); err != nil {
-		return Result{
-			Name:    item.Name,
-			Section: item.Section,
-			Index:   item.Index,
-			Error:   fmt.Sprintf("WebP encode: %v", err),
-		}
-	}
+	t.post += time.Since(t0)
 
-	return Result{
-		Name:    item.Name,
-		Section: item.Section,
-		Index:   item.Index,
-		Success: true,
-	}
+	return renderedItem{img: img, entry: entry, stats: stats}
 }
@@ -0,0 +1,173 @@
+package batch_test
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/texture"
+)
+
+// countingResolver counts the texture lookups of a run: one per mesh drawn,
+// so it shows how many times an item was rasterized.
+type countingResolver struct {
+	texture.Resolver
+	n atomic.Int64
+}
+
+func (c *countingResolver) Resolve(name string) *image.NRGBA {
+	c.n.Add(1)
+	return c.Resolver.Resolve(name)
+}
+
+// TestProfiles writes the fixture sword through two profiles at the same
+// size (one on a dark background) and one smaller: three output trees, from
+// two rasterizations.
+func TestProfiles(t *testing.T) {
+	cfg, items := newFixture(t)
+	single := &countingResolver{Resolver: cfg.TexResolver}
+	cfg.TexResolver = single
+	cfg.OutputFormat = "png"
+	for _, r := range batch.Run(cfg, fixtureItem0(t, items)) {
+		if !r.Success {
+			t.Fatal(r.Error)
+		}
+	}
+
+	cfg, items = newFixture(t)
+	counted := &countingResolver{Resolver: cfg.TexResolver}
+	cfg.TexResolver = counted
+	cfg.OutputFormat = "png"
+	dark := color.NRGBA{20, 20, 30, 255}
+	cfg.Profiles = []batch.Profile{
+		{Name: "clear", OutputDir: filepath.Join(cfg.OutputDir, "clear")},
+		{Name: "dark", OutputDir: filepath.Join(cfg.OutputDir, "dark"), Background: &dark},
+		{Name: "small", OutputDir: filepath.Join(cfg.OutputDir, "small"), Width: 96, Height: 96},
+	}
+	for _, r := range batch.Run(cfg, fixtureItem0(t, items)) {
+		if !r.Success {
+			t.Fatal(r.Error)
+		}
+	}
+	if got, want := counted.n.Load(), 2*single.n.Load(); got != want {
+		t.Errorf("%d texture lookups, want %d: profiles of one size must share a render", got, want)
+	}
+
+	for _, c := range []struct {
+		profile string
+		size    int
+		corner  color.NRGBA
+	}{
+		{"clear", 256, color.NRGBA{}},
+		{"dark", 256, dark},
+		{"small", 96, color.NRGBA{}},
+	} {
+		f, err := os.Open(filepath.Join(cfg.OutputDir, c.profile, "0", "0.png"))
+		if err != nil {
+			t.Fatalf("%s: %v", c.profile, err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", c.profile, err)
+		}
+		if b := img.Bounds(); b.Dx() != c.size || b.Dy() != c.size {
+			t.Errorf("%s: %dx%d, want %dx%d", c.profile, b.Dx(), b.Dy(), c.size, c.size)
+		}
+		corner := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA)
+		if corner != c.corner {
+			t.Errorf("%s: corner %v, want %v", c.profile, corner, c.corner)
+		}
+	}
+	if exists(filepath.Join(cfg.OutputDir, "0", "0.png")) {
+		t.Error("image written to OutputDir as well as the profiles")
+	}
+}
+
+// TestBackgroundOpaque flattens the fixture sword onto a background given
+// with half alpha: the written image is opaque everywhere anyway, with the
+// background's color showing in the corner.
+func TestBackgroundOpaque(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.OutputFormat = "png"
+	cfg.Background = &color.NRGBA{26, 26, 46, 128}
+	for _, r := range batch.Run(cfg, fixtureItem0(t, items)) {
+		if !r.Success {
+			t.Fatal(r.Error)
+		}
+	}
+	f, err := os.Open(filepath.Join(cfg.OutputDir, "0", "0.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA); c.A != 255 {
+				t.Fatalf("alpha %d at (%d,%d), want 255", c.A, x, y)
+			}
+		}
+	}
+	if c, want := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA), (color.NRGBA{26, 26, 46, 255}); c != want {
+		t.Errorf("corner %v, want %v", c, want)
+	}
+}
+
+// TestDecorationsKeepSize writes the fixture sword with an outline and a
+// drop shadow: the written image keeps the render's size though both grow
+// the canvas, and they show around the item.
+func TestDecorationsKeepSize(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.OutputFormat = "png"
+	cfg.Shadow = &batch.DropShadow{OffsetX: 6, OffsetY: 6, Blur: 3, Color: color.NRGBA{0, 0, 0, 255}, Opacity: 0.6}
+	cfg.Outline = &batch.Outline{Thickness: 4, Color: color.NRGBA{255, 255, 255, 255}}
+	plain, _ := newFixture(t)
+	plain.OutputFormat = "png"
+	for _, c := range []batch.Config{cfg, plain} {
+		for _, r := range batch.Run(c, fixtureItem0(t, items)) {
+			if !r.Success {
+				t.Fatal(r.Error)
+			}
+		}
+	}
+	decode := func(dir string) image.Image {
+		f, err := os.Open(filepath.Join(dir, "0", "0.png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		img, err := png.Decode(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+	shadowed, bare := decode(cfg.OutputDir), decode(plain.OutputDir)
+	if shadowed.Bounds() != bare.Bounds() {
+		t.Fatalf("with shadow %v, without %v", shadowed.Bounds(), bare.Bounds())
+	}
+	more := 0
+	b := bare.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := bare.At(x, y).RGBA(); a == 0 {
+				if _, _, _, sa := shadowed.At(x, y).RGBA(); sa > 0 {
+					more++
+				}
+			}
+		}
+	}
+	if more == 0 {
+		t.Error("no outline or shadow pixels around the item")
+	}
+}
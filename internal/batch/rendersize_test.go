@@ -0,0 +1,55 @@
+package batch_test
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestRenderSize gives the fixture sword a render_size in custom_trs.json:
+// it is written at that size, and the jewel without one at the run's.
+func TestRenderSize(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.OutputFormat = "png"
+	data := filepath.Dir(filepath.Dir(cfg.ItemDir)) // the fixture's base, above Data/Item
+	custom := filepath.Join(t.TempDir(), "custom_trs.json")
+	if err := os.WriteFile(custom, []byte(`{"items": {"0_0": {"render_size": 96}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	cfg.TRSData, err = trs.LoadWith(
+		filepath.Join(data, "Data", "Local", "itemtrsdata.bmd"), custom,
+		filepath.Join(data, "Data", "Xml", "ItemList.xml"), trs.Options{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"0_0": 96, "14_0": cfg.RenderWidth}
+	for i, r := range batch.Run(cfg, items) {
+		size, ok := want[itemKeys(items)[i]]
+		if !ok {
+			continue
+		}
+		if !r.Success {
+			t.Fatalf("%s: %s", itemKeys(items)[i], r.Error)
+		}
+		f, err := os.Open(filepath.Join(cfg.OutputDir, fmt.Sprint(r.Section), fmt.Sprintf("%d.png", r.Index)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf, err := png.DecodeConfig(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.Width != size || conf.Height != size || r.Output.Width != size || r.Output.Height != size {
+			t.Errorf("%s: file %dx%d, result %dx%d; want %dx%d", itemKeys(items)[i],
+				conf.Width, conf.Height, r.Output.Width, r.Output.Height, size, size)
+		}
+	}
+}
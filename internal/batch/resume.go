@@ -0,0 +1,53 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// outputProfiles returns cfg.Profiles, or without profiles the one that
+// writes each item once, to cfg.OutputDir.
+func outputProfiles(cfg Config) []Profile {
+	if len(cfg.Profiles) > 0 {
+		return cfg.Profiles
+	}
+	return []Profile{{OutputDir: cfg.OutputDir}}
+}
+
+// imagePath returns the main image writeImages writes for item under dir:
+// <section>/<index>.<ext>, or the alpha matte's RGB file when it is written
+// instead.
+func imagePath(cfg Config, dir string, item itemlist.ItemDef) string {
+	name := fmt.Sprintf("%d.%s", item.Index, OutputExt(cfg.OutputFormat))
+	if cfg.AlphaMatte == "instead" {
+		name = fmt.Sprintf("%d_rgb.%s", item.Index, MatteRGBExt(cfg.MattePNG))
+	}
+	return filepath.Join(dir, fmt.Sprintf("%d", item.Section), name)
+}
+
+// upToDate reports whether item's image exists for every profile, so
+// SkipExisting can leave it. With checkMtime each image must also be at
+// least as new as the BMD, so a re-exported model is rendered again; a BMD
+// that can't be read is never up to date (rendering reports why).
+func upToDate(cfg Config, item itemlist.ItemDef, checkMtime bool) bool {
+	var bmdInfo os.FileInfo
+	if checkMtime {
+		var err error
+		if bmdInfo, err = os.Stat(filepath.Join(cfg.ItemDir, item.SubDir, item.ModelFile)); err != nil {
+			return false
+		}
+	}
+	for _, p := range outputProfiles(cfg) {
+		info, err := os.Stat(imagePath(cfg, p.OutputDir, item))
+		if err != nil {
+			return false
+		}
+		if bmdInfo != nil && info.ModTime().Before(bmdInfo.ModTime()) {
+			return false
+		}
+	}
+	return true
+}
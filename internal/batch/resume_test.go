@@ -0,0 +1,85 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// skipped runs cfg over items and returns which of them SkipExisting left,
+// by key; every item must succeed.
+func skipped(t *testing.T, cfg batch.Config, items []itemlist.ItemDef) map[string]bool {
+	t.Helper()
+	out := map[string]bool{}
+	for i, r := range batch.Run(cfg, items) {
+		if !r.Success && !r.Skipped {
+			t.Fatalf("%s: %s", itemKeys(items)[i], r.Error)
+		}
+		out[itemKeys(items)[i]] = r.Skipped
+	}
+	return out
+}
+
+// TestSkipExisting renders the fixture's sword and jewel, removes one image
+// and resumes: the item whose image is still there is left alone, the other is
+// rendered again. With profiles an item is only left when every profile's
+// image exists.
+func TestSkipExisting(t *testing.T) {
+	cfg, items := newFixture(t)
+	items = []itemlist.ItemDef{items[0], items[2]} // 0_0 and 14_0, from different BMDs
+	skipped(t, cfg, items)
+	kept := filepath.Join(cfg.OutputDir, "0", "0.webp")
+	before, err := os.Stat(kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(cfg.OutputDir, "14", "0.webp")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.SkipExisting = true
+	got := skipped(t, cfg, items)
+	if !got["0_0"] || got["14_0"] {
+		t.Errorf("skipped %v, want only 0_0", got)
+	}
+	if after, err := os.Stat(kept); err != nil || !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("skipped image rewritten: %v", err)
+	}
+	if !exists(filepath.Join(cfg.OutputDir, "14", "0.webp")) {
+		t.Error("missing image not rendered again")
+	}
+
+	// CheckMtime renders again an item whose BMD is newer than its image
+	cfg.CheckMtime = true
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(cfg.ItemDir, items[0].SubDir, items[0].ModelFile), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := skipped(t, cfg, items); got["0_0"] || !got["14_0"] {
+		t.Errorf("CheckMtime: skipped %v, want only 14_0", got)
+	}
+
+	cfg, items = newFixture(t)
+	items = []itemlist.ItemDef{items[0], items[2]}
+	cfg.Profiles = []batch.Profile{
+		{Name: "a", OutputDir: filepath.Join(cfg.OutputDir, "a")},
+		{Name: "b", OutputDir: filepath.Join(cfg.OutputDir, "b")},
+	}
+	skipped(t, cfg, items)
+	if err := os.Remove(filepath.Join(cfg.OutputDir, "b", "14", "0.webp")); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SkipExisting = true
+	if got := skipped(t, cfg, items); !got["0_0"] || got["14_0"] {
+		t.Errorf("profiles: skipped %v, want only 0_0", got)
+	}
+	for _, p := range []string{"a", "b"} {
+		if !exists(filepath.Join(cfg.OutputDir, p, "14", "0.webp")) {
+			t.Errorf("profile %s: 14_0 not written", p)
+		}
+	}
+}
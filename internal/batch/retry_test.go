@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// blockedItem returns a pending 0_1 item whose section directory under a
+// fresh output dir is taken by a plain file, so writing it fails with an
+// I/O error until that file is removed, and the file's path.
+func blockedItem(t *testing.T) (Config, *pendingItem, string) {
+	t.Helper()
+	old := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = old })
+
+	out := t.TempDir()
+	block := filepath.Join(out, "0")
+	if err := os.WriteFile(block, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{OutputDir: out, OutputFormat: "png"}
+	p := &pendingItem{
+		item:     itemlist.ItemDef{Section: 0, Index: 1, Name: "test"},
+		profiles: outputProfiles(cfg),
+		renders:  []renderedItem{{img: image.NewNRGBA(image.Rect(0, 0, 4, 4))}},
+	}
+	return cfg, p, block
+}
+
+func TestWriteRetryGivesUp(t *testing.T) {
+	cfg, p, _ := blockedItem(t)
+	cfg.Retries = 2
+	res := writeWithRetry(cfg, p)
+	if res.Success || res.Failure != FailIO {
+		t.Fatalf("Success %v, Failure %v; want an I/O failure", res.Success, res.Failure)
+	}
+	if res.Retries != 2 {
+		t.Errorf("Retries = %d, want 2", res.Retries)
+	}
+}
+
+func TestWriteRetryRecovers(t *testing.T) {
+	cfg, p, block := blockedItem(t)
+	cfg.Retries = 3
+	calls := 0
+	retries, err := withRetry(cfg, func() error {
+		calls++
+		err := p.write(cfg)
+		if calls == 2 {
+			// The obstruction clears after the second failed write
+			os.Remove(block)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("write still failing after %d retries: %v", retries, err)
+	}
+	if retries != 2 || calls != 3 {
+		t.Errorf("retries %d, calls %d; want 2 and 3", retries, calls)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputDir, "0", "1.png")); err != nil {
+		t.Errorf("image not written: %v", err)
+	}
+}
+
+func TestRetryOnlyIOErrors(t *testing.T) {
+	for _, err := range []error{ErrNoMeshes, &EncodeError{Format: "webp", Err: os.ErrInvalid}} {
+		calls := 0
+		retries, got := withRetry(Config{Retries: 3}, func() error {
+			calls++
+			return err
+		})
+		if calls != 1 || retries != 0 || got != err {
+			t.Errorf("%v: calls %d, retries %d, err %v; want one call and no retry", err, calls, retries, got)
+		}
+	}
+}
+
+// TestRenderRetryPermanentFailures renders items whose model is missing,
+// corrupt or empty: they fail the same way every time, so no retry is spent
+// on them.
+func TestRenderRetryPermanentFailures(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"Broken.bmd": "BMD\x0c",                              // truncated XOR header
+		"Empty.bmd":  "BMD\x0a" + strings.Repeat("\x00", 38), // name, no meshes, bones or actions
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := Config{ItemDir: dir, OutputDir: t.TempDir(), Retries: 3}
+	for _, c := range []struct {
+		model string
+		want  Failure
+	}{
+		{"Missing.bmd", FailMissingBMD},
+		{"Broken.bmd", FailParse},
+		{"Empty.bmd", FailNoMeshes},
+	} {
+		item := itemlist.ItemDef{Section: 0, Index: 1, Name: "test", ModelFile: c.model}
+		p, res := renderWithRetry(cfg, item)
+		if p != nil || res.Failure != c.want || res.Retries != 0 {
+			t.Errorf("%s: pending %v, Failure %v, Retries %d; want a %v with no retry", c.model, p != nil, res.Failure, res.Retries, c.want)
+		}
+	}
+}
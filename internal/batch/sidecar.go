@@ -0,0 +1,111 @@
+package batch
+
+import (
+	"encoding/json"
+	"image"
+	"os"
+
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
+)
+
+// Sidecar is the per-item render metadata written next to the image as
+// <index>.json when Config.Sidecar is set: enough to see exactly how a given
+// thumbnail was produced.
+type Sidecar struct {
+	Section    int               `json:"section"`
+	Index      int               `json:"index"`
+	Name       string            `json:"name"`
+	ModelFile  string            `json:"model_file"`
+	TRS        *trs.Entry        `json:"trs"`        // effective entry; null = rendered with defaults
+	Camera     string            `json:"camera"`     // view path: noflip, correction, or fallback
+	Projection string            `json:"projection"` // ortho, perspective, or positioned (cam_height)
+	Bones      bool              `json:"bones"`
+	Meshes     int               `json:"meshes"` // meshes in the BMD
+	Rendered   []raster.MeshStat `json:"rendered"`
+	Filtered   []raster.MeshStat `json:"filtered,omitempty"`
+	Promoted   bool              `json:"promoted,omitempty"` // no opaque mesh: first additive/alpha mesh drawn opaque
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	BBox       [4]int            `json:"bbox"`     // content bounds in the image: x0, y0, x1, y1 (exclusive)
+	Coverage   float64           `json:"coverage"` // opaque-pixel fraction of the image
+	TimeMs     sidecarTimes      `json:"time_ms"`
+}
+
+type sidecarTimes struct {
+	Parse  float64 `json:"parse"`
+	Render float64 `json:"render"`
+	Post   float64 `json:"post"`
+	Encode float64 `json:"encode"`
+}
+
+// buildSidecar collects r's metadata; r.stats must be set.
+func buildSidecar(item itemlist.ItemDef, r renderedItem, t itemTimings) Sidecar {
+	projection := "ortho"
+	if r.stats.PosCamera {
+		projection = "positioned"
+	} else if viewmatrix.IsPerspective(r.entry) {
+		projection = "perspective"
+	}
+	b := r.img.Bounds()
+	return Sidecar{
+		Section:    item.Section,
+		Index:      item.Index,
+		Name:       item.Name,
+		ModelFile:  item.ModelFile,
+		TRS:        r.entry,
+		Camera:     viewmatrix.CameraPath(r.entry),
+		Projection: projection,
+		Bones:      r.stats.UseBones,
+		Meshes:     r.meshCount,
+		Rendered:   r.stats.Rendered,
+		Filtered:   r.stats.Filtered,
+		Promoted:   r.stats.Promoted,
+		Width:      b.Dx(),
+		Height:     b.Dy(),
+		BBox:       contentBounds(r.img),
+		Coverage:   coverage(r.img),
+		TimeMs: sidecarTimes{
+			Parse:  ms(t.parse.Seconds()),
+			Render: ms(t.render.Seconds()),
+			Post:   ms(t.post.Seconds()),
+			Encode: ms(t.encode.Seconds()),
+		},
+	}
+}
+
+// ms converts seconds to milliseconds rounded to 0.01.
+func ms(sec float64) float64 {
+	return float64(int64(sec*1e5+0.5)) / 100
+}
+
+// contentBounds returns the bounding box of non-transparent pixels
+// (all zero for an empty image).
+func contentBounds(img *image.NRGBA) [4]int {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	minX, minY, maxX, maxY := w, h, -1, -1
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if img.Pix[y*img.Stride+x*4+3] > 0 {
+				minX, maxX = min(minX, x), max(maxX, x)
+				minY, maxY = min(minY, y), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		return [4]int{}
+	}
+	return [4]int{minX, minY, maxX + 1, maxY + 1}
+}
+
+// writeSidecar writes s to path as indented JSON.
+func writeSidecar(path string, s Sidecar) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
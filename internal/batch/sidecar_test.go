@@ -0,0 +1,91 @@
+package batch_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+)
+
+// TestSidecar renders the fixture sword with sidecar set and checks the
+// JSON written beside its image.
+func TestSidecar(t *testing.T) {
+	cfg, items := newFixture(t)
+	cfg.Sidecar = true
+	for _, r := range batch.Run(cfg, fixtureItem0(t, items)) {
+		if !r.Success {
+			t.Fatal(r.Error)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "0", "0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s struct {
+		Section   int    `json:"section"`
+		Index     int    `json:"index"`
+		ModelFile string `json:"model_file"`
+		TRS       *struct {
+			Source string  `json:"source"`
+			Scale  float64 `json:"scale"`
+		} `json:"trs"`
+		Camera     string `json:"camera"`
+		Projection string `json:"projection"`
+		Meshes     int    `json:"meshes"`
+		Rendered   []struct {
+			Texture string `json:"texture"`
+			Tris    int    `json:"tris"`
+			Pass    string `json:"pass"`
+		} `json:"rendered"`
+		Width    int                `json:"width"`
+		Height   int                `json:"height"`
+		BBox     [4]int             `json:"bbox"`
+		Coverage float64            `json:"coverage"`
+		TimeMs   map[string]float64 `json:"time_ms"`
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Section != 0 || s.Index != 0 || s.ModelFile != "Sword01.bmd" {
+		t.Errorf("item %d_%d %q, want 0_0 Sword01.bmd", s.Section, s.Index, s.ModelFile)
+	}
+	if s.TRS == nil || s.TRS.Source != "binary" || s.TRS.Scale == 0 {
+		t.Errorf("trs %+v, want the binary entry", s.TRS)
+	}
+	if s.Camera == "" || s.Projection == "" {
+		t.Errorf("camera %q, projection %q: want both set", s.Camera, s.Projection)
+	}
+	if s.Meshes != 3 || len(s.Rendered) != 3 {
+		t.Errorf("%d meshes, %d rendered; want 3 and 3", s.Meshes, len(s.Rendered))
+	}
+	for _, m := range s.Rendered {
+		if m.Texture == "" || m.Tris == 0 || m.Pass != "opaque" {
+			t.Errorf("rendered mesh %+v: want texture, triangles and the opaque pass", m)
+		}
+	}
+	if s.Width != 256 || s.Height != 256 {
+		t.Errorf("size %dx%d, want 256x256", s.Width, s.Height)
+	}
+	if b := s.BBox; b[0] >= b[2] || b[1] >= b[3] || b[2] > s.Width || b[3] > s.Height {
+		t.Errorf("bbox %v is empty or outside the image", b)
+	}
+	if s.Coverage <= 0 || s.Coverage > 1 {
+		t.Errorf("coverage %v, want in (0, 1]", s.Coverage)
+	}
+	for _, k := range []string{"parse", "render", "post", "encode"} {
+		if _, ok := s.TimeMs[k]; !ok {
+			t.Errorf("time_ms has no %q", k)
+		}
+	}
+
+	// Off by default
+	cfg, items = newFixture(t)
+	batch.Run(cfg, fixtureItem0(t, items))
+	if exists(filepath.Join(cfg.OutputDir, "0", "0.json")) {
+		t.Error("sidecar written without sidecar set")
+	}
+}
@@ -0,0 +1,36 @@
+package batch
+
+import (
+	"fmt"
+
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// SkipItems removes items matching any of keys ("section_index" or
+// "section_start-end", as in custom_trs.json) and returns the remaining items
+// and the number removed. A malformed key is an error rather than a silent
+// no-op, so a typo can't quietly let a known-broken item back in.
+func SkipItems(items []itemlist.ItemDef, keys []string) ([]itemlist.ItemDef, int, error) {
+	if len(keys) == 0 {
+		return items, 0, nil
+	}
+	skip := make(map[[2]int]bool)
+	for _, k := range keys {
+		pairs := trs.ParseItemKeys(k)
+		if pairs == nil {
+			return nil, 0, fmt.Errorf("skip_items: bad key %q (want section_index or section_start-end)", k)
+		}
+		for _, p := range pairs {
+			skip[p] = true
+		}
+	}
+
+	kept := make([]itemlist.ItemDef, 0, len(items))
+	for _, it := range items {
+		if !skip[[2]int{it.Section, it.Index}] {
+			kept = append(kept, it)
+		}
+	}
+	return kept, len(items) - len(kept), nil
+}
@@ -0,0 +1,41 @@
+package batch_test
+
+import (
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+func TestSkipItems(t *testing.T) {
+	var items []itemlist.ItemDef
+	for _, k := range [][2]int{{0, 0}, {0, 1}, {12, 3}, {12, 4}, {12, 5}, {14, 72}} {
+		items = append(items, itemlist.ItemDef{Section: k[0], Index: k[1]})
+	}
+	keys := func(items []itemlist.ItemDef) [][2]int {
+		var out [][2]int
+		for _, it := range items {
+			out = append(out, [2]int{it.Section, it.Index})
+		}
+		return out
+	}
+
+	kept, n, err := batch.SkipItems(items, []string{"0_1", "12_4-9", "7_0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{0, 0}, {12, 3}, {14, 72}}; n != 3 || !reflect.DeepEqual(keys(kept), want) {
+		t.Errorf("kept %v (%d skipped), want %v (3 skipped)", keys(kept), n, want)
+	}
+
+	if kept, n, err := batch.SkipItems(items, nil); err != nil || n != 0 || len(kept) != len(items) {
+		t.Errorf("no keys: kept %d of %d, %d skipped, %v", len(kept), len(items), n, err)
+	}
+
+	for _, bad := range []string{"12", "12_x", "12_9-4", "a_1"} {
+		if _, _, err := batch.SkipItems(items, []string{"0_0", bad}); err == nil {
+			t.Errorf("key %q: no error", bad)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package batch
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// ModelIndex lists the .bmd files under an item directory, for suggesting
+// the intended model when an ItemList entry names one that doesn't exist.
+type ModelIndex struct {
+	paths []string // relative to the item directory, slash-separated
+	stems []string // lowercased file names without extension, parallel to paths
+}
+
+// IndexModels walks itemDir once and records every .bmd file in it.
+func IndexModels(itemDir string) *ModelIndex {
+	mi := &ModelIndex{}
+	filepath.WalkDir(itemDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".bmd") {
+			return nil
+		}
+		rel, err := filepath.Rel(itemDir, p)
+		if err != nil {
+			return nil
+		}
+		mi.paths = append(mi.paths, filepath.ToSlash(rel))
+		mi.stems = append(mi.stems, modelStem(p))
+		return nil
+	})
+	return mi
+}
+
+// Suggest returns the indexed model closest to subDir/modelFile: the
+// smallest edit distance between lowercased file stems, preferring a model
+// in subDir on ties. Matches more than a third of the stem's length away
+// (at least 2 edits allowed) are not suggested. The result is relative to
+// the item directory, e.g. "Jewel/Jewel01.bmd".
+func (mi *ModelIndex) Suggest(subDir, modelFile string) (string, bool) {
+	want := modelStem(modelFile)
+	dir := strings.ToLower(filepath.ToSlash(subDir))
+	limit := max(2, len(want)/3)
+
+	best, bestDist, bestHere := -1, limit+1, false
+	for i, stem := range mi.stems {
+		d := levenshtein(want, stem)
+		here := strings.EqualFold(strings.TrimPrefix(path.Dir(mi.paths[i]), "."), dir)
+		if d < bestDist || (d == bestDist && here && !bestHere) {
+			best, bestDist, bestHere = i, d, here
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return mi.paths[best], true
+}
+
+// suggestModels sets Suggestion on each missing-BMD result and appends it to
+// the error. The item directory is only indexed if some model is missing.
+func suggestModels(itemDir string, items []itemlist.ItemDef, results []Result) {
+	var mi *ModelIndex
+	for i := range results {
+		r := &results[i]
+		if r.Failure != FailMissingBMD {
+			continue
+		}
+		if mi == nil {
+			mi = IndexModels(itemDir)
+		}
+		if s, ok := mi.Suggest(items[i].SubDir, items[i].ModelFile); ok {
+			r.Suggestion = s
+			r.Error += " (did you mean " + s + "?)"
+		}
+	}
+}
+
+func modelStem(name string) string {
+	base := filepath.Base(filepath.ToSlash(name))
+	return strings.ToLower(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// levenshtein returns the edit distance between a and b (bytes; model
+// names are ASCII).
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
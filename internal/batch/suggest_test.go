@@ -0,0 +1,70 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/batch"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+func TestSuggest(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{"Sword01.bmd", "Wing511_core1.bmd", "Jewel/Jewel01.bmd", "Jewel/Sword01.bmd", "Helm01.BMD", "notes.txt"} {
+		p = filepath.Join(dir, filepath.FromSlash(p))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mi := batch.IndexModels(dir)
+
+	for _, c := range []struct {
+		subDir, file string
+		want         string // "" = no suggestion
+	}{
+		{"", "Wing511_cor1.bmd", "Wing511_core1.bmd"},
+		{"", "wing511_CORE1.bmd", "Wing511_core1.bmd"}, // case only
+		{"", "Jewl01.bmd", "Jewel/Jewel01.bmd"},
+		{"Jewel", "Sword1.bmd", "Jewel/Sword01.bmd"}, // tie: the item's own directory wins
+		{"", "Sword1.bmd", "Sword01.bmd"},
+		{"", "Helm02.bmd", "Helm01.BMD"},
+		{"", "Shield77.bmd", ""},
+		{"", "notes.bmd", ""}, // only .bmd files are indexed
+	} {
+		got, ok := mi.Suggest(c.subDir, c.file)
+		if got != c.want || ok != (c.want != "") {
+			t.Errorf("Suggest(%q, %q) = %q, %v; want %q", c.subDir, c.file, got, ok, c.want)
+		}
+	}
+}
+
+// TestRunSuggestsModel runs the fixture's items with missing models: the
+// misplaced and misspelled ones report the existing model, the other none.
+func TestRunSuggestsModel(t *testing.T) {
+	cfg, items := newFixture(t)
+	want := map[string]string{} // model file → suggestion
+	var missing []itemlist.ItemDef
+	for _, it := range items {
+		for _, f := range fixtureItems {
+			if f.section == it.Section && f.index == it.Index && !f.render {
+				want[it.ModelFile] = f.suggest
+				missing = append(missing, it)
+			}
+		}
+	}
+	if len(missing) != 3 {
+		t.Fatalf("%d fixture items with missing models, want 3", len(missing))
+	}
+	for i, r := range batch.Run(cfg, missing) {
+		w := want[missing[i].ModelFile]
+		if r.Failure != batch.FailMissingBMD || r.Suggestion != w {
+			t.Errorf("%s: failure %v, suggestion %q; want a missing BMD suggesting %q", missing[i].ModelFile, r.Failure, r.Suggestion, w)
+		}
+		if hint := strings.Contains(r.Error, "did you mean"); hint != (w != "") {
+			t.Errorf("%s: error %q, want the hint only with a suggestion", missing[i].ModelFile, r.Error)
+		}
+	}
+}
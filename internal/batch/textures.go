@@ -0,0 +1,62 @@
+package batch
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// textureRefs returns the distinct texture references of meshes, lowercased
+// and sorted.
+func textureRefs(meshes []bmd.Mesh) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, m := range meshes {
+		n := strings.ToLower(m.TexPath)
+		if n != "" && !seen[n] {
+			seen[n] = true
+			refs = append(refs, n)
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// forEachModel parses the distinct model files of items on workers
+// goroutines and calls fn, concurrently, with each one that parses. Items
+// often share a model file; each is parsed once.
+func forEachModel(itemDir string, items []itemlist.ItemDef, workers int, fn func(path string, meshes []bmd.Mesh)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	paths := make(map[string]bool)
+	for _, it := range items {
+		paths[filepath.Join(itemDir, it.SubDir, it.ModelFile)] = true
+	}
+
+	ch := make(chan string, workers*2)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range ch {
+				meshes, _, err := bmd.Parse(p)
+				if err != nil {
+					continue
+				}
+				fn(p, meshes)
+			}
+		}()
+	}
+	for p := range paths {
+		ch <- p
+	}
+	close(ch)
+	wg.Wait()
+}
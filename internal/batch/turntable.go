@@ -0,0 +1,78 @@
+package batch
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/trs"
+	"mu-bmd-renderer/internal/viewmatrix"
+)
+
+// renderTurntable renders cfg.TurntableFrames views of meshes at renderW×
+// renderH, the item turned a further 360/n degrees about rotY each frame.
+// The frames share one scale, the largest at which every angle fits, so the
+// item keeps its size from frame to frame: a first pass finds each angle's
+// auto-fit scale, a second renders them all at the smallest. meshes are
+// left as they are.
+func renderTurntable(cfg Config, meshes []bmd.Mesh, bones []bmd.Bone, entry *trs.Entry, renderW, renderH int, t *itemTimings) []*image.NRGBA {
+	entries := turntableEntries(entry, cfg.TurntableFrames)
+	if !entries[0].AbsoluteScale || entries[0].Scale <= 0 {
+		t0 := time.Now()
+		fit := math.Inf(1)
+		for _, e := range entries {
+			_, stats := raster.RenderBMDWithStats(bmd.CloneMeshes(meshes), bones, e, cfg.TexResolver, renderW, renderH, cfg.Supersample, cfg.Render)
+			if stats.Scale > 0 {
+				fit = min(fit, stats.Scale)
+			}
+		}
+		t.render += time.Since(t0)
+		if !math.IsInf(fit, 1) {
+			// Absolute scale is a fraction of the (supersampled) canvas's
+			// shorter side per model unit
+			scale := fit / float64(min(renderW, renderH)*cfg.Supersample)
+			for _, e := range entries {
+				e.AbsoluteScale, e.Scale = true, scale
+			}
+		}
+	}
+
+	frames := make([]*image.NRGBA, len(entries))
+	for i, e := range entries {
+		frames[i] = renderAt(cfg, bmd.CloneMeshes(meshes), bones, e, renderW, renderH, t).img
+	}
+	return frames
+}
+
+// turntableEntries returns n copies of entry (defaults for nil) with rotY
+// stepped through a full turn. Everything that would otherwise change with
+// the angle is pinned to frame 0's choice: the camera path (an item on
+// VIEW_FALLBACK, which ignores rotation, turns on the noflip camera) and bone
+// use. Standardization is off and the pivot defaults to the centroid, so the
+// item spins in place instead of being re-aligned and re-centered per frame.
+func turntableEntries(entry *trs.Entry, n int) []*trs.Entry {
+	base := trs.Entry{DisplayAngle: trs.DefaultDisplayAngle, FillRatio: trs.DefaultFillRatio}
+	if entry != nil {
+		base = *entry
+	}
+	base.Camera = "noflip"
+	if entry != nil && viewmatrix.CameraPath(entry) != "fallback" {
+		base.Camera = viewmatrix.CameraPath(entry)
+	}
+	useBones := viewmatrix.ShouldUseBones(entry)
+	standardize := false
+	base.UseBones, base.Standardize = &useBones, &standardize
+	if base.Pivot == "" {
+		base.Pivot = "centroid"
+	}
+
+	entries := make([]*trs.Entry, n)
+	for i := range entries {
+		e := base
+		e.RotY += 360 * float64(i) / float64(n)
+		entries[i] = &e
+	}
+	return entries
+}
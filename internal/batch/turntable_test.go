@@ -0,0 +1,44 @@
+package batch
+
+import (
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestTurntableEntries steps rotY evenly through a full turn from the
+// entry's own, keeps everything else the same in every frame (scale,
+// pivot, camera, bone use) and leaves the entry itself alone.
+func TestTurntableEntries(t *testing.T) {
+	entry := &trs.Entry{RotX: 270, RotY: 30, Scale: 1.5, DisplayAngle: trs.DefaultDisplayAngle, FillRatio: trs.DefaultFillRatio}
+	before := *entry
+	entries := turntableEntries(entry, 8)
+	if !reflect.DeepEqual(*entry, before) {
+		t.Errorf("entry changed to %+v", *entry)
+	}
+	if len(entries) != 8 {
+		t.Fatalf("%d entries, want 8", len(entries))
+	}
+	for i, e := range entries {
+		if want := 30 + 45*float64(i); e.RotY != want {
+			t.Errorf("frame %d: rotY %v, want %v", i, e.RotY, want)
+		}
+		if e == entry {
+			t.Errorf("frame %d is the entry itself", i)
+		}
+		if e.Scale != 1.5 || e.Pivot != "centroid" || e.Standardize == nil || *e.Standardize {
+			t.Errorf("frame %d: scale %v, pivot %q, standardize %v", i, e.Scale, e.Pivot, e.Standardize)
+		}
+		first := entries[0]
+		if e.Camera != first.Camera || *e.UseBones != *first.UseBones || e.RotX != first.RotX {
+			t.Errorf("frame %d: camera %q, bones %v, rotX %v; frame 0 has %q, %v, %v",
+				i, e.Camera, *e.UseBones, e.RotX, first.Camera, *first.UseBones, first.RotX)
+		}
+	}
+
+	// Without an entry the frames start from the defaults
+	if entries := turntableEntries(nil, 3); len(entries) != 3 || entries[2].RotY != 240 || entries[0].FillRatio != trs.DefaultFillRatio {
+		t.Errorf("nil entry: %+v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+package batch
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// itemTimings holds per-phase durations for one item (verbose mode).
+type itemTimings struct {
+	parse, render, post, encode time.Duration
+}
+
+// formatItemLog builds the verbose per-item report as one string, so lines
+// from concurrent workers don't interleave.
+func formatItemLog(item itemlist.ItemDef, entry *trs.Entry, meshCount int, stats *raster.RenderStats, img *image.NRGBA, t itemTimings) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %d_%d %s (%s, %s): %d meshes\n", item.Section, item.Index, item.Name, item.ModelFile, item.Category, meshCount)
+
+	if entry == nil {
+		sb.WriteString("    trs: none (defaults)\n")
+	} else {
+		angle := fmt.Sprintf("%.1f", entry.DisplayAngle)
+		if entry.AutoDisplayAngle {
+			angle = "auto(" + angle + ")"
+		}
+		fmt.Fprintf(&sb, "    trs: rot=(%.1f, %.1f, %.1f) scale=%g camera=%q display_angle=%s fill=%.2f\n",
+			entry.RotX, entry.RotY, entry.RotZ, entry.Scale, entry.Camera, angle, entry.FillRatio)
+	}
+
+	if stats != nil {
+		for _, m := range stats.Filtered {
+			fmt.Fprintf(&sb, "    filtered %-16s %s (%dv/%dt)\n", m.Reason, m.TexPath, m.Verts, m.Tris)
+		}
+		for _, m := range stats.Rendered {
+			fmt.Fprintf(&sb, "    pass     %-16s %s (%dv/%dt)\n", m.Pass, m.TexPath, m.Verts, m.Tris)
+		}
+		if stats.Promoted {
+			sb.WriteString("    no opaque mesh: promoted first additive/alpha mesh to opaque\n")
+		}
+		cam := "ortho"
+		if stats.PosCamera {
+			cam = "positioned (cam_height)"
+		}
+		fmt.Fprintf(&sb, "    camera: %s bones=%v span=%.1fx%.1f scale=%.3f\n",
+			cam, stats.UseBones, stats.Span[0], stats.Span[1], stats.Scale)
+	}
+
+	fmt.Fprintf(&sb, "    coverage: %.1f%%\n", coverage(img)*100)
+	fmt.Fprintf(&sb, "    time: parse %v, render %v, post %v, encode %v\n",
+		t.parse.Round(time.Microsecond), t.render.Round(time.Microsecond),
+		t.post.Round(time.Microsecond), t.encode.Round(time.Microsecond))
+	return sb.String()
+}
+
+// coverage returns the fraction of non-transparent pixels.
+func coverage(img *image.NRGBA) float64 {
+	b := img.Bounds()
+	total := b.Dx() * b.Dy()
+	if total == 0 {
+		return 0
+	}
+	n := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 0 {
+			n++
+		}
+	}
+	return float64(n) / float64(total)
+}
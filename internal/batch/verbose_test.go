@@ -0,0 +1,57 @@
+package batch
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestFormatItemLog checks the verbose report of one item: a line per
+// filtered mesh with its reason, a line per rendered mesh with its pass,
+// then the camera, coverage and timings.
+func TestFormatItemLog(t *testing.T) {
+	item := itemlist.ItemDef{Section: 7, Index: 2, Name: "Pad Helm", ModelFile: "HelmMale03.bmd", Category: "helm"}
+	stats := &raster.RenderStats{
+		Filtered: []raster.MeshStat{
+			{TexPath: "cape.jpg", Verts: 28, Tris: 26, Reason: "hide_meshes"},
+			{TexPath: "glow01.jpg", Verts: 4, Tris: 2, Reason: "effect"},
+			{TexPath: "skinclass301.jpg", Verts: 40, Tris: 38, Reason: "body"},
+		},
+		Rendered: []raster.MeshStat{{TexPath: "helm.tga", Verts: 28, Tris: 26, Pass: "opaque"}},
+		Promoted: true,
+		UseBones: true,
+		Scale:    2.5,
+		Span:     [2]float64{40, 60},
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := 3; i < 16; i += 4 {
+		img.Pix[i] = 255 // first row: 25% coverage
+	}
+	entry := &trs.Entry{RotX: 270, Scale: 1, DisplayAngle: -45, AutoDisplayAngle: true, FillRatio: 0.7}
+	got := formatItemLog(item, entry, 4, stats, img, itemTimings{parse: time.Millisecond})
+
+	want := []string{
+		"  7_2 Pad Helm (HelmMale03.bmd, helm): 4 meshes",
+		`    trs: rot=(270.0, 0.0, 0.0) scale=1 camera="" display_angle=auto(-45.0) fill=0.70`,
+		"    filtered hide_meshes      cape.jpg (28v/26t)",
+		"    filtered effect           glow01.jpg (4v/2t)",
+		"    filtered body             skinclass301.jpg (40v/38t)",
+		"    pass     opaque           helm.tga (28v/26t)",
+		"    no opaque mesh: promoted first additive/alpha mesh to opaque",
+		"    camera: ortho bones=true span=40.0x60.0 scale=2.500",
+		"    coverage: 25.0%",
+		"    time: parse 1ms, render 0s, post 0s, encode 0s",
+	}
+	if w := strings.Join(want, "\n") + "\n"; got != w {
+		t.Errorf("got:\n%swant:\n%s", got, w)
+	}
+
+	if got := formatItemLog(item, nil, 0, nil, img, itemTimings{}); !strings.Contains(got, "trs: none (defaults)") || strings.Contains(got, "filtered") {
+		t.Errorf("no entry or stats:\n%s", got)
+	}
+}
@@ -0,0 +1,22 @@
+package bmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned (wrapped) by Parse when the BMD file does not exist.
+var ErrNotFound = errors.New("bmd: not found")
+
+// ParseError reports a malformed BMD file. Offset is the byte position where
+// the problem was detected: in the file for header errors, in the decrypted
+// payload for everything after it.
+type ParseError struct {
+	Path   string
+	Offset int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("bmd: %s at offset %d in %s", e.Reason, e.Offset, e.Path)
+}
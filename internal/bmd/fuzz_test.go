@@ -0,0 +1,49 @@
+package bmd_test
+
+import (
+	"errors"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/raster"
+	"mu-bmd-renderer/internal/texture"
+)
+
+// FuzzParse feeds mutated models to bmd.ParseBytes, which must give meshes
+// or a *ParseError for any input and never panic. Models that parse are
+// also rendered, small and untextured: the batch workers recover from a
+// panic per item, but a corrupt model should fail as a parse error before
+// it gets that far. The seeds in testdata/fuzz/FuzzParse are one small box
+// model in plain (version 10), XOR (12) and LEA (15) form, a plain one with
+// a dummy bone and several actions, and an empty model claiming 65535 bones
+// and actions, which once took seconds to parse.
+//
+//	go test -fuzz=FuzzParse ./internal/bmd
+func FuzzParse(f *testing.F) {
+	f.Add([]byte("BMD\x0a"))
+	noTextures := texture.NewCache(texture.BuildIndex(f.TempDir()))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		orig := string(data)
+		meshes, bones, err := bmd.ParseBytes("fuzz.bmd", data)
+		if string(data) != orig {
+			t.Fatal("ParseBytes modified its input")
+		}
+		if err != nil {
+			var pe *bmd.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error %v is not a *ParseError", err)
+			}
+			return
+		}
+		for i, m := range meshes {
+			if len(m.Nodes) != len(m.Verts) {
+				t.Fatalf("mesh %d: %d nodes for %d vertices", i, len(m.Nodes), len(m.Verts))
+			}
+		}
+		for i := range bones {
+			bones[i].Pose(0, 0)
+			bones[i].Frames(0)
+		}
+		raster.RenderBMD(meshes, bones, nil, noTextures, 32, 32, 1, raster.Options{})
+	})
+}
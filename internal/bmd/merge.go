@@ -0,0 +1,81 @@
+package bmd
+
+// MergeMeshesByTexture concatenates meshes that share a texture path and
+// material slot (TexIndex) into one mesh per pair, placed at the position of
+// the pair's first mesh. Callers merge only meshes drawn in the same blend
+// pass; the texture alone doesn't decide how a mesh is classified.
+// Triangle vertex/normal/texcoord indices are offset into the combined arrays,
+// and Nodes stay per-vertex, so bone skinning survives the merge.
+// Index arrays are int16: a merge that would overflow them starts a new mesh.
+func MergeMeshesByTexture(meshes []Mesh) []Mesh {
+	var out []Mesh
+	type key struct {
+		tex  string
+		slot int16
+	}
+	byTex := make(map[key]int) // texture path and slot → index in out
+	for _, m := range meshes {
+		k := key{m.TexPath, m.TexIndex}
+		i, ok := byTex[k]
+		if ok && !fitsInt16(&out[i], &m) {
+			ok = false
+		}
+		if !ok {
+			byTex[k] = len(out)
+			out = append(out, cloneMesh(&m))
+			continue
+		}
+		appendMesh(&out[i], &m)
+	}
+	return out
+}
+
+func fitsInt16(dst, src *Mesh) bool {
+	const max = 1<<15 - 1
+	return len(dst.Verts)+len(src.Verts) <= max &&
+		len(dst.Normals)+len(src.Normals) <= max &&
+		len(dst.UVs)+len(src.UVs) <= max
+}
+
+// cloneMesh copies m's slices so appending to the result never writes
+// into the source mesh's backing arrays.
+func cloneMesh(m *Mesh) Mesh {
+	return Mesh{
+		Verts:    append([][3]float32(nil), m.Verts...),
+		Nodes:    append([]int16(nil), m.Nodes...),
+		Normals:  append([][3]float32(nil), m.Normals...),
+		UVs:      append([][2]float32(nil), m.UVs...),
+		Tris:     append([]Triangle(nil), m.Tris...),
+		TexPath:  m.TexPath,
+		TexIndex: m.TexIndex,
+	}
+}
+
+// CloneMeshes deep-copies meshes, so the same parsed model can be rendered
+// more than once (bone transforms move vertices in place).
+func CloneMeshes(meshes []Mesh) []Mesh {
+	out := make([]Mesh, len(meshes))
+	for i := range meshes {
+		out[i] = cloneMesh(&meshes[i])
+	}
+	return out
+}
+
+func appendMesh(dst, src *Mesh) {
+	vOff := int16(len(dst.Verts))
+	nOff := int16(len(dst.Normals))
+	tOff := int16(len(dst.UVs))
+
+	dst.Verts = append(dst.Verts, src.Verts...)
+	dst.Nodes = append(dst.Nodes, src.Nodes...)
+	dst.Normals = append(dst.Normals, src.Normals...)
+	dst.UVs = append(dst.UVs, src.UVs...)
+	for _, t := range src.Tris {
+		for k := 0; k < 4; k++ {
+			t.VI[k] += vOff
+			t.NI[k] += nOff
+			t.TI[k] += tOff
+		}
+		dst.Tris = append(dst.Tris, t)
+	}
+}
@@ -0,0 +1,70 @@
+package bmd
+
+import "testing"
+
+// strip returns a mesh of n quads in a row along x, starting at x0.
+func strip(x0 float32, n int, tex string, slot int16) Mesh {
+	m := Mesh{TexPath: tex, TexIndex: slot, UVs: [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}
+	for i := 0; i <= n; i++ {
+		x := x0 + float32(i)
+		m.Verts = append(m.Verts, [3]float32{x, 0, 0}, [3]float32{x, 1, 0})
+		m.Normals = append(m.Normals, [3]float32{0, 0, 1}, [3]float32{0, 0, 1})
+		m.Nodes = append(m.Nodes, int16(i%2), int16(i%2))
+	}
+	for i := 0; i < n; i++ {
+		a := int16(2 * i)
+		m.Tris = append(m.Tris, Triangle{Polygon: 4, VI: [4]int16{a, a + 2, a + 3, a + 1}, NI: [4]int16{a, a + 2, a + 3, a + 1}, TI: [4]int16{0, 1, 2, 3}})
+	}
+	return m
+}
+
+func TestMergeMeshesByTexture(t *testing.T) {
+	meshes := []Mesh{
+		strip(0, 2, "blade.jpg", 0),
+		strip(10, 3, "hilt.tga", 0),
+		strip(20, 1, "blade.jpg", 0),
+		strip(30, 1, "blade.jpg", 1), // another material slot: kept apart
+	}
+	orig := CloneMeshes(meshes)
+	got := MergeMeshesByTexture(meshes)
+
+	if len(got) != 3 {
+		t.Fatalf("%d meshes, want 3", len(got))
+	}
+	m := got[0]
+	if m.TexPath != "blade.jpg" || m.TexIndex != 0 || len(m.Verts) != 6+4 || len(m.Nodes) != 10 || len(m.Normals) != 10 || len(m.UVs) != 8 || len(m.Tris) != 3 {
+		t.Fatalf("merged mesh: %s slot %d, %d verts %d nodes %d normals %d UVs %d tris; want blade.jpg slot 0, 10 10 10 8 3",
+			m.TexPath, m.TexIndex, len(m.Verts), len(m.Nodes), len(m.Normals), len(m.UVs), len(m.Tris))
+	}
+	if got[1].TexPath != "hilt.tga" || got[2].TexIndex != 1 {
+		t.Errorf("order: %s, slot %d; want hilt.tga second, slot 1 third", got[1].TexPath, got[2].TexIndex)
+	}
+	// The appended triangle still addresses the second strip's corners
+	tri := m.Tris[2]
+	for k := 0; k < 4; k++ {
+		want := orig[2].Verts[orig[2].Tris[0].VI[k]]
+		if v := m.Verts[tri.VI[k]]; v != want {
+			t.Errorf("corner %d: vertex %v, want %v", k, v, want)
+		}
+		if uv := m.UVs[tri.TI[k]]; uv != orig[2].UVs[k] {
+			t.Errorf("corner %d: UV %v, want %v", k, uv, orig[2].UVs[k])
+		}
+	}
+	for i := range meshes {
+		if len(meshes[i].Verts) != len(orig[i].Verts) || meshes[i].Tris[0] != orig[i].Tris[0] {
+			t.Errorf("source mesh %d modified", i)
+		}
+	}
+}
+
+func TestMergeMeshesInt16Limit(t *testing.T) {
+	// 20002 vertices each: the second starts a new mesh, which the third joins
+	got := MergeMeshesByTexture([]Mesh{strip(0, 10000, "a.jpg", 0), strip(0, 10000, "a.jpg", 0), strip(0, 1, "a.jpg", 0)})
+	if len(got) != 2 || len(got[0].Verts) != 20002 || len(got[1].Verts) != 20002+4 {
+		var n []int
+		for _, m := range got {
+			n = append(n, len(m.Verts))
+		}
+		t.Errorf("merged vertex counts %v, want [20002 20006]", n)
+	}
+}
@@ -2,24 +2,49 @@ package bmd
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"math"
-	"os"
 	"strings"
 
 	"mu-bmd-renderer/internal/crypto"
+	"mu-bmd-renderer/internal/mmap"
 )
 
 // Parse reads a BMD file and returns meshes and bones.
 // Supports versions 10 (unencrypted), 12 (XOR), 14 (ModulusCryptor), and 15 (LEA-256 ECB).
 func Parse(filepath string) ([]Mesh, []Bone, error) {
-	raw, err := os.ReadFile(filepath)
-	if err != nil {
+	raw, release, err := mmap.ReadFile(filepath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotFound, filepath)
+	} else if err != nil {
 		return nil, nil, fmt.Errorf("bmd: read %s: %w", filepath, err)
 	}
+	// raw may be a read-only mapping: the decrypt paths write to fresh
+	// buffers and the reader copies everything it keeps
+	defer release()
+	return ParseBytes(filepath, raw)
+}
+
+// ParseReader parses a BMD file read to the end from r, e.g. a model packed
+// in an archive; name only identifies it in errors. Parse maps files
+// instead, so use it for models on disk.
+func ParseReader(r io.Reader, name string) ([]Mesh, []Bone, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bmd: read %s: %w", name, err)
+	}
+	return ParseBytes(name, raw)
+}
 
+// ParseBytes parses a BMD file already in memory; filepath only names it in
+// errors. It doesn't modify or keep raw. Any input gives meshes or an error,
+// never a panic (FuzzParse checks this).
+func ParseBytes(filepath string, raw []byte) ([]Mesh, []Bone, error) {
 	if len(raw) < 4 || string(raw[:3]) != "BMD" {
-		return nil, nil, fmt.Errorf("bmd: invalid header in %s", filepath)
+		return nil, nil, &ParseError{Path: filepath, Offset: 0, Reason: "invalid header"}
 	}
 
 	version := raw[3]
@@ -28,29 +53,32 @@ func Parse(filepath string) ([]Mesh, []Bone, error) {
 	switch version {
 	case 15:
 		if len(raw) < 8 {
-			return nil, nil, fmt.Errorf("bmd: truncated v15 header in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 4, Reason: "truncated v15 header"}
 		}
 		size := binary.LittleEndian.Uint32(raw[4:8])
 		if 8+int(size) > len(raw) {
-			return nil, nil, fmt.Errorf("bmd: truncated v15 data in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 8, Reason: fmt.Sprintf("truncated v15 data (%d bytes declared)", size)}
+		}
+		if size%16 != 0 {
+			return nil, nil, &ParseError{Path: filepath, Offset: 4, Reason: fmt.Sprintf("v15 data size %d is not whole 16-byte blocks", size)}
 		}
 		data = crypto.DecryptLEA(raw[8:8+size], crypto.LEAKey)
 	case 14:
 		if len(raw) < 8 {
-			return nil, nil, fmt.Errorf("bmd: truncated v14 header in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 4, Reason: "truncated v14 header"}
 		}
 		size := binary.LittleEndian.Uint32(raw[4:8])
 		if 8+int(size) > len(raw) {
-			return nil, nil, fmt.Errorf("bmd: truncated v14 data in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 8, Reason: fmt.Sprintf("truncated v14 data (%d bytes declared)", size)}
 		}
 		data = crypto.DecryptModulus(raw[8 : 8+size])
 	case 12:
 		if len(raw) < 8 {
-			return nil, nil, fmt.Errorf("bmd: truncated v12 header in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 4, Reason: "truncated v12 header"}
 		}
 		size := binary.LittleEndian.Uint32(raw[4:8])
 		if 8+int(size) > len(raw) {
-			return nil, nil, fmt.Errorf("bmd: truncated v12 data in %s", filepath)
+			return nil, nil, &ParseError{Path: filepath, Offset: 8, Reason: fmt.Sprintf("truncated v12 data (%d bytes declared)", size)}
 		}
 		data = crypto.DecryptXOR(raw[8 : 8+size])
 	default:
@@ -121,23 +149,41 @@ func (r *reader) readByte() byte {
 	return b
 }
 
+// remaining returns the number of unread bytes.
+func (r *reader) remaining() int {
+	return max(len(r.data)-r.off, 0)
+}
+
+// parse reads the decrypted payload. Counts that can't be right (negative,
+// or a vertex, normal or UV block longer than what is left of the data) are
+// a ParseError, so a corrupt header fails instead of allocating for it; a
+// short final block is still read as zeros, as before.
 func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 	_ = r.readStr(32) // model name
+	countOff := r.off
 	meshCount := int(r.readU16())
 	boneCount := int(r.readU16())
 	actionCount := int(r.readU16())
 
 	if meshCount > 100 || meshCount < 0 {
-		return nil, nil, fmt.Errorf("bmd: invalid mesh count %d in %s", meshCount, filepath)
+		return nil, nil, &ParseError{Path: filepath, Offset: countOff, Reason: fmt.Sprintf("invalid mesh count %d", meshCount)}
 	}
 
 	meshes := make([]Mesh, 0, meshCount)
 	for i := 0; i < meshCount; i++ {
+		headerOff := r.off
 		nv := int(r.readI16())
 		nn := int(r.readI16())
 		ntc := int(r.readI16())
 		nt := int(r.readI16())
-		_ = r.readI16() // texture index
+		texIndex := r.readI16()
+
+		if nv < 0 || nn < 0 || ntc < 0 || nt < 0 {
+			return nil, nil, &ParseError{Path: filepath, Offset: headerOff, Reason: fmt.Sprintf("mesh %d: negative count (%d verts, %d normals, %d UVs, %d triangles)", i, nv, nn, ntc, nt)}
+		}
+		if need := nv*16 + nn*20 + ntc*8; need > r.remaining() {
+			return nil, nil, &ParseError{Path: filepath, Offset: headerOff, Reason: fmt.Sprintf("mesh %d: %d verts, %d normals, %d UVs need %d bytes, %d left", i, nv, nn, ntc, need, r.remaining())}
+		}
 
 		// Vertices: 16 bytes each (node:i16, pad:i16, x:f32, y:f32, z:f32)
 		verts := make([][3]float32, nv)
@@ -150,7 +196,10 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 			verts[j][2] = r.readF32()
 		}
 
-		// Normals: 20 bytes each (node:i16, pad:i16, nx:f32, ny:f32, nz:f32, bind:i16, pad:i16)
+		// Normals: 20 bytes each (node:i16, pad:i16, nx:f32, ny:f32, nz:f32, bind:i16, pad:i16).
+		// bind is the index of the vertex the normal belongs to, not a second
+		// bone: no record in any version carries bone weights, so every
+		// vertex follows exactly its one node (see skeleton.ApplyTransforms).
 		normals := make([][3]float32, nn)
 		for j := 0; j < nn; j++ {
 			_ = r.readI16() // node
@@ -169,7 +218,11 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 			uvs[j][1] = r.readF32()
 		}
 
-		// Triangles: 64 bytes each
+		// Triangles: 64 bytes each (poly:u8, pad:u8, vi/ni/ti:4×i16 each, pad:u16,
+		// lightmapUV:4×(f32,f32), lightmapIndex:i16, pad:u16). The lightmap
+		// fields are unused by item models and are skipped. No part of the format
+		// (vertex, normal, or triangle records, any version) carries vertex
+		// colors: shading comes from textures and the renderer's lighting only.
 		tris := make([]Triangle, nt)
 		for j := 0; j < nt; j++ {
 			base := r.off
@@ -196,19 +249,24 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 		texPath = strings.ReplaceAll(texPath, "\\", "/")
 
 		meshes = append(meshes, Mesh{
-			Verts:   verts,
-			Nodes:   nodes,
-			Normals: normals,
-			UVs:     uvs,
-			Tris:    tris,
-			TexPath: texPath,
+			Verts:    verts,
+			Nodes:    nodes,
+			Normals:  normals,
+			UVs:      uvs,
+			Tris:     tris,
+			TexPath:  texPath,
+			TexIndex: texIndex,
 		})
 	}
 
 	// Parse actions
 	actionKeys := make([]int, actionCount)
 	for a := 0; a < int(actionCount); a++ {
+		keysOff := r.off
 		numKeys := int(r.readI16())
+		if numKeys < 0 {
+			return nil, nil, &ParseError{Path: filepath, Offset: keysOff, Reason: fmt.Sprintf("action %d: negative key count %d", a, numKeys)}
+		}
 		lockPos := r.readByte() > 0
 		if lockPos {
 			r.off += numKeys * 12 // skip float32 x,y,z per key
@@ -216,6 +274,12 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 		actionKeys[a] = numKeys
 	}
 
+	// Where each action's keys start in a bone's keyframes; shared by all bones
+	keyStart := make([]int, actionCount+1)
+	for a, n := range actionKeys {
+		keyStart[a+1] = keyStart[a] + n
+	}
+
 	// Parse bones
 	bones := make([]Bone, 0, boneCount)
 	for b := 0; b < int(boneCount); b++ {
@@ -225,15 +289,25 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 			continue
 		}
 
-		_ = r.readStr(32) // bone name
+		name := r.readStr(32)
 		parent := int(r.readI16())
 
 		var bindPos, bindRot [3]float64
+		// Keep every keyframe only if the file holds them all, so a corrupt
+		// key count can't allocate more than the file's size
+		var positions, rotations [][3]float64
+		if keyBytes := keyStart[len(keyStart)-1] * 24; keyBytes <= r.remaining() {
+			positions = make([][3]float64, 0, keyStart[len(keyStart)-1])
+			rotations = make([][3]float64, 0, keyStart[len(keyStart)-1])
+		}
 		for a := 0; a < int(actionCount); a++ {
 			numKeys := 0
 			if a < len(actionKeys) {
 				numKeys = actionKeys[a]
 			}
+			if r.off >= len(r.data) {
+				break // past the end every key reads as zero: the bind pose stays zero
+			}
 			if numKeys == 0 {
 				continue
 			}
@@ -245,6 +319,9 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 				if a == 0 && k == 0 {
 					bindPos = [3]float64{px, py, pz}
 				}
+				if positions != nil {
+					positions = append(positions, [3]float64{px, py, pz})
+				}
 			}
 			// Rotations: numKeys × (rx, ry, rz) float32
 			for k := 0; k < numKeys; k++ {
@@ -254,14 +331,21 @@ func (r *reader) parse(filepath string) ([]Mesh, []Bone, error) {
 				if a == 0 && k == 0 {
 					bindRot = [3]float64{rx, ry, rz}
 				}
+				if rotations != nil {
+					rotations = append(rotations, [3]float64{rx, ry, rz})
+				}
 			}
 		}
 
 		bones = append(bones, Bone{
+			Name:         name,
 			Parent:       parent,
 			IsDummy:      false,
 			BindPosition: bindPos,
 			BindRotation: bindRot,
+			Positions:    positions,
+			Rotations:    rotations,
+			KeyStart:     keyStart,
 		})
 	}
 
@@ -0,0 +1,308 @@
+package bmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// testMesh is one mesh of a plain (version 10) model built by encodeV10.
+// lightmap fills each triangle's lightmap fields, which the parser skips.
+type testMesh struct {
+	nv, nn, ntc, nt int16 // counts as written, so tests can corrupt them
+	texIndex        int16
+	verts           [][3]float32
+	nodes           []int16
+	normals         [][3]float32
+	uvs             [][2]float32
+	tris            []Triangle
+	tex             string
+	lightmap        byte
+}
+
+// newTestMesh returns a mesh of two triangles (a quad split in two) with
+// consistent counts.
+func newTestMesh(tex string) testMesh {
+	return testMesh{
+		nv: 4, nn: 4, ntc: 4, nt: 2,
+		verts:    [][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0.5}},
+		nodes:    []int16{0, 0, 1, 1},
+		normals:  [][3]float32{{0, 0, 1}, {0, 0, 1}, {0, 0.6, 0.8}, {0, 0.6, 0.8}},
+		uvs:      [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		tris:     []Triangle{{Polygon: 3, VI: [4]int16{0, 1, 2}, NI: [4]int16{0, 1, 2}, TI: [4]int16{0, 1, 2}}, {Polygon: 3, VI: [4]int16{0, 2, 3}, NI: [4]int16{0, 2, 3}, TI: [4]int16{0, 2, 3}}},
+		tex:      tex,
+		lightmap: 0xAB,
+	}
+}
+
+// encodeV10 writes meshes as an unencrypted model with no bones.
+func encodeV10(meshes ...testMesh) []byte {
+	le := binary.LittleEndian
+	f32 := func(b []byte, v float32) []byte { return le.AppendUint32(b, math.Float32bits(v)) }
+	i16 := func(b []byte, v int16) []byte { return le.AppendUint16(b, uint16(v)) }
+	str := func(b []byte, s string) []byte { return append(b, []byte(s+strings.Repeat("\x00", 32-len(s)))...) }
+
+	b := str([]byte("BMD\x0a"), "Test")
+	b = i16(i16(i16(b, int16(len(meshes))), 0), 0)
+	for _, m := range meshes {
+		for _, n := range []int16{m.nv, m.nn, m.ntc, m.nt, m.texIndex} {
+			b = i16(b, n)
+		}
+		for j, v := range m.verts {
+			b = i16(i16(b, m.nodes[j]), 0)
+			b = f32(f32(f32(b, v[0]), v[1]), v[2])
+		}
+		for j, n := range m.normals {
+			b = i16(i16(b, m.nodes[j]), 0)
+			b = f32(f32(f32(b, n[0]), n[1]), n[2])
+			b = i16(i16(b, int16(j)), 0) // bind vertex
+		}
+		for _, uv := range m.uvs {
+			b = f32(f32(b, uv[0]), uv[1])
+		}
+		for _, tr := range m.tris {
+			b = append(b, byte(tr.Polygon), 0)
+			for _, idx := range [][4]int16{tr.VI, tr.NI, tr.TI} {
+				for _, v := range idx {
+					b = i16(b, v)
+				}
+			}
+			b = i16(b, 0)
+			for k := 0; k < 36; k++ { // lightmap UVs, index and padding
+				b = append(b, m.lightmap)
+			}
+		}
+		b = str(b, m.tex)
+	}
+	return b
+}
+
+// TestParseTriangleLayout reads a model whose triangles carry non-zero
+// lightmap fields: the 64-byte stride must land every record and the mesh
+// after them exactly, with the lightmap data read into nothing.
+func TestParseTriangleLayout(t *testing.T) {
+	a, b := newTestMesh(`Sword\blade.jpg`), newTestMesh("hilt.tga")
+	b.verts[3][2] = -2
+	meshes, bones, err := ParseBytes("test.bmd", encodeV10(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meshes) != 2 || len(bones) != 0 {
+		t.Fatalf("%d meshes, %d bones; want 2, 0", len(meshes), len(bones))
+	}
+	for i, want := range []testMesh{a, b} {
+		m := meshes[i]
+		if !reflect.DeepEqual(m.Verts, want.verts) || !reflect.DeepEqual(m.Nodes, want.nodes) ||
+			!reflect.DeepEqual(m.Normals, want.normals) || !reflect.DeepEqual(m.UVs, want.uvs) {
+			t.Errorf("mesh %d: geometry %+v, want %+v", i, m, want)
+		}
+		if !reflect.DeepEqual(m.Tris, want.tris) {
+			t.Errorf("mesh %d: triangles %+v, want %+v", i, m.Tris, want.tris)
+		}
+	}
+	if meshes[0].TexPath != "Sword/blade.jpg" || meshes[1].TexPath != "hilt.tga" {
+		t.Errorf("textures %q, %q", meshes[0].TexPath, meshes[1].TexPath)
+	}
+}
+
+// TestParseTexIndex parses two meshes with one texture in different
+// material slots: each keeps the slot from its header, which is what keeps
+// MergeMeshesByTexture from joining them.
+func TestParseTexIndex(t *testing.T) {
+	a, b := newTestMesh("blade.jpg"), newTestMesh("blade.jpg")
+	a.texIndex, b.texIndex = 0, 3
+	meshes, _, err := ParseBytes("test.bmd", encodeV10(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meshes[0].TexIndex != 0 || meshes[1].TexIndex != 3 {
+		t.Errorf("TexIndex %d, %d; want 0, 3", meshes[0].TexIndex, meshes[1].TexIndex)
+	}
+	if merged := MergeMeshesByTexture(meshes); len(merged) != 2 {
+		t.Errorf("%d meshes after merging, want the two slots kept apart", len(merged))
+	}
+}
+
+// testBone is one bone appended by withBones: its keyframes, per action.
+type testBone struct {
+	dummy    bool
+	name     string
+	parent   int16
+	pos, rot [][][3]float32 // [action][key]
+}
+
+// withBones returns encodeV10(meshes...) followed by actions with keys[a]
+// keyframes each and bones.
+func withBones(meshes []testMesh, keys []int16, bones []testBone) []byte {
+	le := binary.LittleEndian
+	f32 := func(b []byte, v float32) []byte { return le.AppendUint32(b, math.Float32bits(v)) }
+	b := encodeV10(meshes...)
+	le.PutUint16(b[38:], uint16(len(bones)))
+	le.PutUint16(b[40:], uint16(len(keys)))
+	for _, n := range keys {
+		b = append(le.AppendUint16(b, uint16(n)), 0) // no locked positions
+	}
+	for _, bone := range bones {
+		if bone.dummy {
+			b = append(b, 1)
+			continue
+		}
+		b = append(b, 0)
+		b = append(b, []byte(bone.name+strings.Repeat("\x00", 32-len(bone.name)))...)
+		b = le.AppendUint16(b, uint16(bone.parent))
+		for a := range keys {
+			for _, keys := range [][][3]float32{bone.pos[a], bone.rot[a]} {
+				for _, v := range keys {
+					b = f32(f32(f32(b, v[0]), v[1]), v[2])
+				}
+			}
+		}
+	}
+	return b
+}
+
+// TestParseKeyframes parses two bones (and a dummy) over two actions of 2
+// and 3 frames: Pose returns each frame's key, wrapping past the last frame
+// and falling back to the bind pose (action 0, frame 0) outside the actions.
+func TestParseKeyframes(t *testing.T) {
+	key := func(bone, action, frame int) [3]float32 {
+		return [3]float32{float32(bone), float32(action), float32(frame)}
+	}
+	bone := func(i int, name string, parent int16) testBone {
+		tb := testBone{name: name, parent: parent}
+		for a, n := range []int{2, 3} {
+			var pos, rot [][3]float32
+			for f := 0; f < n; f++ {
+				pos = append(pos, key(i, a, f))
+				rot = append(rot, key(i+10, a, f))
+			}
+			tb.pos, tb.rot = append(tb.pos, pos), append(tb.rot, rot)
+		}
+		return tb
+	}
+	data := withBones([]testMesh{newTestMesh("a.jpg")}, []int16{2, 3}, []testBone{bone(0, "Root", -1), {dummy: true}, bone(2, "Tip", 0)})
+	_, bones, err := ParseBytes("anim.bmd", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bones) != 3 || !bones[1].IsDummy || bones[2].Name != "Tip" || bones[2].Parent != 0 {
+		t.Fatalf("bones %+v", bones)
+	}
+
+	tip := &bones[2]
+	if tip.Frames(0) != 2 || tip.Frames(1) != 3 || tip.Frames(2) != 0 || tip.Frames(-1) != 0 {
+		t.Errorf("frames %d, %d, %d, %d; want 2, 3, 0, 0", tip.Frames(0), tip.Frames(1), tip.Frames(2), tip.Frames(-1))
+	}
+	want := func(i, action, frame int) ([3]float64, [3]float64) {
+		p, r := key(i, action, frame), key(i+10, action, frame)
+		return [3]float64{float64(p[0]), float64(p[1]), float64(p[2])}, [3]float64{float64(r[0]), float64(r[1]), float64(r[2])}
+	}
+	for _, c := range []struct {
+		action, frame      int
+		wantAct, wantFrame int
+	}{
+		{0, 0, 0, 0},
+		{0, 1, 0, 1},
+		{1, 2, 1, 2},
+		{1, 4, 1, 1}, // wraps
+		{2, 0, 0, 0}, // no such action: bind pose
+		{1, -1, 0, 0},
+	} {
+		pos, rot := tip.Pose(c.action, c.frame)
+		wp, wr := want(2, c.wantAct, c.wantFrame)
+		if pos != wp || rot != wr {
+			t.Errorf("Pose(%d, %d) = %v, %v; want %v, %v", c.action, c.frame, pos, rot, wp, wr)
+		}
+	}
+	if p, r := want(2, 0, 0); tip.BindPosition != p || tip.BindRotation != r {
+		t.Errorf("bind pose %v, %v; want action 0 frame 0", tip.BindPosition, tip.BindRotation)
+	}
+	if pos, _ := bones[0].Pose(1, 1); pos != [3]float64{0, 1, 1} {
+		t.Errorf("root Pose(1, 1) = %v, want its own key", pos)
+	}
+}
+
+// TestParseReader parses one model from a file and from a reader: the
+// meshes and bones match. A reader cut short or failing gives an error
+// naming the model.
+func TestParseReader(t *testing.T) {
+	root := testBone{name: "Root", parent: -1, pos: [][][3]float32{{{1, 2, 3}}}, rot: [][][3]float32{{{0, 0, 1}}}}
+	data := withBones([]testMesh{newTestMesh("a.jpg"), newTestMesh("b.tga")}, []int16{1}, []testBone{root})
+	path := filepath.Join(t.TempDir(), "Sword01.bmd")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wantMeshes, wantBones, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meshes, bones, err := ParseReader(bytes.NewReader(data), "Item/Sword01.bmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(meshes, wantMeshes) || !reflect.DeepEqual(bones, wantBones) {
+		t.Errorf("ParseReader: %d meshes, bones %+v; Parse: %d meshes, bones %+v", len(meshes), bones, len(wantMeshes), wantBones)
+	}
+
+	_, _, err = ParseReader(io.LimitReader(bytes.NewReader(data), 60), "Item/Sword01.bmd")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Path != "Item/Sword01.bmd" || !strings.Contains(err.Error(), "Item/Sword01.bmd") {
+		t.Errorf("truncated: %v; want a ParseError naming the model", err)
+	}
+	boom := errors.New("archive corrupt")
+	if _, _, err = ParseReader(iotest.ErrReader(boom), "Item/Sword01.bmd"); !errors.Is(err, boom) || !strings.Contains(err.Error(), "Item/Sword01.bmd") {
+		t.Errorf("read error: %v; want it wrapped with the model name", err)
+	}
+}
+
+// TestParseBadCounts feeds headers whose counts can't be right: each must
+// fail with a ParseError at the offending header, without allocating for
+// the count.
+func TestParseBadCounts(t *testing.T) {
+	le := binary.LittleEndian
+	huge := newTestMesh("a.jpg")
+	huge.nv = 32767
+	negative := newTestMesh("a.jpg")
+	negative.nn = -3
+	manyUVs := newTestMesh("a.jpg")
+	manyUVs.ntc = 20000
+
+	// No meshes or bones, one action with -5 keys
+	action := append(encodeV10()[:40:40], 1, 0)
+	action = le.AppendUint16(action, 0xfffb)
+
+	// v15 payload of 20 bytes: not whole LEA blocks
+	lea := le.AppendUint32([]byte("BMD\x0f"), 20)
+	lea = append(lea, make([]byte, 20)...)
+
+	for _, c := range []struct {
+		name   string
+		data   []byte
+		offset int
+		reason string
+	}{
+		{"huge vertex count", encodeV10(huge), 38, "need"},
+		{"negative normal count", encodeV10(newTestMesh("ok.jpg"), negative), 38 + 10 + 4*16 + 4*20 + 4*8 + 2*64 + 32, "negative count"},
+		{"UVs past the end", encodeV10(manyUVs), 38, "need"},
+		{"negative key count", action, 38, "negative key count"},
+		{"partial LEA block", lea, 4, "16-byte blocks"},
+	} {
+		meshes, _, err := ParseBytes("bad.bmd", c.data)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%s: %d meshes, error %v; want a *ParseError", c.name, len(meshes), err)
+			continue
+		}
+		if pe.Offset != c.offset || !strings.Contains(pe.Reason, c.reason) {
+			t.Errorf("%s: %v; want offset %d and %q", c.name, pe, c.offset, c.reason)
+		}
+	}
+}
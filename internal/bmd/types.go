@@ -11,18 +11,51 @@ type Triangle struct {
 
 // Mesh holds parsed geometry for one sub-mesh within a BMD file.
 type Mesh struct {
-	Verts   [][3]float32 // vertex positions, mutable for bone transforms
-	Nodes   []int16      // bone index per vertex
-	Normals [][3]float32
-	UVs     [][2]float32
-	Tris    []Triangle
-	TexPath string // texture reference from BMD (e.g. "sword04.jpg")
+	Verts    [][3]float32 // vertex positions, mutable for bone transforms
+	Nodes    []int16      // bone index per vertex
+	Normals  [][3]float32
+	UVs      [][2]float32
+	Tris     []Triangle
+	TexPath  string // texture reference from BMD (e.g. "sword04.jpg")
+	TexIndex int16  // material slot from the mesh header; meshes sharing a TexPath can differ here
 }
 
 // Bone holds bind-pose data for one bone in the skeleton hierarchy.
 type Bone struct {
+	Name         string
 	Parent       int
 	IsDummy      bool
 	BindPosition [3]float64
 	BindRotation [3]float64 // Euler XYZ radians
+
+	// Keyframes of every action back to back (rotations as Euler XYZ
+	// radians): action a's are [KeyStart[a], KeyStart[a+1]). KeyStart is
+	// shared by the model's bones. Both are nil when the file ends before the
+	// bone's keys; the bind pose is action 0, key 0.
+	Positions [][3]float64
+	Rotations [][3]float64
+	KeyStart  []int
+}
+
+// Pose returns the bone's position and rotation at key frame of action.
+// Frames past the action's last key wrap around, as the action loops in
+// game; an action out of range or without keys gives the bind pose.
+func (b *Bone) Pose(action, frame int) (pos, rot [3]float64) {
+	if b.Positions == nil || action < 0 || action+1 >= len(b.KeyStart) || frame < 0 {
+		return b.BindPosition, b.BindRotation
+	}
+	start, end := b.KeyStart[action], b.KeyStart[action+1]
+	if start == end {
+		return b.BindPosition, b.BindRotation
+	}
+	k := start + frame%(end-start)
+	return b.Positions[k], b.Rotations[k]
+}
+
+// Frames returns the number of key frames in action (0 if it has none).
+func (b *Bone) Frames(action int) int {
+	if action < 0 || action+1 >= len(b.KeyStart) {
+		return 0
+	}
+	return b.KeyStart[action+1] - b.KeyStart[action]
 }
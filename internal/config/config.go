@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,23 +13,91 @@ import (
 // Config holds all configurable paths and render settings.
 type Config struct {
 	// Paths
-	BaseDir     string `json:"base_dir"`
-	ItemDir     string `json:"item_dir"`
-	ItemListXML string `json:"item_list_xml"`
-	TRSBMD      string `json:"trs_bmd"`
-	CustomTRS   string `json:"custom_trs_json"`
-	OutputDir   string `json:"output_dir"`
+	BaseDir      string `json:"base_dir"`
+	ItemDir      string `json:"item_dir"`
+	ItemListXML  string `json:"item_list_xml"`
+	TRSBMD       string `json:"trs_bmd"`
+	CustomTRS    string `json:"custom_trs_json"`
+	StrictTRS    bool   `json:"strict_trs"`        // a missing or malformed custom_trs.json stops the run instead of rendering with binary TRS only
+	TRSOverrides string `json:"trs_overrides_dir"` // directory of per-item override files (<section>_<index>.json) applied after custom_trs.json ("" = none)
+	OutputDir    string `json:"output_dir"`
 
 	// Render settings
-	RenderSize   int `json:"render_size"`   // Square shorthand (sets both width and height)
-	RenderWidth  int `json:"render_width"`  // Output width (0 = use render_size)
-	RenderHeight int `json:"render_height"` // Output height (0 = use render_size)
-	Supersample  int `json:"supersample"`
-	WebPQuality  int `json:"webp_quality"`
-	Workers      int `json:"workers"`
+	RenderSize         int        `json:"render_size"`   // Square shorthand (sets both width and height)
+	RenderWidth        int        `json:"render_width"`  // Output width (0 = use render_size)
+	RenderHeight       int        `json:"render_height"` // Output height (0 = use render_size)
+	Supersample        int        `json:"supersample"`
+	DownsamplePasses   int        `json:"downsample_passes"` // stages to come down from the supersampled render (0/1 = one resample)
+	SSAO               bool       `json:"ssao"`              // darken creases using the depth buffer (screen-space ambient occlusion; costs render time)
+	SSAORadius         float64    `json:"ssao_radius"`       // ssao sampling distance in output px (default 3)
+	SSAOIntensity      float64    `json:"ssao_intensity"`    // ssao darkening of a fully occluded pixel, 0..1 (default 0.5)
+	SmoothShading      bool       `json:"smooth_shading"`    // shade opaque meshes with interpolated vertex normals instead of flat per face
+	WebPQuality        int        `json:"webp_quality"`
+	Workers            int        `json:"workers"`
+	EncodeWorkers      int        `json:"encode_workers"`        // goroutines encoding finished items while workers render the next (0 = workers encode their own)
+	OutputFormat       string     `json:"output_format"`         // "webp" (default), "png", or "exr"
+	PremultipliedAlpha bool       `json:"premultiplied_alpha"`   // write WebP RGB premultiplied by alpha (default straight, as the WebP spec defines)
+	AlphaMatte         string     `json:"alpha_matte"`           // "" (off), "alongside", "instead": separate RGB JPEG + alpha PNG
+	MatteQuality       int        `json:"matte_quality"`         // JPEG quality of the alpha_matte RGB image (default 95)
+	MattePNG           bool       `json:"matte_png"`             // write the alpha_matte RGB image as lossless PNG instead of JPEG
+	IconCrop           string     `json:"icon_crop"`             // "" (off), "center", or "dense": square crop of the item's middle
+	Sidecar            bool       `json:"sidecar"`               // also write <index>.json with the TRS entry, camera, bbox, and timings used
+	TurntableFrames    int        `json:"turntable_frames"`      // > 1: also write this many <index>_NNN frames turning the item about rotY
+	TurntableWebP      bool       `json:"turntable_webp"`        // also assemble the turntable frames into <index>_turntable.webp (animated)
+	TurntableDelay     int        `json:"turntable_delay_ms"`    // turntable_webp frame delay in ms (default 100)
+	BackgroundColor    string     `json:"background_color"`      // "#RRGGBB" to flatten written images onto, always opaque ("" = transparent)
+	DropShadow         bool       `json:"drop_shadow"`           // draw a blurred, offset shadow of the item beneath it in written images
+	DropShadowOffset   [2]int     `json:"drop_shadow_offset"`    // drop_shadow offset in output px, [x, y] (default [3, 3]: down and right)
+	DropShadowBlur     int        `json:"drop_shadow_blur"`      // drop_shadow blur radius in output px (default 4)
+	DropShadowColor    string     `json:"drop_shadow_color"`     // "#RRGGBB[AA]" for drop_shadow (default black)
+	DropShadowOpacity  float64    `json:"drop_shadow_opacity"`   // drop_shadow strength, 0..1 (default 0.5)
+	Outline            int        `json:"outline"`               // stroke this many output px wide around the item in written images (0 = off)
+	OutlineColor       string     `json:"outline_color"`         // "#RRGGBB[AA]" for outline (default white)
+	DebugCanvas        string     `json:"debug_canvas"`          // "" (off), "fill", or "grid": show the canvas bounds (framing debug aid)
+	DebugCanvasColor   string     `json:"debug_canvas_color"`    // "#RRGGBBAA" for debug_canvas (default faint magenta)
+	Wireframe          string     `json:"wireframe"`             // "" (off), "overlay", or "only": draw triangle edges (model debugging aid)
+	WireframeColor     string     `json:"wireframe_color"`       // "#RRGGBBAA" for wireframe (default opaque green)
+	Retries            int        `json:"retries"`               // extra attempts for items that fail with an I/O error
+	LogLevel           string     `json:"log_level"`             // "error", "warn", "info" (default), or "debug" (= -verbose)
+	MaxTextureSize     int        `json:"max_texture_size"`      // downscale decoded textures to at most this many px on the longer side (0 = off)
+	TextureCacheMB     int        `json:"texture_cache_mb"`      // keep at most this many MiB of decoded textures, dropping the least recently used (0 = unbounded)
+	Mmap               bool       `json:"mmap"`                  // memory-map BMD/texture files instead of reading them into the heap (unix only)
+	Convention         string     `json:"coordinate_convention"` // "mu-default", "mirrored", or "y-up" (see viewmatrix.Conventions)
+	RotationOffset     [3]float64 `json:"rotation_offset"`       // degrees added to every TRS entry's rotX, rotY, rotZ
+
+	// Cleanup
+	MinFeaturePixels  int     `json:"min_feature_px"`      // min cluster area in px at 256×256, scaled to output (0 = 2% of item area)
+	MinTriangleArea   float64 `json:"min_triangle_area"`   // skip triangles projecting to less than this many output px² (0 = off)
+	NoOpaquePromotion bool    `json:"no_opaque_promotion"` // don't draw a glow/overlay mesh opaque when a model has no opaque mesh
+	ContentAlpha      int     `json:"content_alpha"`       // alpha at or above which a pixel counts as content when framing (default 8; 1 = any)
+	MaxImageDimension int     `json:"max_image_dimension"` // largest width/height a postprocess step may create; larger steps are skipped (0 = 32768)
+
+	// Items never rendered ("section_index" or "section_start-end" keys)
+	SkipItems []string `json:"skip_items"`
+
+	// Render order: priority_items first (same keys, in list order), then
+	// the rest by render_order: "itemlist" (default), "section", or "cost"
+	RenderOrder   string   `json:"render_order"`
+	PriorityItems []string `json:"priority_items"`
+
+	// Output variants written from one parse per item
+	Profiles []Profile `json:"profiles"`
 }
 
-// Load reads a JSON config file and returns Config.
+// Profile is one named output variant. With profiles set, every item is
+// written once per profile into its own directory instead of output_dir.
+type Profile struct {
+	Name         string `json:"name"`
+	OutputDir    string `json:"output_dir"`    // relative to output_dir (default: name)
+	RenderSize   int    `json:"render_size"`   // 0 = top-level size (or the item's TRS override)
+	RenderWidth  int    `json:"render_width"`  // 0 = render_size
+	RenderHeight int    `json:"render_height"` // 0 = render_size
+	Background   string `json:"background"`    // "#RRGGBB[AA]" behind the item ("" = transparent)
+}
+
+// Load reads a config file and returns Config.
+// The format is chosen by extension: .toml and .yaml/.yml are read with the
+// same keys and structure as JSON; anything else is JSON.
 // Fields not set in the file keep their zero values.
 func Load(path string) (Config, error) {
 	data, err := os.ReadFile(path)
@@ -36,7 +106,7 @@ func Load(path string) (Config, error) {
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := unmarshalFormat(formatOf(path), data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("config: parse %s: %w", path, err)
 	}
 
@@ -56,6 +126,9 @@ func (c *Config) Resolve(flags Flags) {
 	if flags.Quality > 0 {
 		c.WebPQuality = flags.Quality
 	}
+	if flags.Format != "" {
+		c.OutputFormat = flags.Format
+	}
 	if flags.Workers > 0 {
 		c.Workers = flags.Workers
 	}
@@ -91,6 +164,10 @@ func (c *Config) Resolve(flags Flags) {
 			c.CustomTRS = filepath.Join(c.BaseDir, c.CustomTRS)
 		}
 
+		if c.TRSOverrides != "" && !filepath.IsAbs(c.TRSOverrides) {
+			c.TRSOverrides = filepath.Join(c.BaseDir, c.TRSOverrides)
+		}
+
 		if c.OutputDir == "" {
 			c.OutputDir = filepath.Join(c.BaseDir, "Data", "Item-renders")
 		} else if !filepath.IsAbs(c.OutputDir) {
@@ -114,9 +191,52 @@ func (c *Config) Resolve(flags Flags) {
 	if c.WebPQuality <= 0 {
 		c.WebPQuality = 90
 	}
+	if c.MatteQuality <= 0 {
+		c.MatteQuality = 95
+	}
+	if c.ContentAlpha <= 0 {
+		c.ContentAlpha = 8
+	}
+	if c.TurntableDelay <= 0 {
+		c.TurntableDelay = 100
+	}
+	if c.DropShadowOffset == [2]int{} {
+		c.DropShadowOffset = [2]int{3, 3}
+	}
+	if c.DropShadowBlur <= 0 {
+		c.DropShadowBlur = 4
+	}
+	if c.DropShadowOpacity <= 0 {
+		c.DropShadowOpacity = 0.5
+	}
+	if c.SSAORadius <= 0 {
+		c.SSAORadius = 3
+	}
+	if c.SSAOIntensity <= 0 {
+		c.SSAOIntensity = 0.5
+	}
+	if c.OutputFormat == "" {
+		c.OutputFormat = "webp"
+	}
 	if c.Workers <= 0 {
 		c.Workers = runtime.NumCPU()
 	}
+
+	for i := range c.Profiles {
+		p := &c.Profiles[i]
+		if p.OutputDir == "" {
+			p.OutputDir = p.Name
+		}
+		if !filepath.IsAbs(p.OutputDir) {
+			p.OutputDir = filepath.Join(c.OutputDir, p.OutputDir)
+		}
+		if p.RenderWidth <= 0 {
+			p.RenderWidth = p.RenderSize
+		}
+		if p.RenderHeight <= 0 {
+			p.RenderHeight = p.RenderSize
+		}
+	}
 }
 
 // Flags holds CLI flag values that override config file settings.
@@ -124,9 +244,27 @@ type Flags struct {
 	DataDir   string
 	OutputDir string
 	Quality   int
+	Format    string
 	Workers   int
 }
 
+// OutputKey returns a hash of the resolved settings that can change a
+// rendered image, so an incremental run can tell when every item needs
+// rendering again. Paths, concurrency, logging, memory, and item selection
+// are left out, since they change no pixel; each item's BMD, textures, and
+// TRS entry are checked on their own (see batch.Config.Incremental).
+func (c Config) OutputKey() string {
+	c.BaseDir, c.ItemDir, c.ItemListXML, c.TRSBMD, c.CustomTRS, c.TRSOverrides, c.OutputDir = "", "", "", "", "", "", ""
+	c.StrictTRS = false
+	c.Workers, c.EncodeWorkers, c.Retries, c.TextureCacheMB = 0, 0, 0, 0
+	c.LogLevel = ""
+	c.Mmap = false
+	c.SkipItems, c.RenderOrder, c.PriorityItems = nil, "", nil
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
 func detectBaseDir() string {
 	// Try relative to executable
 	exe, _ := os.Executable()
@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestResolveMatteQuality(t *testing.T) {
+	for _, c := range []struct {
+		set, flag, want int
+	}{
+		{0, 0, 95},
+		{0, 70, 95}, // -quality is the WebP quality only
+		{80, 0, 80},
+	} {
+		cfg := Config{BaseDir: t.TempDir(), MatteQuality: c.set}
+		cfg.Resolve(Flags{Quality: c.flag})
+		if cfg.MatteQuality != c.want {
+			t.Errorf("matte_quality %d, -quality %d: resolved to %d, want %d", c.set, c.flag, cfg.MatteQuality, c.want)
+		}
+	}
+}
+
+func TestResolveContentAlpha(t *testing.T) {
+	for _, c := range []struct{ set, want int }{{0, 8}, {-3, 8}, {1, 1}, {40, 40}} {
+		cfg := Config{BaseDir: t.TempDir(), ContentAlpha: c.set}
+		cfg.Resolve(Flags{})
+		if cfg.ContentAlpha != c.want {
+			t.Errorf("content_alpha %d: resolved to %d, want %d", c.set, cfg.ContentAlpha, c.want)
+		}
+	}
+}
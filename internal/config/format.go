@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"mu-bmd-renderer/internal/yamljson"
+)
+
+// unmarshalFormat decodes a config document in format ("json", "toml", or
+// "yaml") into cfg. TOML and YAML are converted to JSON first, which keeps
+// the json struct tags the single source of truth for key names, nested
+// values (profiles) included.
+func unmarshalFormat(format string, data []byte, cfg *Config) error {
+	switch format {
+	case "toml":
+		var doc map[string]any
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return err
+		}
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		data = raw
+	case "yaml":
+		raw, err := yamljson.Convert(data)
+		if err != nil {
+			return err
+		}
+		data = raw
+	}
+	err := json.Unmarshal(data, cfg)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		err = fmt.Errorf("%s: got a %s, want %s", typeErr.Field, typeErr.Value, typeErr.Type)
+		if format == "toml" && typeErr.Value == "number" && typeErr.Type.Kind() == reflect.String {
+			err = fmt.Errorf(`%w (quote it: TOML reads an item key like 12_40 as the number 1240)`, err)
+		}
+	}
+	return err
+}
+
+// formatOf picks the config format from the file extension (default JSON).
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return "toml"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The same config in each format; Load must read them identically.
+var sameConfig = map[string]string{
+	"config.json": `{
+  "item_dir": "Data/Item",
+  "render_width": 512,
+  "supersample": 3,
+  "ssao": true,
+  "ssao_intensity": 0.35,
+  "rotation_offset": [0, 15.5, -90],
+  "skip_items": ["12_40", "14_72-77"],
+  "background_color": "#102030",
+  "profiles": [
+    {"name": "icon", "render_size": 128, "background": "#ffffff"},
+    {"name": "large", "output_dir": "big", "render_width": 1024, "render_height": 768}
+  ]
+}`,
+	"config.toml": `
+item_dir = "Data/Item"
+render_width = 512
+supersample = 3
+ssao = true
+ssao_intensity = 0.35
+rotation_offset = [0, 15.5, -90]
+skip_items = ["12_40", "14_72-77"]
+background_color = "#102030"
+
+[[profiles]]
+name = "icon"
+render_size = 128
+background = "#ffffff"
+
+[[profiles]]
+name = "large"
+output_dir = "big"
+render_width = 1024
+render_height = 768
+`,
+	"config.yaml": `
+# comments are fine
+item_dir: Data/Item
+render_width: 512
+supersample: 3
+ssao: true
+ssao_intensity: 0.35
+rotation_offset: [0, 15.5, -90]
+skip_items:
+  - 12_40
+  - 14_72-77
+background_color: "#102030"
+profiles:
+  - name: icon
+    render_size: 128
+    background: "#ffffff"
+  - {name: large, output_dir: big, render_width: 1024, render_height: 768}
+`,
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFormatsAgree(t *testing.T) {
+	want, err := Load(writeConfig(t, "config.json", sameConfig["config.json"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.RenderWidth != 512 || len(want.Profiles) != 2 || want.Profiles[1].OutputDir != "big" || want.RotationOffset[1] != 15.5 {
+		t.Fatalf("JSON config read as %+v", want)
+	}
+	for _, name := range []string{"config.toml", "config.yaml", "config.YML"} {
+		content := sameConfig[strings.ToLower(strings.Replace(name, "YML", "yaml", 1))]
+		got, err := Load(writeConfig(t, name, content))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s read as\n%+v\nwant\n%+v", name, got, want)
+		}
+	}
+}
+
+func TestLoadTOMLUnquotedItemKey(t *testing.T) {
+	_, err := Load(writeConfig(t, "config.toml", "skip_items = [12_40]\n"))
+	if err == nil || !strings.Contains(err.Error(), "quote it") {
+		t.Errorf("unquoted TOML item key: err = %v, want the quoting hint", err)
+	}
+}
+
+func TestLoadTypeError(t *testing.T) {
+	_, err := Load(writeConfig(t, "config.yaml", "render_width: wide\n"))
+	if err == nil || !strings.Contains(err.Error(), "render_width") {
+		t.Errorf("string render_width: err = %v, want it named", err)
+	}
+}
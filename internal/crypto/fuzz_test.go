@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The decryptors run on whatever a BMD header claims, so each must handle
+// any input without panicking, return as many bytes as it decrypts and
+// leave its input alone. Where the encryption is known, decrypting what it
+// produced must give the input back. Seeds are in testdata/fuzz:
+//
+//	go test -fuzz=FuzzDecryptModulus ./internal/crypto
+
+// encryptXOR is the inverse of DecryptXOR, as the game's tools write v12.
+func encryptXOR(plain []byte) []byte {
+	out := make([]byte, len(plain))
+	chain := byte(0x5E)
+	for i, p := range plain {
+		out[i] = (p + chain) ^ XORKey[i&15]
+		chain = out[i] + 0x3D
+	}
+	return out
+}
+
+// checkDecrypt runs decrypt on data and fails if it panics, changes data or
+// returns other than want bytes.
+func checkDecrypt(t *testing.T, decrypt func([]byte) []byte, data []byte, want int) []byte {
+	t.Helper()
+	orig := bytes.Clone(data)
+	out := decrypt(data)
+	if !bytes.Equal(data, orig) {
+		t.Fatal("input modified")
+	}
+	if len(out) != want {
+		t.Fatalf("%d bytes out for %d in, want %d", len(out), len(data), want)
+	}
+	return out
+}
+
+func FuzzDecryptXOR(f *testing.F) {
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkDecrypt(t, DecryptXOR, data, len(data))
+		if got := DecryptXOR(encryptXOR(data)); !bytes.Equal(got, data) {
+			t.Errorf("DecryptXOR(encryptXOR(%x)) = %x", data, got)
+		}
+	})
+}
+
+func FuzzDecryptTRS(f *testing.F) {
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		out := checkDecrypt(t, DecryptTRS, data, len(data))
+		// A repeating XOR is its own inverse
+		if got := DecryptTRS(out); !bytes.Equal(got, data) {
+			t.Errorf("DecryptTRS twice = %x, want %x", got, data)
+		}
+	})
+}
+
+func FuzzDecryptModulus(f *testing.F) {
+	f.Add(make([]byte, 33))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// The 2 selector bytes and 32-byte key aren't part of the output;
+		// anything shorter is returned as is
+		want := len(data) - 34
+		if want < 0 {
+			want = len(data)
+		}
+		checkDecrypt(t, DecryptModulus, data, want)
+	})
+}
+
+func FuzzDecryptLEA(f *testing.F) {
+	f.Add(make([]byte, 16))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		data = data[:len(data)&^15] // v15 bodies are whole blocks; ParseBytes checks
+		checkDecrypt(t, func(b []byte) []byte { return DecryptLEA(b, LEAKey) }, data, len(data))
+		if got := DecryptLEA(EncryptLEA(data, LEAKey), LEAKey); !bytes.Equal(got, data) {
+			t.Errorf("DecryptLEA(EncryptLEA(%x)) = %x", data, got)
+		}
+	})
+}
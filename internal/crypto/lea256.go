@@ -70,3 +70,36 @@ func DecryptLEA(data []byte, key [32]byte) []byte {
 	}
 	return out
 }
+
+// EncryptLEA encrypts data in 16-byte blocks using LEA-256 ECB mode, the
+// inverse of DecryptLEA. The input length must be a multiple of 16.
+func EncryptLEA(data []byte, key [32]byte) []byte {
+	rk := leaKeySchedule(key)
+	out := make([]byte, len(data))
+
+	for off := 0; off < len(data); off += 16 {
+		block := data[off : off+16]
+		s0 := binary.LittleEndian.Uint32(block[0:])
+		s1 := binary.LittleEndian.Uint32(block[4:])
+		s2 := binary.LittleEndian.Uint32(block[8:])
+		s3 := binary.LittleEndian.Uint32(block[12:])
+
+		for r := 0; r < 32; r++ {
+			base := r * 6
+			t0 := bits.RotateLeft32((s0^rk[base])+(s1^rk[base+1]), 9)
+			t1 := bits.RotateLeft32((s1^rk[base+2])+(s2^rk[base+3]), -5)
+			t2 := bits.RotateLeft32((s2^rk[base+4])+(s3^rk[base+5]), -3)
+
+			s3 = s0
+			s0 = t0
+			s1 = t1
+			s2 = t2
+		}
+
+		binary.LittleEndian.PutUint32(out[off+0:], s0)
+		binary.LittleEndian.PutUint32(out[off+4:], s1)
+		binary.LittleEndian.PutUint32(out[off+8:], s2)
+		binary.LittleEndian.PutUint32(out[off+12:], s3)
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+// Package export writes rendered images in formats other than WebP.
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"image"
+	"io"
+	"math"
+
+	"mu-bmd-renderer/internal/raster"
+)
+
+// WriteEXR writes img as an uncompressed single-part scanline OpenEXR file
+// with half-float A, B, G, R channels.
+//
+// The renderer's framebuffer is 8-bit sRGB, so colors are converted to linear
+// light (with the renderer's own decode, so a render round-trips) and premultiplied by alpha (EXR's convention); values above 1.0 from
+// additive glow are already clipped by the time they reach img.
+func WriteEXR(w io.Writer, img *image.NRGBA) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	bw := bufio.NewWriter(w)
+	var hdr []byte
+	hdr = append(hdr, 0x76, 0x2f, 0x31, 0x01) // magic
+	hdr = append(hdr, 2, 0, 0, 0)             // version 2, single-part scanline
+
+	// channels: must be sorted by name
+	var ch []byte
+	for _, name := range []string{"A", "B", "G", "R"} {
+		ch = append(ch, name...)
+		ch = append(ch, 0)
+		ch = binary.LittleEndian.AppendUint32(ch, 1) // HALF
+		ch = append(ch, 0, 0, 0, 0)                  // pLinear + reserved
+		ch = binary.LittleEndian.AppendUint32(ch, 1) // xSampling
+		ch = binary.LittleEndian.AppendUint32(ch, 1) // ySampling
+	}
+	ch = append(ch, 0)
+	hdr = appendAttr(hdr, "channels", "chlist", ch)
+
+	hdr = appendAttr(hdr, "compression", "compression", []byte{0}) // NO_COMPRESSION
+	box := make([]byte, 0, 16)
+	box = binary.LittleEndian.AppendUint32(box, 0)
+	box = binary.LittleEndian.AppendUint32(box, 0)
+	box = binary.LittleEndian.AppendUint32(box, uint32(width-1))
+	box = binary.LittleEndian.AppendUint32(box, uint32(height-1))
+	hdr = appendAttr(hdr, "dataWindow", "box2i", box)
+	hdr = appendAttr(hdr, "displayWindow", "box2i", box)
+	hdr = appendAttr(hdr, "lineOrder", "lineOrder", []byte{0}) // INCREASING_Y
+	hdr = appendAttr(hdr, "pixelAspectRatio", "float", f32(1))
+	hdr = appendAttr(hdr, "screenWindowCenter", "v2f", append(f32(0), f32(0)...))
+	hdr = appendAttr(hdr, "screenWindowWidth", "float", f32(1))
+	hdr = append(hdr, 0) // end of header
+
+	// Offset table: one uncompressed scanline per block
+	lineBytes := width * 4 * 2
+	blockSize := 8 + lineBytes
+	tableStart := len(hdr)
+	first := tableStart + height*8
+	for y := 0; y < height; y++ {
+		hdr = binary.LittleEndian.AppendUint64(hdr, uint64(first+y*blockSize))
+	}
+	if _, err := bw.Write(hdr); err != nil {
+		return err
+	}
+
+	line := make([]byte, 8+lineBytes)
+	for y := 0; y < height; y++ {
+		binary.LittleEndian.PutUint32(line[0:], uint32(y))
+		binary.LittleEndian.PutUint32(line[4:], uint32(lineBytes))
+		row := img.Pix[(y)*img.Stride:]
+		for x := 0; x < width; x++ {
+			p := row[x*4 : x*4+4]
+			a := float32(p[3]) / 255
+			vals := [4]float32{
+				a,
+				float32(raster.SRGBToLinear(p[2])) * a,
+				float32(raster.SRGBToLinear(p[1])) * a,
+				float32(raster.SRGBToLinear(p[0])) * a,
+			}
+			for c, v := range vals {
+				off := 8 + (c*width+x)*2
+				binary.LittleEndian.PutUint16(line[off:], toHalf(v))
+			}
+		}
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func appendAttr(dst []byte, name, typ string, val []byte) []byte {
+	dst = append(dst, name...)
+	dst = append(dst, 0)
+	dst = append(dst, typ...)
+	dst = append(dst, 0)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(val)))
+	return append(dst, val...)
+}
+
+func f32(v float32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, math.Float32bits(v))
+}
+
+// toHalf converts a non-negative float32 to IEEE 754 half precision
+// (round to nearest; out-of-range values saturate to infinity).
+func toHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int((bits>>23)&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp >= 31:
+		return sign | 0x7c00
+	case exp <= 0:
+		if exp < -10 {
+			return sign
+		}
+		// Subnormal half
+		mant |= 0x800000
+		shift := uint(14 - exp)
+		return sign | uint16((mant+(1<<(shift-1)))>>shift)
+	}
+	h := sign | uint16(exp)<<10 | uint16(mant>>13)
+	if mant&0x1000 != 0 {
+		h++ // round half up; carry into the exponent is correct
+	}
+	return h
+}
@@ -0,0 +1,149 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/raster"
+)
+
+// fromHalf converts IEEE 754 half precision to float32.
+func fromHalf(h uint16) float32 {
+	sign := float32(1)
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float32(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * mant / 1024 * float32(math.Pow(2, -14))
+	case 31:
+		return sign * float32(math.Inf(1))
+	}
+	return sign * (1 + mant/1024) * float32(math.Pow(2, float64(exp-15)))
+}
+
+func TestToHalf(t *testing.T) {
+	for _, c := range []struct {
+		f    float32
+		want uint16
+	}{
+		{0, 0x0000},
+		{1, 0x3c00},
+		{0.5, 0x3800},
+		{1.0 / 3, 0x3555},
+		{65504, 0x7bff},   // largest half
+		{1e6, 0x7c00},     // saturates to infinity
+		{0x1p-24, 0x0001}, // smallest subnormal
+		{0x1p-14, 0x0400}, // smallest normal
+	} {
+		if got := toHalf(c.f); got != c.want {
+			t.Errorf("toHalf(%g) = %#04x, want %#04x", c.f, got, c.want)
+		}
+	}
+}
+
+// exrImage is what readEXR recovers from a file.
+type exrImage struct {
+	attrs         map[string][]byte
+	width, height int
+	pix           [][4]float32 // per pixel, in file channel order A, B, G, R
+}
+
+// readEXR parses the uncompressed, single-part scanline files WriteEXR
+// produces, following the offset table.
+func readEXR(t *testing.T, raw []byte) exrImage {
+	t.Helper()
+	if !bytes.HasPrefix(raw, []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}) {
+		t.Fatalf("bad magic/version % x", raw[:8])
+	}
+	img := exrImage{attrs: make(map[string][]byte)}
+	off := 8
+	cstr := func() string {
+		end := bytes.IndexByte(raw[off:], 0)
+		s := string(raw[off : off+end])
+		off += end + 1
+		return s
+	}
+	for {
+		name := cstr()
+		if name == "" {
+			break
+		}
+		cstr() // type
+		n := int(binary.LittleEndian.Uint32(raw[off:]))
+		img.attrs[name] = raw[off+4 : off+4+n]
+		off += 4 + n
+	}
+	box := img.attrs["dataWindow"]
+	img.width = int(binary.LittleEndian.Uint32(box[8:])) + 1
+	img.height = int(binary.LittleEndian.Uint32(box[12:])) + 1
+	img.pix = make([][4]float32, img.width*img.height)
+	for y := 0; y < img.height; y++ {
+		block := int(binary.LittleEndian.Uint64(raw[off+y*8:]))
+		if line := int(binary.LittleEndian.Uint32(raw[block:])); line != y {
+			t.Fatalf("block %d holds line %d", y, line)
+		}
+		if n := int(binary.LittleEndian.Uint32(raw[block+4:])); n != img.width*8 {
+			t.Fatalf("line %d: %d bytes, want %d", y, n, img.width*8)
+		}
+		for c := 0; c < 4; c++ {
+			for x := 0; x < img.width; x++ {
+				h := binary.LittleEndian.Uint16(raw[block+8+(c*img.width+x)*2:])
+				img.pix[y*img.width+x][c] = fromHalf(h)
+			}
+		}
+	}
+	return img
+}
+
+func TestWriteEXRReadBack(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 5, 3))
+	colors := []color.NRGBA{{255, 0, 0, 255}, {0, 128, 255, 255}, {255, 255, 255, 128}, {10, 200, 90, 0}, {64, 64, 64, 200}}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			src.SetNRGBA(x, y, colors[(x+y)%len(colors)])
+		}
+	}
+	var buf bytes.Buffer
+	if err := WriteEXR(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	img := readEXR(t, buf.Bytes())
+	if img.width != 5 || img.height != 3 {
+		t.Fatalf("%d×%d, want 5×3", img.width, img.height)
+	}
+	if !bytes.Equal(img.attrs["displayWindow"], img.attrs["dataWindow"]) || img.attrs["compression"][0] != 0 {
+		t.Errorf("displayWindow %x, dataWindow %x, compression %d", img.attrs["displayWindow"], img.attrs["dataWindow"], img.attrs["compression"][0])
+	}
+	// Four 18-byte HALF channels, sorted by name, and the list terminator
+	ch := img.attrs["channels"]
+	for i, name := range "ABGR" {
+		if rec := ch[i*18:]; rec[0] != byte(name) || rec[1] != 0 || binary.LittleEndian.Uint32(rec[2:]) != 1 {
+			t.Errorf("channel %d: % x, want %c as HALF", i, rec[:18], name)
+		}
+	}
+	if len(ch) != 4*18+1 {
+		t.Errorf("channel list is %d bytes, want %d", len(ch), 4*18+1)
+	}
+
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			c := src.NRGBAAt(x, y)
+			a := float64(c.A) / 255
+			want := [4]float64{a, raster.SRGBToLinear(c.B) * a, raster.SRGBToLinear(c.G) * a, raster.SRGBToLinear(c.R) * a}
+			got := img.pix[y*5+x]
+			for i := range want {
+				// half precision: 11 significant bits
+				if math.Abs(float64(got[i])-want[i]) > want[i]/1024+1e-6 {
+					t.Errorf("pixel %d,%d channel %c: %g, want %g", x, y, "ABGR"[i], got[i], want[i])
+				}
+			}
+		}
+	}
+}
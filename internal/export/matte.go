@@ -0,0 +1,65 @@
+package export
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SplitAlpha separates img into an opaque RGB image and a grayscale alpha
+// matte. Colors are kept straight (not premultiplied), so fully transparent
+// pixels come out black in the RGB image.
+func SplitAlpha(img *image.NRGBA) (*image.RGBA, *image.Gray) {
+	b := img.Bounds()
+	rgb := image.NewRGBA(b)
+	alpha := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		src := img.Pix[(y-b.Min.Y)*img.Stride:]
+		dst := rgb.Pix[(y-b.Min.Y)*rgb.Stride:]
+		am := alpha.Pix[(y-b.Min.Y)*alpha.Stride:]
+		for x := 0; x < b.Dx(); x++ {
+			copy(dst[x*4:x*4+3], src[x*4:x*4+3])
+			dst[x*4+3] = 255
+			am[x] = src[x*4+3]
+		}
+	}
+	return rgb, alpha
+}
+
+// WriteMatte writes img as an opaque RGB image (rgbPath) plus a grayscale
+// alpha PNG (alphaPath), for engines that can't read an alpha channel from
+// WebP. The RGB image is a JPEG at jpegQuality, or a lossless PNG when rgbPath
+// ends in .png — worth it when the result is recompressed again downstream.
+func WriteMatte(rgbPath, alphaPath string, img *image.NRGBA, jpegQuality int) error {
+	rgb, alpha := SplitAlpha(img)
+
+	f, err := os.Create(rgbPath)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(rgbPath), ".png") {
+		err = png.Encode(f, rgb)
+	} else {
+		err = jpeg.Encode(f, rgb, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.Create(alphaPath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, alpha); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
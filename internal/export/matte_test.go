@@ -0,0 +1,121 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gradient returns a w×h image whose alpha ramps left to right over a
+// red-to-blue color ramp.
+func gradient(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(255 * x / (w - 1)), 60, uint8(255 * y / (h - 1)), uint8(255 * x / (w - 1))})
+		}
+	}
+	return img
+}
+
+func TestSplitAlpha(t *testing.T) {
+	src := gradient(16, 8)
+	rgb, alpha := SplitAlpha(src)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			s := src.NRGBAAt(x, y)
+			if c := rgb.RGBAAt(x, y); c.R != s.R || c.G != s.G || c.B != s.B || c.A != 255 {
+				t.Fatalf("%d,%d: RGB %v, want %v opaque", x, y, c, s)
+			}
+			if a := alpha.GrayAt(x, y).Y; a != s.A {
+				t.Fatalf("%d,%d: matte %d, want %d", x, y, a, s.A)
+			}
+		}
+	}
+}
+
+// readPNG decodes the PNG at path.
+func readPNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return img
+}
+
+// TestWriteMattePNGRoundTrip recombines a lossless matte: it must give the
+// source image back exactly.
+func TestWriteMattePNGRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := gradient(16, 8)
+	rgbPath, alphaPath := filepath.Join(dir, "0_rgb.png"), filepath.Join(dir, "0_alpha.png")
+	if err := WriteMatte(rgbPath, alphaPath, src, 90); err != nil {
+		t.Fatal(err)
+	}
+	rgb, alpha := readPNG(t, rgbPath), readPNG(t, alphaPath)
+	if _, ok := alpha.(*image.Gray); !ok {
+		t.Errorf("matte decodes as %T, want grayscale", alpha)
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			r, g, b, _ := rgb.At(x, y).RGBA()
+			a, _, _, _ := alpha.At(x, y).RGBA()
+			got := color.NRGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+			if want := src.NRGBAAt(x, y); got != want {
+				t.Fatalf("%d,%d: recombined %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+// TestWriteMatteJPEGQuality writes the RGB image as JPEG at two qualities:
+// the higher one must be the larger file and the closer to the source.
+func TestWriteMatteJPEGQuality(t *testing.T) {
+	dir := t.TempDir()
+	src := gradient(64, 64)
+	size := make(map[int]int64)
+	errSum := make(map[int]int)
+	for _, q := range []int{30, 95} {
+		rgbPath := filepath.Join(dir, "rgb.jpg")
+		if err := WriteMatte(rgbPath, filepath.Join(dir, "alpha.png"), src, q); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(rgbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := jpeg.Decode(f)
+		fi, _ := f.Stat()
+		f.Close()
+		if err != nil {
+			t.Fatalf("quality %d: %v", q, err)
+		}
+		size[q] = fi.Size()
+		for y := 0; y < 64; y++ {
+			for x := 0; x < 64; x++ {
+				r, _, _, _ := img.At(x, y).RGBA()
+				errSum[q] += abs(int(r>>8) - int(src.NRGBAAt(x, y).R))
+			}
+		}
+	}
+	if size[95] <= size[30] || errSum[95] >= errSum[30] {
+		t.Errorf("quality 30: %d bytes, error %d; quality 95: %d bytes, error %d", size[30], errSum[30], size[95], errSum[95])
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -69,6 +69,26 @@ func IsBodyMesh(m *bmd.Mesh) bool {
 	return bodyTextureRE.MatchString(stem)
 }
 
+// IsBoundToBones reports whether more than half of m's vertices are bound
+// (via Nodes) to one of bones. It catches character body parts that ship in
+// equipment BMDs under an ordinary texture name, which IsBodyMesh can't see;
+// the body bone indices differ per model, so they come from the TRS entry.
+func IsBoundToBones(m *bmd.Mesh, bones []int) bool {
+	if len(m.Nodes) == 0 || len(bones) == 0 {
+		return false
+	}
+	n := 0
+	for _, node := range m.Nodes {
+		for _, b := range bones {
+			if int(node) == b {
+				n++
+				break
+			}
+		}
+	}
+	return 2*n > len(m.Nodes)
+}
+
 // IsJPGUnderTGA returns true if meshes[idx] is a JPG-textured mesh that has
 // a TGA counterpart with the same texture stem AND the TGA mesh extends
 // significantly beyond the JPG in at least one axis (span ratio > 1.5×).
@@ -0,0 +1,27 @@
+package filter
+
+import (
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+func TestIsBoundToBones(t *testing.T) {
+	for _, c := range []struct {
+		nodes []int16
+		bones []int
+		want  bool
+	}{
+		{[]int16{3, 3, 3, 0}, []int{3}, true},
+		{[]int16{3, 4, 0, 0}, []int{3, 4}, false}, // exactly half is not most
+		{[]int16{3, 4, 4, 0}, []int{3, 4}, true},
+		{[]int16{0, 1, 2}, []int{3}, false},
+		{[]int16{3, 3}, nil, false},
+		{nil, []int{3}, false},
+	} {
+		m := bmd.Mesh{Nodes: c.nodes}
+		if got := IsBoundToBones(&m, c.bones); got != c.want {
+			t.Errorf("nodes %v, bones %v: %v, want %v", c.nodes, c.bones, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package imgdiff compares rendered items against reference images.
+package imgdiff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// bgTolerance is how far (max RGB channel difference) a pixel of an opaque
+// reference may be from the border color and still count as background.
+const bgTolerance = 24
+
+// Plane is a single-channel luma image with values in [0, 255].
+type Plane struct {
+	W, H int
+	Pix  []float64
+}
+
+// Normalize aligns img for comparison: it crops to the item's content, scales
+// it to fit a size×size square keeping its aspect ratio, centers it, and
+// composites it over bg. Content is the non-transparent pixels when img has
+// any transparency, else the pixels that differ from the border color (an
+// in-game screenshot cut around the item). Two images of the same item
+// framed or sized differently normalize to roughly the same plane.
+func Normalize(img image.Image, size int, bg color.NRGBA) Plane {
+	src := toNRGBA(img)
+	box := contentBox(src)
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	if !box.Empty() {
+		scale := float64(size) / float64(max(box.Dx(), box.Dy()))
+		w := max(1, int(math.Round(float64(box.Dx())*scale)))
+		h := max(1, int(math.Round(float64(box.Dy())*scale)))
+		x0, y0 := (size-w)/2, (size-h)/2
+		xdraw.CatmullRom.Scale(canvas, image.Rect(x0, y0, x0+w, y0+h), src, box, xdraw.Over, nil)
+	}
+
+	p := Plane{W: size, H: size, Pix: make([]float64, size*size)}
+	for i := range p.Pix {
+		c := canvas.Pix[i*4 : i*4+3]
+		p.Pix[i] = 0.299*float64(c[0]) + 0.587*float64(c[1]) + 0.114*float64(c[2])
+	}
+	return p
+}
+
+// BorderColor returns the average color of img's outermost pixels, opaque —
+// the background of a screenshot cut around an item.
+func BorderColor(img image.Image) color.NRGBA {
+	src := toNRGBA(img)
+	b := src.Bounds()
+	var sum [3]int
+	n := 0
+	add := func(x, y int) {
+		c := src.NRGBAAt(x, y)
+		sum[0] += int(c.R)
+		sum[1] += int(c.G)
+		sum[2] += int(c.B)
+		n++
+	}
+	for x := b.Min.X; x < b.Max.X; x++ {
+		add(x, b.Min.Y)
+		add(x, b.Max.Y-1)
+	}
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y++ {
+		add(b.Min.X, y)
+		add(b.Max.X-1, y)
+	}
+	if n == 0 {
+		return color.NRGBA{A: 255}
+	}
+	return color.NRGBA{R: uint8(sum[0] / n), G: uint8(sum[1] / n), B: uint8(sum[2] / n), A: 255}
+}
+
+// SSIM returns the mean structural similarity of a and b (same size) over
+// 8×8 windows with a stride of 4: 1 for identical planes, near 0 for
+// unrelated ones.
+func SSIM(a, b Plane) float64 {
+	const (
+		win = 8
+		c1  = (0.01 * 255) * (0.01 * 255)
+		c2  = (0.03 * 255) * (0.03 * 255)
+	)
+	if a.W != b.W || a.H != b.H || a.W < win || a.H < win {
+		return 0
+	}
+
+	var total float64
+	n := 0
+	for y0 := 0; y0+win <= a.H; y0 += win / 2 {
+		for x0 := 0; x0+win <= a.W; x0 += win / 2 {
+			var sa, sb, saa, sbb, sab float64
+			for y := y0; y < y0+win; y++ {
+				for x := x0; x < x0+win; x++ {
+					va, vb := a.Pix[y*a.W+x], b.Pix[y*b.W+x]
+					sa += va
+					sb += vb
+					saa += va * va
+					sbb += vb * vb
+					sab += va * vb
+				}
+			}
+			const m = win * win
+			ma, mb := sa/m, sb/m
+			varA := saa/m - ma*ma
+			varB := sbb/m - mb*mb
+			cov := sab/m - ma*mb
+			total += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (varA + varB + c2))
+			n++
+		}
+	}
+	return total / float64(n)
+}
+
+// contentBox returns the bounds of img's content (see Normalize).
+func contentBox(img *image.NRGBA) image.Rectangle {
+	b := img.Bounds()
+	transparent := false
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] < 255 {
+			transparent = true
+			break
+		}
+	}
+	bg := BorderColor(img)
+
+	box := image.Rectangle{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			var content bool
+			if transparent {
+				content = c.A > 0
+			} else {
+				content = absDiff(c.R, bg.R) > bgTolerance || absDiff(c.G, bg.G) > bgTolerance || absDiff(c.B, bg.B) > bgTolerance
+			}
+			if content {
+				box = box.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return box
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok {
+		return n
+	}
+	b := img.Bounds()
+	n := image.NewNRGBA(b)
+	draw.Draw(n, b, img, b.Min, draw.Src)
+	return n
+}
@@ -0,0 +1,74 @@
+package imgdiff
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var (
+	itemColor = color.NRGBA{220, 180, 40, 255}
+	screenBG  = color.NRGBA{20, 30, 40, 255}
+)
+
+// cross draws a plus sign of arm length s and thickness s/3 at (x, y) on a
+// w×h image filled with bg.
+func cross(w, h, x, y, s int, bg color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			img.SetNRGBA(px, py, bg)
+		}
+	}
+	t := s / 3
+	for py := y; py < y+s; py++ {
+		for px := x; px < x+s; px++ {
+			if (px >= x+t && px < x+2*t) || (py >= y+t && py < y+2*t) {
+				img.SetNRGBA(px, py, itemColor)
+			}
+		}
+	}
+	return img
+}
+
+func TestBorderColor(t *testing.T) {
+	if got := BorderColor(cross(40, 30, 10, 5, 18, screenBG)); got != screenBG {
+		t.Errorf("border color %v, want %v", got, screenBG)
+	}
+}
+
+func TestSSIM(t *testing.T) {
+	a := Normalize(cross(64, 64, 8, 8, 48, screenBG), 64, screenBG)
+	if got := SSIM(a, a); got < 0.999 {
+		t.Errorf("identical planes: SSIM %.3f, want 1", got)
+	}
+	if got := SSIM(a, Plane{W: 32, H: 32, Pix: make([]float64, 32*32)}); got != 0 {
+		t.Errorf("size mismatch: SSIM %.3f, want 0", got)
+	}
+}
+
+// TestNormalizeAligns compares a transparent render against an opaque
+// screenshot of the same item at another size and position: after Normalize
+// they match closely, and much better than a different item does.
+func TestNormalizeAligns(t *testing.T) {
+	const size = 64
+	render := cross(96, 96, 12, 12, 72, color.NRGBA{})
+	shot := cross(70, 50, 30, 8, 36, screenBG)
+	bg := BorderColor(shot)
+	ref := Normalize(shot, size, bg)
+
+	same := SSIM(ref, Normalize(render, size, bg))
+	if same < 0.8 {
+		t.Errorf("same item: SSIM %.3f, want ≥ 0.8", same)
+	}
+
+	other := image.NewNRGBA(image.Rect(0, 0, 96, 96))
+	for y := 10; y < 86; y++ {
+		for x := 40; x < 56; x++ {
+			other.SetNRGBA(x, y, itemColor)
+		}
+	}
+	if diff := SSIM(ref, Normalize(other, size, bg)); diff >= same-0.1 {
+		t.Errorf("different item: SSIM %.3f, want well below %.3f", diff, same)
+	}
+}
@@ -0,0 +1,68 @@
+package itembmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// LookupCodepage returns the text encoding for a codepage name such as
+// "windows-1252", "windows-874", "euc-kr" or "utf-8".
+// An empty name or "auto" returns nil, which selects auto-detection.
+func LookupCodepage(name string) (encoding.Encoding, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	if name == "" || name == "auto" {
+		return nil, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("itembmd: unknown codepage %q", name)
+	}
+	return enc, nil
+}
+
+// DecodeString converts raw item.bmd string bytes to UTF-8.
+// Bytes after the first NUL are ignored and surrounding whitespace is trimmed.
+//
+// With enc == nil the charset is auto-detected: bytes that already form valid
+// UTF-8 are kept as-is (private-server files are often re-saved as UTF-8),
+// anything else is decoded as Windows-1252, the official client's codepage.
+// Pure ASCII is identical under both, so detection never changes it.
+func DecodeString(b []byte, enc encoding.Encoding) string {
+	if i := indexNUL(b); i >= 0 {
+		b = b[:i]
+	}
+	if enc == nil {
+		if utf8.Valid(b) {
+			return strings.TrimSpace(string(b))
+		}
+		enc = charmap.Windows1252
+	}
+	decoded, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return strings.TrimSpace(string(b))
+	}
+	return strings.TrimSpace(string(decoded))
+}
+
+// ReadString decodes the fixed-width string field at data[offset:offset+length].
+// Returns "" if the field extends past the end of data.
+func ReadString(data []byte, offset, length int, enc encoding.Encoding) string {
+	if offset < 0 || offset+length > len(data) {
+		return ""
+	}
+	return DecodeString(data[offset:offset+length], enc)
+}
+
+func indexNUL(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return -1
+}
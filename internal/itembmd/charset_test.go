@@ -0,0 +1,44 @@
+package itembmd
+
+import "testing"
+
+func TestDecodeStringAutoDetect(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{"ascii", []byte("Kris\x00garbage"), "Kris"},
+		{"utf-8", []byte("Épée d'acier  \x00"), "Épée d'acier"},
+		{"windows-1252", []byte{0xC9, 'p', 0xE9, 'e', ' ', 0x80, 0}, "Épée €"},
+		{"thai in utf-8", []byte("ดาบ\x00"), "ดาบ"},
+	} {
+		if got := DecodeString(c.raw, nil); got != c.want {
+			t.Errorf("%s: DecodeString(%q) = %q, want %q", c.name, c.raw, got, c.want)
+		}
+	}
+}
+
+func TestDecodeStringCodepage(t *testing.T) {
+	enc, err := LookupCodepage("windows-874")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ดาบ in TIS-620, which auto-detection would read as Windows-1252
+	raw := []byte{0xB4, 0xD2, 0xBA}
+	if got := DecodeString(raw, enc); got != "ดาบ" {
+		t.Errorf("windows-874: got %q, want %q", got, "ดาบ")
+	}
+	if enc, err := LookupCodepage(" AUTO "); enc != nil || err != nil {
+		t.Errorf("LookupCodepage(auto) = %v, %v; want nil, nil", enc, err)
+	}
+	if _, err := LookupCodepage("klingon"); err == nil {
+		t.Error("LookupCodepage(klingon): want an error")
+	}
+}
+
+func TestReadStringOutOfRange(t *testing.T) {
+	if got := ReadString([]byte("abc"), 1, 8, nil); got != "" {
+		t.Errorf("ReadString past the end = %q, want empty", got)
+	}
+}
@@ -0,0 +1,94 @@
+package itembmd
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"mu-bmd-renderer/internal/crypto"
+
+	"golang.org/x/text/encoding"
+)
+
+// Record is one decoded item.bmd entry.
+type Record struct {
+	Section   int
+	Index     int
+	Name      string
+	ModelPath string
+	ModelFile string
+	Attrs     map[string]int // numeric attributes keyed by Field.Attr
+}
+
+// Layout describes the framing of an item.bmd file:
+// a u32 item count, Count fixed-size records, and a 4-byte trailer.
+type Layout struct {
+	Count      int
+	RecordSize int
+}
+
+// ReadLayout reads the item count and derives the per-record size.
+func ReadLayout(raw []byte) (Layout, error) {
+	if len(raw) < 8 {
+		return Layout{}, fmt.Errorf("itembmd: file too small (%d bytes)", len(raw))
+	}
+	count := int(binary.LittleEndian.Uint32(raw[0:4]))
+	if count <= 0 {
+		return Layout{}, fmt.Errorf("itembmd: invalid item count %d", count)
+	}
+	size := (len(raw) - 8) / count
+	if size <= 0 {
+		return Layout{}, fmt.Errorf("itembmd: %d items do not fit in %d bytes", count, len(raw))
+	}
+	return Layout{Count: count, RecordSize: size}, nil
+}
+
+// Decode decrypts every record in raw using profile p.
+// enc selects the item name codepage (nil = auto-detect, see DecodeString).
+func Decode(raw []byte, p *Profile, enc encoding.Encoding) ([]Record, error) {
+	lay, err := ReadLayout(raw)
+	if err != nil {
+		return nil, err
+	}
+	if lay.RecordSize < p.MinRecordSize() {
+		return nil, fmt.Errorf("itembmd: %d-byte records are too small for profile %q (needs %d)",
+			lay.RecordSize, p.Name, p.MinRecordSize())
+	}
+
+	records := make([]Record, 0, lay.Count)
+	offset := 4
+	for i := 0; i < lay.Count && offset+lay.RecordSize <= len(raw)-4; i++ {
+		// item.bmd uses the same 3-byte repeating XOR as ItemTRSData.bmd
+		rec := crypto.DecryptTRS(raw[offset : offset+lay.RecordSize])
+		records = append(records, p.decodeRecord(rec, enc))
+		offset += lay.RecordSize
+	}
+	return records, nil
+}
+
+func (p *Profile) decodeRecord(rec []byte, enc encoding.Encoding) Record {
+	r := Record{
+		Section:   int(binary.LittleEndian.Uint16(rec[p.Section:])),
+		Index:     int(binary.LittleEndian.Uint16(rec[p.Index:])),
+		ModelPath: ReadString(rec, p.ModelPath.Offset, p.ModelPath.Length, enc),
+		ModelFile: ReadString(rec, p.ModelFile.Offset, p.ModelFile.Length, enc),
+		Name:      ReadString(rec, p.ItemName.Offset, p.ItemName.Length, enc),
+		Attrs:     make(map[string]int, len(p.Fields)),
+	}
+	for _, f := range p.Fields {
+		r.Attrs[f.Attr] = readField(rec, f)
+	}
+	return r
+}
+
+func readField(rec []byte, f Field) int {
+	switch f.Kind {
+	case I8:
+		return int(int8(rec[f.Offset]))
+	case U16:
+		return int(binary.LittleEndian.Uint16(rec[f.Offset:]))
+	case U32:
+		return int(binary.LittleEndian.Uint32(rec[f.Offset:]))
+	default:
+		return int(rec[f.Offset])
+	}
+}
@@ -0,0 +1,93 @@
+package itembmd
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/crypto"
+)
+
+// fixtureRecord is the plain content of one test record.
+type fixtureRecord struct {
+	section, index int
+	name, file     string
+	damageMin      int
+	money          int
+}
+
+// buildItemBMD returns an encrypted item.bmd holding recs as size-byte
+// records laid out per Default, with a 4-byte trailer.
+func buildItemBMD(recs []fixtureRecord, size int) []byte {
+	raw := make([]byte, 4, 8+len(recs)*size)
+	binary.LittleEndian.PutUint32(raw, uint32(len(recs)))
+	for _, r := range recs {
+		rec := make([]byte, size)
+		binary.LittleEndian.PutUint16(rec[Default.Section:], uint16(r.section))
+		binary.LittleEndian.PutUint16(rec[Default.Index:], uint16(r.index))
+		copy(rec[Default.ModelFile.Offset:], r.file)
+		copy(rec[Default.ItemName.Offset:], r.name)
+		binary.LittleEndian.PutUint16(rec[604:], uint16(r.damageMin))
+		binary.LittleEndian.PutUint32(rec[652:], uint32(r.money))
+		// Padding past the fields, which decoding must ignore
+		for i := Default.MinRecordSize(); i < size; i++ {
+			rec[i] = 0x5A
+		}
+		raw = append(raw, crypto.DecryptTRS(rec)...)
+	}
+	return append(raw, 0xDE, 0xAD, 0xBE, 0xEF)
+}
+
+func TestDecodeRecordSizes(t *testing.T) {
+	recs := []fixtureRecord{
+		{section: 0, index: 3, name: "Kris", file: "Sword04.bmd", damageMin: 7, money: 1500},
+		{section: 12, index: 40, name: "Wings of Storm", file: "Wing40.bmd", damageMin: 0, money: 70000000},
+	}
+	for _, size := range []int{Default.MinRecordSize(), Default.MinRecordSize() + 36} {
+		raw := buildItemBMD(recs, size)
+		lay, err := ReadLayout(raw)
+		if err != nil {
+			t.Fatalf("size %d: ReadLayout: %v", size, err)
+		}
+		if lay.Count != len(recs) || lay.RecordSize != size {
+			t.Fatalf("size %d: layout %+v, want %d records of %d bytes", size, lay, len(recs), size)
+		}
+		got, err := Decode(raw, Default, nil)
+		if err != nil {
+			t.Fatalf("size %d: Decode: %v", size, err)
+		}
+		if len(got) != len(recs) {
+			t.Fatalf("size %d: %d records, want %d", size, len(got), len(recs))
+		}
+		for i, want := range recs {
+			r := got[i]
+			if r.Section != want.section || r.Index != want.index || r.Name != want.name || r.ModelFile != want.file {
+				t.Errorf("size %d, record %d: got %d_%d %q %q, want %d_%d %q %q", size, i,
+					r.Section, r.Index, r.Name, r.ModelFile, want.section, want.index, want.name, want.file)
+			}
+			if r.Attrs["DamageMin"] != want.damageMin || r.Attrs["Money"] != want.money {
+				t.Errorf("size %d, record %d: DamageMin %d Money %d, want %d %d", size, i,
+					r.Attrs["DamageMin"], r.Attrs["Money"], want.damageMin, want.money)
+			}
+		}
+	}
+}
+
+func TestDecodeRecordTooSmall(t *testing.T) {
+	raw := buildItemBMD([]fixtureRecord{{name: "x"}}, Default.MinRecordSize())
+	// Drop the last field byte from the record: 4-byte count + record + trailer
+	short := append(raw[:4+Default.MinRecordSize()-1:4+Default.MinRecordSize()-1], raw[len(raw)-4:]...)
+	_, err := Decode(short, Default, nil)
+	if err == nil || !strings.Contains(err.Error(), "too small") {
+		t.Fatalf("Decode of short records: err = %v, want a too-small error", err)
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	if p, err := LookupProfile("DEFAULT"); err != nil || p != Default {
+		t.Errorf("LookupProfile(DEFAULT) = %v, %v; want Default", p, err)
+	}
+	if _, err := LookupProfile("season99"); err == nil {
+		t.Error("LookupProfile(season99): want an error")
+	}
+}
@@ -0,0 +1,119 @@
+package itembmd
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"mu-bmd-renderer/internal/crypto"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Encode re-encodes records into item.bmd format using base as the template.
+//
+// Records are matched to base records by (Section, Index). Only bytes covered
+// by profile p are rewritten, so fields the profile does not know about and the
+// 4-byte file trailer are carried over from base unchanged. Records missing from
+// base are appended as new zero-filled records. String fields whose decoded value
+// is unchanged keep their original bytes; changed ones are encoded with enc
+// (nil = Windows-1252, the inverse of auto-detection's fallback).
+func Encode(base []byte, records []Record, p *Profile, enc encoding.Encoding) ([]byte, error) {
+	lay, err := ReadLayout(base)
+	if err != nil {
+		return nil, err
+	}
+	if lay.RecordSize < p.MinRecordSize() {
+		return nil, fmt.Errorf("itembmd: %d-byte records are too small for profile %q (needs %d)",
+			lay.RecordSize, p.Name, p.MinRecordSize())
+	}
+
+	// Decrypt base records and index them by (section, index)
+	var plain [][]byte
+	byKey := make(map[[2]int]int)
+	offset := 4
+	for i := 0; i < lay.Count && offset+lay.RecordSize <= len(base)-4; i++ {
+		rec := crypto.DecryptTRS(base[offset : offset+lay.RecordSize])
+		key := [2]int{
+			int(binary.LittleEndian.Uint16(rec[p.Section:])),
+			int(binary.LittleEndian.Uint16(rec[p.Index:])),
+		}
+		byKey[key] = len(plain)
+		plain = append(plain, rec)
+		offset += lay.RecordSize
+	}
+	trailer := base[len(base)-4:]
+
+	for _, r := range records {
+		key := [2]int{r.Section, r.Index}
+		i, ok := byKey[key]
+		if !ok {
+			i = len(plain)
+			byKey[key] = i
+			plain = append(plain, make([]byte, lay.RecordSize))
+		}
+		if err := p.encodeRecord(plain[i], r, enc); err != nil {
+			return nil, fmt.Errorf("itembmd: item %d_%d: %w", r.Section, r.Index, err)
+		}
+	}
+
+	out := make([]byte, 4, 8+len(plain)*lay.RecordSize)
+	binary.LittleEndian.PutUint32(out, uint32(len(plain)))
+	for _, rec := range plain {
+		// XOR is its own inverse
+		out = append(out, crypto.DecryptTRS(rec)...)
+	}
+	out = append(out, trailer...)
+	return out, nil
+}
+
+func (p *Profile) encodeRecord(rec []byte, r Record, enc encoding.Encoding) error {
+	binary.LittleEndian.PutUint16(rec[p.Section:], uint16(r.Section))
+	binary.LittleEndian.PutUint16(rec[p.Index:], uint16(r.Index))
+	if err := writeString(rec, p.ModelPath, r.ModelPath, enc); err != nil {
+		return fmt.Errorf("ModelPath: %w", err)
+	}
+	if err := writeString(rec, p.ModelFile, r.ModelFile, enc); err != nil {
+		return fmt.Errorf("ModelFile: %w", err)
+	}
+	if err := writeString(rec, p.ItemName, r.Name, enc); err != nil {
+		return fmt.Errorf("Name: %w", err)
+	}
+	for _, f := range p.Fields {
+		v, ok := r.Attrs[f.Attr]
+		if !ok {
+			continue
+		}
+		switch f.Kind {
+		case I8, U8:
+			rec[f.Offset] = byte(v)
+		case U16:
+			binary.LittleEndian.PutUint16(rec[f.Offset:], uint16(v))
+		case U32:
+			binary.LittleEndian.PutUint32(rec[f.Offset:], uint32(v))
+		}
+	}
+	return nil
+}
+
+// writeString stores s NUL-padded into field sf, leaving the original bytes
+// alone when they already decode to s.
+func writeString(rec []byte, sf StringField, s string, enc encoding.Encoding) error {
+	field := rec[sf.Offset : sf.Offset+sf.Length]
+	if DecodeString(field, enc) == s {
+		return nil
+	}
+	if enc == nil {
+		enc = charmap.Windows1252
+	}
+	b, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		return fmt.Errorf("encode %q: %w", s, err)
+	}
+	if len(b) >= sf.Length {
+		return fmt.Errorf("%q is %d bytes, field holds %d", s, len(b), sf.Length-1)
+	}
+	copy(field, b)
+	clear(field[len(b):])
+	return nil
+}
@@ -0,0 +1,122 @@
+package itembmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/crypto"
+)
+
+func TestEncodeUnchangedIsIdentity(t *testing.T) {
+	size := Default.MinRecordSize() + 36
+	base := buildItemBMD([]fixtureRecord{
+		{section: 0, index: 3, name: "Kris", file: "Sword04.bmd", damageMin: 7, money: 1500},
+		{section: 7, index: 1, name: "Épée", file: "Helm02.bmd", money: 20},
+	}, size)
+	recs, err := Decode(base, Default, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(base, recs, Default, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, base) {
+		t.Error("re-encoding the decoded records changed the file")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	size := Default.MinRecordSize() + 36
+	base := buildItemBMD([]fixtureRecord{
+		{section: 0, index: 3, name: "Kris", file: "Sword04.bmd", damageMin: 7, money: 1500},
+		{section: 7, index: 1, name: "Helm", file: "Helm02.bmd", money: 20},
+	}, size)
+	recs, err := Decode(base, Default, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs[0].Name = "Kris +1"
+	recs[0].Attrs["DamageMin"] = 9
+	recs = append(recs, Record{Section: 12, Index: 40, Name: "Cape", ModelFile: "Wing40.bmd", Attrs: map[string]int{"Money": 5}})
+
+	out, err := Encode(base, recs, Default, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out[len(out)-4:], base[len(base)-4:]) {
+		t.Error("trailer not carried over")
+	}
+	got, err := Decode(out, Default, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("%d records, want 3", len(got))
+	}
+	for i, want := range recs {
+		r := got[i]
+		if r.Section != want.Section || r.Index != want.Index || r.Name != want.Name || r.ModelFile != want.ModelFile {
+			t.Errorf("record %d: got %d_%d %q %q, want %d_%d %q %q", i,
+				r.Section, r.Index, r.Name, r.ModelFile, want.Section, want.Index, want.Name, want.ModelFile)
+		}
+		for k, v := range want.Attrs {
+			if r.Attrs[k] != v {
+				t.Errorf("record %d: %s = %d, want %d", i, k, r.Attrs[k], v)
+			}
+		}
+	}
+	// Bytes past the profile's fields come from base, zeros for the new record
+	rec := func(i int) []byte { return crypto.DecryptTRS(out[4+i*size : 4+(i+1)*size]) }
+	if rec(0)[size-1] != 0x5A || rec(2)[size-1] != 0 {
+		t.Errorf("padding: %#x in an existing record, %#x in the new one; want 0x5a, 0", rec(0)[size-1], rec(2)[size-1])
+	}
+}
+
+func TestEncodeNameTooLong(t *testing.T) {
+	base := buildItemBMD([]fixtureRecord{{name: "x"}}, Default.MinRecordSize())
+	recs, _ := Decode(base, Default, nil)
+	recs[0].Name = strings.Repeat("n", Default.ItemName.Length)
+	if _, err := Encode(base, recs, Default, nil); err == nil {
+		t.Error("Encode of an overlong name: want an error")
+	}
+}
+
+func TestParseXML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ItemList.xml")
+	xml := `<ItemList>
+  <Section Index="0" Name="Swords">
+    <Item Index="3" Name="Kris" ModelPath="Data\Item\" ModelFile="Sword04.bmd" DamageMin="7" Money="1500"/>
+  </Section>
+  <Section Index="12">
+    <Item Index="40" Name="Cape" ModelFile="Wing40.bmd"/>
+  </Section>
+</ItemList>`
+	if err := os.WriteFile(path, []byte(xml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := ParseXML(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("%d records, want 2", len(recs))
+	}
+	r := recs[0]
+	if r.Section != 0 || r.Index != 3 || r.Name != "Kris" || r.ModelPath != `Data\Item\` || r.Attrs["DamageMin"] != 7 || r.Attrs["Money"] != 1500 {
+		t.Errorf("record 0 = %+v", r)
+	}
+	if recs[1].Section != 12 || recs[1].Index != 40 {
+		t.Errorf("record 1 = %+v", recs[1])
+	}
+
+	if err := os.WriteFile(path, []byte(`<ItemList><Section Index="0"><Item Index="1" Money="lots"/></Section></ItemList>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseXML(path); err == nil {
+		t.Error("ParseXML of a non-numeric attribute: want an error")
+	}
+}
@@ -0,0 +1,171 @@
+package itembmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldKind is the binary encoding of a numeric record field.
+type FieldKind int
+
+const (
+	U8 FieldKind = iota
+	I8
+	U16
+	U32
+)
+
+// Size returns the field width in bytes.
+func (k FieldKind) Size() int {
+	switch k {
+	case U16:
+		return 2
+	case U32:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// Field maps one ItemList.xml attribute to its location in a decrypted record.
+type Field struct {
+	Attr   string // XML attribute name, e.g. "DamageMin"
+	Offset int
+	Kind   FieldKind
+}
+
+// StringField is a fixed-width, NUL-padded string inside a record.
+type StringField struct {
+	Offset int
+	Length int
+}
+
+// Profile describes the record layout of one item.bmd client version.
+//
+// To support another client version, add a Profile to Profiles with the
+// offsets of each field and select it by Name (decodeitem -profile). The
+// layout is not guessed from the record size (decodeitem prints it as
+// "bytes/item"): records only need to be at least MinRecordSize bytes, and
+// client versions pad them differently. Fields may be listed in any order and
+// omitted if the version lacks them; missing attributes decode as 0.
+// ItemList.xml attribute order always follows Default.Fields.
+type Profile struct {
+	Name string
+
+	Section   int // u16 offset of the item group (section)
+	Index     int // u16 offset of the item index within the section
+	ModelPath StringField
+	ModelFile StringField
+	ItemName  StringField
+
+	Fields []Field // numeric attributes
+}
+
+// Default is the layout decodeitem was originally written against.
+var Default = &Profile{
+	Name:      "default",
+	Section:   4,
+	Index:     6,
+	ModelPath: StringField{8, 260},
+	ModelFile: StringField{268, 260},
+	ItemName:  StringField{528, 64},
+	Fields: []Field{
+		{"KindA", 592, U8},
+		{"KindB", 593, U8},
+		{"Type", 594, U8},
+		{"Slot", 598, I8},
+		{"TwoHand", 595, U8},
+		{"SkillIndex", 600, U16},
+		{"Width", 602, U8},
+		{"Height", 603, U8},
+		{"DamageMin", 604, U16},
+		{"DamageMax", 606, U16},
+		{"Defense", 610, U16},
+		{"SuccessfulBlocking", 608, U16},
+		{"AttackSpeed", 614, U16},
+		{"WalkSpeed", 612, U16},
+		{"Durability", 616, U16},
+		{"MagicDurability", 620, U16},
+		{"MagicPower", 624, U32},
+		{"DropLevel", 596, U16},
+		{"CombatPower", 628, U16},
+		{"AttackRate", 632, U16},
+		{"ReqLevel", 646, U16},
+		{"ReqStrength", 636, U16},
+		{"ReqDexterity", 638, U16},
+		{"ReqEnergy", 640, U16},
+		{"ReqVitality", 642, U16},
+		{"ReqCommand", 644, U16},
+		{"Money", 652, U32},
+		{"SetAttrib", 656, U8},
+		{"DarkWizard", 657, U8},
+		{"DarkKnight", 658, U8},
+		{"FairyElf", 659, U8},
+		{"MagicGladiator", 660, U8},
+		{"DarkLord", 661, U8},
+		{"Summoner", 662, U8},
+		{"RageFighter", 663, U8},
+		{"GrowLancer", 664, U8},
+		{"RuneWizard", 665, U8},
+		{"Slayer", 666, U8},
+		{"GunCrusher", 667, U8},
+		{"LightWizard", 668, U8},
+		{"LemuriaMage", 669, U8},
+		{"IllusionKnight", 670, U8},
+		{"Alchemist", 671, U8},
+		{"Crusader", 672, U8},
+		{"IceRes", 673, U8},
+		{"PoisonRes", 674, U8},
+		{"LightRes", 675, U8},
+		{"FireRes", 676, U8},
+		{"EarthRes", 677, U8},
+		{"WindRes", 678, U8},
+		{"WaterRes", 679, U8},
+		{"Dump", 680, U8},
+		{"Transaction", 681, U8},
+		{"PersonalStore", 682, U8},
+		{"StoreWarehouse", 683, U8},
+		{"SellToNPC", 684, U8},
+		{"ExpensiveItem", 685, U8},
+		{"Repair", 686, U8},
+		{"Overlap", 687, U8},
+		{"NonValue", 688, U8},
+		{"ElementalDefense", 702, U16},
+	},
+}
+
+// Profiles lists all known layouts.
+var Profiles = []*Profile{Default}
+
+// MinRecordSize returns the smallest record size that holds every field.
+func (p *Profile) MinRecordSize() int {
+	end := p.Index + 2
+	if e := p.Section + 2; e > end {
+		end = e
+	}
+	for _, s := range []StringField{p.ModelPath, p.ModelFile, p.ItemName} {
+		if e := s.Offset + s.Length; e > end {
+			end = e
+		}
+	}
+	for _, f := range p.Fields {
+		if e := f.Offset + f.Kind.Size(); e > end {
+			end = e
+		}
+	}
+	return end
+}
+
+// LookupProfile returns the profile with the given name (case-insensitive).
+func LookupProfile(name string) (*Profile, error) {
+	for _, p := range Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	names := make([]string, len(Profiles))
+	for i, p := range Profiles {
+		names[i] = p.Name
+	}
+	return nil, fmt.Errorf("itembmd: unknown profile %q (known: %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,130 @@
+package itembmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Cond is one comparison in a Query, e.g. ReqLevel>300.
+type Cond struct {
+	Field string // Section, Index, Name, ModelPath, ModelFile, or a Field.Attr
+	Op    string // =, !=, <, <=, >, >=, or ~ (case-insensitive substring, strings only)
+	Num   int
+	Str   string
+}
+
+// Query is a conjunction of conditions over a Record.
+type Query []Cond
+
+var (
+	querySplit = regexp.MustCompile(`(?i)\s+and\s+|\s*&&\s*`)
+	condRe     = regexp.MustCompile(`^\s*(\w+)\s*(==|!=|<=|>=|=|<|>|~)\s*(.*?)\s*$`)
+)
+
+// ParseQuery parses an expression such as
+//
+//	ReqLevel>300 and DarkKnight=1
+//	Name~"dragon" && Section=7
+//
+// Clauses are joined with "and" or "&&"; there is no "or". Numeric fields are
+// Section, Index and any attribute known to a profile in Profiles; Name,
+// ModelPath and ModelFile compare as strings (= and != ignore case, ~ matches
+// a substring). An unknown field is an error, so a typo can't match nothing.
+func ParseQuery(expr string) (Query, error) {
+	known := make(map[string]bool)
+	for _, p := range Profiles {
+		for _, f := range p.Fields {
+			known[f.Attr] = true
+		}
+	}
+
+	var q Query
+	for _, clause := range querySplit.Split(strings.TrimSpace(expr), -1) {
+		m := condRe.FindStringSubmatch(clause)
+		if m == nil {
+			return nil, fmt.Errorf("itembmd: bad condition %q (want Field op value)", clause)
+		}
+		c := Cond{Field: m[1], Op: m[2], Str: strings.Trim(m[3], `"'`)}
+		if c.Op == "==" {
+			c.Op = "="
+		}
+		switch {
+		case isStringField(c.Field):
+			if c.Op != "=" && c.Op != "!=" && c.Op != "~" {
+				return nil, fmt.Errorf("itembmd: %s is text; use =, != or ~", c.Field)
+			}
+		case c.Field == "Section" || c.Field == "Index" || known[c.Field]:
+			if c.Op == "~" {
+				return nil, fmt.Errorf("itembmd: %s is a number; ~ only applies to text fields", c.Field)
+			}
+			n, err := strconv.Atoi(c.Str)
+			if err != nil {
+				return nil, fmt.Errorf("itembmd: %s%s%s: %q is not a number", c.Field, c.Op, m[3], c.Str)
+			}
+			c.Num = n
+		default:
+			return nil, fmt.Errorf("itembmd: unknown field %q", c.Field)
+		}
+		q = append(q, c)
+	}
+	return q, nil
+}
+
+// Match reports whether r satisfies every condition.
+// Attributes missing from r compare as 0, as decodeRecord leaves them.
+func (q Query) Match(r Record) bool {
+	for _, c := range q {
+		if !c.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Cond) match(r Record) bool {
+	if isStringField(c.Field) {
+		v := r.Name
+		switch c.Field {
+		case "ModelPath":
+			v = r.ModelPath
+		case "ModelFile":
+			v = r.ModelFile
+		}
+		switch c.Op {
+		case "~":
+			return strings.Contains(strings.ToLower(v), strings.ToLower(c.Str))
+		case "!=":
+			return !strings.EqualFold(v, c.Str)
+		default:
+			return strings.EqualFold(v, c.Str)
+		}
+	}
+
+	v := r.Attrs[c.Field]
+	switch c.Field {
+	case "Section":
+		v = r.Section
+	case "Index":
+		v = r.Index
+	}
+	switch c.Op {
+	case "!=":
+		return v != c.Num
+	case "<":
+		return v < c.Num
+	case "<=":
+		return v <= c.Num
+	case ">":
+		return v > c.Num
+	case ">=":
+		return v >= c.Num
+	default:
+		return v == c.Num
+	}
+}
+
+func isStringField(name string) bool {
+	return name == "Name" || name == "ModelPath" || name == "ModelFile"
+}
@@ -0,0 +1,49 @@
+package itembmd
+
+import "testing"
+
+func TestQueryMatch(t *testing.T) {
+	helm := Record{Section: 7, Index: 3, Name: "Dragon Helm", ModelFile: "HelmMale03.bmd", Attrs: map[string]int{"ReqLevel": 320, "DarkKnight": 1}}
+	sword := Record{Section: 0, Index: 5, Name: "Blade", ModelFile: "Sword06.bmd", Attrs: map[string]int{"ReqLevel": 60}}
+	for _, c := range []struct {
+		expr        string
+		helm, sword bool
+	}{
+		{"ReqLevel>300", true, false},
+		{"ReqLevel>300 and DarkKnight=1", true, false},
+		{"ReqLevel>=60 AND Section != 7", false, true},
+		{`Name~"dragon" && Section=7`, true, false},
+		{"Name=blade", false, true},
+		{"ModelFile!=sword06.BMD", true, false},
+		{"DarkKnight=0", false, true}, // missing attributes compare as 0
+		{"Index<=5 && Index>3", false, true},
+		{"Section==0", false, true},
+	} {
+		q, err := ParseQuery(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		if got := q.Match(helm); got != c.helm {
+			t.Errorf("%s: helm matched %v, want %v", c.expr, got, c.helm)
+		}
+		if got := q.Match(sword); got != c.sword {
+			t.Errorf("%s: sword matched %v, want %v", c.expr, got, c.sword)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, expr := range []string{
+		"ReqLvl>300",           // unknown field
+		"ReqLevel>high",        // not a number
+		"ReqLevel~3",           // ~ on a number
+		"Name>dragon",          // order on text
+		"ReqLevel",             // no operator
+		"Section=1 or Index=2", // no "or"
+	} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("%q: no error", expr)
+		}
+	}
+}
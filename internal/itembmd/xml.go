@@ -0,0 +1,66 @@
+package itembmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type xmlItemList struct {
+	Sections []xmlSection `xml:"Section"`
+}
+
+type xmlSection struct {
+	Index int       `xml:"Index,attr"`
+	Items []xmlItem `xml:"Item"`
+}
+
+type xmlItem struct {
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+// ParseXML reads an ItemList.xml (as written by decodeitem) back into records.
+// Every numeric attribute is kept in Record.Attrs, so it can be re-encoded
+// with any profile that knows the field.
+func ParseXML(xmlPath string) ([]Record, error) {
+	raw, err := os.ReadFile(xmlPath)
+	if err != nil {
+		return nil, fmt.Errorf("itembmd: read %s: %w", xmlPath, err)
+	}
+
+	var list xmlItemList
+	if err := xml.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("itembmd: parse %s: %w", xmlPath, err)
+	}
+
+	var records []Record
+	for _, sec := range list.Sections {
+		for _, it := range sec.Items {
+			r := Record{Section: sec.Index, Attrs: make(map[string]int, len(it.Attrs))}
+			for _, a := range it.Attrs {
+				switch a.Name.Local {
+				case "Name":
+					r.Name = a.Value
+				case "ModelPath":
+					r.ModelPath = a.Value
+				case "ModelFile":
+					r.ModelFile = a.Value
+				default:
+					v, err := strconv.Atoi(a.Value)
+					if err != nil {
+						return nil, fmt.Errorf("itembmd: %s: section %d: %s=%q is not a number",
+							xmlPath, sec.Index, a.Name.Local, a.Value)
+					}
+					if a.Name.Local == "Index" {
+						r.Index = v
+					} else {
+						r.Attrs[a.Name.Local] = v
+					}
+				}
+			}
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
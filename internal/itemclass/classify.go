@@ -0,0 +1,60 @@
+// Package itemclass guesses an item's category from its model file name.
+package itemclass
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Category is an item type inferred from the model file name.
+type Category string
+
+const (
+	Wing    Category = "wing"
+	Cape    Category = "cape"
+	Pet     Category = "pet"
+	Gem     Category = "gem"
+	Seed    Category = "seed"
+	Penta   Category = "penta"
+	Scroll  Category = "scroll"
+	Earring Category = "earring"
+	Ring    Category = "ring"
+	Box     Category = "box"
+	Book    Category = "book"
+	Misc    Category = "misc" // no pattern matched
+)
+
+// Categories lists every category in classification priority order.
+var Categories = []Category{Wing, Cape, Pet, Gem, Seed, Penta, Scroll, Earring, Ring, Box, Book, Misc}
+
+// Model name patterns, checked in order (first match wins)
+var rules = []struct {
+	cat Category
+	re  *regexp.Regexp
+}{
+	{Wing, regexp.MustCompile(`(?i)^wing\d+|chaoswing|magic_wing|flamewing|conquerorwing|angel_devil_wing|wingsofpower|ManaBurstWing|SpiritualWorldWing`)},
+	{Cape, regexp.MustCompile(`(?i)^darklord|cape_of|cloak|jacquard_|PureWhite|Innocence|Sparkle|Resplendent|Limit_wing`)},
+	{Pet, regexp.MustCompile(`(?i)_inven|_in[BR]\.|moru|petEgg|Apocal_stone|lightning_(?:stone|anvil)|Ghosthorse`)},
+	{Gem, regexp.MustCompile(`(?i)^gem\d+|^jewel|^attjewel|gemmix|SpiritDust|spellstone`)},
+	{Seed, regexp.MustCompile(`(?i)^s30_`)},
+	{Penta, regexp.MustCompile(`(?i)^penta`)},
+	{Scroll, regexp.MustCompile(`(?i)scroll|rollofpaper`)},
+	{Earring, regexp.MustCompile(`(?i)earring|so_neck`)},
+	{Ring, regexp.MustCompile(`(?i)^ring\d+`)},
+	{Box, regexp.MustCompile(`(?i)box|gift`)},
+	{Book, regexp.MustCompile(`(?i)^book\d+|strengscroll`)},
+}
+
+// Classify returns the category for a model file name (e.g. "Wing01.bmd").
+// Only the base name without extension is matched; Misc means unknown.
+func Classify(modelFile string) Category {
+	base := filepath.Base(strings.ReplaceAll(modelFile, "\\", "/"))
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	for _, r := range rules {
+		if r.re.MatchString(stem) {
+			return r.cat
+		}
+	}
+	return Misc
+}
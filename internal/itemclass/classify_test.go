@@ -0,0 +1,55 @@
+package itemclass
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	for _, c := range []struct {
+		model string
+		want  Category
+	}{
+		{"Wing01.bmd", Wing},
+		{"wing01.BMD", Wing},
+		{`Data\Item\ChaosWing.bmd`, Wing},
+		{"Limit_wing03.bmd", Cape}, // capes named like wings
+		{"DarkLordCape.bmd", Cape},
+		{"Horn_inven.bmd", Pet},
+		{"Jewel01.bmd", Gem},
+		{"s30_fire.bmd", Seed},
+		{"PentaGram01.bmd", Penta},
+		{"ScrollOfPower.bmd", Scroll},
+		{"StrengScroll01.bmd", Scroll}, // Scroll ranks above Book
+		{"Earring03.bmd", Earring},
+		{"Ring01.bmd", Ring},
+		{"EarRing01.bmd", Earring}, // not ^ring
+		{"GiftBox.bmd", Box},
+		{"Book02.bmd", Book},
+		{"Sword01.bmd", Misc},
+		{"Wing.bmd", Misc}, // ^wing needs a number
+		{"", Misc},
+	} {
+		if got := Classify(c.model); got != c.want {
+			t.Errorf("Classify(%q) = %q, want %q", c.model, got, c.want)
+		}
+	}
+}
+
+func TestCategoriesCoverRules(t *testing.T) {
+	seen := map[Category]bool{}
+	for _, c := range Categories {
+		if seen[c] {
+			t.Errorf("category %q listed twice", c)
+		}
+		seen[c] = true
+	}
+	for i, r := range rules {
+		if !seen[r.cat] {
+			t.Errorf("rule %d: category %q missing from Categories", i, r.cat)
+		}
+		if Categories[i] != r.cat {
+			t.Errorf("Categories[%d] = %q, want rule order %q", i, Categories[i], r.cat)
+		}
+	}
+	if Categories[len(Categories)-1] != Misc {
+		t.Errorf("Categories ends with %q, want Misc", Categories[len(Categories)-1])
+	}
+}
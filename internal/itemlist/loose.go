@@ -0,0 +1,141 @@
+package itemlist
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Attribute names accepted by parseLoose, compared case-insensitively.
+// The first one present wins.
+var (
+	looseSectionAttrs = []string{"Section", "Group"}
+	looseIndexAttrs   = []string{"Index", "ID", "ItemIndex"}
+	looseNameAttrs    = []string{"Name", "ItemName"}
+	loosePathAttrs    = []string{"ModelPath", "Path"}
+	looseFileAttrs    = []string{"ModelFile", "Model", "File"}
+)
+
+// isStandardSchema reports whether raw's first element under the root is a
+// Section element with an Index attribute, spelled exactly as parseStandard
+// expects.
+func isStandardSchema(raw []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return true // let parseStandard report the error
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				return t.Name.Local == "Section" && attr(t, "Index", false) != ""
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// parseLoose reads item lists that don't follow the standard schema:
+// sections and items in any attribute casing, or a flat list of Item elements
+// that carry their section in a Group (or Section) attribute.
+// Items take their section from that attribute if present, else from the
+// enclosing Section element; section names come from the enclosing element's
+// Name (Sections falls back to SectionNames when there is none).
+func parseLoose(raw []byte) ([]ItemDef, error) {
+	var stack []looseSection // enclosing Section elements
+	var items []ItemDef
+
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "section", "group":
+				idx, err := strconv.Atoi(firstAttr(t, looseIndexAttrs))
+				stack = append(stack, looseSection{idx, firstAttr(t, looseNameAttrs), err == nil})
+			case "item":
+				it, ok := looseItem(t, stack)
+				if ok {
+					items = append(items, it)
+				}
+			}
+		case xml.EndElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "section", "group":
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+	}
+}
+
+// looseSection is an open Section element in parseLoose.
+type looseSection struct {
+	index int
+	name  string
+	ok    bool // index parsed
+}
+
+// looseItem converts one Item element; ok is false when it has no model
+// file or no usable section or index.
+func looseItem(t xml.StartElement, stack []looseSection) (ItemDef, bool) {
+	file := firstAttr(t, looseFileAttrs)
+	idx, err := strconv.Atoi(firstAttr(t, looseIndexAttrs))
+	if file == "" || err != nil {
+		return ItemDef{}, false
+	}
+
+	var enclosing looseSection
+	if len(stack) > 0 {
+		enclosing = stack[len(stack)-1]
+	}
+	secIdx := enclosing.index
+	if v := firstAttr(t, looseSectionAttrs); v != "" {
+		if secIdx, err = strconv.Atoi(v); err != nil {
+			return ItemDef{}, false
+		}
+	} else if !enclosing.ok {
+		return ItemDef{}, false
+	}
+	secName := ""
+	if enclosing.ok && enclosing.index == secIdx {
+		secName = enclosing.name
+	}
+
+	return newItem(secIdx, secName, idx, firstAttr(t, looseNameAttrs), firstAttr(t, loosePathAttrs), file), true
+}
+
+// firstAttr returns the value of the first of names present on t
+// (case-insensitive), or "".
+func firstAttr(t xml.StartElement, names []string) string {
+	for _, n := range names {
+		if v := attr(t, n, true); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// attr returns t's attribute name, optionally ignoring case.
+func attr(t xml.StartElement, name string, fold bool) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name || (fold && strings.EqualFold(a.Name.Local, name)) {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"mu-bmd-renderer/internal/itemclass"
 )
 
 // xmlItemList matches the ItemList.xml schema.
@@ -27,15 +29,31 @@ type xmlItem struct {
 }
 
 // Parse reads ItemList.xml and returns all items with model files.
+// The standard schema (ItemList > Section > Item with the attribute names
+// above) is tried first; files from other tools — a flat list of items with
+// a Group attribute, or different attribute casing — are read by parseLoose.
 func Parse(xmlPath string) ([]ItemDef, error) {
 	raw, err := os.ReadFile(xmlPath)
 	if err != nil {
 		return nil, fmt.Errorf("itemlist: read %s: %w", xmlPath, err)
 	}
 
+	var items []ItemDef
+	if isStandardSchema(raw) {
+		items, err = parseStandard(raw)
+	} else {
+		items, err = parseLoose(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("itemlist: parse %s: %w", xmlPath, err)
+	}
+	return items, nil
+}
+
+func parseStandard(raw []byte) ([]ItemDef, error) {
 	var list xmlItemList
 	if err := xml.Unmarshal(raw, &list); err != nil {
-		return nil, fmt.Errorf("itemlist: parse %s: %w", xmlPath, err)
+		return nil, err
 	}
 
 	var items []ItemDef
@@ -52,28 +70,33 @@ func Parse(xmlPath string) ([]ItemDef, error) {
 			if err != nil {
 				continue
 			}
-
-			// Extract subdirectory from ModelPath.
-			// ModelPath is like "Data\Item\Jewel\" — strip "Data\Item\" prefix
-			// to get subdirectory "Jewel" (or "" for default).
-			subDir := ""
-			mp := strings.ReplaceAll(item.ModelPath, "\\", "/")
-			mp = strings.TrimSuffix(mp, "/")
-			const defaultPrefix = "Data/Item"
-			if strings.HasPrefix(mp, defaultPrefix+"/") {
-				subDir = mp[len(defaultPrefix)+1:]
-			}
-
-			items = append(items, ItemDef{
-				Section:     secIdx,
-				SectionName: sec.Name,
-				Index:       idx,
-				Name:        item.Name,
-				ModelFile:   item.ModelFile,
-				SubDir:      subDir,
-			})
+			items = append(items, newItem(secIdx, sec.Name, idx, item.Name, item.ModelPath, item.ModelFile))
 		}
 	}
 
 	return items, nil
 }
+
+// newItem builds an ItemDef, deriving SubDir and Category.
+func newItem(section int, sectionName string, index int, name, modelPath, modelFile string) ItemDef {
+	// Extract subdirectory from ModelPath.
+	// ModelPath is like "Data\Item\Jewel\" — strip "Data\Item\" prefix
+	// to get subdirectory "Jewel" (or "" for default).
+	subDir := ""
+	mp := strings.ReplaceAll(modelPath, "\\", "/")
+	mp = strings.TrimSuffix(mp, "/")
+	const defaultPrefix = "Data/Item"
+	if strings.HasPrefix(mp, defaultPrefix+"/") {
+		subDir = mp[len(defaultPrefix)+1:]
+	}
+
+	return ItemDef{
+		Section:     section,
+		SectionName: sectionName,
+		Index:       index,
+		Name:        name,
+		ModelFile:   modelFile,
+		SubDir:      subDir,
+		Category:    itemclass.Classify(modelFile),
+	}
+}
@@ -0,0 +1,117 @@
+package itemlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/itemclass"
+)
+
+// The same three items in each schema Parse reads. The flat list has no
+// section elements, so it carries no section names.
+const (
+	standardList = `<?xml version="1.0" encoding="utf-8"?>
+<ItemList>
+  <Section Index="0" Name="Swords">
+    <Item Index="0" Name="Kris" ModelPath="Data\Item\" ModelFile="Sword01.bmd"/>
+    <Item Index="1" Name="No Model"/>
+  </Section>
+  <Section Index="12" Name="Wings">
+    <Item Index="0" Name="Wings of Elf" ModelPath="Data\Item\" ModelFile="Wing01.bmd"/>
+  </Section>
+  <Section Index="14" Name="Jewels">
+    <Item Index="13" Name="Jewel of Bless" ModelPath="Data\Item\Jewel\" ModelFile="Jewel01.bmd"/>
+  </Section>
+</ItemList>`
+
+	casingList = `<itemlist>
+  <section index="0" name="Swords">
+    <item index="0" name="Kris" modelpath="Data\Item\" modelfile="Sword01.bmd"/>
+    <item index="1" name="No Model"/>
+  </section>
+  <SECTION INDEX="12" NAME="Wings">
+    <ITEM INDEX="0" NAME="Wings of Elf" MODELPATH="Data\Item\" MODELFILE="Wing01.bmd"/>
+  </SECTION>
+  <Section Index="14" Name="Jewels">
+    <Item ID="13" ItemName="Jewel of Bless" Path="Data\Item\Jewel\" Model="Jewel01.bmd"/>
+  </Section>
+</itemlist>`
+
+	flatList = `<Items>
+  <Item Group="0" Index="0" Name="Kris" ModelPath="Data\Item\" ModelFile="Sword01.bmd"/>
+  <Item Group="0" Index="1" Name="No Model"/>
+  <Item Group="12" Index="0" Name="Wings of Elf" ModelPath="Data\Item\" ModelFile="Wing01.bmd"/>
+  <Item Group="14" Index="13" Name="Jewel of Bless" ModelPath="Data\Item\Jewel\" ModelFile="Jewel01.bmd"/>
+  <Item Index="20" Name="No Group" ModelFile="Sword02.bmd"/>
+</Items>`
+)
+
+var wantItems = []ItemDef{
+	{Section: 0, SectionName: "Swords", Index: 0, Name: "Kris", ModelFile: "Sword01.bmd", Category: itemclass.Misc},
+	{Section: 12, SectionName: "Wings", Index: 0, Name: "Wings of Elf", ModelFile: "Wing01.bmd", Category: itemclass.Wing},
+	{Section: 14, SectionName: "Jewels", Index: 13, Name: "Jewel of Bless", ModelFile: "Jewel01.bmd", SubDir: "Jewel", Category: itemclass.Gem},
+}
+
+func parseString(t *testing.T, xml string) []ItemDef {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ItemList.xml")
+	if err := os.WriteFile(path, []byte(xml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestParseSchemas(t *testing.T) {
+	for _, c := range []struct {
+		name, xml string
+		names     bool // section names expected
+	}{
+		{"standard", standardList, true},
+		{"casing", casingList, true},
+		{"flat", flatList, false},
+	} {
+		want := make([]ItemDef, len(wantItems))
+		copy(want, wantItems)
+		if !c.names {
+			for i := range want {
+				want[i].SectionName = ""
+			}
+		}
+		if got := parseString(t, c.xml); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", c.name, got, want)
+		}
+	}
+}
+
+func TestIsStandardSchema(t *testing.T) {
+	for _, c := range []struct {
+		xml  string
+		want bool
+	}{
+		{standardList, true},
+		{casingList, false},
+		{flatList, false},
+		{"<ItemList><Section Name=\"x\"/></ItemList>", false}, // no Index
+	} {
+		if got := isStandardSchema([]byte(c.xml)); got != c.want {
+			t.Errorf("isStandardSchema(%.40q) = %v, want %v", c.xml, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse(filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("missing file: no error")
+	}
+	path := filepath.Join(t.TempDir(), "ItemList.xml")
+	os.WriteFile(path, []byte(`<Items><Item Group="0" Index="0" ModelFile="a.bmd">`), 0644)
+	if _, err := Parse(path); err == nil {
+		t.Error("truncated flat list: no error")
+	}
+}
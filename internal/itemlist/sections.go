@@ -0,0 +1,45 @@
+package itemlist
+
+import "sort"
+
+// SectionNames holds the conventional names of item sections, used when an
+// ItemList.xml section has no Name attribute.
+var SectionNames = map[int]string{
+	0: "Swords", 1: "Axes", 2: "Maces and Scepters", 3: "Spears",
+	4: "Bows and Crossbows", 5: "Staffs", 6: "Shields",
+	7: "Helmets", 8: "Armors", 9: "Pants", 10: "Gloves", 11: "Boots",
+	12: "Pets and Rings and Misc", 13: "Jewel and Misc",
+	14: "Wings and Orbs and Spheres", 15: "Scrolls", 16: "Muuns",
+	19: "Uncategorized", 20: "Uncategorized", 21: "Cloaks",
+}
+
+// SectionInfo summarizes one section of an item list.
+type SectionInfo struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+// Sections groups items by section, in section order. The name comes from
+// the XML, falling back to SectionNames.
+func Sections(items []ItemDef) []SectionInfo {
+	bySec := make(map[int]*SectionInfo)
+	for _, it := range items {
+		s := bySec[it.Section]
+		if s == nil {
+			s = &SectionInfo{Index: it.Section, Name: it.SectionName}
+			if s.Name == "" {
+				s.Name = SectionNames[it.Section]
+			}
+			bySec[it.Section] = s
+		}
+		s.Items++
+	}
+
+	out := make([]SectionInfo, 0, len(bySec))
+	for _, s := range bySec {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Index < out[j].Index })
+	return out
+}
@@ -1,11 +1,14 @@
 package itemlist
 
+import "mu-bmd-renderer/internal/itemclass"
+
 // ItemDef holds one item parsed from ItemList.xml.
 type ItemDef struct {
 	Section     int
 	SectionName string
 	Index       int
 	Name        string
-	ModelFile   string // e.g. "sword04.bmd"
-	SubDir      string // subdirectory under ItemDir, e.g. "Jewel" (from ModelPath)
+	ModelFile   string             // e.g. "sword04.bmd"
+	SubDir      string             // subdirectory under ItemDir, e.g. "Jewel" (from ModelPath)
+	Category    itemclass.Category // guessed from ModelFile
 }
@@ -0,0 +1,161 @@
+// Package logging is the leveled output the renderer packages print
+// through, so a caller can quiet or redirect them.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is how much gets printed: each level includes the ones before it.
+type Level int
+
+const (
+	LevelError Level = iota // failures only
+	LevelWarn               // + recoverable problems (bad config files, skipped steps)
+	LevelInfo               // + progress and summaries (the default)
+	LevelDebug              // + per-item detail (-verbose)
+)
+
+var levelNames = [...]string{"error", "warn", "info", "debug"}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses "error", "warn", "info", or "debug" ("" = info).
+func ParseLevel(s string) (Level, error) {
+	if s == "" {
+		return LevelInfo, nil
+	}
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (use error, warn, info, or debug)", s)
+}
+
+// Logger receives the packages' output. Errorf and Warnf messages are
+// complete lines without a prefix; Printf and Debugf text is printed as
+// given, so it may span several lines or none.
+type Logger interface {
+	Errorf(format string, args ...any)
+	Warnf(format string, args ...any)
+	Printf(format string, args ...any)
+	Debugf(format string, args ...any)
+}
+
+// writer is the Logger returned by New.
+type writer struct {
+	out, errOut io.Writer
+	level       Level
+	mu          sync.Mutex // one message at a time, so concurrent workers' lines don't interleave
+}
+
+// New returns a Logger that prints messages up to level: Printf and Debugf
+// text to out, errors and warnings to errOut as "Error: ..." and
+// "Warning: ..." lines.
+func New(out, errOut io.Writer, level Level) Logger {
+	return &writer{out: out, errOut: errOut, level: level}
+}
+
+// std is the Logger returned by Default.
+var std atomic.Pointer[Logger]
+
+func init() {
+	SetDefault(New(os.Stdout, os.Stderr, LevelInfo))
+}
+
+// Default returns the Logger that packages without a Config (trs,
+// postprocess) print through, and that a nil Config Logger stands for.
+// Until SetDefault it is info level on stdout and stderr, the output the
+// tools have always printed.
+func Default() Logger {
+	return *std.Load()
+}
+
+// SetDefault replaces the Logger returned by Default, e.g. to silence the
+// library (Discard) or send it to a server's log (FromStd). Call it before
+// loading or rendering starts.
+func SetDefault(l Logger) {
+	std.Store(&l)
+}
+
+// Discard drops everything.
+var Discard = New(io.Discard, io.Discard, LevelError)
+
+func (w *writer) Errorf(format string, args ...any) {
+	w.line(LevelError, w.errOut, "Error: ", format, args)
+}
+
+func (w *writer) Warnf(format string, args ...any) {
+	w.line(LevelWarn, w.errOut, "Warning: ", format, args)
+}
+
+func (w *writer) Printf(format string, args ...any) {
+	w.print(LevelInfo, format, args)
+}
+
+func (w *writer) Debugf(format string, args ...any) {
+	w.print(LevelDebug, format, args)
+}
+
+func (w *writer) line(l Level, dst io.Writer, prefix, format string, args []any) {
+	if l > w.level {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(dst, "%s%s\n", prefix, msg)
+}
+
+func (w *writer) print(l Level, format string, args []any) {
+	if l > w.level {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, format, args...)
+}
+
+// Or returns l, or Default() if l is nil.
+func Or(l Logger) Logger {
+	if l == nil {
+		return Default()
+	}
+	return l
+}
+
+// stdLogger is the Logger returned by FromStd.
+type stdLogger struct {
+	l     *log.Logger
+	level Level
+}
+
+// FromStd returns a Logger that prints messages up to level through a
+// standard library logger, one l.Print call per message (so with its
+// prefix and flags), errors and warnings with "Error: " and "Warning: ".
+func FromStd(l *log.Logger, level Level) Logger {
+	return stdLogger{l, level}
+}
+
+func (s stdLogger) Errorf(format string, args ...any) { s.print(LevelError, "Error: ", format, args) }
+func (s stdLogger) Warnf(format string, args ...any)  { s.print(LevelWarn, "Warning: ", format, args) }
+func (s stdLogger) Printf(format string, args ...any) { s.print(LevelInfo, "", format, args) }
+func (s stdLogger) Debugf(format string, args ...any) { s.print(LevelDebug, "", format, args) }
+
+func (s stdLogger) print(l Level, prefix, format string, args []any) {
+	if l <= s.level {
+		s.l.Print(prefix + fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+// TestLevels prints one message of each kind at every level: each level
+// keeps the ones at or below it, errors and warnings go to errOut as
+// prefixed lines, Printf and Debugf text to out as given.
+func TestLevels(t *testing.T) {
+	for _, c := range []struct {
+		level       Level
+		out, errOut string
+	}{
+		{LevelError, "", "Error: e 1\n"},
+		{LevelWarn, "", "Error: e 1\nWarning: w 2\n"},
+		{LevelInfo, "i 3", "Error: e 1\nWarning: w 2\n"},
+		{LevelDebug, "i 3d 4\n", "Error: e 1\nWarning: w 2\n"},
+	} {
+		var out, errOut bytes.Buffer
+		l := New(&out, &errOut, c.level)
+		l.Errorf("e %d", 1)
+		l.Warnf("w %d\n", 2) // the trailing newline isn't doubled
+		l.Printf("i %d", 3)
+		l.Debugf("d %d\n", 4)
+		if out.String() != c.out || errOut.String() != c.errOut {
+			t.Errorf("%v: out %q, errOut %q; want %q, %q", c.level, out.String(), errOut.String(), c.out, c.errOut)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{"": LevelInfo, "error": LevelError, "WARN": LevelWarn, "Debug": LevelDebug} {
+		if got, err := ParseLevel(s); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("quiet"); err == nil {
+		t.Error(`ParseLevel("quiet"): no error`)
+	}
+	if s := Level(7).String(); s != "Level(7)" {
+		t.Errorf("Level(7).String() = %q", s)
+	}
+}
+
+func TestFromStd(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromStd(log.New(&buf, "srv ", 0), LevelWarn)
+	l.Errorf("e")
+	l.Warnf("w")
+	l.Printf("i")
+	l.Debugf("d")
+	if want := "srv Error: e\nsrv Warning: w\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+// TestDefault checks SetDefault replaces what Default and Or(nil) return.
+func TestDefault(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+
+	var buf bytes.Buffer
+	SetDefault(New(&buf, &buf, LevelInfo))
+	Or(nil).Printf("a")
+	Default().Warnf("b")
+	Or(Discard).Errorf("c")
+	if want := "aWarning: b\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
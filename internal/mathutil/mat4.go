@@ -33,6 +33,15 @@ func (m Mat4) MulPoint(v Vec3) Vec3 {
 	}
 }
 
+// MulDir transforms a 3D direction (w=0) by the 4×4 matrix: rotation only.
+func (m Mat4) MulDir(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v[0] + m[1]*v[1] + m[2]*v[2],
+		m[4]*v[0] + m[5]*v[1] + m[6]*v[2],
+		m[8]*v[0] + m[9]*v[1] + m[10]*v[2],
+	}
+}
+
 // FromMat3Translation builds a 4×4 affine matrix from a 3×3 rotation and translation.
 func FromMat3Translation(r Mat3, t Vec3) Mat4 {
 	return Mat4{
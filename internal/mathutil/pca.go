@@ -44,3 +44,50 @@ func normalize2(x, y float64) [2]float64 {
 	}
 	return [2]float64{x / l, y / l}
 }
+
+// EigenSym3 computes eigenvalues and unit eigenvectors of a symmetric 3×3
+// matrix by cyclic Jacobi rotation. Returns them sorted so that
+// evals[0] >= evals[1] >= evals[2]; evecs[i] belongs to evals[i].
+func EigenSym3(m Mat3) ([3]float64, [3]Vec3) {
+	a := m
+	v := Mat3Identity() // columns accumulate the eigenvectors
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[1]*a[1] + a[2]*a[2] + a[5]*a[5]
+		if off < 1e-24 {
+			break
+		}
+		for _, pq := range [3][2]int{{0, 1}, {0, 2}, {1, 2}} {
+			p, q := pq[0], pq[1]
+			apq := a[p*3+q]
+			if math.Abs(apq) < 1e-30 {
+				continue
+			}
+			// Rotation angle that zeroes a[p][q]
+			theta := (a[q*3+q] - a[p*3+p]) / (2 * apq)
+			t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+			if theta < 0 {
+				t = -t
+			}
+			c := 1 / math.Sqrt(t*t+1)
+			s := t * c
+			j := Mat3Identity()
+			j[p*3+p], j[q*3+q] = c, c
+			j[p*3+q], j[q*3+p] = s, -s
+			a = Mat3Mul(Mat3Mul(j.Transpose(), a), j)
+			v = Mat3Mul(v, j)
+		}
+	}
+
+	evals := [3]float64{a[0], a[4], a[8]}
+	evecs := [3]Vec3{{v[0], v[3], v[6]}, {v[1], v[4], v[7]}, {v[2], v[5], v[8]}}
+	// Sort descending (three elements: simple exchange sort)
+	for i := 0; i < 2; i++ {
+		for k := i + 1; k < 3; k++ {
+			if evals[k] > evals[i] {
+				evals[i], evals[k] = evals[k], evals[i]
+				evecs[i], evecs[k] = evecs[k], evecs[i]
+			}
+		}
+	}
+	return evals, evecs
+}
@@ -0,0 +1,24 @@
+package mathutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEigenSym3(t *testing.T) {
+	// A known spectrum in a rotated frame
+	q := Mat3Mul(RotX(0.4), RotZ(1.1))
+	m := Mat3Mul(Mat3Mul(q, Mat3Diag(1, 9, 4)), q.Transpose())
+	evals, evecs := EigenSym3(m)
+	if want := [3]float64{9, 4, 1}; math.Abs(evals[0]-want[0]) > 1e-9 || math.Abs(evals[1]-want[1]) > 1e-9 || math.Abs(evals[2]-want[2]) > 1e-9 {
+		t.Errorf("eigenvalues %v, want %v", evals, want)
+	}
+	for i, v := range evecs {
+		if math.Abs(v.Len()-1) > 1e-9 {
+			t.Errorf("eigenvector %d has length %v", i, v.Len())
+		}
+		if got := m.MulVec3(v); !nearVec(got, v.Scale(evals[i])) {
+			t.Errorf("M·v%d = %v, want %v", i, got, v.Scale(evals[i]))
+		}
+	}
+}
@@ -36,3 +36,37 @@ func RotZ(a float64) Mat3 {
 func Deg2Rad(d float64) float64 {
 	return d * math.Pi / 180
 }
+
+// RotateOnto returns the smallest rotation taking unit vector from onto unit
+// vector to (Rodrigues' formula). Opposite vectors rotate 180° about an axis
+// perpendicular to from.
+func RotateOnto(from, to Vec3) Mat3 {
+	axis := from.Cross(to)
+	s := axis.Len()
+	c := from.Dot(to)
+	if s < 1e-12 {
+		if c > 0 {
+			return Mat3Identity()
+		}
+		// 180°: any axis perpendicular to from
+		axis = from.Cross(Vec3{1, 0, 0})
+		if axis.Len() < 1e-6 {
+			axis = from.Cross(Vec3{0, 1, 0})
+		}
+		axis = axis.Normalize()
+		x, y, z := axis[0], axis[1], axis[2]
+		return Mat3{
+			2*x*x - 1, 2 * x * y, 2 * x * z,
+			2 * x * y, 2*y*y - 1, 2 * y * z,
+			2 * x * z, 2 * y * z, 2*z*z - 1,
+		}
+	}
+	k := axis.Scale(1 / s)
+	x, y, z := k[0], k[1], k[2]
+	t := 1 - c
+	return Mat3{
+		t*x*x + c, t*x*y - s*z, t*x*z + s*y,
+		t*x*y + s*z, t*y*y + c, t*y*z - s*x,
+		t*x*z - s*y, t*y*z + s*x, t*z*z + c,
+	}
+}
@@ -0,0 +1,38 @@
+package mathutil
+
+import (
+	"math"
+	"testing"
+)
+
+// nearVec reports whether a and b agree to within 1e-9 per component.
+func nearVec(a, b Vec3) bool {
+	return math.Abs(a[0]-b[0]) < 1e-9 && math.Abs(a[1]-b[1]) < 1e-9 && math.Abs(a[2]-b[2]) < 1e-9
+}
+
+func TestRotateOnto(t *testing.T) {
+	tilted := Vec3{1, 2, -2}.Normalize()
+	for _, c := range []struct{ from, to Vec3 }{
+		{Vec3{1, 0, 0}, Vec3{0, 0, 1}},
+		{tilted, Vec3{0, 0, 1}},
+		{tilted, tilted},                // identity
+		{Vec3{0, 0, 1}, Vec3{0, 0, -1}}, // opposite
+		{Vec3{1, 0, 0}, Vec3{-1, 0, 0}}, // opposite, along the fallback axis
+		{tilted, tilted.Scale(-1)},
+	} {
+		r := RotateOnto(c.from, c.to)
+		if got := r.MulVec3(c.from); !nearVec(got, c.to) {
+			t.Errorf("RotateOnto(%v, %v) maps from to %v", c.from, c.to, got)
+		}
+		if d := r.Det(); math.Abs(d-1) > 1e-9 {
+			t.Errorf("RotateOnto(%v, %v): det %v, want a rotation", c.from, c.to, d)
+		}
+		if p := Mat3Mul(r, r.Transpose()); !nearVec(Vec3{p[0], p[4], p[8]}, Vec3{1, 1, 1}) || !nearVec(Vec3{p[1], p[2], p[5]}, Vec3{}) {
+			t.Errorf("RotateOnto(%v, %v) is not orthonormal: R·Rᵀ = %v", c.from, c.to, p)
+		}
+	}
+	// The smallest rotation leaves the axis perpendicular to both alone
+	if got := RotateOnto(Vec3{1, 0, 0}, Vec3{0, 1, 0}).MulVec3(Vec3{0, 0, 1}); !nearVec(got, Vec3{0, 0, 1}) {
+		t.Errorf("x→y moved z to %v", got)
+	}
+}
@@ -0,0 +1,32 @@
+// Package mmap reads whole files either with os.ReadFile or, when enabled,
+// by memory-mapping them read-only so workers share the OS page cache
+// instead of each holding a heap copy.
+package mmap
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+var enabled atomic.Bool
+
+// SetEnabled turns memory-mapped reads on or off for ReadFile. On platforms
+// without mmap support it has no effect.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// ReadFile returns the contents of path and a release func that must be
+// called once the bytes are no longer referenced. Mapped bytes are read-only:
+// writing to them faults, so callers that transform data must copy first.
+func ReadFile(path string) ([]byte, func(), error) {
+	if enabled.Load() && supported {
+		if data, release, err := mapFile(path); err == nil {
+			return data, release, nil
+		} else if !errTryRead(err) {
+			return nil, nil, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	return data, func() {}, err
+}
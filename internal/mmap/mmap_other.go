@@ -0,0 +1,11 @@
+//go:build !unix
+
+package mmap
+
+const supported = false
+
+func mapFile(path string) ([]byte, func(), error) {
+	panic("mmap: not supported")
+}
+
+func errTryRead(err error) bool { return true }
@@ -0,0 +1,48 @@
+package mmap
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	full := filepath.Join(dir, "full.bin")
+	empty := filepath.Join(dir, "empty.bin")
+	content := bytes.Repeat([]byte("BMD\x0c"), 5000) // spans pages
+	if err := os.WriteFile(full, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetEnabled(false) })
+
+	for _, on := range []bool{false, true} {
+		SetEnabled(on)
+		data, release, err := ReadFile(full)
+		if err != nil {
+			t.Fatalf("enabled %v: %v", on, err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Errorf("enabled %v: contents differ", on)
+		}
+		release()
+
+		// Empty files can't be mapped and fall back to a plain read
+		data, release, err = ReadFile(empty)
+		if err != nil || len(data) != 0 {
+			t.Errorf("enabled %v: empty file gave %d bytes, %v", on, len(data), err)
+		} else {
+			release()
+		}
+
+		if _, _, err := ReadFile(filepath.Join(dir, "missing.bin")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("enabled %v: missing file gave %v, want fs.ErrNotExist", on, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package mmap
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+const supported = true
+
+// errEmpty marks files that can't be mapped because they are empty.
+var errEmpty = errors.New("mmap: empty file")
+
+func mapFile(path string) ([]byte, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		return nil, nil, errEmpty
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return data, func() { syscall.Munmap(data) }, nil
+}
+
+// errTryRead reports whether ReadFile should fall back to os.ReadFile
+// (empty files, or mmap refused by the filesystem) rather than fail.
+func errTryRead(err error) bool {
+	var pe *os.PathError
+	return err == errEmpty || (errors.As(err, &pe) && pe.Op == "mmap")
+}
@@ -1,42 +1,99 @@
 package postprocess
 
-import "image"
+import (
+	"image"
+	"sort"
+)
 
 // RemoveSmallClusters zeroes out small disconnected pixel groups.
 // minRatio is the minimum fraction of total non-transparent pixels to keep.
-func RemoveSmallClusters(img *image.NRGBA, minRatio float64) *image.NRGBA {
+func (o Options) RemoveSmallClusters(img *image.NRGBA, minRatio float64) *image.NRGBA {
+	return o.removeClusters(img, func(totalAlpha int) int {
+		return int(float64(totalAlpha) * minRatio)
+	})
+}
+
+// FeatureRefSize is the output size (square) that RemoveClustersBelowArea's
+// reference pixel count is measured at.
+const FeatureRefSize = 256
+
+// RemoveClustersBelowArea zeroes out disconnected pixel groups smaller than
+// refPixels, measured at a FeatureRefSize×FeatureRefSize output and scaled by
+// the image's area. Unlike RemoveSmallClusters the threshold does not depend on
+// how much of the canvas the item covers, so a feature of a given on-screen size
+// is kept or removed the same way at 128px and at 512px.
+func (o Options) RemoveClustersBelowArea(img *image.NRGBA, refPixels int) *image.NRGBA {
+	b := img.Bounds()
+	scale := float64(b.Dx()*b.Dy()) / float64(FeatureRefSize*FeatureRefSize)
+	minSize := int(float64(refPixels)*scale + 0.5)
+	return o.removeClusters(img, func(int) int { return minSize })
+}
+
+// KeepLargestN keeps the n biggest 8-connected pixel groups and clears the rest.
+// Sits between keepLargestComponent (exactly one) and RemoveSmallClusters (all
+// above a ratio): for items that are legitimately a few big pieces but have specks.
+// Equal-sized groups keep scan order (top-left first).
+func (o Options) KeepLargestN(img *image.NRGBA, n int) *image.NRGBA {
+	labels, sizes, _ := o.labelComponents(img)
+	if n <= 0 || len(sizes) <= n {
+		return img
+	}
+
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return sizes[order[a]] > sizes[order[b]] })
+	keep := make([]bool, len(sizes))
+	for _, id := range order[:n] {
+		keep[id] = true
+	}
+	return clearComponents(img, labels, func(id int) bool { return !keep[id] })
+}
+
+// removeClusters clears components smaller than the size returned by
+// threshold (given the total non-transparent pixel count).
+func (o Options) removeClusters(img *image.NRGBA, threshold func(totalAlpha int) int) *image.NRGBA {
+	labels, sizes, totalAlpha := o.labelComponents(img)
+	if len(sizes) <= 1 {
+		return img
+	}
+	minSize := threshold(totalAlpha)
+	return clearComponents(img, labels, func(id int) bool { return sizes[id] < minSize })
+}
+
+// labelComponents labels 8-connected groups of content pixels (see Options.ContentAlpha)
+// with BFS. labels holds a component ID per pixel (-1 = transparent or fringe),
+// sizes the pixel count per ID, and total the number of content pixels.
+func (o Options) labelComponents(img *image.NRGBA) (labels []int, sizes []int, total int) {
 	b := img.Bounds()
 	w, h := b.Dx(), b.Dy()
 	stride := img.Stride
 
-	// Find non-transparent pixels
+	// Find content pixels
 	alpha := make([]bool, w*h)
-	totalAlpha := 0
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			if img.Pix[y*stride+x*4+3] > 0 {
+			if o.isContent(img.Pix[y*stride+x*4+3]) {
 				alpha[y*w+x] = true
-				totalAlpha++
+				total++
 			}
 		}
 	}
 
-	if totalAlpha == 0 {
-		return img
-	}
-
-	// 8-connected flood fill BFS
-	labels := make([]int, w*h)
+	labels = make([]int, w*h)
 	for i := range labels {
 		labels[i] = -1
 	}
-	var compSizes []int
-	compID := 0
+	if total == 0 {
+		return labels, nil, 0
+	}
 
 	dx := [8]int{-1, 0, 1, -1, 1, -1, 0, 1}
 	dy := [8]int{-1, -1, -1, 0, 0, 1, 1, 1}
 
 	queue := make([]int, 0, 1024)
+	compID := 0
 
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
@@ -72,26 +129,45 @@ func RemoveSmallClusters(img *image.NRGBA, minRatio float64) *image.NRGBA {
 				}
 			}
 
-			compSizes = append(compSizes, size)
+			sizes = append(sizes, size)
 			compID++
 		}
 	}
+	return labels, sizes, total
+}
 
-	if compID <= 1 {
-		return img
-	}
-
-	minSize := int(float64(totalAlpha) * minRatio)
+// clearComponents returns a copy of img with every pixel whose component
+// satisfies drop zeroed out. Fringe pixels (unlabeled but not fully
+// transparent) touching a dropped pixel go with it, so a removed speck doesn't
+// leave a faint halo behind.
+func clearComponents(img *image.NRGBA, labels []int, drop func(id int) bool) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	stride := img.Stride
 
-	// Zero out small components
 	result := image.NewNRGBA(b)
 	copy(result.Pix, img.Pix)
 
+	dropped := make([]bool, w*h)
+	for idx, id := range labels {
+		dropped[idx] = id >= 0 && drop(id)
+	}
+	touchesDropped := func(x, y int) bool {
+		for ny := max(y-1, 0); ny <= min(y+1, h-1); ny++ {
+			for nx := max(x-1, 0); nx <= min(x+1, w-1); nx++ {
+				if dropped[ny*w+nx] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
 			idx := y*w + x
-			if labels[idx] >= 0 && compSizes[labels[idx]] < minSize {
-				i := y*stride + x*4
+			i := y*stride + x*4
+			if dropped[idx] || (labels[idx] < 0 && img.Pix[i+3] > 0 && touchesDropped(x, y)) {
 				result.Pix[i] = 0
 				result.Pix[i+1] = 0
 				result.Pix[i+2] = 0
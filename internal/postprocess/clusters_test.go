@@ -0,0 +1,88 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var speckColor = color.NRGBA{255, 255, 255, 255}
+
+// opaqueAt reports whether img's pixel at x, y has any alpha.
+func opaqueAt(img *image.NRGBA, x, y int) bool {
+	return img.NRGBAAt(x, y).A > 0
+}
+
+// TestRemoveClustersBelowArea puts the same two specks, scaled with the
+// canvas, beside a big body at 128 and 512 px: the threshold (40 px at
+// 256×256) must keep and drop the same one at both sizes.
+func TestRemoveClustersBelowArea(t *testing.T) {
+	var o Options
+	for _, size := range []int{128, 512} {
+		s := size / 128          // pixels per 128-px unit
+		small, large := 3*s, 4*s // 9 and 16 px at 128: under and over the 10 px threshold
+		img := image.NewNRGBA(image.Rect(0, 0, size, size))
+		fillRect(img, image.Rect(10*s, 10*s, 70*s, 70*s), bodyColor)
+		fillRect(img, image.Rect(90*s, 10*s, 90*s+small, 10*s+small), speckColor)
+		fillRect(img, image.Rect(90*s, 50*s, 90*s+large, 50*s+large), speckColor)
+
+		out := o.RemoveClustersBelowArea(img, 40)
+		if !opaqueAt(out, 20*s, 20*s) {
+			t.Errorf("%d px: body removed", size)
+		}
+		if opaqueAt(out, 90*s, 10*s) {
+			t.Errorf("%d px: %d×%d speck kept, below %d px", size, small, small, 40*size*size/(256*256))
+		}
+		if !opaqueAt(out, 90*s, 50*s) {
+			t.Errorf("%d px: %d×%d speck removed", size, large, large)
+		}
+	}
+}
+
+// TestKeepLargestN lays out pieces of 400, 300, 300 and 201 px and a
+// 4 px speck. The 201 px piece is a 200 px block plus a pixel touching its
+// corner: 8-connected, that is one piece.
+func TestKeepLargestN(t *testing.T) {
+	pieces := func() *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+		fillRect(img, image.Rect(0, 0, 20, 20), bodyColor)    // 400
+		fillRect(img, image.Rect(50, 0, 65, 20), bodyColor)   // 300, first of the tie in scan order
+		fillRect(img, image.Rect(0, 50, 15, 70), bodyColor)   // 300
+		fillRect(img, image.Rect(50, 50, 60, 70), bodyColor)  // 200
+		fillRect(img, image.Rect(60, 70, 61, 71), speckColor) // + 1 diagonally below its corner
+		fillRect(img, image.Rect(90, 90, 92, 92), speckColor) // 4
+		return img
+	}
+	var o Options
+	for _, c := range []struct {
+		n    int
+		keep []image.Point // pixels that must survive
+	}{
+		{1, []image.Point{{0, 0}}},
+		{2, []image.Point{{0, 0}, {50, 0}}},
+		{3, []image.Point{{0, 0}, {50, 0}, {0, 50}}},
+		{4, []image.Point{{0, 0}, {50, 0}, {0, 50}, {55, 60}, {60, 70}}},
+	} {
+		out := o.KeepLargestN(pieces(), c.n)
+		kept := 0
+		for _, p := range []image.Point{{0, 0}, {50, 0}, {0, 50}, {55, 60}, {60, 70}, {90, 90}} {
+			if opaqueAt(out, p.X, p.Y) {
+				kept++
+			}
+		}
+		for _, p := range c.keep {
+			if !opaqueAt(out, p.X, p.Y) {
+				t.Errorf("n=%d: piece at %v removed", c.n, p)
+			}
+		}
+		if kept != len(c.keep) {
+			t.Errorf("n=%d: %d pieces kept, want %d", c.n, kept, len(c.keep))
+		}
+	}
+	for _, n := range []int{0, 5, 9} {
+		in := pieces()
+		if out := o.KeepLargestN(in, n); out != in {
+			t.Errorf("n=%d: image changed, want it returned as is", n)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package postprocess
+
+// DefaultContentAlpha is the content-alpha threshold when
+// Options.ContentAlpha is 0.
+const DefaultContentAlpha = 8
+
+// Options are the postprocess settings that apply to every image of a run;
+// the framing and cleanup steps are its methods. The zero value uses the
+// defaults.
+type Options struct {
+	// ContentAlpha is the alpha at or above which a pixel counts as item
+	// content for cropping, centering, PCA and cluster cleanup (1 = any
+	// non-zero alpha; 0 = DefaultContentAlpha; clamped to 255). Fainter
+	// pixels — the anti-aliased fringe — are still drawn but no longer
+	// widen the bounding box or pull the centroid.
+	ContentAlpha int
+
+	// MaxImageDimension is the largest width or height a step may
+	// allocate; a step that would exceed it returns its input unchanged and
+	// prints a warning instead (0 = DefaultMaxImageDimension).
+	MaxImageDimension int
+}
+
+// isContent reports whether alpha a is at or above the content threshold.
+func (o Options) isContent(a uint8) bool {
+	t := o.ContentAlpha
+	if t <= 0 {
+		t = DefaultContentAlpha
+	}
+	return int(a) >= min(t, 255)
+}
@@ -0,0 +1,42 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestIsContent(t *testing.T) {
+	for _, c := range []struct {
+		threshold int
+		a         uint8
+		want      bool
+	}{
+		{0, 7, false},
+		{0, 8, true}, // 0 = DefaultContentAlpha
+		{1, 1, true},
+		{1, 0, false},
+		{300, 254, false}, // clamped to 255
+		{300, 255, true},
+	} {
+		if got := (Options{ContentAlpha: c.threshold}).isContent(c.a); got != c.want {
+			t.Errorf("content_alpha %d, alpha %d: %v, want %v", c.threshold, c.a, got, c.want)
+		}
+	}
+}
+
+// TestCropIgnoresFringe surrounds a 10×10 opaque square with a one-pixel
+// ring of alpha 5: at the default threshold the crop is the square alone,
+// at threshold 1 it includes the ring.
+func TestCropIgnoresFringe(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	fillRect(img, image.Rect(14, 14, 26, 26), color.NRGBA{255, 0, 0, 5})
+	fillRect(img, image.Rect(15, 15, 25, 25), color.NRGBA{255, 0, 0, 255})
+
+	if got := (Options{}).cropAlpha(img).Bounds().Size(); got != image.Pt(10, 10) {
+		t.Errorf("default threshold: crop %v, want 10×10", got)
+	}
+	if got := (Options{ContentAlpha: 1}).cropAlpha(img).Bounds().Size(); got != image.Pt(12, 12) {
+		t.Errorf("threshold 1: crop %v, want 12×12", got)
+	}
+}
@@ -0,0 +1,102 @@
+package postprocess
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// DefaultDebugColor is the debug_canvas background: a faint magenta that
+// no item texture is likely to blend into.
+var DefaultDebugColor = color.NRGBA{R: 255, G: 0, B: 255, A: 48}
+
+// DebugCanvas returns a copy of img composited over bg, so the canvas bounds
+// show around the item. With grid it also draws, in bg at full opacity, a
+// crosshair at the canvas center and the box the item is scaled to fit
+// (fillRatio of each side, centered). A debugging aid for framing — output
+// made with it is not meant to ship.
+func DebugCanvas(img *image.NRGBA, bg color.NRGBA, grid bool, fillRatio float64) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	out := OverColor(img, bg)
+	if !grid {
+		return out
+	}
+
+	marker := bg
+	marker.A = 255
+
+	// Center crosshair, arms an eighth of the shorter side
+	cx, cy := w/2, h/2
+	arm := max(2, min(w, h)/8)
+	for d := -arm; d <= arm; d++ {
+		setIn(out, cx+d, cy, marker)
+		setIn(out, cx, cy+d, marker)
+	}
+
+	// fillRatio box
+	if fillRatio > 0 && fillRatio <= 1 {
+		bw, bh := int(float64(w)*fillRatio), int(float64(h)*fillRatio)
+		x0, y0 := (w-bw)/2, (h-bh)/2
+		x1, y1 := x0+bw-1, y0+bh-1
+		for x := x0; x <= x1; x++ {
+			setIn(out, x, y0, marker)
+			setIn(out, x, y1, marker)
+		}
+		for y := y0; y <= y1; y++ {
+			setIn(out, x0, y, marker)
+			setIn(out, x1, y, marker)
+		}
+	}
+	return out
+}
+
+// OverColor returns a copy of img composited over the solid color bg.
+func OverColor(img *image.NRGBA, bg color.NRGBA) *image.NRGBA {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			out.SetNRGBA(x, y, over(img.NRGBAAt(b.Min.X+x, b.Min.Y+y), bg))
+		}
+	}
+	return out
+}
+
+// ParseHexColor parses "#RRGGBB" or "#RRGGBBAA" (the # is optional);
+// alpha defaults to 255.
+func ParseHexColor(s string) (color.NRGBA, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) != 6 && len(h) != 8 {
+		return color.NRGBA{}, fmt.Errorf("color %q: want #RRGGBB or #RRGGBBAA", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("color %q: %w", s, err)
+	}
+	if len(h) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// over composites fg over bg (both straight alpha).
+func over(fg, bg color.NRGBA) color.NRGBA {
+	if fg.A == 255 || bg.A == 0 {
+		return fg
+	}
+	fa, ba := float64(fg.A)/255, float64(bg.A)/255
+	a := fa + ba*(1-fa)
+	mix := func(f, b uint8) uint8 {
+		return uint8((float64(f)*fa+float64(b)*ba*(1-fa))/a + 0.5)
+	}
+	return color.NRGBA{R: mix(fg.R, bg.R), G: mix(fg.G, bg.G), B: mix(fg.B, bg.B), A: uint8(a*255 + 0.5)}
+}
+
+func setIn(img *image.NRGBA, x, y int, c color.NRGBA) {
+	if image.Pt(x, y).In(img.Rect) {
+		img.SetNRGBA(x, y, c)
+	}
+}
@@ -0,0 +1,70 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestDebugCanvas draws an item off-center on a 64×48 canvas: the
+// background fills with the debug color, the item is kept, and with grid the
+// crosshair crosses the canvas center and the fill box sits where
+// scaleAndCenter fits the item.
+func TestDebugCanvas(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	item := color.NRGBA{10, 200, 10, 255}
+	fillRect(img, image.Rect(4, 4, 12, 12), item)
+	bg := DefaultDebugColor
+	marker := color.NRGBA{bg.R, bg.G, bg.B, 255}
+
+	plain := DebugCanvas(img, bg, false, 0.8)
+	if got := plain.NRGBAAt(40, 40); got != bg {
+		t.Errorf("fill: background %v, want %v", got, bg)
+	}
+	if got := plain.NRGBAAt(32, 24); got != bg {
+		t.Errorf("fill: center %v, want background without grid", got)
+	}
+	if got := plain.NRGBAAt(6, 6); got != item {
+		t.Errorf("fill: item pixel %v, want %v", got, item)
+	}
+
+	grid := DebugCanvas(img, bg, true, 0.5)
+	for _, p := range []image.Point{{32, 24}, {26, 24}, {38, 24}, {32, 18}, {32, 30}} {
+		if got := grid.NRGBAAt(p.X, p.Y); got != marker {
+			t.Errorf("grid: crosshair pixel %v is %v, want %v", p, got, marker)
+		}
+	}
+	// Fill box: 32×24 centered, corners (16,12) and (47,35)
+	for _, p := range []image.Point{{16, 12}, {47, 12}, {16, 35}, {47, 35}, {20, 12}, {16, 30}} {
+		if got := grid.NRGBAAt(p.X, p.Y); got != marker {
+			t.Errorf("grid: fill box pixel %v is %v, want %v", p, got, marker)
+		}
+	}
+	if got := grid.NRGBAAt(20, 20); got != bg {
+		t.Errorf("grid: pixel inside the box %v, want background", got)
+	}
+	if got := grid.NRGBAAt(6, 6); got != item {
+		t.Errorf("grid: item pixel %v, want %v", got, item)
+	}
+	if img.NRGBAAt(40, 40).A != 0 {
+		t.Error("DebugCanvas changed its input")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want color.NRGBA
+		ok   bool
+	}{
+		{"#FF00FF30", color.NRGBA{255, 0, 255, 0x30}, true},
+		{"102030", color.NRGBA{0x10, 0x20, 0x30, 255}, true},
+		{"#12345", color.NRGBA{}, false},
+		{"#GG0000", color.NRGBA{}, false},
+	} {
+		got, err := ParseHexColor(c.in)
+		if (err == nil) != c.ok || got != c.want {
+			t.Errorf("ParseHexColor(%q) = %v, %v; want %v, ok %v", c.in, got, err, c.want, c.ok)
+		}
+	}
+}
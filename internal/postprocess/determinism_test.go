@@ -0,0 +1,61 @@
+package postprocess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// fillRect paints r opaque c on img.
+func fillRect(img *image.NRGBA, r image.Rectangle, c color.NRGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+}
+
+// tiedShape returns a diagonal bar with four equal 6×6 blobs around it:
+// every cluster size ties and the bar is close to its mirror image, the
+// cases where an order-dependent pick would show.
+func tiedShape() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	for i := 20; i < 108; i++ {
+		fillRect(img, image.Rect(i-4, i-4, i+4, i+4), color.NRGBA{180, uint8(i), 60, 255})
+	}
+	for _, p := range []image.Point{{100, 10}, {10, 100}, {110, 40}, {40, 110}} {
+		fillRect(img, image.Rect(p.X, p.Y, p.X+6, p.Y+6), color.NRGBA{40, 40, 200, 255})
+	}
+	return img
+}
+
+// TestPostprocessDeterministic runs each step that picks between clusters
+// or orientations 10 times on the same input and requires identical output.
+func TestPostprocessDeterministic(t *testing.T) {
+	var o Options
+	steps := map[string]func(*image.NRGBA) []byte{
+		"KeepLargestN": func(img *image.NRGBA) []byte { return o.KeepLargestN(img, 2).Pix },
+		"RemoveSmallClusters": func(img *image.NRGBA) []byte {
+			return o.RemoveSmallClusters(img, 0.05).Pix
+		},
+		"StandardizeImage": func(img *image.NRGBA) []byte {
+			return o.StandardizeImage(img, 96, 96, 45, 0.8, false, true).Pix
+		},
+		"AutoDisplayAngle": func(img *image.NRGBA) []byte {
+			return binary.LittleEndian.AppendUint64(nil, math.Float64bits(o.AutoDisplayAngle(img, 45)))
+		},
+		"CropAndCenter": func(img *image.NRGBA) []byte { return o.CropAndCenter(img, 64, 64, 0.9).Pix },
+	}
+	for name, step := range steps {
+		first := step(tiedShape())
+		for run := 1; run < 10; run++ {
+			if got := step(tiedShape()); !bytes.Equal(got, first) {
+				t.Errorf("%s: run %d differs from the first", name, run)
+				break
+			}
+		}
+	}
+}
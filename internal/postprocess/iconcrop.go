@@ -0,0 +1,94 @@
+package postprocess
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// CenterSquareCrop cuts a square icon out of the item and scales it to
+// size×size. The square's side is the shorter side of the content's bounding
+// box, so an elongated item is cropped to its middle section. It is centered
+// on the alpha-weighted content centroid (not the canvas center), or, with
+// dense set, placed where it covers the most alpha — which zooms in on the
+// visually heaviest part, e.g. a sword's hilt rather than its blade.
+func (o Options) CenterSquareCrop(img *image.NRGBA, size int, dense bool) *image.NRGBA {
+	if !o.fitsLimit("square crop", size, size) {
+		return img
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, size, size))
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// Content bounds and alpha-weighted centroid
+	minX, minY, maxX, maxY := w, h, -1, -1
+	var sumA, sumX, sumY float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a := img.Pix[y*img.Stride+x*4+3]
+			if !o.isContent(a) {
+				continue
+			}
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+			fa := float64(a)
+			sumA += fa
+			sumX += fa * float64(x)
+			sumY += fa * float64(y)
+		}
+	}
+	if maxX < 0 {
+		return out
+	}
+	side := min(maxX-minX+1, maxY-minY+1)
+	cx, cy := sumX/sumA, sumY/sumA
+
+	// Top-left of the window centered on the centroid (pixel centers are at
+	// +0.5, so a side-pixel window around c starts at c+0.5-side/2)
+	x0 := int(math.Round(cx + 0.5 - float64(side)/2))
+	y0 := int(math.Round(cy + 0.5 - float64(side)/2))
+	if dense {
+		x0, y0 = densestWindow(img, side, x0, y0)
+	}
+	x0 = max(0, min(x0, w-side))
+	y0 = max(0, min(y0, h-side))
+
+	src := image.Rect(b.Min.X+x0, b.Min.Y+y0, b.Min.X+x0+side, b.Min.Y+y0+side)
+	draw.CatmullRom.Scale(out, out.Bounds(), img, src, draw.Src, nil)
+	return out
+}
+
+// densestWindow returns the top-left corner of the side×side window with the
+// largest total alpha, using a summed-area table. Ties go to the window
+// nearest (prefX, prefY) so flat regions stay centered.
+func densestWindow(img *image.NRGBA, side, prefX, prefY int) (int, int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if side >= w && side >= h {
+		return prefX, prefY
+	}
+
+	// sat[(y)*(w+1)+x] = alpha sum over [0,x)×[0,y)
+	sat := make([]int, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		row := 0
+		for x := 0; x < w; x++ {
+			row += int(img.Pix[y*img.Stride+x*4+3])
+			sat[(y+1)*(w+1)+x+1] = sat[y*(w+1)+x+1] + row
+		}
+	}
+
+	bestX, bestY, best, bestDist := prefX, prefY, -1, 0
+	for y := 0; y+side <= h; y++ {
+		for x := 0; x+side <= w; x++ {
+			sum := sat[(y+side)*(w+1)+x+side] - sat[y*(w+1)+x+side] -
+				sat[(y+side)*(w+1)+x] + sat[y*(w+1)+x]
+			dist := (x-prefX)*(x-prefX) + (y-prefY)*(y-prefY)
+			if sum > best || (sum == best && dist < bestDist) {
+				bestX, bestY, best, bestDist = x, y, sum, dist
+			}
+		}
+	}
+	return bestX, bestY
+}
@@ -0,0 +1,51 @@
+package postprocess
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// opaqueShare returns the fraction of img's pixels that are fully opaque.
+func opaqueShare(img *image.NRGBA) float64 {
+	n := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] == 255 {
+			n++
+		}
+	}
+	return float64(n) / float64(len(img.Pix)/4)
+}
+
+func TestCenterSquareCrop(t *testing.T) {
+	var o Options
+
+	// The 60×10 bar is cropped to a 10×10 square from its middle: all body,
+	// none of the red tip at its end
+	out := o.CenterSquareCrop(bar(), 32, false)
+	if b := out.Bounds(); b.Dx() != 32 || b.Dy() != 32 {
+		t.Fatalf("icon %v, want 32×32", b)
+	}
+	if s := opaqueShare(out); s < 0.95 {
+		t.Errorf("bar: %.2f of the icon opaque, want it filled by the bar's middle", s)
+	}
+	if _, ty := centroidOf(out, true); !math.IsNaN(ty) {
+		t.Error("bar: the tip is in the icon, want only the middle")
+	}
+
+	// A thin handle with a heavy 20×20 head: the centroid window straddles
+	// both, the dense one covers the head
+	club := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	fillRect(club, image.Rect(10, 62, 100, 66), bodyColor)
+	fillRect(club, image.Rect(100, 54, 120, 74), tipColor)
+	centered := opaqueShare(o.CenterSquareCrop(club, 40, false))
+	dense := opaqueShare(o.CenterSquareCrop(club, 40, true))
+	if dense < 0.95 || centered > 0.8 {
+		t.Errorf("club: %.2f opaque centered, %.2f dense; want the dense window on the head", centered, dense)
+	}
+
+	empty := o.CenterSquareCrop(image.NewNRGBA(image.Rect(0, 0, 64, 64)), 16, true)
+	if b := empty.Bounds(); b.Dx() != 16 || opaqueShare(empty) != 0 {
+		t.Errorf("empty: %v, %.2f opaque; want a transparent 16×16 icon", b, opaqueShare(empty))
+	}
+}
@@ -0,0 +1,22 @@
+package postprocess
+
+import "mu-bmd-renderer/internal/logging"
+
+// DefaultMaxImageDimension is the largest width or height a postprocess step
+// creates when Options.MaxImageDimension is 0. A 4096 px render at 4×
+// supersample, rotated 45°, stays below it.
+const DefaultMaxImageDimension = 32768
+
+// fitsLimit reports whether step may allocate a w×h image, warning when it
+// may not (see Options.MaxImageDimension).
+func (o Options) fitsLimit(step string, w, h int) bool {
+	limit := o.MaxImageDimension
+	if limit <= 0 {
+		limit = DefaultMaxImageDimension
+	}
+	if w <= limit && h <= limit && w >= 0 && h >= 0 {
+		return true
+	}
+	logging.Default().Warnf("%s: %dx%d image exceeds max_image_dimension %d, left unchanged", step, w, h, limit)
+	return false
+}
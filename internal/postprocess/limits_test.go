@@ -0,0 +1,55 @@
+package postprocess
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/logging"
+)
+
+// TestMaxImageDimension caps images at 100 px: steps that would allocate
+// past it return their input unchanged and warn, steps within it run.
+func TestMaxImageDimension(t *testing.T) {
+	var warnings bytes.Buffer
+	prev := logging.Default()
+	logging.SetDefault(logging.New(&warnings, &warnings, logging.LevelWarn))
+	defer logging.SetDefault(prev)
+
+	img := image.NewNRGBA(image.Rect(0, 0, 80, 80))
+	fillRect(img, image.Rect(10, 30, 70, 50), color.NRGBA{200, 0, 0, 255})
+	o := Options{MaxImageDimension: 100}
+
+	// 80×80 turned 45° needs 114×114
+	if got := o.rotateImage(img, 45); got != img {
+		t.Errorf("rotate past the cap: %v, want the input", got.Bounds())
+	}
+	if got := o.rotateImage(img, 10); got == img || got.Bounds().Dx() > 100 {
+		t.Errorf("rotate within the cap: %v, want a new image", got.Bounds())
+	}
+	for name, step := range map[string]func() *image.NRGBA{
+		"crop and center":   func() *image.NRGBA { return o.CropAndCenter(img, 200, 200, 0.8) },
+		"rotate and center": func() *image.NRGBA { return o.RotateAndCenter(img, 64, 400, 30, 0.8) },
+		"center content":    func() *image.NRGBA { return o.CenterContent(img, 101, 50) },
+		"standardize":       func() *image.NRGBA { return o.StandardizeImage(img, 128, 128, 45, 0.8, false, true) },
+		"square crop":       func() *image.NRGBA { return o.CenterSquareCrop(img, 150, false) },
+	} {
+		if got := step(); got != img {
+			t.Errorf("%s past the cap: %v, want the input", name, got.Bounds())
+		}
+	}
+	if got := o.CropAndCenter(img, 64, 64, 0.8); got.Bounds().Dx() != 64 {
+		t.Errorf("crop and center within the cap: %v, want 64×64", got.Bounds())
+	}
+
+	if n := strings.Count(warnings.String(), "exceeds max_image_dimension 100"); n != 6 {
+		t.Errorf("%d warnings, want one per capped step:\n%s", n, warnings.String())
+	}
+
+	// 0 is DefaultMaxImageDimension
+	if got := (Options{}).rotateImage(img, 45); got == img {
+		t.Error("default limit: 45° rotate of 80×80 refused")
+	}
+}
@@ -12,15 +12,15 @@ import (
 // side by side to create a pair (e.g. single boot → pair of boots).
 // Works with both bones=false (single item) and bones=true (picks one from pair).
 // The result is centered on a canvas of the given size.
-func MirrorPair(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *image.NRGBA {
+func (o Options) MirrorPair(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *image.NRGBA {
 	// Isolate the largest connected component (picks one boot from walking pair)
-	img = keepLargestComponent(img)
+	img = o.keepLargestComponent(img)
 
-	// Crop to non-transparent bounds
-	cropped := cropAlpha(img)
+	// Crop to content bounds
+	cropped := o.cropAlpha(img)
 	cb := cropped.Bounds()
 	cw, ch := cb.Dx(), cb.Dy()
-	if cw == 0 || ch == 0 {
+	if cw == 0 || ch == 0 || !o.fitsLimit("mirror pair", canvasW, canvasH) {
 		return img
 	}
 
@@ -41,6 +41,9 @@ func MirrorPair(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *imag
 	// Compose: original on left, mirror on right
 	pairW := cw*2 + gap
 	pairH := ch
+	if !o.fitsLimit("mirror pair", pairW, pairH) {
+		return img
+	}
 	pair := image.NewNRGBA(image.Rect(0, 0, pairW, pairH))
 	// Left = original
 	draw.Copy(pair, image.Pt(0, 0), cropped, cb, draw.Over, nil)
@@ -88,96 +91,8 @@ func MirrorPair(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *imag
 // keepLargestComponent finds connected components via 8-connected BFS
 // and zeroes out everything except the largest one.
 // This isolates one boot from a bone-assembled pair.
-func keepLargestComponent(img *image.NRGBA) *image.NRGBA {
-	b := img.Bounds()
-	w, h := b.Dx(), b.Dy()
-	stride := img.Stride
-
-	// Build alpha mask
-	alpha := make([]bool, w*h)
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			if img.Pix[y*stride+x*4+3] > 0 {
-				alpha[y*w+x] = true
-			}
-		}
-	}
-
-	// BFS to label connected components
-	labels := make([]int, w*h)
-	for i := range labels {
-		labels[i] = -1
-	}
-	var compSizes []int
-	compID := 0
-
-	dx := [8]int{-1, 0, 1, -1, 1, -1, 0, 1}
-	dy := [8]int{-1, -1, -1, 0, 0, 1, 1, 1}
-	queue := make([]int, 0, 1024)
-
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			idx := y*w + x
-			if !alpha[idx] || labels[idx] >= 0 {
-				continue
-			}
-			queue = queue[:0]
-			queue = append(queue, idx)
-			labels[idx] = compID
-			size := 0
-			for len(queue) > 0 {
-				curr := queue[0]
-				queue = queue[1:]
-				size++
-				cy := curr / w
-				cx := curr % w
-				for d := 0; d < 8; d++ {
-					nx := cx + dx[d]
-					ny := cy + dy[d]
-					if nx < 0 || nx >= w || ny < 0 || ny >= h {
-						continue
-					}
-					ni := ny*w + nx
-					if alpha[ni] && labels[ni] < 0 {
-						labels[ni] = compID
-						queue = append(queue, ni)
-					}
-				}
-			}
-			compSizes = append(compSizes, size)
-			compID++
-		}
-	}
-
-	// Only one or zero components — nothing to isolate
-	if compID <= 1 {
-		return img
-	}
-
-	// Find the largest component
-	bestID := 0
-	for i := 1; i < compID; i++ {
-		if compSizes[i] > compSizes[bestID] {
-			bestID = i
-		}
-	}
-
-	// Zero out all other components
-	result := image.NewNRGBA(b)
-	copy(result.Pix, img.Pix)
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			idx := y*w + x
-			if labels[idx] >= 0 && labels[idx] != bestID {
-				i := y*stride + x*4
-				result.Pix[i] = 0
-				result.Pix[i+1] = 0
-				result.Pix[i+2] = 0
-				result.Pix[i+3] = 0
-			}
-		}
-	}
-	return result
+func (o Options) keepLargestComponent(img *image.NRGBA) *image.NRGBA {
+	return o.KeepLargestN(img, 1)
 }
 
 // alphaMask implements image.Image using only the alpha channel.
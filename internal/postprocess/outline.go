@@ -0,0 +1,63 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Outline returns a copy of img over a solid stroke of c around its
+// content: the alpha mask dilated by thickness px with a round brush.
+// Each stroke pixel takes the strongest alpha within reach, faded over the
+// brush's last pixel by distance, so antialiased and diagonal edges give a
+// smooth outline instead of a stair-stepped one. The canvas is padded by
+// thickness px all round, so a stroke is never cut; img's pixels keep their
+// coordinates (crop back with SubImage(img.Bounds()) to keep the size, with
+// the margin OutlineMargin leaves). thickness <= 0 returns a plain copy.
+func Outline(img *image.NRGBA, thickness int, c color.NRGBA) *image.NRGBA {
+	if thickness <= 0 {
+		return OverColor(img, color.NRGBA{})
+	}
+	rect := img.Bounds().Inset(-thickness)
+	out := image.NewNRGBA(rect)
+
+	// Brush weights: 1 inside the radius, fading to 0 over the next pixel
+	r := thickness + 1
+	size := 2*r + 1
+	brush := make([]float64, size*size)
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			d := math.Hypot(float64(dx), float64(dy))
+			brush[(dy+r)*size+dx+r] = math.Max(0, math.Min(1, float64(thickness)+0.5-d))
+		}
+	}
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			stroke := 0.0
+			for dy := -r; dy <= r && stroke < 1; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					if wgt := brush[(dy+r)*size+dx+r]; wgt > 0 {
+						// Outside img's bounds NRGBAAt is transparent
+						stroke = math.Max(stroke, float64(img.NRGBAAt(x+dx, y+dy).A)/255*wgt)
+					}
+				}
+			}
+			ring := c
+			ring.A = uint8(stroke*float64(c.A) + 0.5)
+			out.SetNRGBA(x, y, over(img.NRGBAAt(x, y), ring))
+		}
+	}
+	return out
+}
+
+// OutlineMargin returns the fill ratio to frame an item at on a w×h canvas
+// so an Outline of thickness px still fits inside it once cropped back:
+// fillRatio, or less if that would leave under thickness px on a side.
+func OutlineMargin(fillRatio float64, w, h, thickness int) float64 {
+	minDim := min(w, h)
+	if thickness <= 0 || minDim <= 0 {
+		return fillRatio
+	}
+	return math.Min(fillRatio, float64(minDim-2*thickness)/float64(minDim))
+}
@@ -0,0 +1,60 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestOutline strokes a 10 px square 3 px wide: the canvas is padded by
+// the thickness, the square comes through untouched, the stroke is solid
+// out to 3 px along the sides and gone past it, and nothing else is drawn.
+func TestOutline(t *testing.T) {
+	item := color.NRGBA{200, 40, 30, 255}
+	stroke := color.NRGBA{255, 255, 255, 255}
+	img := square(20, 20, 5, 5, 15, 15, item)
+
+	out := Outline(img, 3, stroke)
+	if want := image.Rect(-3, -3, 23, 23); out.Bounds() != want {
+		t.Fatalf("bounds %v, want %v", out.Bounds(), want)
+	}
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 15; x++ {
+			if out.NRGBAAt(x, y) != item {
+				t.Fatalf("interior (%d,%d) = %v, want %v", x, y, out.NRGBAAt(x, y), item)
+			}
+		}
+	}
+	// Along the middle row: 3 px of stroke each side, solid but for its
+	// antialiased outer pixel, then nothing
+	for _, c := range []struct {
+		x    int
+		full bool
+		some bool
+	}{{1, false, false}, {2, false, true}, {3, true, true}, {4, true, true},
+		{15, true, true}, {16, true, true}, {17, false, true}, {18, false, false}} {
+		got := out.NRGBAAt(c.x, 10)
+		if (got.A == 255) != c.full || (got.A > 0) != c.some || (got.A > 0 && (got.R != stroke.R || got.G != stroke.G || got.B != stroke.B)) {
+			t.Errorf("(%d,10) = %v; want full %v, any %v", c.x, got, c.full, c.some)
+		}
+	}
+	// The corners are rounded: past the brush diagonally stays clear
+	if a := out.NRGBAAt(1, 1).A; a != 0 {
+		t.Errorf("corner (1,1) alpha %d, want 0", a)
+	}
+
+	// Content at the edge of the canvas keeps its full stroke
+	edge := Outline(square(10, 10, 0, 0, 4, 4, item), 2, stroke)
+	if edge.Bounds() != image.Rect(-2, -2, 12, 12) || edge.NRGBAAt(-1, 1) != stroke || edge.NRGBAAt(1, -1) != stroke || edge.NRGBAAt(-2, 1).A == 0 {
+		t.Errorf("edge content: bounds %v, stroke past the old edge %v, %v, %v",
+			edge.Bounds(), edge.NRGBAAt(-1, 1), edge.NRGBAAt(1, -1), edge.NRGBAAt(-2, 1))
+	}
+
+	// Thickness 0 is a plain copy
+	for _, th := range []int{0, -1} {
+		same := Outline(img, th, stroke)
+		if same.Bounds() != img.Bounds() || string(same.Pix) != string(img.Pix) || &same.Pix[0] == &img.Pix[0] {
+			t.Errorf("thickness %d: not an unchanged copy", th)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// DropShadow returns a copy of img over its own shadow: img's alpha
+// channel, offset by (offsetX, offsetY) px, blurred over blurRadius px, and
+// filled with c at c's alpha times opacity. The canvas grows to hold the
+// whole shadow, by the offset on its side and the blur radius all round;
+// img's pixels keep their coordinates, so the result's bounds reach past
+// img's (crop back with SubImage(img.Bounds()) to keep the size).
+func DropShadow(img *image.NRGBA, offsetX, offsetY, blurRadius int, c color.NRGBA, opacity float64) *image.NRGBA {
+	blurRadius = max(blurRadius, 0)
+	b := img.Bounds()
+	r := image.Rect(
+		b.Min.X-blurRadius+min(offsetX, 0), b.Min.Y-blurRadius+min(offsetY, 0),
+		b.Max.X+blurRadius+max(offsetX, 0), b.Max.Y+blurRadius+max(offsetY, 0))
+	w, h := r.Dx(), r.Dy()
+
+	// Shadow coverage: the offset alpha channel, 0..1
+	mask := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			mask[y*w+x] = float64(img.NRGBAAt(r.Min.X+x-offsetX, r.Min.Y+y-offsetY).A) / 255
+		}
+	}
+	if blurRadius > 0 {
+		mask = gaussianBlur(mask, w, h, blurRadius)
+	}
+
+	strength := float64(c.A) / 255 * math.Max(0, math.Min(1, opacity))
+	out := image.NewNRGBA(r)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			shadow := c
+			shadow.A = uint8(mask[y*w+x]*strength*255 + 0.5)
+			px, py := r.Min.X+x, r.Min.Y+y
+			out.SetNRGBA(px, py, over(img.NRGBAAt(px, py), shadow))
+		}
+	}
+	return out
+}
+
+// gaussianBlur blurs the w×h plane p with a separable Gaussian reaching
+// radius px (sigma radius/2); outside the plane counts as 0.
+func gaussianBlur(p []float64, w, h, radius int) []float64 {
+	sigma := float64(radius) / 2
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	tmp := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.0
+			for k, kv := range kernel {
+				if sx := x + k - radius; sx >= 0 && sx < w {
+					v += p[y*w+sx] * kv
+				}
+			}
+			tmp[y*w+x] = v
+		}
+	}
+	out := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.0
+			for k, kv := range kernel {
+				if sy := y + k - radius; sy >= 0 && sy < h {
+					v += tmp[sy*w+x] * kv
+				}
+			}
+			out[y*w+x] = v
+		}
+	}
+	return out
+}
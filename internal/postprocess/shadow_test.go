@@ -0,0 +1,71 @@
+package postprocess
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// square returns a w×h transparent canvas with an opaque c square over
+// [x0,x1)×[y0,y1).
+func square(w, h, x0, y0, x1, y1 int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestDropShadow casts a 10 px square's shadow 4 px right and 3 px down:
+// the canvas grows by the offset on its side and the blur all round, the
+// item's pixels come through unchanged at their coordinates, and the
+// shadow sits at the offset with c's alpha times opacity.
+func TestDropShadow(t *testing.T) {
+	item := color.NRGBA{200, 40, 30, 255}
+	img := square(20, 20, 5, 5, 15, 15, item)
+
+	out := DropShadow(img, 4, 3, 0, color.NRGBA{0, 0, 0, 200}, 0.5)
+	if want := image.Rect(0, 0, 24, 23); out.Bounds() != want {
+		t.Errorf("no blur: bounds %v, want %v", out.Bounds(), want)
+	}
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			if c := img.NRGBAAt(x, y); c.A == 255 && out.NRGBAAt(x, y) != c {
+				t.Fatalf("item pixel (%d,%d) %v, want %v", x, y, out.NRGBAAt(x, y), c)
+			}
+		}
+	}
+	// 200 × 0.5 = 100, unblurred: a sharp copy of the square at the offset
+	if c := out.NRGBAAt(17, 16); c != (color.NRGBA{0, 0, 0, 100}) {
+		t.Errorf("shadow at (17,16) = %v, want black at alpha 100", c)
+	}
+	if c := out.NRGBAAt(19, 18); c.A != 0 || out.NRGBAAt(9, 17).A != 100 || out.NRGBAAt(8, 17).A != 0 {
+		t.Errorf("shadow not where the square lands at the offset")
+	}
+
+	blurred := DropShadow(img, 4, 3, 2, color.NRGBA{0, 0, 0, 255}, 1)
+	if want := image.Rect(-2, -2, 26, 25); blurred.Bounds() != want {
+		t.Errorf("blur 2: bounds %v, want %v", blurred.Bounds(), want)
+	}
+	if a := blurred.NRGBAAt(19, 18).A; a == 0 || a == 255 {
+		t.Errorf("blur 2: alpha %d at the shadow's corner, want a soft edge", a)
+	}
+	if blurred.NRGBAAt(10, 10) != item {
+		t.Errorf("blur 2: item pixel %v, want %v", blurred.NRGBAAt(10, 10), item)
+	}
+
+	// Negative offsets grow the canvas up and left; no blur and zero
+	// opacity are fine
+	up := DropShadow(img, -6, -2, 0, color.NRGBA{0, 0, 0, 255}, 0)
+	if want := image.Rect(-6, -2, 20, 20); up.Bounds() != want {
+		t.Errorf("negative offset: bounds %v, want %v", up.Bounds(), want)
+	}
+	if up.NRGBAAt(-1, 3).A != 0 || up.NRGBAAt(10, 10) != item {
+		t.Error("negative offset: opacity 0 must leave just the item")
+	}
+	if empty := DropShadow(image.NewNRGBA(image.Rect(0, 0, 0, 0)), -3, 3, 0, color.NRGBA{A: 255}, 1); empty.Bounds().Dx() != 3 {
+		t.Errorf("empty image: bounds %v", empty.Bounds())
+	}
+}
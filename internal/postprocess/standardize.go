@@ -9,23 +9,107 @@ import (
 	"golang.org/x/image/draw"
 )
 
-// CropAndCenter crops to the bounding box of non-transparent pixels, then scales and centers.
+// CropAndCenter crops to the bounding box of content pixels (see Options.ContentAlpha), then scales and centers.
 // Used when PCA standardization is disabled (standardize: false).
-func CropAndCenter(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *image.NRGBA {
-	cropped := cropAlpha(img)
+func (o Options) CropAndCenter(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *image.NRGBA {
+	if !o.fitsLimit("crop and center", canvasW, canvasH) {
+		return img
+	}
+	cropped := o.cropAlpha(img)
 	return scaleAndCenter(cropped, canvasW, canvasH, fillRatio)
 }
 
+// RotateAndCenter rotates the item by a fixed angle, then crops, scales, and centers.
+// Used instead of PCA alignment when post_rotate is set: angleDeg is counter-clockwise
+// (same convention as display_angle), so the result is predictable regardless of shape.
+func (o Options) RotateAndCenter(img *image.NRGBA, canvasW, canvasH int, angleDeg, fillRatio float64) *image.NRGBA {
+	if !o.fitsLimit("rotate and center", canvasW, canvasH) {
+		return img
+	}
+	// rotateImage(θ) rotates clockwise in image space (y-down)
+	rotated := o.rotateImage(o.cropAlpha(img), -angleDeg)
+	return scaleAndCenter(o.cropAlpha(rotated), canvasW, canvasH, fillRatio)
+}
+
+// CenterContent moves the content to the middle of a
+// canvasW×canvasH canvas without scaling it (content larger than the canvas is
+// clipped). Used for absolute_scale items, whose on-canvas size is meaningful.
+func (o Options) CenterContent(img *image.NRGBA, canvasW, canvasH int) *image.NRGBA {
+	if !o.fitsLimit("center content", canvasW, canvasH) {
+		return img
+	}
+	cropped := o.cropAlpha(img)
+	b := cropped.Bounds()
+	canvas := image.NewNRGBA(image.Rect(0, 0, canvasW, canvasH))
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return canvas
+	}
+	offX := (canvasW - b.Dx()) / 2
+	offY := (canvasH - b.Dy()) / 2
+	dst := image.Rect(offX, offY, offX+b.Dx(), offY+b.Dy())
+	draw.Draw(canvas, dst, cropped, b.Min, draw.Src)
+	return canvas
+}
+
 // StandardizeImage rotates, scales, and centers the item image using PCA alignment.
-func StandardizeImage(img *image.NRGBA, canvasW, canvasH int, targetAngleDeg, fillRatio float64, forceFlip bool) *image.NRGBA {
+// When autoFlip is false the spread-based 180° orientation guess is skipped, so
+// near-symmetric items keep a stable orientation; forceFlip still applies.
+func (o Options) StandardizeImage(img *image.NRGBA, canvasW, canvasH int, targetAngleDeg, fillRatio float64, forceFlip, autoFlip bool) *image.NRGBA {
+	// Current PCA angle in image coordinates (atan2(y, x), y-down)
+	currentAngle, _, _, ok := o.principalAxis(img)
+	if !ok || !o.fitsLimit("standardize", canvasW, canvasH) {
+		return img
+	}
+
+	// Target angle in image space: negate math convention (y-up → y-down)
+	targetImg := -targetAngleDeg
+
+	// Rotation needed: rotateImage(θ) rotates CW in image space, new_angle = old_angle + θ
+	// So θ = target_angle - old_angle
+	pilRotate := targetImg - currentAngle
+	// Normalize to [-90, 90] — PCA eigenvector has 180° ambiguity
+	for pilRotate > 90 {
+		pilRotate -= 180
+	}
+	for pilRotate < -90 {
+		pilRotate += 180
+	}
+
+	// Rotate image
+	rotated := o.rotateImage(img, pilRotate)
+
+	// Auto-detect orientation on the rotated image:
+	// Project rotated pixels along target direction, check which half is wider
+	needFlip := false
+	if autoFlip {
+		needFlip = o.detectFlipRotated(rotated, targetImg)
+	}
+	if forceFlip {
+		needFlip = !needFlip
+	}
+	if needFlip {
+		rotated = rotate180(rotated)
+	}
+
+	// Crop to bounding box of content pixels
+	cropped := o.cropAlpha(rotated)
+
+	// Scale to fill_ratio of canvas and center
+	return scaleAndCenter(cropped, canvasW, canvasH, fillRatio)
+}
+
+// principalAxis returns the angle of the content's major PCA axis in image
+// coordinates (degrees, atan2(y, x) with y down) and the two eigenvalues of the
+// pixel-position covariance (eval1 >= eval2). ok is false for near-empty images.
+func (o Options) principalAxis(img *image.NRGBA) (angleDeg, eval1, eval2 float64, ok bool) {
 	b := img.Bounds()
 	w, h := b.Dx(), b.Dy()
 
-	// Collect non-transparent pixel coordinates
+	// Collect content pixel coordinates
 	var xs, ys []float64
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			if img.Pix[y*img.Stride+x*4+3] > 0 {
+			if o.isContent(img.Pix[y*img.Stride+x*4+3]) {
 				xs = append(xs, float64(x))
 				ys = append(ys, float64(y))
 			}
@@ -33,7 +117,7 @@ func StandardizeImage(img *image.NRGBA, canvasW, canvasH int, targetAngleDeg, fi
 	}
 
 	if len(xs) < 10 {
-		return img
+		return 0, 0, 0, false
 	}
 
 	// PCA: compute covariance matrix of pixel positions
@@ -59,60 +143,62 @@ func StandardizeImage(img *image.NRGBA, canvasW, canvasH int, targetAngleDeg, fi
 	covYY /= n
 
 	// Eigendecomposition of 2×2 symmetric matrix
-	_, _, evec1, _ := mathutil.Eigen2x2Sym(covXX, covXY, covYY)
-
-	// Current PCA angle in image coordinates (atan2(y, x), y-down)
-	currentAngle := math.Atan2(evec1[1], evec1[0]) * 180.0 / math.Pi
+	eval1, eval2, evec1, _ := mathutil.Eigen2x2Sym(covXX, covXY, covYY)
+	return math.Atan2(evec1[1], evec1[0]) * 180.0 / math.Pi, eval1, eval2, true
+}
 
-	// Target angle in image space: negate math convention (y-up → y-down)
-	targetImg := -targetAngleDeg
+// Elongation (major/minor axis length ratio) range over which AutoDisplayAngle
+// blends from "keep as rendered" to "rotate fully to the canonical angle"
+const (
+	autoAngleMinElongation = 1.2
+	autoAngleMaxElongation = 2.0
+)
 
-	// Rotation needed: rotateImage(θ) rotates CW in image space, new_angle = old_angle + θ
-	// So θ = target_angle - old_angle
-	pilRotate := targetImg - currentAngle
-	// Normalize to [-90, 90] — PCA eigenvector has 180° ambiguity
-	for pilRotate > 90 {
-		pilRotate -= 180
+// AutoDisplayAngle picks a display angle (same convention as display_angle:
+// degrees, counter-clockwise) from the item's shape. Long thin items get their
+// major axis turned to canonicalDeg; compact items, whose PCA axis is
+// unreliable, keep their rendered orientation; shapes in between are rotated
+// part of the way, so the result changes smoothly with elongation.
+func (o Options) AutoDisplayAngle(img *image.NRGBA, canonicalDeg float64) float64 {
+	axisImg, eval1, eval2, ok := o.principalAxis(img)
+	if !ok || eval1 <= 0 {
+		return canonicalDeg
 	}
-	for pilRotate < -90 {
-		pilRotate += 180
-	}
-
-	// Rotate image
-	rotated := rotateImage(img, pilRotate)
+	current := -axisImg // image (y-down) → display convention
 
-	// Auto-detect orientation on the rotated image:
-	// Project rotated pixels along target direction, check which half is wider
-	needFlip := detectFlipRotated(rotated, targetImg)
-	if forceFlip {
-		needFlip = !needFlip
+	// Shortest turn onto the canonical axis (axes are 180°-ambiguous)
+	delta := canonicalDeg - current
+	for delta > 90 {
+		delta -= 180
 	}
-	if needFlip {
-		rotated = rotate180(rotated)
+	for delta < -90 {
+		delta += 180
 	}
 
-	// Crop to bounding box of non-transparent pixels
-	cropped := cropAlpha(rotated)
-
-	// Scale to fill_ratio of canvas and center
-	return scaleAndCenter(cropped, canvasW, canvasH, fillRatio)
+	elongation := math.Inf(1)
+	if eval2 > 0 {
+		elongation = math.Sqrt(eval1 / eval2)
+	}
+	t := (elongation - autoAngleMinElongation) / (autoAngleMaxElongation - autoAngleMinElongation)
+	t = math.Max(0, math.Min(1, t))
+	return current + t*delta
 }
 
 // detectFlipRotated checks orientation on the already-rotated image.
 // Projects pixels along the target angle direction and compares
 // perpendicular spread of top-left vs bottom-right halves.
 // Matches Python's approach: std-based spread comparison on rotated pixels.
-func detectFlipRotated(rotated *image.NRGBA, targetImgDeg float64) bool {
+func (o Options) detectFlipRotated(rotated *image.NRGBA, targetImgDeg float64) bool {
 	b := rotated.Bounds()
 	w, h := b.Dx(), b.Dy()
 
-	// Collect non-transparent pixel coordinates
+	// Collect content pixel coordinates
 	var rxs, rys []float64
 	var minX, maxX, minY, maxY float64
 	first := true
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			if rotated.Pix[y*rotated.Stride+x*4+3] > 0 {
+			if o.isContent(rotated.Pix[y*rotated.Stride+x*4+3]) {
 				fx, fy := float64(x), float64(y)
 				rxs = append(rxs, fx)
 				rys = append(rys, fy)
@@ -191,7 +277,7 @@ func stddev(vals []float64) float64 {
 	return math.Sqrt(variance)
 }
 
-func rotateImage(img *image.NRGBA, angleDeg float64) *image.NRGBA {
+func (o Options) rotateImage(img *image.NRGBA, angleDeg float64) *image.NRGBA {
 	if math.Abs(angleDeg) < 0.5 {
 		return img
 	}
@@ -205,12 +291,16 @@ func rotateImage(img *image.NRGBA, angleDeg float64) *image.NRGBA {
 	// Expanded canvas to fit rotated image
 	newW := int(math.Ceil(w*cos + h*sin))
 	newH := int(math.Ceil(w*sin + h*cos))
+	if !o.fitsLimit("rotate", newW, newH) {
+		return img
+	}
 
 	dst := image.NewNRGBA(image.Rect(0, 0, newW, newH))
 
-	// Affine transform: rotate around center
-	cx, cy := w/2, h/2
-	ncx, ncy := float64(newW)/2, float64(newH)/2
+	// Affine transform: rotate around center (in pixel-index space the
+	// center of an n-pixel axis is (n-1)/2, not n/2)
+	cx, cy := (w-1)/2, (h-1)/2
+	ncx, ncy := float64(newW-1)/2, float64(newH-1)/2
 	cosA := math.Cos(rad)
 	sinA := math.Sin(rad)
 
@@ -286,7 +376,7 @@ func rotate180(img *image.NRGBA) *image.NRGBA {
 	return dst
 }
 
-func cropAlpha(img *image.NRGBA) *image.NRGBA {
+func (o Options) cropAlpha(img *image.NRGBA) *image.NRGBA {
 	b := img.Bounds()
 	w, h := b.Dx(), b.Dy()
 
@@ -294,7 +384,7 @@ func cropAlpha(img *image.NRGBA) *image.NRGBA {
 	maxX, maxY := 0, 0
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			if img.Pix[y*img.Stride+x*4+3] > 0 {
+			if o.isContent(img.Pix[y*img.Stride+x*4+3]) {
 				if x < minX {
 					minX = x
 				}
@@ -326,6 +416,24 @@ func cropAlpha(img *image.NRGBA) *image.NRGBA {
 	return cropped
 }
 
+// roundToParity rounds v to the nearest integer ≥ 1 with the same parity as
+// canvas, so (canvas - n) / 2 is exact and the content is not biased half a
+// pixel toward the top-left. The size changes by at most one pixel.
+func roundToParity(v float64, canvas int) int {
+	n := int(math.Floor(v))
+	if n < 1 {
+		n = 1
+	}
+	if (canvas-n)%2 != 0 {
+		if v-float64(n) >= 0.5 || n == 1 {
+			n++
+		} else {
+			n--
+		}
+	}
+	return n
+}
+
 func scaleAndCenter(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *image.NRGBA {
 	b := img.Bounds()
 	srcW, srcH := b.Dx(), b.Dy()
@@ -340,14 +448,8 @@ func scaleAndCenter(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *
 	if scaleY < scaleF {
 		scaleF = scaleY
 	}
-	newW := int(float64(srcW)*scaleF + 0.5)
-	newH := int(float64(srcH)*scaleF + 0.5)
-	if newW < 1 {
-		newW = 1
-	}
-	if newH < 1 {
-		newH = 1
-	}
+	newW := roundToParity(float64(srcW)*scaleF, canvasW)
+	newH := roundToParity(float64(srcH)*scaleF, canvasH)
 
 	// Resize
 	scaled := image.NewNRGBA(image.Rect(0, 0, newW, newH))
@@ -377,8 +479,11 @@ func scaleAndCenter(img *image.NRGBA, canvasW, canvasH int, fillRatio float64) *
 // TrimToContent crops transparent borders and scales the content to fill the
 // canvas with only a small pixel padding. This is the final post-processing
 // step ensuring items use the full canvas area.
-func TrimToContent(img *image.NRGBA, canvasW, canvasH int, padding int) *image.NRGBA {
-	cropped := cropAlpha(img)
+func (o Options) TrimToContent(img *image.NRGBA, canvasW, canvasH int, padding int) *image.NRGBA {
+	if !o.fitsLimit("trim", canvasW, canvasH) {
+		return img
+	}
+	cropped := o.cropAlpha(img)
 	b := cropped.Bounds()
 	if b.Dx() == 0 || b.Dy() == 0 {
 		return img
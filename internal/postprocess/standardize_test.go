@@ -0,0 +1,228 @@
+package postprocess
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+var (
+	bodyColor = color.NRGBA{40, 40, 200, 255}
+	tipColor  = color.NRGBA{220, 30, 30, 255}
+)
+
+// contentRect returns the bounds of img's pixels with any alpha.
+func contentRect(img *image.NRGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.NRGBAAt(x, y).A > 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+// centroidOf returns the mean position of img's mostly opaque pixels that
+// are redder than blue (tipColor) when red is set, else of the others
+// (bodyColor).
+func centroidOf(img *image.NRGBA, red bool) (float64, float64) {
+	var sx, sy, n float64
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := img.NRGBAAt(x, y)
+			if p.A < 128 || (p.R > p.B) != red {
+				continue
+			}
+			sx += float64(x)
+			sy += float64(y)
+			n++
+		}
+	}
+	return sx / n, sy / n
+}
+
+// bar returns a horizontal 60×10 bar on a 128×128 canvas with a red tip at
+// its right end.
+func bar() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	fillRect(img, image.Rect(34, 59, 84, 69), bodyColor)
+	fillRect(img, image.Rect(84, 59, 94, 69), tipColor)
+	return img
+}
+
+func TestRotateAndCenter(t *testing.T) {
+	var o Options
+	for _, c := range []struct {
+		angle    float64
+		tall     bool
+		tipAbove bool // the tip ends up above the body, else right of it
+	}{
+		{0, false, false},
+		{90, true, true}, // counter-clockwise: the right end turns up
+	} {
+		out := o.RotateAndCenter(bar(), 100, 100, c.angle, 0.8)
+		r := contentRect(out)
+		if tall := r.Dy() > r.Dx(); tall != c.tall {
+			t.Errorf("%v°: content %v, want tall=%v", c.angle, r, c.tall)
+		}
+		if long := max(r.Dx(), r.Dy()); long < 78 || long > 82 {
+			t.Errorf("%v°: long side %d px, want 80 (fill 0.8 of 100)", c.angle, long)
+		}
+		if cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2; cx < 49 || cx > 51 || cy < 49 || cy > 51 {
+			t.Errorf("%v°: content centered at %.1f,%.1f, want 50,50", c.angle, cx, cy)
+		}
+		tx, ty := centroidOf(out, true)
+		bx, by := centroidOf(out, false)
+		if above := ty < by-10 && math.Abs(tx-bx) < 3; above != c.tipAbove {
+			t.Errorf("%v°: tip at %.0f,%.0f, body at %.0f,%.0f; want tip above=%v", c.angle, tx, ty, bx, by, c.tipAbove)
+		}
+	}
+}
+
+// club returns a diagonal bar with a wide red head at its lower right end,
+// so the spread-based flip detection has a clear answer.
+func club() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	for i := 24; i < 90; i++ {
+		fillRect(img, image.Rect(i-3, i-3, i+3, i+3), bodyColor)
+	}
+	fillRect(img, image.Rect(84, 84, 104, 104), tipColor)
+	return img
+}
+
+func TestStandardizeAutoFlip(t *testing.T) {
+	var o Options
+	for _, autoFlip := range []bool{true, false} {
+		// The same club pointing both ways
+		a := o.StandardizeImage(club(), 100, 100, -45, 0.8, false, autoFlip)
+		b := o.StandardizeImage(rotate180(club()), 100, 100, -45, 0.8, false, autoFlip)
+		ax, ay := centroidOf(a, true)
+		bx, by := centroidOf(b, true)
+		same := math.Abs(ax-bx) < 5 && math.Abs(ay-by) < 5
+		if same != autoFlip {
+			t.Errorf("autoFlip %v: heads at %.0f,%.0f and %.0f,%.0f; want same side=%v", autoFlip, ax, ay, bx, by, autoFlip)
+		}
+	}
+	// flip still turns the result over with detection off
+	plain := o.StandardizeImage(club(), 100, 100, -45, 0.8, false, false)
+	flipped := o.StandardizeImage(club(), 100, 100, -45, 0.8, true, false)
+	px, py := centroidOf(plain, true)
+	fx, fy := centroidOf(flipped, true)
+	if math.Abs(px-(100-fx)) > 3 || math.Abs(py-(100-fy)) > 3 {
+		t.Errorf("forceFlip: head at %.0f,%.0f, want the mirror of %.0f,%.0f", fx, fy, px, py)
+	}
+}
+
+func TestCenterContent(t *testing.T) {
+	var o Options
+	out := o.CenterContent(bar(), 100, 80)
+	if b := out.Bounds(); b.Dx() != 100 || b.Dy() != 80 {
+		t.Fatalf("canvas %v, want 100×80", b)
+	}
+	r := contentRect(out)
+	if r.Dx() != 60 || r.Dy() != 10 {
+		t.Errorf("content %v, want the bar's 60×10 unscaled", r)
+	}
+	if r.Min.X != 20 || r.Min.Y != 35 {
+		t.Errorf("content at %v, want centered at 20,35", r.Min)
+	}
+}
+
+func TestRoundToParity(t *testing.T) {
+	for _, c := range []struct {
+		v      float64
+		canvas int
+		want   int
+	}{
+		{40.2, 100, 40},
+		{40.7, 100, 40},
+		{41.2, 100, 40},
+		{41.6, 100, 42},
+		{41.6, 101, 41},
+		{0.3, 100, 2},
+		{0.3, 101, 1},
+	} {
+		if got := roundToParity(c.v, c.canvas); got != c.want {
+			t.Errorf("roundToParity(%v, %d) = %d, want %d", c.v, c.canvas, got, c.want)
+		}
+	}
+}
+
+// alphaCentroid returns the alpha-weighted mean pixel position of img.
+func alphaCentroid(img *image.NRGBA) (float64, float64) {
+	var sx, sy, n float64
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			a := float64(img.NRGBAAt(x, y).A)
+			sx += a * float64(x)
+			sy += a * float64(y)
+			n += a
+		}
+	}
+	return sx / n, sy / n
+}
+
+// TestCenteredNoHalfPixelBias scales and rotates symmetric content onto even
+// and odd canvases: its centroid must land on the canvas center, (n-1)/2 in
+// pixel-index space, where the old n/2 arithmetic left it half a pixel off.
+func TestCenteredNoHalfPixelBias(t *testing.T) {
+	check := func(name string, img *image.NRGBA) {
+		t.Helper()
+		b := img.Bounds()
+		cx, cy := alphaCentroid(img)
+		wx, wy := float64(b.Dx()-1)/2, float64(b.Dy()-1)/2
+		if math.Abs(cx-wx) > 0.25 || math.Abs(cy-wy) > 0.25 {
+			t.Errorf("%s: centroid %.2f,%.2f, want %.1f,%.1f", name, cx, cy, wx, wy)
+		}
+	}
+	for _, src := range []image.Rectangle{image.Rect(0, 0, 7, 7), image.Rect(0, 0, 8, 8), image.Rect(0, 0, 13, 20)} {
+		img := image.NewNRGBA(src)
+		fillRect(img, src, bodyColor)
+		for _, canvas := range []int{100, 101} {
+			for _, fill := range []float64{0.8, 0.77} {
+				check(fmt.Sprintf("scaleAndCenter %v onto %d fill %v", src.Size(), canvas, fill), scaleAndCenter(img, canvas, canvas, fill))
+			}
+		}
+	}
+	var o Options
+	for _, size := range []int{40, 41} {
+		img := image.NewNRGBA(image.Rect(0, 0, size, size))
+		fillRect(img, image.Rect(10, 10, size-10, size-10), bodyColor)
+		for _, angle := range []float64{30, 45, 90} {
+			check(fmt.Sprintf("rotateImage %d px by %v°", size, angle), o.rotateImage(img, angle))
+		}
+	}
+}
+
+func TestAutoDisplayAngle(t *testing.T) {
+	var o Options
+	const canonical = -45.0
+	rect := func(w, h int) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+		fillRect(img, image.Rect(64-w/2, 64-h/2, 64+w/2, 64+h/2), bodyColor)
+		return img
+	}
+	for _, c := range []struct {
+		name      string
+		img       *image.NRGBA
+		want, tol float64 // axis angle, compared modulo 180°
+	}{
+		{"long bar", bar(), canonical, 1},          // turned fully
+		{"near square", rect(40, 38), 0, 1},        // kept as rendered
+		{"tall near square", rect(38, 40), 90, 1},  // kept, axis vertical
+		{"1.5:1 block", rect(60, 40), -22.5, 12.5}, // part of the way
+		{"empty", image.NewNRGBA(image.Rect(0, 0, 8, 8)), canonical, 0},
+	} {
+		got := o.AutoDisplayAngle(c.img, canonical)
+		if d := math.Mod(math.Abs(got-c.want), 180); math.Min(d, 180-d) > c.tol {
+			t.Errorf("%s: angle %.1f, want %.1f ± %.1f", c.name, got, c.want, c.tol)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package postprocess
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// disc returns an s×s image with an opaque disc of c on transparent black.
+func disc(s int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, s, s))
+	r := float64(s) * 0.35
+	for y := 0; y < s; y++ {
+		for x := 0; x < s; x++ {
+			dx, dy := float64(x)-float64(s)/2+0.5, float64(y)-float64(s)/2+0.5
+			if dx*dx+dy*dy <= r*r {
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// TestDownsampleNoHalo downsamples a solid disc on transparent black: the
+// partially covered edge pixels keep the disc's color at partial alpha, not
+// a color darkened toward the black background, up to 8-bit rounding.
+func TestDownsampleNoHalo(t *testing.T) {
+	c := color.NRGBA{40, 180, 220, 255}
+	for _, passes := range []int{1, 3} {
+		out := DownsamplePasses(disc(512, c), 64, 64, passes)
+		edges := 0
+		for i := 0; i < len(out.Pix); i += 4 {
+			a := out.Pix[i+3]
+			if a < 16 || a > 240 {
+				continue
+			}
+			edges++
+			// One 8-bit premultiplied step is 255/a after unpremultiplying
+			tol := 4 + 255/int(a)
+			for k, want := range []uint8{c.R, c.G, c.B} {
+				if d := int(out.Pix[i+k]) - int(want); d < -tol || d > tol {
+					t.Fatalf("passes %d: edge pixel %v at alpha %d, want the disc color %v", passes, out.Pix[i:i+3], a, c)
+				}
+			}
+		}
+		if edges == 0 {
+			t.Fatalf("passes %d: no partially covered pixels", passes)
+		}
+	}
+}
+
+func TestPremultiply(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{200, 100, 50, 255})
+	img.SetNRGBA(1, 0, color.NRGBA{200, 100, 50, 128})
+	img.SetNRGBA(2, 0, color.NRGBA{200, 100, 50, 0})
+	out := Premultiply(img)
+	want := []uint8{200, 100, 50, 255, 100, 50, 25, 128, 0, 0, 0, 0}
+	for i, v := range want {
+		if out.Pix[i] != v {
+			t.Fatalf("premultiplied %v, want %v", out.Pix, want)
+		}
+	}
+	if img.Pix[4] != 200 {
+		t.Error("Premultiply changed its input")
+	}
+}
+
+// zonePlate returns an opaque s×s grayscale zone plate, whose frequency
+// rises toward the corners past what a 1/8 downsample can hold.
+func zonePlate(s int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, s, s))
+	k := math.Pi / float64(s)
+	for y := 0; y < s; y++ {
+		for x := 0; x < s; x++ {
+			dx, dy := float64(x-s/2), float64(y-s/2)
+			v := uint8(127.5 + 127.5*math.Cos(k*(dx*dx+dy*dy)))
+			img.SetNRGBA(x, y, color.NRGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+// boxReference averages each f×f block of img's red channel.
+func boxReference(img *image.NRGBA, f int) []float64 {
+	w, h := img.Rect.Dx()/f, img.Rect.Dy()/f
+	out := make([]float64, w*h)
+	for y := 0; y < h*f; y++ {
+		for x := 0; x < w*f; x++ {
+			out[(y/f)*w+x/f] += float64(img.Pix[img.PixOffset(x, y)])
+		}
+	}
+	for i := range out {
+		out[i] /= float64(f * f)
+	}
+	return out
+}
+
+// TestDownsamplePasses takes a zone plate from 1024 to 128 px: staged
+// reduction aliases less (is closer to the area average) than one
+// CatmullRom pass, passes past the useful ones change nothing, and an image
+// already within the target is returned as is.
+func TestDownsamplePasses(t *testing.T) {
+	src := zonePlate(1024)
+	ref := boxReference(src, 8)
+	rms := func(img *image.NRGBA) float64 {
+		var sum float64
+		for i, r := range ref {
+			d := float64(img.Pix[i*4]) - r
+			sum += d * d
+		}
+		return math.Sqrt(sum / float64(len(ref)))
+	}
+
+	single := DownsamplePasses(src, 128, 128, 1)
+	staged := DownsamplePasses(src, 128, 128, 3)
+	if b := staged.Bounds(); b.Dx() != 128 || b.Dy() != 128 {
+		t.Fatalf("staged: %v, want 128×128", b)
+	}
+	if es, e1 := rms(staged), rms(single); es >= e1 {
+		t.Errorf("staged RMS error %.2f, single pass %.2f: want staged lower", es, e1)
+	}
+	if !bytes.Equal(DownsamplePasses(src, 128, 128, 10).Pix, staged.Pix) {
+		t.Error("passes 10 differs from 3: stages past 4× the target must be skipped")
+	}
+	if !bytes.Equal(Downsample(src, 128, 128).Pix, single.Pix) {
+		t.Error("Downsample differs from one pass")
+	}
+	small := zonePlate(64)
+	if DownsamplePasses(small, 128, 128, 3) != small {
+		t.Error("image within the target was resampled")
+	}
+}
@@ -0,0 +1,55 @@
+package raster
+
+import (
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// passes returns the render pass of each texture drawn, with the opaque
+// pass's decorations counted as opaque.
+func passes(stats *RenderStats) map[string]string {
+	p := map[string]string{}
+	for _, m := range stats.Rendered {
+		p[m.TexPath] = m.Pass
+		if m.Pass == "decoration" {
+			p[m.TexPath] = "opaque"
+		}
+	}
+	return p
+}
+
+// TestBlendOverrides renders a TGA overlay on a JPEG body, a small JPEG
+// billboard and an effect mesh: without overrides the heuristics make them
+// alpha, additive and filtered; blend_overrides keeps the first two opaque
+// and draws the effect additively, matching texture stems in any case.
+func TestBlendOverrides(t *testing.T) {
+	tex := solidTextures{
+		"body.jpg": {150, 150, 150, 255}, "cloth.tga": {150, 40, 40, 255},
+		"decal.jpg": {40, 150, 40, 255}, "glow01.jpg": {40, 40, 150, 255},
+	}
+	meshes := []bmd.Mesh{
+		box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "body.jpg"),
+		box([3]float32{-11, -11, -28}, [3]float32{11, 11, 28}, 5, "cloth.tga"),
+		box([3]float32{12, -3, -3}, [3]float32{14, 3, 3}, 2, "decal.jpg"),
+		box([3]float32{-6, -6, 30}, [3]float32{6, 6, 34}, 6, "glow01.jpg"),
+	}
+	_, stats := RenderBMDWithStats(meshes, nil, testEntry(), tex, 64, 64, 1, Options{})
+	if got := passes(stats); got["cloth.tga"] != "alpha" || got["decal.jpg"] != "additive" || got["glow01.jpg"] != "" {
+		t.Fatalf("without overrides: passes %v; want cloth alpha, decal additive, glow01 filtered", got)
+	}
+
+	e := testEntry()
+	e.BlendOverrides = map[string]string{"cloth": "opaque", "decal": "opaque", "glow01": "additive"}
+	meshes[1].TexPath, meshes[2].TexPath = "Cloth.TGA", "DECAL.jpg"
+	_, stats = RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{})
+	got := passes(stats)
+	for texPath, want := range map[string]string{"Cloth.TGA": "opaque", "DECAL.jpg": "opaque", "glow01.jpg": "additive", "body.jpg": "opaque"} {
+		if got[texPath] != want {
+			t.Errorf("%s: pass %q, want %q (all: %v)", texPath, got[texPath], want, got)
+		}
+	}
+	if len(stats.Filtered) != 0 {
+		t.Errorf("filtered %+v, want nothing", stats.Filtered)
+	}
+}
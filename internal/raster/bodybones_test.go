@@ -0,0 +1,38 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestBodyBones binds the second of two boxes that share a texture to bone
+// 5: with body_bones [5] the render must match the first box alone, with the
+// bound one recorded as filtered.
+func TestBodyBones(t *testing.T) {
+	tex := solidTextures{"armor.jpg": {150, 150, 150, 255}}
+	armor := box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "armor.jpg")
+	body := box([3]float32{20, -5, -5}, [3]float32{30, 5, 5}, 6, "armor.jpg")
+	for i := range body.Nodes {
+		body.Nodes[i] = 5
+	}
+	meshes := []bmd.Mesh{armor, body}
+	want := RenderBMD(meshes[:1], nil, testEntry(), tex, 64, 64, 1, Options{})
+
+	e := testEntry()
+	e.BodyBones = []int{5}
+	img, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{})
+	if !bytes.Equal(img.Pix, want.Pix) {
+		t.Error("render with body_bones differs from the equipment alone")
+	}
+	if len(stats.Filtered) != 1 || stats.Filtered[0].Reason != "body_bones" || len(stats.Rendered) != 1 {
+		t.Errorf("filtered %+v, rendered %d; want the body mesh filtered by body_bones", stats.Filtered, len(stats.Rendered))
+	}
+
+	// A bone set that binds every mesh is ignored rather than rendering nothing
+	e.BodyBones = []int{0, 5}
+	if _, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{}); len(stats.Rendered) != 2 {
+		t.Errorf("every mesh bound: %d meshes rendered, want 2", len(stats.Rendered))
+	}
+}
@@ -7,15 +7,16 @@ type FrameBuffer struct {
 	Width  int
 	Height int
 	Color  []uint8   // RGBA interleaved, len = W*H*4
-	ZBuf   []float64 // depth per pixel, len = W*H, initialized to -inf
+	ZBuf   []float32 // depth per pixel, len = W*H, initialized to -inf (float32 halves memory; depths are pixel-scale, and every pass tests depth in float32)
 }
 
 // NewFrameBuffer allocates a zeroed color buffer and -inf z-buffer.
 func NewFrameBuffer(w, h int) *FrameBuffer {
 	n := w * h
-	zbuf := make([]float64, n)
+	zbuf := make([]float32, n)
+	negInf := float32(math.Inf(-1))
 	for i := range zbuf {
-		zbuf[i] = math.Inf(-1)
+		zbuf[i] = negInf
 	}
 	return &FrameBuffer{
 		Width:  w,
@@ -0,0 +1,64 @@
+package raster
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFloat32DepthMatchesFloat64 draws two planes that cross the canvas at a
+// shallow angle, at the depths a 4096 px supersampled render reaches, and
+// compares which one wins each pixel with the float64 answer. The float32
+// z-buffer may only disagree where the planes are closer than its precision.
+func TestFloat32DepthMatchesFloat64(t *testing.T) {
+	const size = 512
+	const base = 4000.0 // pixel-scale depth, as far from 0 as large renders get
+	planeA := func(x, y float64) float64 { return base + 0.002*(x-size/2) }
+	planeB := func(x, y float64) float64 { return base - 0.002*(x-size/2) + 0.001*(y-size/2) }
+
+	fb := NewFrameBuffer(size, size)
+	lc := DefaultLightConfig()
+	draw := func(plane func(x, y float64) float64, r, b uint8) {
+		px := []float64{-1, size + 1, size + 1, -1}
+		py := []float64{-1, -1, size + 1, size + 1}
+		pz := make([]float64, 4)
+		for i := range pz {
+			pz[i] = plane(px[i], py[i])
+		}
+		for _, vi := range [][3]int{{0, 1, 2}, {0, 2, 3}} {
+			RasterizeTriangle(fb, px, py, pz, nil, vi, vi, nil, r, 0, b, 255, &lc, nil)
+		}
+	}
+	draw(planeA, 255, 0)
+	draw(planeB, 0, 255)
+
+	// float32 spacing at base, doubled for the rounding of both planes
+	eps := 2 * float64(math.Nextafter32(base, math.MaxFloat32)-base)
+	mismatches, close := 0, 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			i := (y*size + x) * 4
+			if fb.Color[i+3] == 0 {
+				t.Fatalf("pixel %d,%d not covered", x, y)
+			}
+			za, zb := planeA(float64(x), float64(y)), planeB(float64(x), float64(y))
+			wantB := zb >= za // B is drawn second and wins ties
+			gotB := fb.Color[i+2] > fb.Color[i]
+			if math.Abs(za-zb) <= eps {
+				close++
+				continue
+			}
+			if gotB != wantB {
+				mismatches++
+				if mismatches <= 5 {
+					t.Errorf("pixel %d,%d: z %.6f vs %.6f, got plane B %v", x, y, za, zb, gotB)
+				}
+			}
+		}
+	}
+	if mismatches > 0 {
+		t.Errorf("%d pixels resolved differently from float64 depth", mismatches)
+	}
+	if close > size {
+		t.Errorf("%d pixels within float32 precision of a tie, want at most one row's worth", close)
+	}
+}
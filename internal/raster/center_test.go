@@ -0,0 +1,43 @@
+package raster
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// alphaCentroid returns the alpha-weighted mean pixel position of img.
+func alphaCentroid(img *image.NRGBA) (float64, float64) {
+	var sx, sy, n float64
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			a := float64(img.Pix[img.PixOffset(x, y)+3])
+			sx += a * float64(x)
+			sy += a * float64(y)
+			n += a
+		}
+	}
+	return sx / n, sy / n
+}
+
+// TestRenderCentered renders a box centered on the origin on even and odd
+// canvases, with and without supersampling: its centroid must sit at the
+// center of the rendered image, (n-1)/2 in pixel-index space. Projecting
+// around n/2 put it half a pixel down and right.
+func TestRenderCentered(t *testing.T) {
+	tex := solidTextures{"box.jpg": {150, 150, 150, 255}}
+	m := []bmd.Mesh{box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "box.jpg")}
+	for _, size := range []int{64, 65, 128} {
+		for _, ss := range []int{1, 2} {
+			img := RenderBMD(m, nil, testEntry(), tex, size, size, ss, Options{})
+			cx, cy := alphaCentroid(img)
+			want := float64(img.Bounds().Dx()-1) / 2
+			if math.Abs(cx-want) > 0.25 || math.Abs(cy-want) > 0.25 {
+				t.Errorf("%d px, supersample %d: centroid %.2f,%.2f, want %.1f", size, ss, cx, cy, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package raster
+
+import (
+	"math"
+	"testing"
+)
+
+// The color path the rasterizers share (srgbToLinear, ACESTonemap,
+// shadeTexel, clamp255) must stay bounded and monotonic: a regression in
+// lighting.go or triangle.go otherwise shows up as banding, NaN pixels or
+// inverted gradients in the renders rather than as an error.
+
+// acesLimit is ACESTonemap's supremum: 2.51/2.43, just above 1.
+const acesLimit = 2.51 / 2.43
+
+// maxShade returns an upper bound of ComputeShade over all normals: every
+// term at its peak at once.
+func maxShade(lc LightConfig) float64 {
+	return lc.Ambient + lc.Hemi + lc.Direct + lc.Rim + lc.SpecInt
+}
+
+func TestSRGBToLinear(t *testing.T) {
+	if srgbToLinear[0] != 0 || srgbToLinear[255] != 1 {
+		t.Errorf("srgbToLinear runs from %g to %g, want 0 to 1", srgbToLinear[0], srgbToLinear[255])
+	}
+	for i := 1; i < 256; i++ {
+		if !(srgbToLinear[i] > srgbToLinear[i-1]) {
+			t.Fatalf("srgbToLinear not increasing at %d: %g after %g", i, srgbToLinear[i], srgbToLinear[i-1])
+		}
+	}
+}
+
+// TestACESTonemap sweeps [0, 1e6], densely near 0 where the curve bends.
+func TestACESTonemap(t *testing.T) {
+	if v := ACESTonemap(0); v != 0 {
+		t.Errorf("ACESTonemap(0) = %g, want 0", v)
+	}
+	prev := 0.0
+	for x := 1e-6; x <= 1e6; x *= 1.01 {
+		v := ACESTonemap(x)
+		if math.IsNaN(v) || v < 0 || v >= acesLimit {
+			t.Fatalf("ACESTonemap(%g) = %g, outside [0, %.4f)", x, v, acesLimit)
+		}
+		if v < prev {
+			t.Fatalf("ACESTonemap decreases at %g: %g after %g", x, v, prev)
+		}
+		prev = v
+	}
+}
+
+// TestToneMapHeadroom checks that no texel clips under the default
+// lighting: the brightest reachable shade stays under 1 once tone-mapped.
+func TestToneMapHeadroom(t *testing.T) {
+	lc := DefaultLightConfig()
+	peak := maxShade(lc) * lc.Exposure
+	if v := ACESTonemap(peak); v >= 1 {
+		t.Errorf("brightest shade %.3f (max shade × exposure) tone-maps to %.4f ≥ 1: lit texels clip", peak, v)
+	}
+}
+
+// TestShadeTexel runs the full texel path over every byte and a sweep of
+// shades up to 4× the brightest, covering additive stacking and exposure
+// tweaks. It must be finite, non-negative and non-decreasing in both the
+// texel and the shade, so the clamp to a byte only ever trims values above
+// 255.
+func TestShadeTexel(t *testing.T) {
+	lc := DefaultLightConfig()
+	const steps = 512
+	prevRow := make([]float64, 256)
+	for s := 0; s <= steps; s++ {
+		shade := 4 * maxShade(lc) * float64(s) / steps
+		last := 0.0
+		for c := 0; c < 256; c++ {
+			v := shadeTexel(uint8(c), shade, lc.Exposure, lc.InvGamma)
+			switch {
+			case math.IsNaN(v) || math.IsInf(v, 0) || v < 0:
+				t.Fatalf("shadeTexel(%d, shade %.3f) = %g", c, shade, v)
+			case v < last:
+				t.Fatalf("shadeTexel decreases in texel value at %d (shade %.3f): %g after %g", c, shade, v, last)
+			case s > 0 && v < prevRow[c]:
+				t.Fatalf("shadeTexel decreases in shade at texel %d (shade %.3f): %g after %g", c, shade, v, prevRow[c])
+			}
+			want := uint8(255)
+			if v < 254.5 {
+				want = uint8(v + 0.5)
+			}
+			if b := clamp255(v); b != want {
+				t.Fatalf("clamp255(%g) = %d, want %d", v, b, want)
+			}
+			last = v
+			prevRow[c] = v
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package raster
+
+import (
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/viewmatrix"
+)
+
+// diffShare returns the fraction of pixels whose channels differ by more
+// than 8 between two same-size images.
+func diffShare(a, b []uint8) float64 {
+	n := 0
+	for i := 0; i < len(a); i += 4 {
+		for c := 0; c < 4; c++ {
+			if d := int(a[i+c]) - int(b[i+c]); d > 8 || d < -8 {
+				n++
+				break
+			}
+		}
+	}
+	return float64(n) / float64(len(a)/4)
+}
+
+// TestCoordinateConvention stores a model the way a Y-up and a mirrored
+// export would: rendered with the matching convention it must look like the
+// MU-space original.
+func TestCoordinateConvention(t *testing.T) {
+	model := []bmd.Mesh{
+		box([3]float32{-10, -4, 0}, [3]float32{10, 4, 100}, 4, "blade.jpg"),
+		box([3]float32{-25, -6, -8}, [3]float32{25, 6, 0}, 4, "hilt.jpg"),
+		box([3]float32{10, 10, 60}, [3]float32{50, 50, 80}, 4, "hilt.jpg"),
+	}
+	tex := solidTextures{
+		"blade.jpg": {200, 40, 30, 255},
+		"hilt.jpg":  {210, 170, 40, 255},
+	}
+	want := RenderBMD(model, nil, testEntry(), tex, 128, 128, 2, Options{})
+
+	for _, c := range []struct {
+		name string
+		conv func([3]float32) [3]float32 // MU space → the export's space
+	}{
+		{"y-up", func(v [3]float32) [3]float32 { return [3]float32{v[0], v[2], -v[1]} }},
+		{"mirrored", func(v [3]float32) [3]float32 { return [3]float32{-v[0], v[1], v[2]} }},
+	} {
+		exported := make([]bmd.Mesh, len(model))
+		for i, m := range model {
+			e := m
+			e.Verts, e.Normals = make([][3]float32, len(m.Verts)), make([][3]float32, len(m.Normals))
+			for j := range m.Verts {
+				e.Verts[j], e.Normals[j] = c.conv(m.Verts[j]), c.conv(m.Normals[j])
+			}
+			exported[i] = e
+		}
+		conv, err := viewmatrix.Convention(c.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := RenderBMD(exported, nil, testEntry(), tex, 128, 128, 2, Options{Convention: conv})
+		if d := diffShare(got.Pix, want.Pix); d > 0.01 {
+			t.Errorf("%s: %.1f%% of pixels differ from the MU-space render", c.name, 100*d)
+		}
+		if plain := RenderBMD(exported, nil, testEntry(), tex, 128, 128, 2, Options{}); diffShare(plain.Pix, want.Pix) < 0.05 {
+			t.Errorf("%s: renders like the original without the convention; the test model is too symmetric", c.name)
+		}
+	}
+}
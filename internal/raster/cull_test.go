@@ -0,0 +1,48 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+func TestBelowMinArea(t *testing.T) {
+	px := []float64{0, 4, 0, 0.1}
+	py := []float64{0, 0, 2, 0.1}
+	for _, c := range []struct {
+		vi      [3]int
+		minArea float64
+		want    bool
+	}{
+		{[3]int{0, 1, 2}, 3.9, false}, // area 4
+		{[3]int{0, 1, 2}, 4.1, true},
+		{[3]int{0, 1, 3}, 0.5, true},  // area 0.2
+		{[3]int{0, 1, 3}, 0, false},   // off
+		{[3]int{0, 1, 9}, 0.5, false}, // bad index: the rasterizer's call
+	} {
+		if got := belowMinArea(px, py, c.vi, c.minArea); got != c.want {
+			t.Errorf("triangle %v, min %v: %v, want %v", c.vi, c.minArea, got, c.want)
+		}
+	}
+}
+
+// TestMinTriangleArea renders a body with a thin wire beside it: the cull
+// must drop the wire's sliver triangles and keep the body as drawn.
+func TestMinTriangleArea(t *testing.T) {
+	tex := solidTextures{"body.jpg": {150, 150, 150, 255}, "wire.jpg": {40, 40, 200, 255}}
+	body := box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "body.jpg")
+	wire := box([3]float32{14, -0.05, -30}, [3]float32{14.1, 0.05, 30}, 30, "wire.jpg")
+	hidden := wire
+	hidden.Tris = nil // same framing, nothing drawn
+
+	drawn := RenderBMD([]bmd.Mesh{body, wire}, nil, testEntry(), tex, 128, 128, 2, Options{})
+	want := RenderBMD([]bmd.Mesh{body, hidden}, nil, testEntry(), tex, 128, 128, 2, Options{})
+	if bytes.Equal(drawn.Pix, want.Pix) {
+		t.Fatal("the wire doesn't show without the cull; the test model is wrong")
+	}
+	got := RenderBMD([]bmd.Mesh{body, wire}, nil, testEntry(), tex, 128, 128, 2, Options{MinTriangleArea: 0.5})
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("min_triangle_area 0.5: %.4f coverage, want %.4f (body only)", coverage(got), coverage(want))
+	}
+}
@@ -0,0 +1,73 @@
+package raster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// imageTextures resolves texture names to fixed images.
+type imageTextures map[string]*image.NRGBA
+
+func (m imageTextures) Resolve(name string) *image.NRGBA { return m[name] }
+
+// TestEffectsAsGlow renders a body box beside a "glow" effect box whose
+// texture is black on its left half and bright on its right. With
+// render_effects_as_glow the effect goes to the additive pass: where it
+// shows a black texel the canvas stays fully transparent, where it shows a
+// bright one the glow stays. Without the option the effect mesh is still
+// filtered, and the render is the body's alone.
+func TestEffectsAsGlow(t *testing.T) {
+	half := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	half.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+	half.SetNRGBA(1, 0, color.NRGBA{240, 240, 240, 255})
+	white := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	white.SetNRGBA(0, 0, color.NRGBA{240, 240, 240, 255})
+	white.SetNRGBA(1, 0, color.NRGBA{240, 240, 240, 255})
+	body := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	body.SetNRGBA(0, 0, color.NRGBA{150, 150, 150, 255})
+
+	meshes := []bmd.Mesh{
+		box([3]float32{-10, -10, -40}, [3]float32{10, 10, 40}, 6, "body.tga"),
+		box([3]float32{30, -10, -20}, [3]float32{60, 10, 20}, 6, "glow_ring.tga"),
+	}
+	render := func(glow bool, effect *image.NRGBA, meshes []bmd.Mesh) (*image.NRGBA, *RenderStats) {
+		e := testEntry()
+		e.EffectsAsGlow = glow
+		tex := imageTextures{"body.tga": body, "glow_ring.tga": effect}
+		return RenderBMDWithStats(bmd.CloneMeshes(meshes), nil, e, tex, 64, 64, 1, Options{})
+	}
+
+	// Where the effect box lands: everything an all-bright texture covers
+	full, _ := render(true, white, meshes)
+	img, stats := render(true, half, meshes)
+	if len(stats.Filtered) != 0 || len(stats.Rendered) != 2 || stats.Rendered[1].Pass != "additive" {
+		t.Fatalf("glow: filtered %+v, rendered %+v; want the effect in the additive pass", stats.Filtered, stats.Rendered)
+	}
+	var clear, bright int
+	for i := 3; i < len(img.Pix); i += 4 {
+		if full.Pix[i] == 0 {
+			continue
+		}
+		switch a := img.Pix[i]; {
+		case a == 0:
+			clear++
+		case a == 255 && img.Pix[i-3] > 200:
+			bright++
+		}
+	}
+	if clear == 0 || bright == 0 {
+		t.Errorf("glow: %d transparent and %d bright effect pixels, want both", clear, bright)
+	}
+
+	off, stats := render(false, half, meshes)
+	if len(stats.Filtered) != 1 || stats.Filtered[0].Reason != "effect" {
+		t.Errorf("off: filtered %+v, want the effect mesh", stats.Filtered)
+	}
+	if plain, _ := render(false, half, meshes[:1]); !bytes.Equal(off.Pix, plain.Pix) {
+		t.Error("off: render differs from the body's alone")
+	}
+}
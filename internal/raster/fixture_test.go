@@ -0,0 +1,83 @@
+package raster
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// box returns an axis-aligned box mesh cut into segments along z, textured
+// with tex. Body meshes need more than 16 vertices: a smaller JPEG mesh is
+// taken for a glow billboard.
+func box(lo, hi [3]float32, segments int, tex string) bmd.Mesh {
+	m := bmd.Mesh{TexPath: tex, UVs: [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}
+	ring := [4][2]float32{{lo[0], lo[1]}, {hi[0], lo[1]}, {hi[0], hi[1]}, {lo[0], hi[1]}}
+	c := [3]float32{(lo[0] + hi[0]) / 2, (lo[1] + hi[1]) / 2, (lo[2] + hi[2]) / 2}
+	for k := 0; k <= segments; k++ {
+		z := lo[2] + (hi[2]-lo[2])*float32(k)/float32(segments)
+		for _, r := range ring {
+			v := [3]float32{r[0], r[1], z}
+			d := [3]float32{v[0] - c[0], v[1] - c[1], v[2] - c[2]}
+			l := float32(math.Sqrt(float64(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])))
+			m.Verts = append(m.Verts, v)
+			m.Normals = append(m.Normals, [3]float32{d[0] / l, d[1] / l, d[2] / l})
+			m.Nodes = append(m.Nodes, 0)
+		}
+	}
+	quad := func(a, b, c, d int16) {
+		m.Tris = append(m.Tris, bmd.Triangle{Polygon: 4, VI: [4]int16{a, b, c, d}, NI: [4]int16{a, b, c, d}, TI: [4]int16{0, 1, 2, 3}})
+	}
+	for k := 0; k < segments; k++ {
+		for r := 0; r < 4; r++ {
+			a, b := int16(k*4+r), int16(k*4+(r+1)%4)
+			quad(a, b, b+4, a+4)
+		}
+	}
+	top := int16(segments * 4)
+	quad(3, 2, 1, 0)
+	quad(top, top+1, top+2, top+3)
+	return m
+}
+
+// solidTextures resolves a texture name to a 4×4 texture of its color;
+// names it doesn't hold are missing.
+type solidTextures map[string]color.NRGBA
+
+func (s solidTextures) Resolve(name string) *image.NRGBA {
+	c, ok := s[strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+// testEntry returns a TRS entry with the loader's defaults, viewing the
+// model from the side.
+func testEntry() *trs.Entry {
+	return &trs.Entry{
+		RotX:         -90,
+		Scale:        1,
+		DisplayAngle: trs.DefaultDisplayAngle,
+		FillRatio:    trs.DefaultFillRatio,
+		FOV:          trs.DefaultFOV,
+	}
+}
+
+// coverage returns the fraction of img's pixels with any alpha.
+func coverage(img *image.NRGBA) float64 {
+	n := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 0 {
+			n++
+		}
+	}
+	return float64(n) / float64(len(img.Pix)/4)
+}
@@ -0,0 +1,49 @@
+package raster
+
+import (
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestFilterGlowLayers gives filterGlowLayers one mesh of each pattern it
+// removes. Its geometry groups live in a map, so the result is checked over
+// repeated runs: it must not depend on map order.
+func TestFilterGlowLayers(t *testing.T) {
+	tex := solidTextures{
+		"body.jpg":  {150, 150, 150, 255},
+		"pair.jpg":  {120, 90, 60, 255},
+		"pair.tga":  {120, 90, 60, 255},
+		"shine.jpg": {250, 250, 245, 255},
+		"hide.jpg":  {0, 0, 0, 255},
+		"trim.tga":  {200, 180, 40, 255},
+	}
+	lo, hi := [3]float32{-10, -10, -10}, [3]float32{10, 10, 10}
+	meshes := []bmd.Mesh{
+		box(lo, hi, 8, "body.jpg"),    // body: the most triangles
+		box(lo, hi, 2, "pair.jpg"),    // same geometry as the next:
+		box(lo, hi, 2, "Pair.tga"),    // a glow pair
+		box(lo, hi, 3, "shine.jpg"),   // bright, unsaturated JPEG
+		box(lo, hi, 4, "hide.jpg"),    // tiny black filler
+		box(lo, hi, 5, "missing.jpg"), // unresolved texture
+		box(lo, hi, 6, "trim.tga"),    // kept
+	}
+	for run := 0; run < 20; run++ {
+		if got, want := filterGlowLayers(meshes, tex), []int{0, 6}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: kept %v, want %v", run, got, want)
+		}
+	}
+
+	// The body is never filtered, however bright
+	bright := []bmd.Mesh{box(lo, hi, 8, "shine.jpg"), box(lo, hi, 3, "shine.jpg")}
+	if got := filterGlowLayers(bright, tex); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("bright body: kept %v, want [0]", got)
+	}
+
+	// Filtering everything keeps everything
+	pair := []bmd.Mesh{box(lo, hi, 2, "pair.jpg"), box(lo, hi, 2, "pair.tga")}
+	if got := filterGlowLayers(pair, tex); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("only a pair: kept %v, want both", got)
+	}
+}
@@ -0,0 +1,36 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestHideMeshes hides the second of two boxes that share a texture: the
+// render must match the first box alone, and the hidden one is recorded as
+// filtered by hide_meshes.
+func TestHideMeshes(t *testing.T) {
+	tex := solidTextures{"box.jpg": {150, 150, 150, 255}}
+	meshes := []bmd.Mesh{
+		box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "box.jpg"),
+		box([3]float32{20, -5, -5}, [3]float32{30, 5, 5}, 6, "box.jpg"),
+	}
+	want := RenderBMD(meshes[:1], nil, testEntry(), tex, 64, 64, 1, Options{})
+
+	e := testEntry()
+	e.HideMeshIndices = []int{1, 7} // out-of-range indices are ignored
+	img, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{})
+	if !bytes.Equal(img.Pix, want.Pix) {
+		t.Error("render with mesh 1 hidden differs from mesh 0 alone")
+	}
+	if len(stats.Filtered) != 1 || stats.Filtered[0].Reason != "hide_meshes" || len(stats.Rendered) != 1 {
+		t.Errorf("filtered %+v, rendered %d; want mesh 1 filtered by hide_meshes", stats.Filtered, len(stats.Rendered))
+	}
+
+	// Hiding every mesh is ignored rather than rendering nothing
+	e.HideMeshIndices = []int{0, 1}
+	if _, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{}); len(stats.Rendered) != 2 {
+		t.Errorf("all hidden: %d meshes rendered, want 2", len(stats.Rendered))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"mu-bmd-renderer/internal/mathutil"
+	"mu-bmd-renderer/internal/trs"
 )
 
 // LightConfig holds precomputed lighting parameters.
@@ -22,6 +23,8 @@ type LightConfig struct {
 	SRGBGamma         float64
 	InvGamma          float64
 	AdditiveDarkFloor float64 // minimum luminance for additive pass (default 80)
+	MinTriArea        float64 // skip triangles whose projected area is below this, in render px² (0 = off)
+	Smooth            bool    // shade opaque meshes with interpolated vertex normals instead of per face
 }
 
 // DefaultLightConfig returns the standard lighting matching the Python renderer.
@@ -50,6 +53,31 @@ func DefaultLightConfig() LightConfig {
 	}
 }
 
+// apply overrides lc's light settings with those l sets (nil = none).
+func (lc *LightConfig) apply(l *trs.Lighting) {
+	if l == nil {
+		return
+	}
+	if l.Ambient != nil {
+		lc.Ambient = *l.Ambient
+	}
+	if l.Direct != nil {
+		lc.Direct = *l.Direct
+	}
+	if l.Rim != nil {
+		lc.Rim = *l.Rim
+	}
+	if l.Exposure != nil {
+		lc.Exposure = *l.Exposure
+	}
+	if l.SpecInt != nil {
+		lc.SpecInt = *l.SpecInt
+	}
+	if l.SpecPow != nil {
+		lc.SpecPow = *l.SpecPow
+	}
+}
+
 // ComputeShade returns the combined lighting scalar for a face normal.
 func (lc *LightConfig) ComputeShade(normal mathutil.Vec3) float64 {
 	// Lambertian (abs for double-sided)
@@ -79,6 +107,12 @@ func init() {
 	}
 }
 
+// SRGBToLinear returns 8-bit channel value c in linear light, decoded the
+// way the rasterizers decode texels (gamma 2.2, the inverse of InvGamma).
+func SRGBToLinear(c uint8) float64 {
+	return srgbToLinear[c]
+}
+
 // ACESTonemap applies ACES Filmic tone mapping to a linear value.
 func ACESTonemap(x float64) float64 {
 	return (x * (2.51*x + 0.03)) / (x*(2.43*x+0.59) + 0.14)
@@ -0,0 +1,58 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestLightingOverride checks an entry's lighting replaces only the light
+// settings it sets, the rest keeping DefaultLightConfig's, both in the
+// config and in what the renderer draws.
+func TestLightingOverride(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	lc := DefaultLightConfig()
+	lc.apply(&trs.Lighting{Exposure: f(1.3), SpecPow: f(20)})
+	want := DefaultLightConfig()
+	want.Exposure, want.SpecPow = 1.3, 20
+	if lc != want {
+		t.Errorf("apply: got %+v, want %+v", lc, want)
+	}
+	for _, l := range []*trs.Lighting{nil, {}} {
+		lc := DefaultLightConfig()
+		lc.apply(l)
+		if lc != DefaultLightConfig() {
+			t.Errorf("apply(%v): changed the defaults to %+v", l, lc)
+		}
+	}
+
+	meshes := []bmd.Mesh{box([3]float32{-20, -10, -40}, [3]float32{20, 10, 40}, 6, "body.tga")}
+	tex := solidTextures{"body.tga": {R: 120, G: 110, B: 100, A: 255}}
+	render := func(l *trs.Lighting) []byte {
+		e := testEntry()
+		e.Lighting = l
+		return RenderBMD(bmd.CloneMeshes(meshes), nil, e, tex, 48, 48, 1, Options{}).Pix
+	}
+	plain := render(nil)
+	if !bytes.Equal(render(&trs.Lighting{}), plain) {
+		t.Error("empty lighting: render differs from the defaults'")
+	}
+	if !bytes.Equal(render(&trs.Lighting{Ambient: f(0.55), Rim: f(0.60)}), plain) {
+		t.Error("lighting at the default values: render differs from the defaults'")
+	}
+	if sum(render(&trs.Lighting{Exposure: f(2)})) <= sum(plain) {
+		t.Error("exposure 2: not brighter than the default")
+	}
+}
+
+// sum adds up the color channels of NRGBA pixels.
+func sum(pix []byte) int {
+	n := 0
+	for i := 0; i < len(pix); i += 4 {
+		n += int(pix[i]) + int(pix[i+1]) + int(pix[i+2])
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestMergeMeshesRendersIdentically draws a model with two same-texture
+// body meshes apart from each other and a third texture, with and without
+// merge_meshes: merging may only save draw passes, never change a pixel.
+func TestMergeMeshesRendersIdentically(t *testing.T) {
+	model := func() []bmd.Mesh {
+		return []bmd.Mesh{
+			box([3]float32{-10, -4, 0}, [3]float32{10, 4, 100}, 4, "blade.jpg"),
+			box([3]float32{-25, -6, -8}, [3]float32{25, 6, 0}, 4, "hilt.jpg"),
+			box([3]float32{-5, -5, -40}, [3]float32{5, 5, -8}, 4, "blade.jpg"),
+		}
+	}
+	tex := solidTextures{
+		"blade.jpg": {200, 40, 30, 255},
+		"hilt.jpg":  {210, 170, 40, 255},
+	}
+	plain := testEntry()
+	merged := testEntry()
+	merged.MergeMeshes = true
+
+	want, stats := RenderBMDWithStats(model(), nil, plain, tex, 128, 128, 2, Options{})
+	if len(stats.Rendered) != 3 || coverage(want) < 0.05 {
+		t.Fatalf("unmerged: %d meshes drawn, coverage %.3f", len(stats.Rendered), coverage(want))
+	}
+	got := RenderBMD(model(), nil, merged, tex, 128, 128, 2, Options{})
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Error("merge_meshes changed the render")
+	}
+}
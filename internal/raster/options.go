@@ -0,0 +1,49 @@
+package raster
+
+import (
+	"image/color"
+
+	"mu-bmd-renderer/internal/mathutil"
+)
+
+// Options are the render settings that apply to every model of a run rather
+// than to one item. The zero value renders with the defaults.
+type Options struct {
+	// MinTriangleArea is the minimum projected triangle area, in output px²,
+	// below which triangles are skipped (0 = off). Slivers that small only
+	// shade a line of stray pixels, but thin rods and wires are built from
+	// them too, so keep it well under 1. It is scaled by supersample²
+	// internally.
+	MinTriangleArea float64
+
+	// NoOpaquePromotion stops a model with no opaque mesh from getting its
+	// first additive (or else alpha-blend) mesh drawn opaque instead.
+	// Without promotion such a model renders only its glow and overlay
+	// layers, which is right for items that really are all effect. A TRS
+	// entry's promote field overrides it per item.
+	NoOpaquePromotion bool
+
+	// SSAORadius turns on the screen-space ambient occlusion pass (see
+	// ApplySSAO): the sampling distance in output px, scaled by supersample
+	// internally. 0 turns it off.
+	SSAORadius float64
+	// SSAOIntensity is the darkening of a fully occluded pixel (0..1).
+	SSAOIntensity float64
+
+	// SmoothShading shades opaque meshes with the BMD's vertex normals
+	// interpolated across each triangle instead of one normal per face,
+	// which rounds off the facets of low-poly items.
+	SmoothShading bool
+
+	// Wireframe draws every render's triangle edges over the shaded model
+	// (WireframeOverlay) or alone (WireframeOnly). A model debugging aid: it
+	// shows the topology, and degenerate or overlapping faces the shading
+	// hides.
+	Wireframe int
+	// WireframeColor is the edge color (zero = DefaultWireframeColor).
+	WireframeColor color.NRGBA
+
+	// Convention converts the data set's vertex coordinates into MU BMD
+	// space, from viewmatrix.Convention (nil = mu-default).
+	Convention *mathutil.Mat3
+}
@@ -0,0 +1,68 @@
+package raster
+
+import (
+	"strconv"
+	"strings"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/logging"
+	"mu-bmd-renderer/internal/mathutil"
+	"mu-bmd-renderer/internal/skeleton"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// pivotPoint returns the view-space point the entry's pivot puts at the
+// canvas center, and false for the default (the bounding box center):
+//
+//   - "centroid": the mean of the meshes' vertices, which sits toward the
+//     heavy end of an asymmetric model (a sword's pommel and guard);
+//   - "bone:<name or index>": the bone's bind-pose world position, the
+//     point the model was built around (with bone transforms applied, as
+//     the vertices are).
+//
+// An unknown bone falls back to the bbox center with a warning.
+func pivotPoint(meshes []bmd.Mesh, bones []bmd.Bone, R mathutil.Mat3, entry *trs.Entry) ([3]float64, bool) {
+	if entry == nil || entry.Pivot == "" {
+		return [3]float64{}, false
+	}
+	if entry.Pivot == "centroid" {
+		var sum mathutil.Vec3
+		n := 0
+		for _, m := range meshes {
+			for _, v := range m.Verts {
+				sum = sum.Add(mathutil.Vec3{float64(v[0]), float64(v[1]), float64(v[2])})
+				n++
+			}
+		}
+		if n == 0 {
+			return [3]float64{}, false
+		}
+		return R.MulVec3(sum.Scale(1 / float64(n))), true
+	}
+
+	name := strings.TrimPrefix(entry.Pivot, "bone:")
+	b := findBone(bones, name)
+	if b < 0 {
+		logging.Default().Warnf("pivot: no bone %q, using the bbox center", name)
+		return [3]float64{}, false
+	}
+	worlds := skeleton.BuildWorldMatrices(bones, entry.BoneFlip)
+	return R.MulVec3(worlds[b].MulPoint(mathutil.Vec3{})), true
+}
+
+// findBone returns the index of the non-dummy bone called name (case-
+// insensitive) or numbered name, or -1.
+func findBone(bones []bmd.Bone, name string) int {
+	if i, err := strconv.Atoi(name); err == nil {
+		if i >= 0 && i < len(bones) && !bones[i].IsDummy {
+			return i
+		}
+		return -1
+	}
+	for i, b := range bones {
+		if !b.IsDummy && strings.EqualFold(b.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
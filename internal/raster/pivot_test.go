@@ -0,0 +1,88 @@
+package raster
+
+import (
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/logging"
+	"mu-bmd-renderer/internal/mathutil"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestPivotPoint checks each pivot against a known model: the vertex mean,
+// a bone's bind position by name or index, and the bbox fallback.
+func TestPivotPoint(t *testing.T) {
+	meshes := []bmd.Mesh{{Verts: [][3]float32{{0, 0, 0}, {4, 0, 0}, {4, 8, 0}, {0, 0, 12}}}}
+	bones := []bmd.Bone{
+		{Name: "Root", Parent: -1},
+		{Name: "Dummy", Parent: -1, IsDummy: true},
+		{Name: "Tip", Parent: 0, BindPosition: [3]float64{1, 2, 3}},
+	}
+	I := mathutil.Mat3Identity()
+	for pivot, want := range map[string][3]float64{
+		"centroid": {2, 2, 3},
+		"bone:tip": {1, 2, 3},
+		"bone:2":   {1, 2, 3},
+	} {
+		p, ok := pivotPoint(meshes, bones, I, &trs.Entry{Pivot: pivot})
+		if !ok || math.Abs(p[0]-want[0])+math.Abs(p[1]-want[1])+math.Abs(p[2]-want[2]) > 1e-9 {
+			t.Errorf("%s: %v, %v; want %v", pivot, p, ok, want)
+		}
+	}
+	prev := logging.Default()
+	logging.SetDefault(logging.Discard)
+	defer logging.SetDefault(prev)
+	for _, pivot := range []string{"", "bone:Dummy", "bone:Shaft", "bone:7"} {
+		if _, ok := pivotPoint(meshes, bones, I, &trs.Entry{Pivot: pivot}); ok {
+			t.Errorf("%q: pivot found, want the bbox center", pivot)
+		}
+	}
+}
+
+// TestCentroidPivot renders a blade with a densely cut pommel at one end:
+// centered on the bbox, the pommel sits off to the side; centered on the
+// vertex centroid, which the pommel's vertices pull toward it, it moves to
+// the middle while the whole model stays in frame.
+func TestCentroidPivot(t *testing.T) {
+	tex := solidTextures{"blade.jpg": {150, 150, 150, 255}, "pommel.jpg": {200, 0, 0, 255}}
+	meshes := []bmd.Mesh{
+		box([3]float32{-4, -4, -40}, [3]float32{4, 4, 30}, 3, "blade.jpg"),
+		box([3]float32{-8, -8, 30}, [3]float32{8, 8, 40}, 40, "pommel.jpg"),
+	}
+	const size = 128
+	pommelOffset := func(pivot string) float64 {
+		entry := testEntry()
+		entry.Pivot = pivot
+		img := RenderBMD(meshes, nil, entry, tex, size, size, 1, Options{})
+		var sx, sy, n float64
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				i := img.PixOffset(x, y)
+				if img.Pix[i+3] == 0 {
+					continue
+				}
+				if x == 0 || y == 0 || x == size-1 || y == size-1 {
+					t.Errorf("%q: model touches the edge at %d,%d", pivot, x, y)
+					return 0
+				}
+				if int(img.Pix[i]) > 2*int(img.Pix[i+1])+20 {
+					sx, sy, n = sx+float64(x), sy+float64(y), n+1
+				}
+			}
+		}
+		if n == 0 {
+			t.Fatalf("%q: pommel not drawn", pivot)
+		}
+		c := float64(size-1) / 2
+		return math.Hypot(sx/n-c, sy/n-c)
+	}
+
+	bbox, centroid := pommelOffset(""), pommelOffset("centroid")
+	if bbox < 20 {
+		t.Errorf("bbox pivot: pommel %.1f px from the center, want it well off", bbox)
+	}
+	if centroid > bbox/2 {
+		t.Errorf("centroid pivot: pommel %.1f px from the center, bbox pivot %.1f: want it much nearer", centroid, bbox)
+	}
+}
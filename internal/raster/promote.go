@@ -0,0 +1,12 @@
+package raster
+
+import "mu-bmd-renderer/internal/trs"
+
+// promotionEnabled reports whether promotion applies to entry's item: its
+// promote field if set, else opts (see Options.NoOpaquePromotion).
+func promotionEnabled(entry *trs.Entry, opts Options) bool {
+	if entry != nil && entry.Promote != nil {
+		return *entry.Promote
+	}
+	return !opts.NoOpaquePromotion
+}
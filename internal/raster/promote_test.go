@@ -0,0 +1,46 @@
+package raster
+
+import (
+	"bytes"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestOpaquePromotion renders a model whose only meshes are additive (_R
+// textures): by default the first is promoted to opaque; with promotion off,
+// by option or by the entry's promote field, it stays additive and the
+// render differs. The entry's field wins over the option.
+func TestOpaquePromotion(t *testing.T) {
+	tex := solidTextures{"glow_r.jpg": {60, 200, 255, 255}}
+	meshes := []bmd.Mesh{
+		box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "glow_r.jpg"),
+		box([3]float32{-4, -14, -20}, [3]float32{4, 14, 20}, 6, "glow_r.jpg"),
+	}
+	render := func(promote *bool, opts Options) ([]byte, bool) {
+		e := testEntry()
+		e.Promote = promote
+		img, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, opts)
+		return img.Pix, stats.Promoted
+	}
+	yes, no := true, false
+
+	on, promoted := render(nil, Options{})
+	if !promoted {
+		t.Fatal("default: no mesh promoted")
+	}
+	off, promoted := render(nil, Options{NoOpaquePromotion: true})
+	if promoted {
+		t.Error("no_opaque_promotion: mesh promoted")
+	}
+	if bytes.Equal(on, off) {
+		t.Error("render with promotion off matches the promoted one")
+	}
+
+	if pix, promoted := render(&no, Options{}); promoted || !bytes.Equal(pix, off) {
+		t.Errorf("promote false: promoted %v, want the unpromoted render", promoted)
+	}
+	if pix, promoted := render(&yes, Options{NoOpaquePromotion: true}); !promoted || !bytes.Equal(pix, on) {
+		t.Errorf("promote true over no_opaque_promotion: promoted %v, want the promoted render", promoted)
+	}
+}
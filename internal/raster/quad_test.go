@@ -0,0 +1,98 @@
+package raster
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestQuadNormal checks the Newell normal of a planar quad against its cross
+// product, and that degenerate quads and bad indices are rejected.
+func TestQuadNormal(t *testing.T) {
+	px := []float64{0, 2, 2, 0, 5}
+	py := []float64{0, 0, 2, 2, 5}
+	pz := []float64{1, 1, 1, 1, 5}
+	n, ok := quadNormal(px, py, pz, [4]int16{0, 1, 2, 3})
+	if !ok || math.Abs(n[0]) > 1e-9 || math.Abs(n[1]) > 1e-9 || math.Abs(n[2]-1) > 1e-9 {
+		t.Errorf("planar quad: %v, %v; want (0,0,1)", n, ok)
+	}
+	if n, ok := quadNormal(px, py, pz, [4]int16{3, 2, 1, 0}); !ok || math.Abs(n[2]+1) > 1e-9 {
+		t.Errorf("reversed quad: %v, %v; want (0,0,-1)", n, ok)
+	}
+	if _, ok := quadNormal(px, py, pz, [4]int16{0, 0, 0, 0}); ok {
+		t.Error("degenerate quad accepted")
+	}
+	if _, ok := quadNormal(px, py, pz, [4]int16{0, 1, 2, 9}); ok {
+		t.Error("out-of-range index accepted")
+	}
+}
+
+// TestQuadSharesShade renders one strongly non-planar quad facing the
+// camera: both halves must get the same flat shade, so every fully covered
+// pixel has one color.
+func TestQuadSharesShade(t *testing.T) {
+	tex := solidTextures{"quad.tga": {200, 200, 200, 255}}
+	m := bmd.Mesh{
+		TexPath: "quad.tga",
+		Verts:   [][3]float32{{-40, 0, -40}, {40, 0, -40}, {40, 0, 40}, {-40, -30, 40}},
+		Normals: [][3]float32{{0, -1, 0}, {0, -1, 0}, {0, -1, 0}, {0, -1, 0}},
+		Nodes:   []int16{0, 0, 0, 0},
+		UVs:     [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		Tris:    []bmd.Triangle{{Polygon: 4, VI: [4]int16{0, 1, 2, 3}, NI: [4]int16{0, 1, 2, 3}, TI: [4]int16{0, 1, 2, 3}}},
+	}
+	img := RenderBMD([]bmd.Mesh{m}, nil, testEntry(), tex, 64, 64, 1, Options{})
+
+	colors := map[color.NRGBA]int{}
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i+3] == 255 {
+			colors[color.NRGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 255}]++
+		}
+	}
+	if len(colors) == 0 {
+		t.Fatal("quad not rendered")
+	}
+	if len(colors) > 1 {
+		t.Errorf("quad halves shaded differently: %v", colors)
+	}
+}
+
+// TestQuadSplit draws a planar quad face and the same quad as two triangle
+// faces split along its 0-2 diagonal: the renders must match, and both
+// halves of quadHalves keep the quad's winding.
+func TestQuadSplit(t *testing.T) {
+	tex := solidTextures{"quad.tga": {200, 120, 60, 255}}
+	quad := bmd.Mesh{
+		TexPath: "quad.tga",
+		Verts:   [][3]float32{{-40, 0, -30}, {35, 0, -40}, {40, 0, 30}, {-30, 0, 40}},
+		Normals: [][3]float32{{0, -1, 0}, {0, -1, 0}, {0, -1, 0}, {0, -1, 0}},
+		Nodes:   []int16{0, 0, 0, 0},
+		UVs:     [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		Tris:    []bmd.Triangle{{Polygon: 4, VI: [4]int16{0, 1, 2, 3}, NI: [4]int16{0, 1, 2, 3}, TI: [4]int16{0, 1, 2, 3}}},
+	}
+	split := quad
+	split.Tris = nil
+	for _, h := range quadHalves {
+		c := [4]int16{int16(h[0]), int16(h[1]), int16(h[2])}
+		split.Tris = append(split.Tris, bmd.Triangle{Polygon: 3, VI: c, NI: c, TI: c})
+	}
+	want := RenderBMD([]bmd.Mesh{quad}, nil, testEntry(), tex, 64, 64, 1, Options{})
+	got := RenderBMD([]bmd.Mesh{split}, nil, testEntry(), tex, 64, 64, 1, Options{})
+	if coverage(want) == 0 {
+		t.Fatal("quad not rendered")
+	}
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Error("quad face and its two triangles render differently")
+	}
+
+	// Signed area of each half in the quad's own (x, z) plane
+	area := func(h [3]int) float32 {
+		a, b, c := quad.Verts[h[0]], quad.Verts[h[1]], quad.Verts[h[2]]
+		return (b[0]-a[0])*(c[2]-a[2]) - (c[0]-a[0])*(b[2]-a[2])
+	}
+	if a0, a1 := area(quadHalves[0]), area(quadHalves[1]); a0*a1 <= 0 {
+		t.Errorf("quad halves wound opposite ways: signed areas %g and %g", a0, a1)
+	}
+}
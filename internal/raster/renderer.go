@@ -2,6 +2,7 @@ package raster
 
 import (
 	"image"
+	"image/color"
 	"math"
 	"path/filepath"
 	"strings"
@@ -23,38 +24,103 @@ func RenderBMD(
 	texResolver texture.Resolver,
 	width, height int,
 	supersample int,
+	opts Options,
 ) *image.NRGBA {
+	return renderBMD(meshes, bones, entry, texResolver, width, height, supersample, opts, nil)
+}
+
+func renderBMD(
+	meshes []bmd.Mesh,
+	bones []bmd.Bone,
+	entry *trs.Entry,
+	texResolver texture.Resolver,
+	width, height int,
+	supersample int,
+	opts Options,
+	stats *RenderStats,
+) *image.NRGBA {
+	// Hide meshes by BMD index first, so indices match the file's mesh order.
+	// Recorded per mesh: hidden meshes often share a texture with kept ones.
+	if entry != nil && len(entry.HideMeshIndices) > 0 {
+		hide := make(map[int]bool, len(entry.HideMeshIndices))
+		for _, i := range entry.HideMeshIndices {
+			hide[i] = true
+		}
+		var kept []int
+		for i := range meshes {
+			if !hide[i] {
+				kept = append(kept, i)
+			}
+		}
+		if len(kept) > 0 {
+			stats.filtered(meshes, kept, "hide_meshes")
+			meshes = pickMeshes(meshes, kept)
+		}
+	}
+
 	// Pre-filter effect meshes and body meshes on raw geometry (before bone transforms distort shapes)
 	// Always apply exclude_textures filter, even with keep_all_meshes.
 	if entry != nil && len(entry.ExcludeTextures) > 0 {
-		var kept []bmd.Mesh
+		var kept []int
 		for i := range meshes {
 			if !isExcludedTexture(meshes[i].TexPath, entry) {
-				kept = append(kept, meshes[i])
+				kept = append(kept, i)
+			}
+		}
+		if len(kept) > 0 {
+			stats.filtered(meshes, kept, "exclude_textures")
+			meshes = pickMeshes(meshes, kept)
+		}
+	}
+
+	// Body parts bound to the entry's body bones. Explicit like
+	// exclude_textures, so applied even with keep_all_meshes; recorded per
+	// mesh since body parts here share textures with the equipment.
+	if entry != nil && len(entry.BodyBones) > 0 {
+		var kept []int
+		for i := range meshes {
+			if !filter.IsBoundToBones(&meshes[i], entry.BodyBones) {
+				kept = append(kept, i)
 			}
 		}
 		if len(kept) > 0 {
-			meshes = kept
+			stats.filtered(meshes, kept, "body_bones")
+			meshes = pickMeshes(meshes, kept)
 		}
 	}
 
 	keepAll := entry != nil && entry.KeepAllMeshes
+	// render_effects_as_glow keeps effect meshes (they go to the additive
+	// pass below, so black vanishes) but still drops body and jpg-under-tga
+	glowEffects := entry != nil && entry.EffectsAsGlow
 	if !keepAll {
-		var nonEffect []bmd.Mesh
+		// Each mesh is dropped for the first reason that applies; reasons
+		// are recorded one at a time against the meshes each keeps
+		reasons := make([]string, len(meshes))
+		var kept []int
 		for i := range meshes {
-			if filter.IsEffectMesh(&meshes[i]) && !isForceAdditive(meshes[i].TexPath, entry) {
-				continue
-			}
-			if filter.IsBodyMesh(&meshes[i]) {
-				continue
+			switch {
+			case !glowEffects && filter.IsEffectMesh(&meshes[i]) && !isForceAdditive(meshes[i].TexPath, entry) && blendOverride(meshes[i].TexPath, entry) == "":
+				reasons[i] = "effect"
+			case filter.IsBodyMesh(&meshes[i]):
+				reasons[i] = "body"
+			case filter.IsJPGUnderTGA(meshes, i):
+				reasons[i] = "jpg-under-tga"
+			default:
+				kept = append(kept, i)
 			}
-			if filter.IsJPGUnderTGA(meshes, i) {
-				continue
-			}
-			nonEffect = append(nonEffect, meshes[i])
 		}
-		if len(nonEffect) > 0 {
-			meshes = nonEffect
+		if len(kept) > 0 {
+			for _, reason := range []string{"effect", "body", "jpg-under-tga"} {
+				var notThis []int
+				for i, r := range reasons {
+					if r != reason {
+						notThis = append(notThis, i)
+					}
+				}
+				stats.filtered(meshes, notThis, reason)
+			}
+			meshes = pickMeshes(meshes, kept)
 		}
 	}
 
@@ -63,18 +129,25 @@ func RenderBMD(
 	// bright JPEG glow layers. The game composites these with special blending;
 	// without it, their colored backgrounds create visible auras.
 	if !keepAll && texResolver != nil && len(meshes) > 1 {
-		meshes = filterGlowLayers(meshes, texResolver)
+		kept := filterGlowLayers(meshes, texResolver)
+		stats.filtered(meshes, kept, "glow-layer")
+		meshes = pickMeshes(meshes, kept)
 	}
 
 	// Bone transforms
 	useBones := viewmatrix.ShouldUseBones(entry)
 	if useBones {
 		boneFlip := entry != nil && entry.BoneFlip
-		skeleton.ApplyTransforms(meshes, bones, boneFlip)
+		skeleton.ApplyTransforms(meshes, bones, boneFlip, 0, 0)
+	}
+
+	// Compute view matrix + filter components (within each mesh; every
+	// mesh is kept)
+	R, bodyMeshes := viewmatrix.ComputeViewMatrix(meshes, entry, opts.Convention)
+	if entry != nil && entry.LayFlat {
+		R = viewmatrix.LayFlat(bodyMeshes, R)
 	}
 
-	// Compute view matrix + filter components
-	R, bodyMeshes := viewmatrix.ComputeViewMatrix(meshes, entry)
 	if len(bodyMeshes) == 0 {
 		return image.NewNRGBA(image.Rect(0, 0, width, height))
 	}
@@ -107,6 +180,13 @@ func RenderBMD(
 	}
 	spanX := allMax[0] - allMin[0]
 	spanY := allMax[1] - allMin[1]
+	// Pivot: center on that point instead, framing the farther side of the
+	// bbox on each axis so the whole model still fits
+	if p, ok := pivotPoint(bodyMeshes, bones, R, entry); ok {
+		center = p
+		spanX = 2 * math.Max(allMax[0]-p[0], p[0]-allMin[0])
+		spanY = 2 * math.Max(allMax[1]-p[1], p[1]-allMin[1])
+	}
 	if spanX < 0.001 {
 		spanX = 0.001
 	}
@@ -129,25 +209,54 @@ func RenderBMD(
 	if scaleY < scale {
 		scale = scaleY
 	}
+	// absolute_scale: TRS scale is taken literally (fraction of the canvas per
+	// model unit) so items keep their relative sizes instead of filling the frame
+	if entry != nil && entry.AbsoluteScale && entry.Scale > 0 {
+		scale = entry.Scale * float64(minDim)
+	}
 
 	// Positioned camera: when cam_height is set, use parallax perspective
 	// that makes depth-axis geometry visible (e.g. fabric hanging down).
 	var posCamera *viewmatrix.PosCamera
-	if entry != nil && entry.CamHeight != 0 {
+	if viewmatrix.UsesPosCamera(entry) {
 		posCamera = viewmatrix.SetupPosCamera(bodyMeshes, R, entry, renderW, renderH, margin)
 	}
+	if stats != nil {
+		stats.UseBones = useBones
+		stats.PosCamera = posCamera != nil
+		stats.View = R
+		stats.Scale = scale
+		stats.Span = [2]float64{spanX, spanY}
+	}
 
 	// Allocate framebuffer
 	fb := NewFrameBuffer(renderW, renderH)
 	lc := DefaultLightConfig()
+	if entry != nil {
+		lc.apply(entry.Lighting)
+	}
 	if entry != nil && entry.AdditiveFloor > 0 {
 		lc.AdditiveDarkFloor = float64(entry.AdditiveFloor)
 	}
+	lc.MinTriArea = opts.MinTriangleArea * float64(supersample*supersample)
+	lc.Smooth = opts.SmoothShading
 
 	// Split meshes into opaque, alpha-blend, additive, overlay-additive, and force-additive (unlit)
 	var opaqueMeshes, alphaBlendMeshes, additiveMeshes, overlayAdditiveMeshes, forceAdditiveMeshes []bmd.Mesh
 	for i, mesh := range bodyMeshes {
-		// Check per-item additive_textures override first
+		// Per-item blend_overrides win over everything below
+		switch blendOverride(mesh.TexPath, entry) {
+		case "opaque":
+			opaqueMeshes = append(opaqueMeshes, mesh)
+			continue
+		case "alpha":
+			alphaBlendMeshes = append(alphaBlendMeshes, mesh)
+			continue
+		case "additive":
+			additiveMeshes = append(additiveMeshes, mesh)
+			continue
+		}
+		// Then the per-item additive_textures override
 		if isForceAdditive(mesh.TexPath, entry) {
 			if entry != nil && entry.AdditiveOnTop {
 				// additive_on_top: additive only on existing geometry (no dark aura on background)
@@ -157,9 +266,10 @@ func RenderBMD(
 			}
 			continue
 		}
-		// Effect meshes kept by keep_all_meshes → additive blending.
-		// These are glow/energy textures that the game renders additively.
-		if keepAll && filter.IsEffectMesh(&mesh) {
+		// Effect meshes kept by keep_all_meshes or render_effects_as_glow →
+		// additive blending. These are glow/energy textures that the game
+		// renders additively.
+		if (keepAll || glowEffects) && filter.IsEffectMesh(&mesh) {
 			additiveMeshes = append(additiveMeshes, mesh)
 			continue
 		}
@@ -181,7 +291,7 @@ func RenderBMD(
 
 	// Safety: if no opaque mesh exists, promote from additive/alpha to avoid
 	// rendering everything with luminance-based alpha onto an empty canvas.
-	if len(opaqueMeshes) == 0 && (len(additiveMeshes) > 0 || len(alphaBlendMeshes) > 0) {
+	if len(opaqueMeshes) == 0 && (len(additiveMeshes) > 0 || len(alphaBlendMeshes) > 0) && promotionEnabled(entry, opts) {
 		if len(additiveMeshes) > 0 {
 			opaqueMeshes = append(opaqueMeshes, additiveMeshes[0])
 			additiveMeshes = additiveMeshes[1:]
@@ -189,6 +299,9 @@ func RenderBMD(
 			opaqueMeshes = append(opaqueMeshes, alphaBlendMeshes[0])
 			alphaBlendMeshes = alphaBlendMeshes[1:]
 		}
+		if stats != nil {
+			stats.Promoted = true
+		}
 	}
 
 	// Pass 1: Opaque meshes (normal z-buffer rendering)
@@ -210,6 +323,7 @@ func RenderBMD(
 		meshBounds[i] = bb
 	}
 
+	decoration := make([]bool, len(opaqueMeshes))
 	for i, mesh := range opaqueMeshes {
 		// Check if this mesh is contained within any earlier mesh's screen bbox.
 		// Only apply z-bias when the contained mesh is a TGA (surface decoration
@@ -230,6 +344,29 @@ func RenderBMD(
 				}
 			}
 		}
+		decoration[i] = contained
+	}
+
+	// Optional merge, once every mesh has its pass: only meshes drawn the
+	// same way (pass, decoration or not, texture and material slot) are
+	// combined, so merging never changes how a triangle is blended.
+	if entry != nil && entry.MergeMeshes {
+		opaqueMeshes, decoration = mergeOpaque(opaqueMeshes, decoration)
+		alphaBlendMeshes = bmd.MergeMeshesByTexture(alphaBlendMeshes)
+		additiveMeshes = bmd.MergeMeshesByTexture(additiveMeshes)
+		overlayAdditiveMeshes = bmd.MergeMeshesByTexture(overlayAdditiveMeshes)
+		forceAdditiveMeshes = bmd.MergeMeshesByTexture(forceAdditiveMeshes)
+	}
+
+	for i, mesh := range opaqueMeshes {
+		contained := decoration[i]
+		if stats != nil {
+			pass := "opaque"
+			if contained {
+				pass = "decoration"
+			}
+			stats.Rendered = append(stats.Rendered, meshStat(&mesh, "", pass))
+		}
 		if contained {
 			// Surface decoration: render as opaque but skip z-test by using
 			// a large z-bias that guarantees it passes the depth test.
@@ -239,6 +376,16 @@ func RenderBMD(
 		}
 	}
 
+	// Crease darkening from the opaque depth, before blended passes add glow
+	if opts.SSAORadius > 0 {
+		ApplySSAO(fb, opts.SSAORadius*float64(supersample), opts.SSAOIntensity)
+	}
+
+	stats.rendered(alphaBlendMeshes, "alpha")
+	stats.rendered(additiveMeshes, "additive")
+	stats.rendered(overlayAdditiveMeshes, "overlay-additive")
+	stats.rendered(forceAdditiveMeshes, "force-additive")
+
 	// Pass 2: Alpha-blend meshes (z-read but no z-write, alpha composite)
 	for _, mesh := range alphaBlendMeshes {
 		rasterizeMesh(fb, &mesh, R, center, scale, renderW, renderH, entry, texResolver, &lc, blendAlpha, posCamera)
@@ -297,6 +444,23 @@ func RenderBMD(
 		compositeUnder(fb, bgFB)
 	}
 
+	// Debug wireframe over (or instead of) the shaded passes
+	if opts.Wireframe != WireframeOff {
+		if opts.Wireframe == WireframeOnly {
+			clear(fb.Color)
+		}
+		c := opts.WireframeColor
+		if c == (color.NRGBA{}) {
+			c = DefaultWireframeColor
+		}
+		for _, pass := range [][]bmd.Mesh{opaqueMeshes, alphaBlendMeshes, additiveMeshes, overlayAdditiveMeshes, forceAdditiveMeshes} {
+			for i := range pass {
+				px, py, _ := viewmatrix.ProjectVertices(pass[i].Verts, R, center, scale, renderW, renderH, entry, posCamera)
+				DrawWireframe(fb, px, py, pass[i].Tris, c, supersample)
+			}
+		}
+	}
+
 	// Convert framebuffer to image
 	img := image.NewNRGBA(image.Rect(0, 0, renderW, renderH))
 	copy(img.Pix, fb.Color)
@@ -304,6 +468,28 @@ func RenderBMD(
 	return img
 }
 
+// mergeOpaque merges the opaque pass's body meshes and its decorations
+// (decoration[i]) separately, bodies first. Decorations draw with a z-bias
+// that wins every depth test, so drawing them after the bodies doesn't
+// change the image.
+func mergeOpaque(meshes []bmd.Mesh, decoration []bool) ([]bmd.Mesh, []bool) {
+	var bodies, decorations []bmd.Mesh
+	for i := range meshes {
+		if decoration[i] {
+			decorations = append(decorations, meshes[i])
+		} else {
+			bodies = append(bodies, meshes[i])
+		}
+	}
+	bodies = bmd.MergeMeshesByTexture(bodies)
+	decorations = bmd.MergeMeshesByTexture(decorations)
+	flags := make([]bool, len(bodies)+len(decorations))
+	for i := len(bodies); i < len(flags); i++ {
+		flags[i] = true
+	}
+	return append(bodies, decorations...), flags
+}
+
 // isAdditiveTexture returns true if a texture name ends with _R (MU Online convention
 // for additive glow/liquid overlays, e.g. "secret_R.jpg", "songko2_R.jpg").
 func isAdditiveTexture(texPath string) bool {
@@ -639,7 +825,7 @@ func rasterizeMeshWithZBias(
 	for i := range pz {
 		pz[i] += zBias
 	}
-	rasterizeMeshInner(fb, mesh, px, py, pz, entry, texResolver, lc, blendMode)
+	rasterizeMeshInner(fb, mesh, px, py, pz, meshScreenNormals(mesh, R, scale, lc, blendMode), entry, texResolver, lc, blendMode)
 }
 
 func rasterizeMesh(
@@ -652,12 +838,23 @@ func rasterizeMesh(
 		return
 	}
 	px, py, pz := viewmatrix.ProjectVertices(mesh.Verts, R, center, scale, renderW, renderH, entry, posCamera)
-	rasterizeMeshInner(fb, mesh, px, py, pz, entry, texResolver, lc, blendMode)
+	rasterizeMeshInner(fb, mesh, px, py, pz, meshScreenNormals(mesh, R, scale, lc, blendMode), entry, texResolver, lc, blendMode)
 }
 
+// meshScreenNormals returns mesh's screen normals when lc shades blendMode
+// smoothly (opaque meshes only), or nil for flat shading.
+func meshScreenNormals(mesh *bmd.Mesh, R mathutil.Mat3, scale float64, lc *LightConfig, blendMode int) []mathutil.Vec3 {
+	if !lc.Smooth || blendMode != blendOpaque {
+		return nil
+	}
+	return screenNormals(mesh, R, scale)
+}
+
+// rasterizeMeshInner draws mesh's projected triangles; vn, when non-nil, are
+// its screen normals for smooth shading (see screenNormals).
 func rasterizeMeshInner(
 	fb *FrameBuffer, mesh *bmd.Mesh,
-	px, py, pz []float64,
+	px, py, pz []float64, vn []mathutil.Vec3,
 	entry *trs.Entry, texResolver texture.Resolver, lc *LightConfig,
 	blendMode int,
 ) {
@@ -672,21 +869,25 @@ func rasterizeMeshInner(
 			tex = applyTint(tex, entry.Tint)
 		}
 	}
+	if entry != nil && tex != nil && (entry.TexBrightness != 0 || entry.TexContrast != 0 || entry.TexGamma != 0) {
+		tex = adjustedTexture(tex, entry.TexBrightness, entry.TexContrast, entry.TexGamma)
+	}
 
 	var defR, defG, defB, defA uint8 = 160, 160, 170, 255
 	if tex != nil {
 		defR, defG, defB, defA = averageColor(tex)
 	}
 
-	type rasterFunc func(*FrameBuffer, []float64, []float64, []float64, [][2]float32, [3]int, [3]int, *image.NRGBA, uint8, uint8, uint8, uint8, *LightConfig)
+	type rasterFunc func(*FrameBuffer, []float64, []float64, []float64, [][2]float32, [3]int, [3]int, *image.NRGBA, uint8, uint8, uint8, uint8, *LightConfig, *mathutil.Vec3)
 	var rasterFn rasterFunc
 	// For unlit mode, override LightConfig to neutral (texture colors only).
 	if blendMode == blendOpaqueUnlit {
 		unlitLC := LightConfig{
-			Ambient:   1.0,
-			Exposure:  1.0,
-			SRGBGamma: lc.SRGBGamma,
-			InvGamma:  lc.InvGamma,
+			Ambient:    1.0,
+			Exposure:   1.0,
+			SRGBGamma:  lc.SRGBGamma,
+			InvGamma:   lc.InvGamma,
+			MinTriArea: lc.MinTriArea,
 		}
 		lc = &unlitLC
 	}
@@ -702,40 +903,106 @@ func rasterizeMeshInner(
 	}
 
 	for _, tri := range mesh.Tris {
-		vi := [3]int{int(tri.VI[0]), int(tri.VI[1]), int(tri.VI[2])}
-		ti := [3]int{int(tri.TI[0]), int(tri.TI[1]), int(tri.TI[2])}
-		rasterFn(fb, px, py, pz, mesh.UVs, vi, ti, tex, defR, defG, defB, defA, lc)
+		// Quads shade both halves with one normal so a slightly non-planar
+		// quad doesn't show a crease along its diagonal
+		var faceN *mathutil.Vec3
+		if tri.Polygon == 4 {
+			if n, ok := quadNormal(px, py, pz, tri.VI); ok {
+				faceN = &n
+			}
+		}
 
+		halves := 1
 		if tri.Polygon == 4 {
-			vi2 := [3]int{int(tri.VI[0]), int(tri.VI[2]), int(tri.VI[3])}
-			ti2 := [3]int{int(tri.TI[0]), int(tri.TI[2]), int(tri.TI[3])}
-			rasterFn(fb, px, py, pz, mesh.UVs, vi2, ti2, tex, defR, defG, defB, defA, lc)
+			halves = 2
+		}
+		for _, c := range quadHalves[:halves] {
+			vi := [3]int{int(tri.VI[c[0]]), int(tri.VI[c[1]]), int(tri.VI[c[2]])}
+			ti := [3]int{int(tri.TI[c[0]]), int(tri.TI[c[1]]), int(tri.TI[c[2]])}
+			if belowMinArea(px, py, vi, lc.MinTriArea) {
+				continue
+			}
+			if vn != nil {
+				if cn, ok := cornerNormals(vn, tri, c); ok {
+					rasterizeTriangle(fb, px, py, pz, mesh.UVs, vi, ti, tex, defR, defG, defB, defA, lc, faceN, &cn)
+					continue
+				}
+			}
+			rasterFn(fb, px, py, pz, mesh.UVs, vi, ti, tex, defR, defG, defB, defA, lc, faceN)
 		}
 	}
 }
 
-// filterGlowLayers removes glow layer meshes from the body mesh list.
-// Detects two patterns:
+// quadHalves are the corners of the triangles a face is drawn as: a
+// triangle is the first alone, a quad both, sharing its 0-2 diagonal.
+var quadHalves = [2][3]int{{0, 1, 2}, {0, 2, 3}}
+
+// quadNormal returns the unit normal of projected quad vi[0..3] (Newell's
+// method: the area-weighted average of its two triangles' normals, with the
+// same winding the rasterizers use). ok is false for an out-of-range index or
+// a degenerate quad.
+func quadNormal(px, py, pz []float64, vi [4]int16) (mathutil.Vec3, bool) {
+	var n mathutil.Vec3
+	for k := 0; k < 4; k++ {
+		a, b := int(vi[k]), int(vi[(k+1)%4])
+		if a < 0 || a >= len(px) || b < 0 || b >= len(px) {
+			return n, false
+		}
+		n[0] += (py[a] - py[b]) * (pz[a] + pz[b])
+		n[1] += (pz[a] - pz[b]) * (px[a] + px[b])
+		n[2] += (px[a] - px[b]) * (py[a] + py[b])
+	}
+	if n.Len() < 1e-8 {
+		return n, false
+	}
+	return n.Normalize(), true
+}
+
+// belowMinArea reports whether the projected triangle vi covers less than
+// minArea px². Out-of-range indices are left for the rasterizer to reject.
+func belowMinArea(px, py []float64, vi [3]int, minArea float64) bool {
+	if minArea <= 0 {
+		return false
+	}
+	for _, i := range vi {
+		if i < 0 || i >= len(px) {
+			return false
+		}
+	}
+	x0, y0 := px[vi[0]], py[vi[0]]
+	area := math.Abs((px[vi[1]]-x0)*(py[vi[2]]-y0)-(px[vi[2]]-x0)*(py[vi[1]]-y0)) / 2
+	return area < minArea
+}
+
+// filterGlowLayers returns the indices of the meshes that aren't glow
+// layers, in order. Detects two patterns:
 // 1. Geometry pairs: meshes with same (verts, tris) count where one uses JPEG
 //    and another uses TGA — the game composites these with special blending.
 // 2. Standalone bright JPEGs: very bright, low-saturation JPEG textures that
 //    are shimmer/glow overlays.
-func filterGlowLayers(meshes []bmd.Mesh, texResolver texture.Resolver) []bmd.Mesh {
+func filterGlowLayers(meshes []bmd.Mesh, texResolver texture.Resolver) []int {
 	type meshKey struct {
 		verts, tris int
 	}
 
-	// Group by geometry (vertex count, triangle count)
+	// Group by geometry (vertex count, triangle count). Groups are visited in
+	// order of their first mesh, never in map order, so every decision below
+	// is reproducible even if a pattern starts depending on earlier removals.
 	groups := make(map[meshKey][]int)
+	var groupOrder []meshKey
 	for i := range meshes {
 		k := meshKey{len(meshes[i].Verts), len(meshes[i].Tris)}
+		if _, seen := groups[k]; !seen {
+			groupOrder = append(groupOrder, k)
+		}
 		groups[k] = append(groups[k], i)
 	}
 
 	remove := make(map[int]bool)
 
 	// Pattern 1: JPEG+TGA pairs with same geometry
-	for _, indices := range groups {
+	for _, k := range groupOrder {
+		indices := groups[k]
 		if len(indices) < 2 {
 			continue
 		}
@@ -805,20 +1072,26 @@ func filterGlowLayers(meshes []bmd.Mesh, texResolver texture.Resolver) []bmd.Mes
 		}
 	}
 
-	if len(remove) == 0 {
-		return meshes
-	}
-
-	var result []bmd.Mesh
+	var kept, all []int
 	for i := range meshes {
+		all = append(all, i)
 		if !remove[i] {
-			result = append(result, meshes[i])
+			kept = append(kept, i)
 		}
 	}
-	if len(result) == 0 {
-		return meshes // don't filter everything
+	if len(kept) == 0 {
+		return all // don't filter everything
 	}
-	return result
+	return kept
+}
+
+// pickMeshes returns meshes[i] for each i in indices.
+func pickMeshes(meshes []bmd.Mesh, indices []int) []bmd.Mesh {
+	out := make([]bmd.Mesh, len(indices))
+	for k, i := range indices {
+		out[k] = meshes[i]
+	}
+	return out
 }
 
 // isBrightGlowJPEG returns true if a mesh uses a very bright, desaturated
@@ -1148,6 +1421,16 @@ func isForceAdditive(texPath string, entry *trs.Entry) bool {
 	return false
 }
 
+// blendOverride returns the per-item blend_overrides mode ("opaque",
+// "alpha", or "additive") for the mesh's texture stem, or "" if none is set.
+func blendOverride(texPath string, entry *trs.Entry) string {
+	if entry == nil || len(entry.BlendOverrides) == 0 {
+		return ""
+	}
+	base := filepath.Base(strings.ReplaceAll(texPath, "\\", "/"))
+	return entry.BlendOverrides[strings.ToLower(strings.TrimSuffix(base, filepath.Ext(base)))]
+}
+
 // shouldTintMesh returns true if this mesh's texture should receive the tint.
 // If TintTextures is empty, all meshes are tinted. Otherwise, only meshes
 // whose texture stem (case-insensitive) matches one of the listed stems.
@@ -1194,6 +1477,42 @@ func applyTint(src *image.NRGBA, tint [3]float64) *image.NRGBA {
 	return dst
 }
 
+// applyTexAdjust creates a copy of a texture with gamma, then contrast around
+// mid-gray, then brightness applied to RGB. A zero parameter is left at 1
+// (unchanged). Only the RGB channels change; alpha is copied.
+func applyTexAdjust(src *image.NRGBA, brightness, contrast, gamma float64) *image.NRGBA {
+	if brightness == 0 {
+		brightness = 1
+	}
+	if contrast == 0 {
+		contrast = 1
+	}
+	if gamma <= 0 {
+		gamma = 1
+	}
+
+	var lut [256]uint8
+	for v := range lut {
+		f := math.Pow(float64(v)/255, 1/gamma)
+		f = ((f-0.5)*contrast + 0.5) * brightness
+		lut[v] = uint8(math.Round(255 * math.Max(0, math.Min(1, f))))
+	}
+
+	b := src.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := src.PixOffset(x, y)
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = lut[src.Pix[i]]
+			dst.Pix[j+1] = lut[src.Pix[i+1]]
+			dst.Pix[j+2] = lut[src.Pix[i+2]]
+			dst.Pix[j+3] = src.Pix[i+3]
+		}
+	}
+	return dst
+}
+
 func averageColor(tex *image.NRGBA) (uint8, uint8, uint8, uint8) {
 	b := tex.Bounds()
 	w, h := b.Dx(), b.Dy()
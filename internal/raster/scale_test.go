@@ -0,0 +1,55 @@
+package raster
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// contentBounds returns the bounds of img's pixels with any alpha.
+func contentBounds(img *image.NRGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] > 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+// TestAbsoluteScale renders boxes of two sizes at two absolute scales: the
+// drawn size must follow model size × scale, where auto-framing would draw
+// every one of them the same size.
+func TestAbsoluteScale(t *testing.T) {
+	const size = 200
+	tex := solidTextures{"box.jpg": {150, 150, 150, 255}}
+	span := func(half float32, scale float64) float64 {
+		e := testEntry()
+		e.AbsoluteScale, e.Scale = true, scale
+		m := []bmd.Mesh{box([3]float32{-half, -half, -half}, [3]float32{half, half, half}, 4, "box.jpg")}
+		r := contentBounds(RenderBMD(m, nil, e, tex, size, size, 1, Options{}))
+		return float64(max(r.Dx(), r.Dy()))
+	}
+	base := span(10, 0.01)
+	if base < 10 || base > size/2 {
+		t.Fatalf("20-unit box at scale 0.01: %.0f px, want a small part of the %d px canvas", base, size)
+	}
+	for _, c := range []struct {
+		half  float32
+		scale float64
+		ratio float64
+	}{
+		{20, 0.01, 2}, // twice the model
+		{10, 0.02, 2}, // twice the scale
+		{20, 0.005, 1},
+	} {
+		if got := span(c.half, c.scale) / base; math.Abs(got-c.ratio) > 0.1 {
+			t.Errorf("%.0f-unit box at scale %g: %.2f× the base size, want %.0f×", 2*c.half, c.scale, got, c.ratio)
+		}
+	}
+}
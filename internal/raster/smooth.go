@@ -0,0 +1,39 @@
+package raster
+
+import (
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/mathutil"
+)
+
+// screenNormals returns mesh's normals, for smooth shading, in the space the
+// rasterizers take face normals from: viewed by R, y flipped and x, y
+// stretched by scale against depth as ProjectVertices places vertices, so a
+// flat face's normals shade exactly like the face itself. Normals are
+// direction-only, so perspective (and the positioned camera) is left out:
+// those normals are approximate away from the canvas center.
+func screenNormals(mesh *bmd.Mesh, R mathutil.Mat3, scale float64) []mathutil.Vec3 {
+	out := make([]mathutil.Vec3, len(mesh.Normals))
+	for i, n := range mesh.Normals {
+		t := R.MulVec3(mathutil.Vec3{float64(n[0]), float64(n[1]), float64(n[2])})
+		// Normals transform by the inverse transpose of diag(scale, -scale, 1)
+		t = mathutil.Vec3{t[0], -t[1], t[2] * scale}
+		if l := t.Len(); l > 1e-12 {
+			out[i] = t.Scale(1 / l)
+		}
+	}
+	return out
+}
+
+// cornerNormals returns the screen normals (see screenNormals) of tri's
+// corners c, or false if any normal index is out of range or zero.
+func cornerNormals(vn []mathutil.Vec3, tri bmd.Triangle, c [3]int) ([3]mathutil.Vec3, bool) {
+	var out [3]mathutil.Vec3
+	for k := 0; k < 3; k++ {
+		ni := int(tri.NI[c[k]])
+		if ni < 0 || ni >= len(vn) || vn[ni] == (mathutil.Vec3{}) {
+			return out, false
+		}
+		out[k] = vn[ni]
+	}
+	return out, true
+}
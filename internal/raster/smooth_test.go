@@ -0,0 +1,67 @@
+package raster
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestSmoothShading renders one flat quad facing the camera whose vertex
+// normals face the camera on one side and point sideways on the other. Flat shading gives
+// every covered pixel the face's one shade; smooth shading interpolates the
+// normals, so the shade runs as a gradient across the quad.
+func TestSmoothShading(t *testing.T) {
+	tex := solidTextures{"quad.tga": {200, 200, 200, 255}}
+	m := bmd.Mesh{
+		TexPath: "quad.tga",
+		Verts:   [][3]float32{{-40, 0, -40}, {40, 0, -40}, {40, 0, 40}, {-40, 0, 40}},
+		Normals: [][3]float32{{0, -1, 0}, {1, 0, 0}, {1, 0, 0}, {0, -1, 0}},
+		Nodes:   []int16{0, 0, 0, 0},
+		UVs:     [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		Tris:    []bmd.Triangle{{Polygon: 4, VI: [4]int16{0, 1, 2, 3}, NI: [4]int16{0, 1, 2, 3}, TI: [4]int16{0, 1, 2, 3}}},
+	}
+	shades := func(smooth bool) (map[color.NRGBA]int, *image.NRGBA) {
+		img := RenderBMD([]bmd.Mesh{m}, nil, testEntry(), tex, 64, 64, 1, Options{SmoothShading: smooth})
+		colors := map[color.NRGBA]int{}
+		for i := 0; i < len(img.Pix); i += 4 {
+			if img.Pix[i+3] == 255 {
+				colors[color.NRGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 255}]++
+			}
+		}
+		return colors, img
+	}
+
+	flat, _ := shades(false)
+	if len(flat) != 1 {
+		t.Errorf("flat shading: %d shades, want 1: %v", len(flat), flat)
+	}
+
+	smooth, img := shades(true)
+	if len(smooth) < 8 {
+		t.Errorf("smooth shading: %d shades, want a gradient: %v", len(smooth), smooth)
+	}
+	// Along the middle row the shade changes step by step, with no jump
+	// between neighbouring pixels
+	y := img.Bounds().Dy() / 2
+	var row []int
+	for x := 0; x < img.Bounds().Dx(); x++ {
+		if c := img.NRGBAAt(x, y); c.A == 255 {
+			row = append(row, int(c.R))
+		}
+	}
+	if len(row) < 2 {
+		t.Fatal("smooth shading: quad not rendered")
+	}
+	lo, hi, step := row[0], row[0], 0
+	for i, v := range row {
+		lo, hi = min(lo, v), max(hi, v)
+		if i > 0 {
+			step = max(step, abs(v-row[i-1]))
+		}
+	}
+	if hi-lo < 8 || step > 3 {
+		t.Errorf("smooth shading: middle row %v, want a gradual change", row)
+	}
+}
@@ -0,0 +1,84 @@
+package raster
+
+import "math"
+
+// ssaoDirs are the sampling directions: 4 axes through the pixel, each
+// sampled on both sides.
+var ssaoDirs = [4][2]float64{{1, 0}, {0, 1}, {math.Sqrt2 / 2, math.Sqrt2 / 2}, {math.Sqrt2 / 2, -math.Sqrt2 / 2}}
+
+// ApplySSAO darkens the pixels of fb that sit in creases, using its depth
+// buffer (larger z = closer). Along each of 4 axes through a pixel it
+// compares the depth midway between the two samples at ±r with the pixel's
+// own: a plane at any slope has them equal, a groove or an inside corner
+// puts the pair in front. That excess, relative to r, is the axis's
+// occlusion (saturating towards 1); the pixel is darkened by intensity ×
+// the average over the axes and the radii r/2 and r. An axis with either
+// sample off the model doesn't count, so silhouettes don't darken, and a
+// pair far in front (another part crossing over) counts for less the
+// farther it is.
+//
+// Run it after the opaque pass and before any blended one: it reads only
+// opaque depth and scales the already encoded color by ao^(1/2.2), the
+// same as scaling the linear color by ao.
+func ApplySSAO(fb *FrameBuffer, radius, intensity float64) {
+	if radius <= 0 || intensity <= 0 {
+		return
+	}
+	intensity = min(intensity, 1)
+	w, h := fb.Width, fb.Height
+	depth := func(x, y int) (float64, bool) {
+		if x < 0 || y < 0 || x >= w || y >= h {
+			return 0, false
+		}
+		z := float64(fb.ZBuf[y*w+x])
+		return z, !math.IsInf(z, -1)
+	}
+	invGamma := DefaultLightConfig().InvGamma
+
+	ao := make([]float32, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			z, ok := depth(x, y)
+			if !ok {
+				continue
+			}
+			var occ float64
+			n := 0
+			for _, r := range [2]float64{radius / 2, radius} {
+				for _, d := range ssaoDirs {
+					dx, dy := int(math.Round(d[0]*r)), int(math.Round(d[1]*r))
+					za, okA := depth(x+dx, y+dy)
+					zb, okB := depth(x-dx, y-dy)
+					if !okA || !okB {
+						continue
+					}
+					n++
+					crease := (za+zb)/2 - z
+					if crease <= 0 {
+						continue
+					}
+					s := crease / r
+					o := s / (1 + s)
+					if crease > 4*radius {
+						o *= 4 * radius / crease
+					}
+					occ += o
+				}
+			}
+			if n > 0 {
+				ao[y*w+x] = float32(occ / float64(n))
+			}
+		}
+	}
+
+	for i, o := range ao {
+		if o == 0 {
+			continue
+		}
+		f := math.Pow(1-intensity*min(float64(o), 1), invGamma)
+		p := fb.Color[i*4 : i*4+3 : i*4+3]
+		for c := range p {
+			p[c] = uint8(float64(p[c])*f + 0.5)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package raster
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// depthBuffer returns a w×h frame buffer of opaque gray whose depth at
+// each pixel is z(x, y).
+func depthBuffer(w, h int, z func(x, y int) float64) *FrameBuffer {
+	fb := NewFrameBuffer(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			fb.ZBuf[i] = float32(z(x, y))
+			copy(fb.Color[i*4:], []uint8{180, 180, 180, 255})
+		}
+	}
+	return fb
+}
+
+// TestApplySSAO darkens a V-shaped groove running down a surface, leaving
+// the surface away from it, a tilted plane and a silhouette alone.
+func TestApplySSAO(t *testing.T) {
+	const w, h = 64, 32
+	groove := depthBuffer(w, h, func(x, y int) float64 {
+		if d := float64(x - w/2); d > -8 && d < 8 {
+			return -(8 - max(d, -d)) // deepest at the center column
+		}
+		return 0
+	})
+	ApplySSAO(groove, 4, 0.8)
+	center, flat := groove.Color[(h/2*w+w/2)*4], groove.Color[(h/2*w+4)*4]
+	if flat != 180 {
+		t.Errorf("flat surface away from the groove: %d, want 180", flat)
+	}
+	if center >= flat-20 {
+		t.Errorf("groove center %d, flat surface %d: want the groove clearly darker", center, flat)
+	}
+
+	tilted := depthBuffer(w, h, func(x, y int) float64 { return 0.7*float64(x) - 0.3*float64(y) })
+	want := append([]uint8(nil), tilted.Color...)
+	ApplySSAO(tilted, 4, 0.8)
+	if !bytes.Equal(tilted.Color, want) {
+		t.Error("tilted plane darkened")
+	}
+
+	// A disc in front of empty background: nothing to occlude at its edge
+	disc := depthBuffer(w, h, func(x, y int) float64 { return 0 })
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if dx, dy := x-w/2, y-h/2; dx*dx+dy*dy > 12*12 {
+				disc.ZBuf[y*w+x] = float32(math.Inf(-1))
+				copy(disc.Color[(y*w+x)*4:], []uint8{0, 0, 0, 0})
+			}
+		}
+	}
+	want = append(want[:0], disc.Color...)
+	ApplySSAO(disc, 4, 0.8)
+	if !bytes.Equal(disc.Color, want) {
+		t.Error("silhouette darkened")
+	}
+
+	off := depthBuffer(w, h, func(x, y int) float64 { return -float64(abs(x - w/2)) })
+	want = append(want[:0], off.Color...)
+	ApplySSAO(off, 0, 0.8)
+	ApplySSAO(off, 4, 0)
+	if !bytes.Equal(off.Color, want) {
+		t.Error("zero radius or intensity changed the image")
+	}
+}
@@ -0,0 +1,86 @@
+package raster
+
+import (
+	"image"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/mathutil"
+	"mu-bmd-renderer/internal/texture"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// MeshStat describes what RenderBMD did with one mesh.
+type MeshStat struct {
+	TexPath string `json:"texture"`
+	Verts   int    `json:"verts"`
+	Tris    int    `json:"tris"`
+	Reason  string `json:"reason,omitempty"` // why the mesh was filtered out (empty when rendered)
+	Pass    string `json:"pass,omitempty"`   // render pass: opaque, decoration, alpha, additive, overlay-additive, force-additive
+}
+
+// RenderStats records the decisions RenderBMD made for one model:
+// which meshes were filtered and why, which pass each kept mesh landed in,
+// and the camera used. Used for per-item diagnostics (render -verbose).
+type RenderStats struct {
+	Filtered []MeshStat
+	Rendered []MeshStat
+	Promoted bool // no opaque mesh existed, first additive/alpha mesh was promoted
+
+	UseBones  bool
+	PosCamera bool // cam_height parallax camera instead of orthographic
+	View      mathutil.Mat3
+	Scale     float64 // model units → pixels (at supersampled resolution)
+	Span      [2]float64
+}
+
+// RenderBMDWithStats is RenderBMD that also reports its filtering, pass
+// classification, and camera decisions.
+func RenderBMDWithStats(
+	meshes []bmd.Mesh,
+	bones []bmd.Bone,
+	entry *trs.Entry,
+	texResolver texture.Resolver,
+	width, height int,
+	supersample int,
+	opts Options,
+) (*image.NRGBA, *RenderStats) {
+	stats := &RenderStats{}
+	img := renderBMD(meshes, bones, entry, texResolver, width, height, supersample, opts, stats)
+	return img, stats
+}
+
+// filtered records the meshes of before whose index isn't in kept (in
+// increasing order, as filters keep mesh order). Matching by index, not
+// texture, keeps meshes that share a texture apart.
+func (s *RenderStats) filtered(before []bmd.Mesh, kept []int, reason string) {
+	if s == nil {
+		return
+	}
+	j := 0
+	for i := range before {
+		if j < len(kept) && kept[j] == i {
+			j++
+			continue
+		}
+		s.Filtered = append(s.Filtered, meshStat(&before[i], reason, ""))
+	}
+}
+
+func (s *RenderStats) rendered(meshes []bmd.Mesh, pass string) {
+	if s == nil {
+		return
+	}
+	for i := range meshes {
+		s.Rendered = append(s.Rendered, meshStat(&meshes[i], "", pass))
+	}
+}
+
+func meshStat(m *bmd.Mesh, reason, pass string) MeshStat {
+	return MeshStat{
+		TexPath: m.TexPath,
+		Verts:   len(m.Verts),
+		Tris:    len(m.Tris),
+		Reason:  reason,
+		Pass:    pass,
+	}
+}
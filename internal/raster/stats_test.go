@@ -0,0 +1,56 @@
+package raster
+
+import (
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestFilteredReasons renders one mesh caught by each filter: every dropped
+// mesh is recorded once, with its reason, in filter order, and only the
+// equipment is drawn.
+func TestFilteredReasons(t *testing.T) {
+	tex := solidTextures{"helm.jpg": {150, 150, 150, 255}, "helm.tga": {150, 150, 150, 255}}
+	bound := box([3]float32{-4, -4, -4}, [3]float32{4, 4, 4}, 6, "strap.jpg")
+	for i := range bound.Nodes {
+		bound.Nodes[i] = 5
+	}
+	meshes := []bmd.Mesh{
+		box([3]float32{-5, -5, -5}, [3]float32{5, 5, 5}, 6, "helm.jpg"), // under the mask
+		box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "helm.tga"),
+		box([3]float32{-6, -6, -6}, [3]float32{6, 6, 6}, 6, "cape.jpg"),
+		box([3]float32{-6, -6, -6}, [3]float32{6, 6, 6}, 6, "glow01.jpg"),
+		bound,
+		box([3]float32{-6, -6, -6}, [3]float32{6, 6, 6}, 6, "skinclass301.jpg"),
+		box([3]float32{-6, -6, -6}, [3]float32{6, 6, 6}, 6, "rune.jpg"),
+	}
+	e := testEntry()
+	e.HideMeshIndices = []int{2}
+	e.ExcludeTextures = []string{"Rune"} // by stem
+	e.BodyBones = []int{5}
+	_, stats := RenderBMDWithStats(meshes, nil, e, tex, 64, 64, 1, Options{})
+
+	type filtered struct{ tex, reason string }
+	var got []filtered
+	for _, m := range stats.Filtered {
+		got = append(got, filtered{m.TexPath, m.Reason})
+	}
+	want := []filtered{
+		{"cape.jpg", "hide_meshes"},
+		{"rune.jpg", "exclude_textures"},
+		{"strap.jpg", "body_bones"},
+		{"glow01.jpg", "effect"},
+		{"skinclass301.jpg", "body"},
+		{"helm.jpg", "jpg-under-tga"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filtered %v, want %v", got, want)
+	}
+	if len(stats.Rendered) != 1 || stats.Rendered[0].TexPath != "helm.tga" {
+		t.Errorf("rendered %+v, want only helm.tga", stats.Rendered)
+	}
+	if m := stats.Filtered[0]; m.Verts != 28 || m.Tris != 26 {
+		t.Errorf("hidden mesh recorded as %dv/%dt, want 28v/26t", m.Verts, m.Tris)
+	}
+}
@@ -0,0 +1,53 @@
+package raster
+
+import (
+	"image"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// texAdjustKey identifies an adjusted copy of a texture: its source image
+// and the entry's tex_brightness, tex_contrast and tex_gamma.
+type texAdjustKey struct {
+	src                         weak.Pointer[image.NRGBA]
+	brightness, contrast, gamma float64
+}
+
+// texAdjusted holds adjusted copies for as long as their source image lives
+// (normally in the texture cache), so items that share a texture and its
+// settings adjust it once instead of on every mesh of every render.
+var texAdjusted struct {
+	sync.Mutex
+	m map[texAdjustKey]*image.NRGBA
+}
+
+// adjustedTexture is applyTexAdjust(src, ...), reusing an earlier result for
+// the same src and parameters.
+func adjustedTexture(src *image.NRGBA, brightness, contrast, gamma float64) *image.NRGBA {
+	key := texAdjustKey{weak.Make(src), brightness, contrast, gamma}
+	texAdjusted.Lock()
+	dst, ok := texAdjusted.m[key]
+	texAdjusted.Unlock()
+	if ok {
+		return dst
+	}
+
+	dst = applyTexAdjust(src, brightness, contrast, gamma)
+	texAdjusted.Lock()
+	defer texAdjusted.Unlock()
+	// Another worker may have adjusted it meanwhile
+	if prev, ok := texAdjusted.m[key]; ok {
+		return prev
+	}
+	if texAdjusted.m == nil {
+		texAdjusted.m = make(map[texAdjustKey]*image.NRGBA)
+	}
+	texAdjusted.m[key] = dst
+	runtime.AddCleanup(src, func(key texAdjustKey) {
+		texAdjusted.Lock()
+		delete(texAdjusted.m, key)
+		texAdjusted.Unlock()
+	}, key)
+	return dst
+}
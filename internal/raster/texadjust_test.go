@@ -0,0 +1,123 @@
+package raster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// gradient returns a 16×16 texture with RGB ramping from 0 to 255 and alpha
+// alternating between 255 and 128.
+func gradient() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for i := 0; i < 256; i++ {
+		a := uint8(255)
+		if i%2 == 1 {
+			a = 128
+		}
+		img.SetNRGBA(i%16, i/16, color.NRGBA{uint8(i), uint8(i), uint8(255 - i), a})
+	}
+	return img
+}
+
+// meanLuma returns the average Rec. 601 luma of img's pixels with alpha.
+func meanLuma(img *image.NRGBA) float64 {
+	var sum float64
+	n := 0
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i+3] == 0 {
+			continue
+		}
+		sum += 0.299*float64(img.Pix[i]) + 0.587*float64(img.Pix[i+1]) + 0.114*float64(img.Pix[i+2])
+		n++
+	}
+	return sum / float64(n)
+}
+
+func TestApplyTexAdjust(t *testing.T) {
+	src := gradient()
+	base := meanLuma(src)
+
+	if got := applyTexAdjust(src, 0, 0, 0); !bytes.Equal(got.Pix, src.Pix) {
+		t.Error("all-zero parameters changed the texture")
+	}
+	for _, c := range []struct {
+		name                      string
+		brightness, contrast, gam float64
+		brighter                  bool
+	}{
+		{"brightness 1.5", 1.5, 0, 0, true},
+		{"brightness 0.5", 0.5, 0, 0, false},
+		{"gamma 2", 0, 0, 2, true},
+		{"gamma 0.5", 0, 0, 0.5, false},
+	} {
+		got := applyTexAdjust(src, c.brightness, c.contrast, c.gam)
+		if l := meanLuma(got); (l > base) != c.brighter || l == base {
+			t.Errorf("%s: mean luma %.1f from %.1f, want brighter = %v", c.name, l, base, c.brighter)
+		}
+		for i := 3; i < len(got.Pix); i += 4 {
+			if got.Pix[i] != src.Pix[i] {
+				t.Fatalf("%s: alpha changed at %d", c.name, i/4)
+			}
+		}
+	}
+
+	// Contrast > 1 pushes values away from mid-gray
+	got := applyTexAdjust(src, 0, 2, 0)
+	if lo, hi := got.Pix[src.PixOffset(4, 0)], got.Pix[src.PixOffset(12, 15)]; lo != 0 || hi != 255 {
+		t.Errorf("contrast 2: %d and %d at 4 and 252, want 0 and 255", lo, hi)
+	}
+}
+
+// TestTexBrightnessRender checks the entry fields reach the rasterizer: a
+// box rendered with tex_brightness 1.6 is brighter than without.
+func TestTexBrightnessRender(t *testing.T) {
+	tex := solidTextures{"box.tga": {90, 90, 90, 255}}
+	meshes := []bmd.Mesh{box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 6, "box.tga")}
+	plain := RenderBMD(meshes, nil, testEntry(), tex, 64, 64, 1, Options{})
+
+	e := testEntry()
+	e.TexBrightness = 1.6
+	bright := RenderBMD(meshes, nil, e, tex, 64, 64, 1, Options{})
+	if lb, lp := meanLuma(bright), meanLuma(plain); lb <= lp+10 {
+		t.Errorf("tex_brightness 1.6: mean luma %.1f, plain %.1f; want clearly brighter", lb, lp)
+	}
+}
+
+// TestAdjustedTextureCache adjusts the same texture twice with the same
+// settings (one copy, equal to applyTexAdjust's), with other settings and
+// another source (new copies), and then from many goroutines at once.
+func TestAdjustedTextureCache(t *testing.T) {
+	src, other := gradient(), gradient()
+	a := adjustedTexture(src, 1.2, 0, 0)
+	if want := applyTexAdjust(src, 1.2, 0, 0); !bytes.Equal(a.Pix, want.Pix) {
+		t.Error("cached copy differs from applyTexAdjust")
+	}
+	if adjustedTexture(src, 1.2, 0, 0) != a {
+		t.Error("same texture and settings adjusted again")
+	}
+	if adjustedTexture(src, 1.2, 0, 2) == a || adjustedTexture(other, 1.2, 0, 0) == a {
+		t.Error("different settings or source share the copy")
+	}
+
+	shared := gradient()
+	got := make([]*image.NRGBA, 16)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = adjustedTexture(shared, 0.8, 1.1, 0)
+		}()
+	}
+	wg.Wait()
+	for i := range got {
+		if got[i] != got[0] {
+			t.Fatalf("goroutine %d got another copy", i)
+		}
+	}
+}
@@ -41,7 +41,10 @@ func conservativeThresholds(x0, y0, x1, y1, x2, y2, det float64) (float64, float
 // sRGB color space, lighting, and ACES tone mapping.
 //
 // This is the HOT PATH — designed for zero allocation in the inner loop.
-// All lighting is flat-shaded (per-face, not per-pixel).
+// All lighting is flat-shaded (per-face, not per-pixel). faceN, when non-nil,
+// is a unit normal to shade with instead of the triangle's own, so both halves
+// of a split quad share one shade (see quadNormal); the other rasterizers take
+// it too.
 func RasterizeTriangle(
 	fb *FrameBuffer,
 	px, py, pz []float64,
@@ -50,6 +53,24 @@ func RasterizeTriangle(
 	tex *image.NRGBA,
 	defaultR, defaultG, defaultB, defaultA uint8,
 	lc *LightConfig,
+	faceN *mathutil.Vec3,
+) {
+	rasterizeTriangle(fb, px, py, pz, uvs, vi, ti, tex, defaultR, defaultG, defaultB, defaultA, lc, faceN, nil)
+}
+
+// rasterizeTriangle is RasterizeTriangle with optional smooth shading: vn,
+// when non-nil, holds the corners' normals (see screenNormals), interpolated
+// per pixel and shaded with ComputeShade in place of the face's shade.
+func rasterizeTriangle(
+	fb *FrameBuffer,
+	px, py, pz []float64,
+	uvs [][2]float32,
+	vi [3]int, ti [3]int,
+	tex *image.NRGBA,
+	defaultR, defaultG, defaultB, defaultA uint8,
+	lc *LightConfig,
+	faceN *mathutil.Vec3,
+	vn *[3]mathutil.Vec3,
 ) {
 	nv := len(px)
 	nuv := len(uvs)
@@ -99,6 +120,19 @@ func RasterizeTriangle(
 	ny *= invNL
 	nz *= invNL
 
+	// Smooth shading: corner normals turned to the face's side, so the
+	// interpolation never passes through zero (and specular stays on the
+	// lit side) where the mesh's normals disagree with its winding
+	var n0, n1, n2 mathutil.Vec3
+	if vn != nil {
+		face := mathutil.Vec3{nx, ny, nz}
+		n0, n1, n2 = facing(vn[0], face), facing(vn[1], face), facing(vn[2], face)
+	}
+
+	if faceN != nil {
+		nx, ny, nz = faceN[0], faceN[1], faceN[2]
+	}
+
 	// Compute shade using lighting config
 	ndlMain := math.Abs(nx*lc.LightDir[0] + ny*lc.LightDir[1] + nz*lc.LightDir[2])
 	ndlRim := math.Abs(nx*lc.RimDir[0] + ny*lc.RimDir[1] + nz*lc.RimDir[2])
@@ -170,7 +204,7 @@ func RasterizeTriangle(
 
 			z := w0*z0 + w1*z1 + w2*z2
 			zIdx := rowOff + sx
-			if z < fb.ZBuf[zIdx] {
+			if float32(z) < fb.ZBuf[zIdx] {
 				continue
 			}
 
@@ -187,36 +221,37 @@ func RasterizeTriangle(
 			if ca < 8 {
 				continue
 			}
-			fb.ZBuf[zIdx] = z
-
-			// sRGB decode → linear (LUT)
-			lr := srgbToLinear[cr]
-			lg := srgbToLinear[cg]
-			lb := srgbToLinear[cb]
+			fb.ZBuf[zIdx] = float32(z)
 
-			// Apply shading + ACES tone mapping
-			sr := lr * shade * exposure
-			sg := lg * shade * exposure
-			sb := lb * shade * exposure
-
-			tr := ACESTonemap(sr)
-			tg := ACESTonemap(sg)
-			tb := ACESTonemap(sb)
+			if vn != nil {
+				n := n0.Scale(w0).Add(n1.Scale(w1)).Add(n2.Scale(w2))
+				if l := n.Len(); l > 1e-8 {
+					shade = lc.ComputeShade(n.Scale(1 / l))
+				}
+			}
 
-			// Linear → sRGB encode
-			fr := math.Pow(tr, invGamma)
-			fg := math.Pow(tg, invGamma)
-			ffb := math.Pow(tb, invGamma)
+			// sRGB → linear, shade + ACES tone map, → sRGB
+			fr := shadeTexel(cr, shade, exposure, invGamma)
+			fg := shadeTexel(cg, shade, exposure, invGamma)
+			ffb := shadeTexel(cb, shade, exposure, invGamma)
 
 			pxIdx := zIdx * 4
-			fb.Color[pxIdx] = clamp255(fr * 255)
-			fb.Color[pxIdx+1] = clamp255(fg * 255)
-			fb.Color[pxIdx+2] = clamp255(ffb * 255)
+			fb.Color[pxIdx] = clamp255(fr)
+			fb.Color[pxIdx+1] = clamp255(fg)
+			fb.Color[pxIdx+2] = clamp255(ffb)
 			fb.Color[pxIdx+3] = ca
 		}
 	}
 }
 
+// facing returns n, reversed if it points away from face.
+func facing(n, face mathutil.Vec3) mathutil.Vec3 {
+	if n.Dot(face) < 0 {
+		return n.Scale(-1)
+	}
+	return n
+}
+
 // RasterizeTriangleAdditive renders a triangle with additive blending.
 // No z-buffer check/write — colors are ADDED to existing framebuffer values.
 // Used for inner glow meshes (liquid in bottles, _R suffix textures).
@@ -228,6 +263,7 @@ func RasterizeTriangleAdditive(
 	tex *image.NRGBA,
 	defaultR, defaultG, defaultB, defaultA uint8,
 	lc *LightConfig,
+	faceN *mathutil.Vec3,
 ) {
 	nv := len(px)
 	nuv := len(uvs)
@@ -273,6 +309,9 @@ func RasterizeTriangleAdditive(
 	nx *= invNL
 	ny *= invNL
 	nz *= invNL
+	if faceN != nil {
+		nx, ny, nz = faceN[0], faceN[1], faceN[2]
+	}
 
 	ndlMain := math.Abs(nx*lc.LightDir[0] + ny*lc.LightDir[1] + nz*lc.LightDir[2])
 	ndlRim := math.Abs(nx*lc.RimDir[0] + ny*lc.RimDir[1] + nz*lc.RimDir[2])
@@ -357,25 +396,13 @@ func RasterizeTriangleAdditive(
 			// to pass the test despite floating point imprecision.
 			z := w0*z0 + w1*z1 + w2*z2
 			zbIdx := rowOff + sx
-			if z < fb.ZBuf[zbIdx]-0.5 {
+			if float32(z) < fb.ZBuf[zbIdx]-0.5 {
 				continue
 			}
 
-			lr := srgbToLinear[cr]
-			lg := srgbToLinear[cg]
-			lb := srgbToLinear[cb]
-
-			sr := lr * shade * exposure
-			sg := lg * shade * exposure
-			sb := lb * shade * exposure
-
-			tr := ACESTonemap(sr)
-			tg := ACESTonemap(sg)
-			tb := ACESTonemap(sb)
-
-			fr := math.Pow(tr, invGamma) * 255
-			fg := math.Pow(tg, invGamma) * 255
-			ffb := math.Pow(tb, invGamma) * 255
+			fr := shadeTexel(cr, shade, exposure, invGamma)
+			fg := shadeTexel(cg, shade, exposure, invGamma)
+			ffb := shadeTexel(cb, shade, exposure, invGamma)
 
 			// Skip very dark texels — dark fire/energy background should not
 			// brighten existing pixels. Only bright glow/energy parts contribute.
@@ -409,6 +436,7 @@ func RasterizeTriangleAdditiveOverlay(
 	tex *image.NRGBA,
 	defaultR, defaultG, defaultB, defaultA uint8,
 	lc *LightConfig,
+	faceN *mathutil.Vec3,
 ) {
 	nv := len(px)
 	nuv := len(uvs)
@@ -453,6 +481,9 @@ func RasterizeTriangleAdditiveOverlay(
 	nx *= invNL
 	ny *= invNL
 	nz *= invNL
+	if faceN != nil {
+		nx, ny, nz = faceN[0], faceN[1], faceN[2]
+	}
 
 	ndlMain := math.Abs(nx*lc.LightDir[0] + ny*lc.LightDir[1] + nz*lc.LightDir[2])
 	ndlRim := math.Abs(nx*lc.RimDir[0] + ny*lc.RimDir[1] + nz*lc.RimDir[2])
@@ -520,7 +551,7 @@ func RasterizeTriangleAdditiveOverlay(
 			zbIdx := rowOff + sx
 
 			// OVERLAY CHECK: skip pixels on empty background (no opaque geometry drawn)
-			if fb.ZBuf[zbIdx] == math.Inf(-1) {
+			if math.IsInf(float64(fb.ZBuf[zbIdx]), -1) {
 				continue
 			}
 
@@ -538,25 +569,13 @@ func RasterizeTriangleAdditiveOverlay(
 			}
 
 			z := w0*z0 + w1*z1 + w2*z2
-			if z < fb.ZBuf[zbIdx]-0.5 {
+			if float32(z) < fb.ZBuf[zbIdx]-0.5 {
 				continue
 			}
 
-			lr := srgbToLinear[cr]
-			lg := srgbToLinear[cg]
-			lb := srgbToLinear[cb]
-
-			sr := lr * shade * exposure
-			sg := lg * shade * exposure
-			sb := lb * shade * exposure
-
-			tr := ACESTonemap(sr)
-			tg := ACESTonemap(sg)
-			tb := ACESTonemap(sb)
-
-			fr := math.Pow(tr, invGamma) * 255
-			fg := math.Pow(tg, invGamma) * 255
-			ffb := math.Pow(tb, invGamma) * 255
+			fr := shadeTexel(cr, shade, exposure, invGamma)
+			fg := shadeTexel(cg, shade, exposure, invGamma)
+			ffb := shadeTexel(cb, shade, exposure, invGamma)
 
 			lum := fr*0.299 + fg*0.587 + ffb*0.114
 			if lum < lc.AdditiveDarkFloor {
@@ -586,6 +605,7 @@ func RasterizeTriangleAlphaBlend(
 	tex *image.NRGBA,
 	defaultR, defaultG, defaultB, defaultA uint8,
 	lc *LightConfig,
+	faceN *mathutil.Vec3,
 ) {
 	nv := len(px)
 	nuv := len(uvs)
@@ -629,6 +649,9 @@ func RasterizeTriangleAlphaBlend(
 	}
 	inv := 1.0 / nl
 	nx, ny, nz = nx*inv, ny*inv, nz*inv
+	if faceN != nil {
+		nx, ny, nz = faceN[0], faceN[1], faceN[2]
+	}
 	shade := lc.ComputeShade(mathutil.Vec3{nx, ny, nz})
 	exposure := lc.Exposure
 	invGamma := lc.InvGamma
@@ -672,7 +695,7 @@ func RasterizeTriangleAlphaBlend(
 			// ZBuf uses larger-z = closer convention (init=-inf), so skip if behind.
 			z := w0*z0 + w1*z1 + w2*z2
 			zbIdx := rowOff + sx
-			if z < fb.ZBuf[zbIdx] {
+			if float32(z) < fb.ZBuf[zbIdx] {
 				continue
 			}
 
@@ -689,21 +712,9 @@ func RasterizeTriangleAlphaBlend(
 				continue
 			}
 
-			lr := srgbToLinear[cr]
-			lg := srgbToLinear[cg]
-			lb := srgbToLinear[cb]
-
-			sr := lr * shade * exposure
-			sg := lg * shade * exposure
-			sb := lb * shade * exposure
-
-			tr := ACESTonemap(sr)
-			tg := ACESTonemap(sg)
-			tb := ACESTonemap(sb)
-
-			srcR := math.Pow(tr, invGamma) * 255
-			srcG := math.Pow(tg, invGamma) * 255
-			srcB := math.Pow(tb, invGamma) * 255
+			srcR := shadeTexel(cr, shade, exposure, invGamma)
+			srcG := shadeTexel(cg, shade, exposure, invGamma)
+			srcB := shadeTexel(cb, shade, exposure, invGamma)
 
 			// Alpha compositing: dst = src*a + dst*(1-a)
 			a := float64(ca) / 255.0
@@ -722,6 +733,13 @@ func RasterizeTriangleAlphaBlend(
 	}
 }
 
+// shadeTexel is the rasterizers' color path for one channel: sRGB texel c
+// decoded to linear, scaled by shade and exposure, ACES tone-mapped, and
+// encoded back to sRGB in [0, 255] (unclamped; see TestShadeTexel).
+func shadeTexel(c uint8, shade, exposure, invGamma float64) float64 {
+	return math.Pow(ACESTonemap(srgbToLinear[c]*shade*exposure), invGamma) * 255
+}
+
 func clamp255(v float64) uint8 {
 	if v < 0 {
 		return 0
@@ -0,0 +1,97 @@
+package raster
+
+import (
+	"image/color"
+	"math"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// Wireframe modes for Options.Wireframe.
+const (
+	WireframeOff     = iota
+	WireframeOverlay // edges drawn over the shaded render
+	WireframeOnly    // edges only, on a transparent background
+)
+
+// WireframeModes maps the wireframe config values to modes.
+var WireframeModes = map[string]int{"": WireframeOff, "overlay": WireframeOverlay, "only": WireframeOnly}
+
+// DefaultWireframeColor is the edge color when none is configured.
+var DefaultWireframeColor = color.NRGBA{0, 255, 0, 255}
+
+// DrawWireframe draws the edges of tris, with vertices projected to px, py,
+// into fb as width-px Bresenham lines of color c. Quads get their 4 outer
+// edges (not the split diagonal); edges with an out-of-range index are
+// skipped. Lines ignore and don't write the depth buffer, so hidden edges
+// show too.
+func DrawWireframe(fb *FrameBuffer, px, py []float64, tris []bmd.Triangle, c color.NRGBA, width int) {
+	for _, tri := range tris {
+		n := 3
+		if tri.Polygon == 4 {
+			n = 4
+		}
+		for k := 0; k < n; k++ {
+			a, b := int(tri.VI[k]), int(tri.VI[(k+1)%n])
+			if a < 0 || a >= len(px) || b < 0 || b >= len(px) {
+				continue
+			}
+			drawLine(fb, px[a], py[a], px[b], py[b], c, width)
+		}
+	}
+}
+
+// drawLine draws a line from (x0, y0) to (x1, y1), rounded to pixel
+// centers, with a width×width square brush.
+func drawLine(fb *FrameBuffer, x0, y0, x1, y1 float64, c color.NRGBA, width int) {
+	if math.IsNaN(x0+y0+x1+y1) || math.IsInf(x0+y0+x1+y1, 0) {
+		return
+	}
+	// Clamp far-off endpoints so a stray vertex can't make the walk huge;
+	// the part inside the buffer keeps its direction closely enough
+	lim := float64(4 * (fb.Width + fb.Height))
+	ax, ay := int(math.Round(max(-lim, min(x0, lim)))), int(math.Round(max(-lim, min(y0, lim))))
+	bx, by := int(math.Round(max(-lim, min(x1, lim)))), int(math.Round(max(-lim, min(y1, lim))))
+
+	dx, dy := abs(bx-ax), -abs(by-ay)
+	sx, sy := 1, 1
+	if ax > bx {
+		sx = -1
+	}
+	if ay > by {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		plotBrush(fb, ax, ay, c, width)
+		if ax == bx && ay == by {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			ax += sx
+		}
+		if e2 <= dx {
+			err += dx
+			ay += sy
+		}
+	}
+}
+
+func plotBrush(fb *FrameBuffer, x, y int, c color.NRGBA, width int) {
+	x0, y0 := x-(width-1)/2, y-(width-1)/2
+	for py := max(y0, 0); py < min(y0+width, fb.Height); py++ {
+		for px := max(x0, 0); px < min(x0+width, fb.Width); px++ {
+			i := (py*fb.Width + px) * 4
+			fb.Color[i], fb.Color[i+1], fb.Color[i+2], fb.Color[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package raster
+
+import (
+	"image/color"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+func drawnAt(fb *FrameBuffer, x, y int) bool {
+	return fb.Color[(y*fb.Width+x)*4+3] != 0
+}
+
+// TestDrawWireframe draws a known right triangle and a quad: every pixel
+// on their edges is set, the interiors and the quad's split diagonal are
+// not, and an edge with a bad index is skipped.
+func TestDrawWireframe(t *testing.T) {
+	c := color.NRGBA{255, 0, 255, 255}
+	px := []float64{2, 20, 2, 30, 40, 40, 30}
+	py := []float64{2, 2, 14, 2, 2, 12, 12}
+	fb := NewFrameBuffer(48, 16)
+	DrawWireframe(fb, px, py, []bmd.Triangle{
+		{Polygon: 3, VI: [4]int16{0, 1, 2}},
+		{Polygon: 4, VI: [4]int16{3, 4, 5, 6}},
+		{Polygon: 3, VI: [4]int16{0, 1, 9}}, // only 0-1 drawn
+	}, c, 1)
+
+	for x := 2; x <= 20; x++ {
+		if !drawnAt(fb, x, 2) {
+			t.Errorf("edge 0-1: (%d, 2) not drawn", x)
+		}
+	}
+	for y := 2; y <= 14; y++ {
+		if !drawnAt(fb, 2, y) {
+			t.Errorf("edge 0-2: (2, %d) not drawn", y)
+		}
+	}
+	// Hypotenuse from (20, 2) to (2, 14): one pixel per column, within
+	// half a pixel of the line
+	for x := 2; x <= 20; x++ {
+		y := 2 + 12*float64(20-x)/18
+		if !drawnAt(fb, x, int(y+0.5)) && !drawnAt(fb, x, int(y)) {
+			t.Errorf("edge 1-2: nothing near (%d, %.1f)", x, y)
+		}
+	}
+	for _, p := range [][2]int{{30, 7}, {40, 7}, {35, 2}, {35, 12}} {
+		if !drawnAt(fb, p[0], p[1]) {
+			t.Errorf("quad edge: %v not drawn", p)
+		}
+	}
+	for _, p := range [][2]int{{6, 6}, {35, 7}, {33, 5}} { // triangle interior, quad center and diagonal
+		if drawnAt(fb, p[0], p[1]) {
+			t.Errorf("%v drawn, want it empty", p)
+		}
+	}
+	if got := fb.Color[(2*fb.Width+10)*4:][:4]; got[0] != c.R || got[1] != c.G || got[2] != c.B {
+		t.Errorf("edge color %v, want %v", got, c)
+	}
+}
+
+// TestWireframeModes renders a box with each wireframe mode: only draws
+// nothing but edge-colored pixels, overlay keeps the shaded box under them.
+func TestWireframeModes(t *testing.T) {
+	tex := solidTextures{"box.jpg": {150, 150, 150, 255}}
+	meshes := []bmd.Mesh{box([3]float32{-10, -10, -30}, [3]float32{10, 10, 30}, 3, "box.jpg")}
+	edge := color.NRGBA{255, 0, 0, 255}
+	count := func(mode int) (edges, other int) {
+		img := RenderBMD(meshes, nil, testEntry(), tex, 64, 64, 1, Options{Wireframe: mode, WireframeColor: edge})
+		for i := 0; i < len(img.Pix); i += 4 {
+			switch {
+			case img.Pix[i+3] == 0:
+			case img.Pix[i] == 255 && img.Pix[i+1] == 0 && img.Pix[i+2] == 0:
+				edges++
+			default:
+				other++
+			}
+		}
+		return
+	}
+
+	if e, o := count(WireframeOff); e != 0 || o == 0 {
+		t.Errorf("off: %d edge and %d shaded pixels, want only shaded", e, o)
+	}
+	if e, o := count(WireframeOnly); e == 0 || o != 0 {
+		t.Errorf("only: %d edge and %d other pixels, want only edges", e, o)
+	}
+	if e, o := count(WireframeOverlay); e == 0 || o == 0 {
+		t.Errorf("overlay: %d edge and %d shaded pixels, want both", e, o)
+	}
+}
@@ -10,6 +10,12 @@ import (
 // If boneFlip is true, root bone matrices are prefixed with Rx(-90°) to match
 // BMD-viewer's Three.js group inheritance (group.rotation.x = -PI/2).
 func BuildWorldMatrices(bones []bmd.Bone, boneFlip bool) []mathutil.Mat4 {
+	return BuildPoseMatrices(bones, boneFlip, 0, 0)
+}
+
+// BuildPoseMatrices is BuildWorldMatrices at key frame of action (see
+// bmd.Bone.Pose) instead of the bind pose.
+func BuildPoseMatrices(bones []bmd.Bone, boneFlip bool, action, frame int) []mathutil.Mat4 {
 	worlds := make([]mathutil.Mat4, len(bones))
 	for i := range worlds {
 		worlds[i] = mathutil.Mat4Identity()
@@ -27,9 +33,10 @@ func BuildWorldMatrices(bones []bmd.Bone, boneFlip bool) []mathutil.Mat4 {
 		}
 
 		// Local transform: rotation from Euler + translation
-		q := mathutil.EulerToQuat(bone.BindRotation[0], bone.BindRotation[1], bone.BindRotation[2])
+		p, r := bone.Pose(action, frame)
+		q := mathutil.EulerToQuat(r[0], r[1], r[2])
 		rot := mathutil.QuatToMat3(q)
-		pos := mathutil.Vec3{bone.BindPosition[0], bone.BindPosition[1], bone.BindPosition[2]}
+		pos := mathutil.Vec3{p[0], p[1], p[2]}
 		local := mathutil.FromMat3Translation(rot, pos)
 
 		// Chain with parent
@@ -48,14 +55,20 @@ func BuildWorldMatrices(bones []bmd.Bone, boneFlip bool) []mathutil.Mat4 {
 	return worlds
 }
 
-// ApplyTransforms modifies mesh vertex positions in-place using bone world matrices.
-// Rigid skinning: 1 bone per vertex, weight = 1.0.
-func ApplyTransforms(meshes []bmd.Mesh, bones []bmd.Bone, boneFlip bool) {
+// ApplyTransforms modifies mesh vertex positions (and normals, for smooth
+// shading) in-place using bone world matrices at key frame of action (0, 0 =
+// the bind pose). A normal turns with the bone of the first vertex a face
+// pairs it with.
+// Rigid skinning: 1 bone per vertex, weight = 1.0. That is all the format
+// stores (a vertex record has one node and no weights; the normal record's
+// bind field is a vertex index), so the game skins the same way and there is
+// nothing to blend.
+func ApplyTransforms(meshes []bmd.Mesh, bones []bmd.Bone, boneFlip bool, action, frame int) {
 	if len(bones) == 0 {
 		return
 	}
 
-	worlds := BuildWorldMatrices(bones, boneFlip)
+	worlds := BuildPoseMatrices(bones, boneFlip, action, frame)
 
 	// Check if all matrices are identity (skip if so)
 	allIdentity := true
@@ -84,5 +97,27 @@ func ApplyTransforms(meshes []bmd.Mesh, bones []bmd.Bone, boneFlip bool) {
 			t := worlds[boneIdx].MulPoint(v)
 			mesh.Verts[vi] = [3]float32{float32(t[0]), float32(t[1]), float32(t[2])}
 		}
+
+		turned := make([]bool, len(mesh.Normals))
+		for _, tri := range mesh.Tris {
+			corners := 3
+			if tri.Polygon == 4 {
+				corners = 4
+			}
+			for c := 0; c < corners; c++ {
+				ni, vi := int(tri.NI[c]), int(tri.VI[c])
+				if ni < 0 || ni >= len(mesh.Normals) || turned[ni] || vi < 0 || vi >= len(mesh.Nodes) {
+					continue
+				}
+				boneIdx := int(mesh.Nodes[vi])
+				if boneIdx < 0 || boneIdx >= len(worlds) {
+					continue
+				}
+				turned[ni] = true
+				n := mesh.Normals[ni]
+				t := worlds[boneIdx].MulDir(mathutil.Vec3{float64(n[0]), float64(n[1]), float64(n[2])})
+				mesh.Normals[ni] = [3]float32{float32(t[0]), float32(t[1]), float32(t[2])}
+			}
+		}
 	}
 }
@@ -0,0 +1,54 @@
+package skeleton
+
+import (
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+)
+
+// TestApplyTransformsRigid skins a two-bone seam: a child bone 10 up the
+// root, turned 90° about z. Two vertices at the same bind position, one on
+// each bone, each follow only their own bone with weight 1 (the format has
+// no weights to blend), and so do the normals paired with them.
+func TestApplyTransformsRigid(t *testing.T) {
+	bones := []bmd.Bone{
+		{Name: "Root", Parent: -1},
+		{Name: "Tip", Parent: 0, BindPosition: [3]float64{0, 0, 10}, BindRotation: [3]float64{0, 0, math.Pi / 2}},
+	}
+	mesh := bmd.Mesh{
+		Verts:   [][3]float32{{1, 0, 10}, {1, 0, 0}, {0, 0, 0}, {2, 0, 0}},
+		Nodes:   []int16{0, 1, 1, 7}, // the last vertex's bone doesn't exist
+		Normals: [][3]float32{{1, 0, 0}, {1, 0, 0}, {0, 0, 1}},
+		Tris: []bmd.Triangle{
+			{Polygon: 3, VI: [4]int16{0, 1, 2}, NI: [4]int16{0, 1, 2}},
+			{Polygon: 3, VI: [4]int16{1, 0, 2}, NI: [4]int16{0, 1, 2}}, // normals already turned
+		},
+	}
+	meshes := []bmd.Mesh{mesh}
+	ApplyTransforms(meshes, bones, false, 0, 0)
+
+	near := func(a [3]float32, b [3]float64) bool {
+		return math.Abs(float64(a[0])-b[0])+math.Abs(float64(a[1])-b[1])+math.Abs(float64(a[2])-b[2]) < 1e-5
+	}
+	for i, want := range [][3]float64{{1, 0, 10}, {0, 1, 10}, {0, 0, 10}, {2, 0, 0}} {
+		if got := meshes[0].Verts[i]; !near(got, want) {
+			t.Errorf("vertex %d (bone %d): %v, want %v", i, mesh.Nodes[i], got, want)
+		}
+	}
+	for i, want := range [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		if got := meshes[0].Normals[i]; !near(got, want) {
+			t.Errorf("normal %d: %v, want %v", i, got, want)
+		}
+	}
+}
+
+// TestApplyTransformsIdentity leaves a mesh alone when every bone is at the
+// origin without rotation.
+func TestApplyTransformsIdentity(t *testing.T) {
+	meshes := []bmd.Mesh{{Verts: [][3]float32{{1, 2, 3}}, Nodes: []int16{0}}}
+	ApplyTransforms(meshes, []bmd.Bone{{Parent: -1}, {Parent: 0}}, false, 0, 0)
+	if got := meshes[0].Verts[0]; got != [3]float32{1, 2, 3} {
+		t.Errorf("vertex moved to %v", got)
+	}
+}
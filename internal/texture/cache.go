@@ -1,8 +1,14 @@
 package texture
 
 import (
+	"container/list"
+	"crypto/sha256"
 	"image"
+	"path/filepath"
+	"strings"
 	"sync"
+
+	"mu-bmd-renderer/internal/mmap"
 )
 
 // Resolver resolves a texture name to a decoded RGBA image.
@@ -10,26 +16,93 @@ type Resolver interface {
 	Resolve(texName string) *image.NRGBA
 }
 
-// Cache is a concurrency-safe texture cache.
+// PathResolver is a Resolver that can also name the file a texture
+// reference resolves to, without decoding it.
+type PathResolver interface {
+	Resolver
+	ResolvePath(texName string) (string, bool)
+}
+
+// Cache is a concurrency-safe texture cache. Each file is decoded once, even
+// when several workers ask for it at the same time, and files with identical
+// contents (many items ship the same texture under different names) share
+// one decoded image. With a limit (NewCacheWithLimit) the least recently
+// used textures are dropped once the decoded images outgrow it.
 type Cache struct {
-	mu    sync.RWMutex
-	items map[string]*cacheEntry
-	index *Index
+	mu        sync.RWMutex
+	items     map[string]*cacheEntry
+	byContent map[contentKey]*decoded
+	index     *Index
+	maxSize   int // longest side kept after decoding (0 = full size)
+
+	maxBytes int        // decoded image bytes kept (0 = unbounded)
+	used     int        // decoded image bytes held, each shared image once
+	lru      *list.List // paths, most recently used first
+}
+
+// contentKey identifies a texture file by its bytes; the extension is part
+// of the key because it picks the decoder.
+type contentKey struct {
+	sum [sha256.Size]byte
+	ext string
+}
+
+// decoded is an image decoded from one file contents, and how many cached
+// paths use it.
+type decoded struct {
+	img  *image.NRGBA // nil if the contents didn't decode
+	key  contentKey
+	refs int
 }
 
 type cacheEntry struct {
-	img    *image.NRGBA
-	loaded bool // true if we've attempted to load (img may still be nil)
+	img   *image.NRGBA  // nil if the file couldn't be read or decoded
+	data  *decoded      // nil if the file couldn't be read
+	elem  *list.Element // in lru; nil while loading and once evicted
+	ready chan struct{} // closed once img is set
 }
 
-// NewCache creates a new texture cache backed by the given index.
+// NewCache creates a new texture cache backed by the given index. It keeps
+// every texture it decodes.
 func NewCache(index *Index) *Cache {
+	return NewCacheWithLimit(index, 0)
+}
+
+// NewCacheWithLimit is NewCache holding at most maxBytes of decoded images
+// (4 bytes per pixel; 0 = unbounded). Past that, the least recently
+// resolved textures are dropped and decoded again if asked for later; the
+// most recent one stays even if it alone is larger.
+func NewCacheWithLimit(index *Index, maxBytes int) *Cache {
 	return &Cache{
-		items: make(map[string]*cacheEntry),
-		index: index,
+		items:     make(map[string]*cacheEntry),
+		byContent: make(map[contentKey]*decoded),
+		index:     index,
+		maxBytes:  max(maxBytes, 0),
+		lru:       list.New(),
 	}
 }
 
+// SetMaxSize caps cached textures at px on their longer side (0 = no cap).
+// Larger textures are downscaled once, right after decoding, so the cache
+// holds and the sampler reads only the smaller image. Call it before the
+// first Resolve.
+func (c *Cache) SetMaxSize(px int) {
+	c.maxSize = px
+}
+
+// MemoryUsage returns the bytes of decoded images the cache holds.
+func (c *Cache) MemoryUsage() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.used
+}
+
+// ResolvePath returns the file Resolve(texName) decodes (see
+// Index.ResolvePath).
+func (c *Cache) ResolvePath(texName string) (string, bool) {
+	return c.index.ResolvePath(texName)
+}
+
 // Resolve loads and caches a texture by name. Returns nil if not found.
 func (c *Cache) Resolve(texName string) *image.NRGBA {
 	path, ok := c.index.ResolvePath(texName)
@@ -39,23 +112,106 @@ func (c *Cache) Resolve(texName string) *image.NRGBA {
 
 	// Fast path: read lock
 	c.mu.RLock()
-	if entry, exists := c.items[path]; exists {
-		c.mu.RUnlock()
-		return entry.img
-	}
+	entry, exists := c.items[path]
 	c.mu.RUnlock()
 
-	// Slow path: load from disk
-	img, _ := LoadTexture(path)
+	if !exists {
+		// Write lock with double-check; the first caller loads, later ones
+		// wait for it below instead of decoding the same file again
+		c.mu.Lock()
+		entry, exists = c.items[path]
+		if !exists {
+			entry = &cacheEntry{ready: make(chan struct{})}
+			c.items[path] = entry
+		}
+		c.mu.Unlock()
+		if !exists {
+			// Closed even if decoding panics, so waiters don't hang
+			defer close(entry.ready)
+			data := c.load(path)
+			c.mu.Lock()
+			entry.data = data
+			if data != nil {
+				entry.img = data.img
+			}
+			entry.elem = c.lru.PushFront(path)
+			c.evict()
+			c.mu.Unlock()
+			return entry.img
+		}
+	}
+	<-entry.ready
+	if c.maxBytes > 0 {
+		c.mu.Lock()
+		if entry.elem != nil {
+			c.lru.MoveToFront(entry.elem)
+		}
+		c.mu.Unlock()
+	}
+	return entry.img
+}
+
+// evict drops least recently used entries until the decoded images fit
+// maxBytes, always keeping the most recent one. An image shared by several
+// paths is freed with the last of them. c.mu must be held.
+func (c *Cache) evict() {
+	if c.maxBytes == 0 {
+		return
+	}
+	for c.used > c.maxBytes && c.lru.Len() > 1 {
+		path := c.lru.Remove(c.lru.Back()).(string)
+		entry := c.items[path]
+		delete(c.items, path)
+		entry.elem = nil
+		if d := entry.data; d != nil {
+			if d.refs--; d.refs == 0 {
+				delete(c.byContent, d.key)
+				c.used -= imageBytes(d.img)
+			}
+		}
+	}
+}
 
-	// Write lock with double-check
+// load reads path and returns its decoded image, reusing the image of an
+// already-decoded file with the same contents. The result is counted as
+// used by one more path; nil means the file couldn't be read.
+func (c *Cache) load(path string) *decoded {
+	raw, release, err := mmap.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	defer release()
+
+	key := contentKey{sum: sha256.Sum256(raw), ext: strings.ToLower(filepath.Ext(path))}
 	c.mu.Lock()
-	if entry, exists := c.items[path]; exists {
+	if d, ok := c.byContent[key]; ok {
+		d.refs++
 		c.mu.Unlock()
-		return entry.img
+		return d
 	}
-	c.items[path] = &cacheEntry{img: img, loaded: true}
 	c.mu.Unlock()
 
-	return img
+	img, _ := decodeTexture(path, raw)
+	if img != nil && c.maxSize > 0 {
+		img = capSize(img, c.maxSize)
+	}
+
+	// Another worker may have decoded the same contents meanwhile
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.byContent[key]
+	if !ok {
+		d = &decoded{img: img, key: key}
+		c.byContent[key] = d
+		c.used += imageBytes(img)
+	}
+	d.refs++
+	return d
+}
+
+func imageBytes(img *image.NRGBA) int {
+	if img == nil {
+		return 0
+	}
+	return len(img.Pix)
 }
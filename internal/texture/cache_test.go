@@ -0,0 +1,199 @@
+package texture
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// solid returns a w×h image of c.
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+// writeTextures writes each image as a top-left 32-bit OZT under
+// itemDir/texture and returns the index over them.
+func writeTextures(t *testing.T, files map[string]*image.NRGBA) *Index {
+	t.Helper()
+	itemDir := t.TempDir()
+	dir := filepath.Join(itemDir, "texture")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, img := range files {
+		data := append([]byte{0, 0, 0, 0}, encodeTGA(img, 32, 0x28, false)...)
+		if err := os.WriteFile(filepath.Join(dir, name+".ozt"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return BuildIndex(itemDir)
+}
+
+// TestCacheSharesIdenticalFiles resolves two differently named files with
+// the same bytes and a third with other bytes: the first two share one
+// decoded image, counted once.
+func TestCacheSharesIdenticalFiles(t *testing.T) {
+	red := solid(8, 8, color.NRGBA{200, 30, 30, 255})
+	c := NewCache(writeTextures(t, map[string]*image.NRGBA{
+		"sword01": red,
+		"sword02": red,
+		"shield":  solid(8, 8, color.NRGBA{30, 30, 200, 255}),
+	}))
+
+	a, b, other := c.Resolve("sword01.tga"), c.Resolve(`Data\Item\sword02.tga`), c.Resolve("shield.tga")
+	if a == nil || b == nil || other == nil {
+		t.Fatal("texture not resolved")
+	}
+	if a != b {
+		t.Error("identical files decoded into separate images")
+	}
+	if a == other {
+		t.Error("different files share an image")
+	}
+	if got, want := c.MemoryUsage(), 2*len(a.Pix); got != want {
+		t.Errorf("memory usage %d, want %d (the shared image once)", got, want)
+	}
+	if c.Resolve("sword02.tga") != a {
+		t.Error("second resolve returned another image")
+	}
+	if c.Resolve("missing.tga") != nil {
+		t.Error("missing texture resolved")
+	}
+}
+
+// TestCacheMaxSize resolves large textures with a 512 px cap: they are
+// stored at the cap on their longer side, keeping their aspect ratio and
+// color, while smaller ones are left alone.
+func TestCacheMaxSize(t *testing.T) {
+	idx := writeTextures(t, map[string]*image.NRGBA{
+		"big":   solid(1024, 1024, color.NRGBA{90, 160, 30, 255}),
+		"wide":  solid(1024, 256, color.NRGBA{90, 160, 30, 255}),
+		"small": solid(64, 32, color.NRGBA{90, 160, 30, 255}),
+	})
+	for _, c := range []struct {
+		maxSize int
+		name    string
+		want    image.Point
+	}{
+		{512, "big", image.Pt(512, 512)},
+		{512, "wide", image.Pt(512, 128)},
+		{512, "small", image.Pt(64, 32)},
+		{0, "big", image.Pt(1024, 1024)},
+	} {
+		cache := NewCache(idx)
+		cache.SetMaxSize(c.maxSize)
+		img := cache.Resolve(c.name + ".tga")
+		if img == nil {
+			t.Fatalf("%s: not resolved", c.name)
+		}
+		if got := img.Bounds().Size(); got != c.want {
+			t.Errorf("cap %d, %s: %v, want %v", c.maxSize, c.name, got, c.want)
+		}
+		if got := img.NRGBAAt(img.Rect.Dx()/2, img.Rect.Dy()/2); got != (color.NRGBA{90, 160, 30, 255}) {
+			t.Errorf("cap %d, %s: center %v, want the texture color", c.maxSize, c.name, got)
+		}
+	}
+}
+
+// TestCacheConcurrentResolve has 32 goroutines resolve the same stem, by
+// several spellings, all at once: every one gets the same decoded image and
+// the cache holds it once. Run with -race, it also checks the locking,
+// including eviction and LRU updates on a cache limited to two textures.
+func TestCacheConcurrentResolve(t *testing.T) {
+	const workers = 32
+	files := map[string]*image.NRGBA{"wing01": solid(64, 64, color.NRGBA{30, 200, 30, 255})}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files[name] = solid(64, 64, color.NRGBA{byte(len(files)), 0, 0, 255})
+	}
+	idx := writeTextures(t, files)
+	names := []string{"wing01.tga", "Wing01.TGA", `Data\Item\wing01.jpg`, "wing01"}
+
+	for _, limit := range []int{0, 2 * 64 * 64 * 4} {
+		c := NewCacheWithLimit(idx, limit)
+		start := make(chan struct{})
+		imgs := make([]*image.NRGBA, workers)
+		var wg sync.WaitGroup
+		for i := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				imgs[i] = c.Resolve(names[i%len(names)])
+				if limit > 0 { // churn the LRU alongside
+					c.Resolve([]string{"a", "b", "c", "d"}[i%4] + ".tga")
+				}
+				c.MemoryUsage()
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		if imgs[0] == nil {
+			t.Fatalf("limit %d: texture not resolved", limit)
+		}
+		if limit == 0 {
+			for i, img := range imgs {
+				if img != imgs[0] {
+					t.Fatalf("limit %d: goroutine %d got another image", limit, i)
+				}
+			}
+			if got := c.MemoryUsage(); got != len(imgs[0].Pix) {
+				t.Errorf("limit %d: memory usage %d, want one image's %d", limit, got, len(imgs[0].Pix))
+			}
+		} else if got := c.MemoryUsage(); got > limit {
+			t.Errorf("limit %d: memory usage %d", limit, got)
+		}
+		for i, img := range imgs {
+			if img == nil || img.Pix[1] != 200 {
+				t.Fatalf("limit %d: goroutine %d got %v", limit, i, img)
+			}
+		}
+	}
+}
+
+// TestCacheEvictsLeastRecent resolves A, B, A again and C with room for two
+// textures: C evicts B, the least recently resolved, while A stays cached
+// (resolving it again returns the same image).
+func TestCacheEvictsLeastRecent(t *testing.T) {
+	idx := writeTextures(t, map[string]*image.NRGBA{
+		"a": solid(16, 16, color.NRGBA{200, 0, 0, 255}),
+		"b": solid(16, 16, color.NRGBA{0, 200, 0, 255}),
+		"c": solid(16, 16, color.NRGBA{0, 0, 200, 255}),
+	})
+	const size = 16 * 16 * 4
+	c := NewCacheWithLimit(idx, 2*size)
+	a := c.Resolve("a.tga")
+	b := c.Resolve("b.tga")
+	c.Resolve("a.tga")
+	c.Resolve("c.tga")
+
+	cached := func(name string) bool {
+		path, _ := idx.ResolvePath(name)
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		_, ok := c.items[path]
+		return ok
+	}
+	if !cached("a.tga") || cached("b.tga") || !cached("c.tga") {
+		t.Errorf("cached a %v, b %v, c %v; want b evicted", cached("a.tga"), cached("b.tga"), cached("c.tga"))
+	}
+	if got := c.MemoryUsage(); got != 2*size {
+		t.Errorf("memory usage %d, want two textures' %d", got, 2*size)
+	}
+	if c.Resolve("a.tga") != a {
+		t.Error("a decoded again")
+	}
+	if c.Resolve("b.tga") == b {
+		t.Error("b not decoded again after eviction")
+	}
+	if cached("c.tga") {
+		t.Error("resolving b again kept c, now the least recent")
+	}
+}
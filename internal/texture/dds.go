@@ -0,0 +1,147 @@
+package texture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// ddsMagic starts a DDS file; OZD files wrap one behind a short header.
+var ddsMagic = []byte("DDS ")
+
+// decodeOZD decodes an OZD texture: a DDS file after a short wrapper header,
+// found by its magic so the exact header length doesn't matter.
+func decodeOZD(raw []byte) (*image.NRGBA, error) {
+	i := bytes.Index(raw[:min(len(raw), 64)], ddsMagic)
+	if i < 0 {
+		return nil, fmt.Errorf("no DDS header")
+	}
+	return decodeDDS(raw[i:])
+}
+
+// decodeDDS decodes the top mip level of a DXT1, DXT3 or DXT5 compressed
+// DDS file (the formats MU's remastered clients ship).
+func decodeDDS(raw []byte) (*image.NRGBA, error) {
+	// "DDS " + 124-byte header; the pixel format's FourCC is at 84
+	if len(raw) < 128 {
+		return nil, fmt.Errorf("DDS too short (%d bytes)", len(raw))
+	}
+	h := int(binary.LittleEndian.Uint32(raw[12:]))
+	w := int(binary.LittleEndian.Uint32(raw[16:]))
+	fourCC := string(raw[84:88])
+
+	var blockSize int
+	var decodeBlock func(block []byte, px *[16][4]uint8)
+	switch fourCC {
+	case "DXT1":
+		blockSize, decodeBlock = 8, decodeDXT1
+	case "DXT3":
+		blockSize, decodeBlock = 16, decodeDXT3
+	case "DXT5":
+		blockSize, decodeBlock = 16, decodeDXT5
+	default:
+		return nil, fmt.Errorf("unsupported DDS format %q (want DXT1, DXT3 or DXT5)", fourCC)
+	}
+	if w <= 0 || h <= 0 || w > 1<<15 || h > 1<<15 {
+		return nil, fmt.Errorf("bad DDS size %dx%d", w, h)
+	}
+	bw, bh := (w+3)/4, (h+3)/4
+	data := raw[128:]
+	if need := bw * bh * blockSize; need > len(data) {
+		return nil, fmt.Errorf("DDS data truncated: %dx%d %s needs %d bytes, have %d", w, h, fourCC, need, len(data))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	var px [16][4]uint8
+	for by := 0; by < bh; by++ {
+		for bx := 0; bx < bw; bx++ {
+			off := (by*bw + bx) * blockSize
+			decodeBlock(data[off:off+blockSize], &px)
+			// Blocks on the right and bottom edges are cut to the image
+			for y := 0; y < 4 && by*4+y < h; y++ {
+				for x := 0; x < 4 && bx*4+x < w; x++ {
+					copy(img.Pix[img.PixOffset(bx*4+x, by*4+y):], px[y*4+x][:])
+				}
+			}
+		}
+	}
+	return img, nil
+}
+
+// decodeDXT1 decodes an 8-byte DXT1 block: two RGB565 endpoints and a 2-bit
+// palette index per pixel. c0 <= c1 selects the 3-color mode, whose fourth
+// entry is transparent black.
+func decodeDXT1(block []byte, px *[16][4]uint8) {
+	decodeColorBlock(block, px, true)
+}
+
+// decodeDXT3 decodes a 16-byte DXT3 block: explicit 4-bit alpha per pixel,
+// then a DXT1 color block (always 4-color).
+func decodeDXT3(block []byte, px *[16][4]uint8) {
+	decodeColorBlock(block[8:], px, false)
+	bits := binary.LittleEndian.Uint64(block)
+	for i := range px {
+		a := uint8(bits>>(4*i)) & 0xf
+		px[i][3] = a<<4 | a
+	}
+}
+
+// decodeDXT5 decodes a 16-byte DXT5 block: two alpha endpoints with a 3-bit
+// index per pixel into 8 interpolated values (or 6 plus 0 and 255 when
+// a0 <= a1), then a DXT1 color block (always 4-color).
+func decodeDXT5(block []byte, px *[16][4]uint8) {
+	decodeColorBlock(block[8:], px, false)
+	a0, a1 := int(block[0]), int(block[1])
+	var alpha [8]uint8
+	alpha[0], alpha[1] = uint8(a0), uint8(a1)
+	if a0 > a1 {
+		for i := 2; i < 8; i++ {
+			alpha[i] = uint8(((8-i)*a0 + (i-1)*a1) / 7)
+		}
+	} else {
+		for i := 2; i < 6; i++ {
+			alpha[i] = uint8(((6-i)*a0 + (i-1)*a1) / 5)
+		}
+		alpha[6], alpha[7] = 0, 255
+	}
+	var bits uint64
+	for i := 7; i >= 2; i-- {
+		bits = bits<<8 | uint64(block[i])
+	}
+	for i := range px {
+		px[i][3] = alpha[bits>>(3*i)&7]
+	}
+}
+
+// decodeColorBlock decodes the 8-byte color part shared by DXT1/3/5 into px,
+// with alpha 255 (0 for the transparent entry when dxt1 allows 3-color mode).
+func decodeColorBlock(block []byte, px *[16][4]uint8, dxt1 bool) {
+	c0 := binary.LittleEndian.Uint16(block)
+	c1 := binary.LittleEndian.Uint16(block[2:])
+	var pal [4][4]uint8
+	pal[0], pal[1] = rgb565(c0), rgb565(c1)
+	for k := 0; k < 3; k++ {
+		e0, e1 := int(pal[0][k]), int(pal[1][k])
+		if c0 > c1 || !dxt1 {
+			pal[2][k] = uint8((2*e0 + e1) / 3)
+			pal[3][k] = uint8((e0 + 2*e1) / 3)
+		} else {
+			pal[2][k] = uint8((e0 + e1) / 2)
+		}
+	}
+	pal[2][3] = 255
+	if c0 > c1 || !dxt1 {
+		pal[3][3] = 255
+	}
+	idx := binary.LittleEndian.Uint32(block[4:])
+	for i := range px {
+		px[i] = pal[idx>>(2*i)&3]
+	}
+}
+
+// rgb565 expands a 16-bit RGB565 color to 8 bits per channel, opaque.
+func rgb565(c uint16) [4]uint8 {
+	r, g, b := uint8(c>>11&0x1f), uint8(c>>5&0x3f), uint8(c&0x1f)
+	return [4]uint8{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2, 255}
+}
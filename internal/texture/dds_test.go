@@ -0,0 +1,159 @@
+package texture
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Known blocks: endpoints red (0xF800) and blue (0x001F), pixels 0-3 using
+// palette entries 0-3 (indices 0xE4) and the rest entry 0.
+var (
+	dxt1FourColor  = []byte{0x00, 0xF8, 0x1F, 0x00, 0xE4, 0, 0, 0}
+	dxt1ThreeColor = []byte{0x1F, 0x00, 0x00, 0xF8, 0xE4, 0, 0, 0}
+)
+
+// decodeBlock returns the first four pixels decode makes of block.
+func decodeBlock(decode func([]byte, *[16][4]uint8), block []byte) [4][4]uint8 {
+	var px [16][4]uint8
+	decode(block, &px)
+	return [4][4]uint8(px[:4])
+}
+
+func TestRGB565(t *testing.T) {
+	for c, want := range map[uint16][4]uint8{
+		0xF800: {255, 0, 0, 255},
+		0x07E0: {0, 255, 0, 255},
+		0x001F: {0, 0, 255, 255},
+		0x8410: {132, 130, 132, 255},
+		0x0000: {0, 0, 0, 255},
+	} {
+		if got := rgb565(c); got != want {
+			t.Errorf("rgb565(%#04x) = %v, want %v", c, got, want)
+		}
+	}
+}
+
+// TestDecodeDXT checks DXT1's 4- and 3-color modes, DXT3's explicit alpha
+// and DXT5's 8- and 6-value alpha ramps against hand-computed pixels.
+func TestDecodeDXT(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		decode func([]byte, *[16][4]uint8)
+		block  []byte
+		want   [4][4]uint8
+	}{
+		{"DXT1 c0 > c1", decodeDXT1, dxt1FourColor,
+			[4][4]uint8{{255, 0, 0, 255}, {0, 0, 255, 255}, {170, 0, 85, 255}, {85, 0, 170, 255}}},
+		{"DXT1 c0 <= c1", decodeDXT1, dxt1ThreeColor,
+			[4][4]uint8{{0, 0, 255, 255}, {255, 0, 0, 255}, {127, 0, 127, 255}, {0, 0, 0, 0}}},
+		// Alpha nibble i for pixel i; the color block is always 4-color
+		{"DXT3", decodeDXT3, append([]byte{0x10, 0x32, 0x54, 0x76, 0x98, 0xBA, 0xDC, 0xFE}, dxt1ThreeColor...),
+			[4][4]uint8{{0, 0, 255, 0}, {255, 0, 0, 17}, {85, 0, 170, 34}, {170, 0, 85, 51}}},
+		// a0 255 > a1 0: indices 0, 1, 2, 7 give 255, 0, 6/7 and 1/7 of 255
+		{"DXT5 8 alphas", decodeDXT5, append([]byte{255, 0, 0x88, 0x0E, 0, 0, 0, 0}, dxt1FourColor...),
+			[4][4]uint8{{255, 0, 0, 255}, {0, 0, 255, 0}, {170, 0, 85, 218}, {85, 0, 170, 36}}},
+		// a0 0 <= a1 200: indices 2, 5, 6, 7 give 1/5 and 4/5 of 200, 0, 255
+		{"DXT5 6 alphas", decodeDXT5, append([]byte{0, 200, 0xAA, 0x0F, 0, 0, 0, 0}, dxt1FourColor...),
+			[4][4]uint8{{255, 0, 0, 40}, {0, 0, 255, 160}, {170, 0, 85, 0}, {85, 0, 170, 255}}},
+	} {
+		if got := decodeBlock(c.decode, c.block); got != c.want {
+			t.Errorf("%s: %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// dds returns a DDS file of w×h with the given FourCC and block data.
+func dds(w, h int, fourCC string, blocks ...[]byte) []byte {
+	raw := make([]byte, 128)
+	copy(raw, ddsMagic)
+	binary.LittleEndian.PutUint32(raw[4:], 124)
+	binary.LittleEndian.PutUint32(raw[12:], uint32(h))
+	binary.LittleEndian.PutUint32(raw[16:], uint32(w))
+	copy(raw[84:], fourCC)
+	for _, b := range blocks {
+		raw = append(raw, b...)
+	}
+	return raw
+}
+
+// TestDecodeDDS decodes a 6×5 DXT1 image of four solid blocks: each lands
+// in its corner, cut to the image on the right and bottom.
+func TestDecodeDDS(t *testing.T) {
+	solidBlock := func(c uint16) []byte { // c0 > c1 = 0, every pixel entry 0
+		return []byte{byte(c), byte(c >> 8), 0, 0, 0, 0, 0, 0}
+	}
+	img, err := decodeDDS(dds(6, 5, "DXT1", solidBlock(0xF800), solidBlock(0x07E0), solidBlock(0x001F), solidBlock(0xFFFF)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 6 || b.Dy() != 5 {
+		t.Fatalf("size %v, want 6×5", b)
+	}
+	for _, c := range []struct {
+		x, y int
+		want [4]uint8
+	}{
+		{0, 0, [4]uint8{255, 0, 0, 255}},
+		{3, 3, [4]uint8{255, 0, 0, 255}},
+		{4, 0, [4]uint8{0, 255, 0, 255}},
+		{0, 4, [4]uint8{0, 0, 255, 255}},
+		{5, 4, [4]uint8{255, 255, 255, 255}},
+	} {
+		if got := [4]uint8(img.Pix[img.PixOffset(c.x, c.y):]); got != c.want {
+			t.Errorf("(%d, %d): %v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+
+	for name, raw := range map[string][]byte{
+		"truncated":   dds(8, 8, "DXT1", dxt1FourColor),
+		"unsupported": dds(4, 4, "ATI2", make([]byte, 16)),
+		"zero size":   dds(0, 4, "DXT1"),
+		"short":       dds(4, 4, "DXT1")[:100],
+	} {
+		if _, err := decodeDDS(raw); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+// TestLoadOZD loads and resolves an OZD file: a DDS behind a wrapper
+// header, indexed by its stem.
+func TestLoadOZD(t *testing.T) {
+	itemDir := t.TempDir()
+	dir := filepath.Join(itemDir, "texture")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "wing40.ozd")
+	raw := append([]byte{1, 0, 0, 0}, dds(4, 4, "DXT5", append([]byte{0, 200, 0xAA, 0x0F, 0, 0, 0, 0}, dxt1FourColor...))...)
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := LoadTexture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := [4]uint8(img.Pix[4:]); got != [4]uint8{0, 0, 255, 160} {
+		t.Errorf("pixel 1: %v, want the DXT5 block's", got)
+	}
+
+	idx := BuildIndex(itemDir)
+	for _, name := range []string{"wing40.dds", "wing40.tga", `Data\Item\Wing40.jpg`} {
+		if got, ok := idx.ResolvePath(name); !ok || got != path {
+			t.Errorf("ResolvePath(%q) = %q, %v; want the OZD", name, got, ok)
+		}
+	}
+
+	if err := os.WriteFile(path, raw[:60], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTexture(path); err == nil || !strings.Contains(err.Error(), "OZD") {
+		t.Errorf("truncated OZD: %v", err)
+	}
+	if _, err := decodeOZD(make([]byte, 200)); err == nil {
+		t.Error("no DDS magic: no error")
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"strings"
 )
 
-// texEntry stores both OZJ and OZT paths for a texture stem.
+// texEntry stores the OZJ, OZT and OZD paths for a texture stem.
 type texEntry struct {
 	ozj string // OZJ (JPEG, no alpha) path
 	ozt string // OZT (TGA, has alpha) path
+	ozd string // OZD (DDS, DXT-compressed) path, from remastered clients
 }
 
 // Index maps lowercase texture stems to filesystem paths.
@@ -19,7 +20,7 @@ type Index struct {
 	entries map[string]*texEntry // stem.lower() → paths
 }
 
-// BuildIndex scans itemDir/texture/ and subdirectories for OZJ/OZT files.
+// BuildIndex scans itemDir/texture/ and subdirectories for OZJ/OZT/OZD files.
 // extraDirs are additional directories to scan for textures (e.g., Data/Skill).
 func BuildIndex(itemDir string, extraDirs ...string) *Index {
 	idx := &Index{entries: make(map[string]*texEntry)}
@@ -57,7 +58,7 @@ func BuildIndex(itemDir string, extraDirs ...string) *Index {
 				return nil
 			}
 			ext := strings.ToLower(filepath.Ext(path))
-			if ext != ".ozj" && ext != ".ozt" {
+			if ext != ".ozj" && ext != ".ozt" && ext != ".ozd" {
 				return nil
 			}
 			stem := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
@@ -71,6 +72,8 @@ func BuildIndex(itemDir string, extraDirs ...string) *Index {
 				entry.ozj = path
 			} else if ext == ".ozt" && entry.ozt == "" {
 				entry.ozt = path
+			} else if ext == ".ozd" && entry.ozd == "" {
+				entry.ozd = path
 			}
 			return nil
 		})
@@ -84,6 +87,8 @@ func BuildIndex(itemDir string, extraDirs ...string) *Index {
 // determines priority: .jpg/.jpeg → OZJ first, .tga → OZT first.
 // This ensures JPEG-referencing meshes get opaque textures and TGA-referencing
 // meshes get alpha-channel textures, matching the model designer's intent.
+// .dds requests prefer OZD; OZD is otherwise the last resort, for stems a
+// remastered client ships only in that form.
 func (idx *Index) ResolvePath(texName string) (string, bool) {
 	// Strip path prefix (e.g., "Monsters\\texture\\foo.jpg" → "foo")
 	texName = strings.ReplaceAll(texName, "\\", "/")
@@ -98,6 +103,16 @@ func (idx *Index) ResolvePath(texName string) (string, bool) {
 
 	// Choose based on requested extension
 	switch reqExt {
+	case ".dds":
+		if entry.ozd != "" {
+			return entry.ozd, true
+		}
+		if entry.ozt != "" {
+			return entry.ozt, true
+		}
+		if entry.ozj != "" {
+			return entry.ozj, true
+		}
 	case ".jpg", ".jpeg":
 		if entry.ozj != "" {
 			return entry.ozj, true
@@ -121,6 +136,9 @@ func (idx *Index) ResolvePath(texName string) (string, bool) {
 			return entry.ozj, true
 		}
 	}
+	if entry.ozd != "" {
+		return entry.ozd, true
+	}
 
 	return "", false
 }
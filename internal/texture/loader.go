@@ -6,19 +6,29 @@ import (
 	"image"
 	"image/draw"
 	"image/jpeg"
-	"os"
 	"strings"
 
+	"mu-bmd-renderer/internal/mmap"
+
 	"github.com/ftrvxmtrx/tga"
+	xdraw "golang.org/x/image/draw"
 )
 
-// LoadTexture reads an OZJ or OZT file and returns an NRGBA image.
+// LoadTexture reads an OZJ, OZT or OZD file and returns an NRGBA image.
+// Rows are always top-down: both TGA decode paths apply the descriptor's
+// origin bits (bottom-left by default) and DDS is stored top-down, so UV
+// v=0 maps to the first row.
 func LoadTexture(path string) (*image.NRGBA, error) {
-	raw, err := os.ReadFile(path)
+	raw, release, err := mmap.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("texture: read %s: %w", path, err)
 	}
+	defer release() // decoders copy into new images
+	return decodeTexture(path, raw)
+}
 
+// decodeTexture decodes the contents raw of the OZJ, OZT or OZD file at path.
+func decodeTexture(path string, raw []byte) (*image.NRGBA, error) {
 	ext := strings.ToLower(path[len(path)-4:])
 	var img image.Image
 
@@ -41,7 +51,20 @@ func LoadTexture(path string) (*image.NRGBA, error) {
 		var err error
 		img, err = tga.Decode(bytes.NewReader(raw[4:]))
 		if err != nil {
-			return nil, fmt.Errorf("texture: decode OZT %s: %w", path, err)
+			// Fall back to the in-house decoder for variants the library
+			// rejects (e.g. 24-bit with alpha bits set in the descriptor)
+			fb, fbErr := decodeTGA(raw[4:])
+			if fbErr != nil {
+				return nil, fmt.Errorf("texture: decode OZT %s: %w", path, err)
+			}
+			img = fb
+		}
+	case ".ozd":
+		// OZD: wrapper header + DDS (DXT1/3/5)
+		var err error
+		img, err = decodeOZD(raw)
+		if err != nil {
+			return nil, fmt.Errorf("texture: decode OZD %s: %w", path, err)
 		}
 	default:
 		return nil, fmt.Errorf("texture: unknown extension: %s", ext)
@@ -67,3 +90,24 @@ func toNRGBA(src image.Image) *image.NRGBA {
 	}
 	return dst
 }
+
+// capSize returns img scaled down to fit maxSize on its longer side, keeping
+// the aspect ratio; img itself if it already fits. CatmullRom on
+// premultiplied values, so transparent texels don't darken their neighbors.
+func capSize(img *image.NRGBA, maxSize int) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+	if w >= h {
+		h = max(1, (h*maxSize+w/2)/w)
+		w = maxSize
+	} else {
+		w = max(1, (w*maxSize+h/2)/h)
+		h = maxSize
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
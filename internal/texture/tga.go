@@ -0,0 +1,151 @@
+package texture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// TGA image types handled by decodeTGA
+const (
+	tgaTrueColor    = 2
+	tgaTrueColorRLE = 10
+)
+
+// decodeTGA is a minimal true-color TGA decoder used when the third-party
+// decoder rejects a file. It handles uncompressed and RLE 15/16/24/32-bit
+// images and honors the descriptor's origin bits, but ignores the extension
+// area and, for 32-bit images, the alpha-bits field: 32-bit pixels always
+// take alpha from the 4th byte, which is how MU's OZT files use it even when
+// the descriptor says otherwise. 16-bit pixels are A1R5G5B5, with the top
+// bit as alpha only when the descriptor declares an alpha bit.
+//
+// The pixel data is checked against the header's size before anything is
+// allocated, so a corrupt header can't ask for gigabytes.
+func decodeTGA(data []byte) (*image.NRGBA, error) {
+	if len(data) < 18 {
+		return nil, errors.New("tga: header too short")
+	}
+	idLen := int(data[0])
+	cmapType := data[1]
+	imgType := data[2]
+	cmapLen := int(binary.LittleEndian.Uint16(data[5:7]))
+	cmapBPP := int(data[7])
+	w := int(binary.LittleEndian.Uint16(data[12:14]))
+	h := int(binary.LittleEndian.Uint16(data[14:16]))
+	bpp := int(data[16])
+	desc := data[17]
+
+	if imgType != tgaTrueColor && imgType != tgaTrueColorRLE {
+		return nil, fmt.Errorf("tga: unsupported image type %d", imgType)
+	}
+	if bpp != 15 && bpp != 16 && bpp != 24 && bpp != 32 {
+		return nil, fmt.Errorf("tga: unsupported %d bits per pixel", bpp)
+	}
+	if w == 0 || h == 0 || w > 1<<15 || h > 1<<15 {
+		return nil, fmt.Errorf("tga: bad size %dx%d", w, h)
+	}
+
+	// Skip image ID and any (unused) color map
+	off := 18 + idLen
+	if cmapType != 0 {
+		off += cmapLen * ((cmapBPP + 7) / 8)
+	}
+	if off > len(data) {
+		return nil, errors.New("tga: truncated header")
+	}
+
+	// The data must be able to hold w×h pixels: all of them stored, or, with
+	// RLE, at most 128 per packet of a header byte and one pixel
+	ps := (bpp + 7) / 8
+	n := w * h
+	src := data[off:]
+	if imgType == tgaTrueColor && len(src) < n*ps {
+		return nil, errors.New("tga: truncated pixel data")
+	}
+	if imgType == tgaTrueColorRLE && len(src)/(1+ps)*128 < n {
+		return nil, errors.New("tga: truncated RLE data")
+	}
+
+	// Unpack pixels in file order (BGR[A] or A1R5G5B5 → RGBA)
+	alpha16 := bpp == 16 && desc&0x0f != 0
+	pix := make([]byte, n*4)
+	put := func(i int, p []byte) {
+		d := pix[i*4 : i*4+4]
+		if ps == 2 {
+			v := uint16(p[0]) | uint16(p[1])<<8
+			d[0], d[1], d[2], d[3] = expand5(v>>10), expand5(v>>5), expand5(v), 255
+			if alpha16 && v&0x8000 == 0 {
+				d[3] = 0
+			}
+			return
+		}
+		d[0], d[1], d[2], d[3] = p[2], p[1], p[0], 255
+		if ps == 4 {
+			d[3] = p[3]
+		}
+	}
+	if imgType == tgaTrueColor {
+		for i := 0; i < n; i++ {
+			put(i, src[i*ps:])
+		}
+	} else {
+		// RLE: packet header high bit = run, low 7 bits = count-1
+		for i := 0; i < n; {
+			if len(src) < 1 {
+				return nil, errors.New("tga: truncated RLE data")
+			}
+			hdr := src[0]
+			src = src[1:]
+			count := int(hdr&0x7f) + 1
+			if i+count > n {
+				count = n - i
+			}
+			if hdr&0x80 != 0 {
+				if len(src) < ps {
+					return nil, errors.New("tga: truncated RLE data")
+				}
+				for j := 0; j < count; j++ {
+					put(i+j, src)
+				}
+				src = src[ps:]
+			} else {
+				if len(src) < count*ps {
+					return nil, errors.New("tga: truncated RLE data")
+				}
+				for j := 0; j < count; j++ {
+					put(i+j, src[j*ps:])
+				}
+				src = src[count*ps:]
+			}
+			i += count
+		}
+	}
+
+	// Reorder rows/columns to top-left origin. Bit 5 set = top row first
+	// (the TGA default is bottom-left); bit 4 set = right-to-left.
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	flipV := desc&0x20 == 0
+	flipH := desc&0x10 != 0
+	for y := 0; y < h; y++ {
+		sy := y
+		if flipV {
+			sy = h - 1 - y
+		}
+		for x := 0; x < w; x++ {
+			sx := x
+			if flipH {
+				sx = w - 1 - x
+			}
+			copy(img.Pix[y*img.Stride+x*4:y*img.Stride+x*4+4], pix[(sy*w+sx)*4:])
+		}
+	}
+	return img, nil
+}
+
+// expand5 widens the low 5 bits of v to 8.
+func expand5(v uint16) uint8 {
+	c := uint8(v & 0x1f)
+	return c<<3 | c>>2
+}
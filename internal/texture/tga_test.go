@@ -0,0 +1,190 @@
+package texture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// tgaTestImage is a 4×2 image with a run of equal pixels (for RLE run
+// packets) and black, white and transparent texels. Its colors survive a
+// round trip through 5 bits per channel.
+func tgaTestImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for i, c := range []color.NRGBA{
+		{255, 0, 0, 255}, {255, 0, 0, 255}, {255, 0, 0, 255}, {0, 255, 0, 255},
+		{0, 0, 255, 255}, {255, 255, 255, 255}, {0, 0, 0, 255}, {255, 255, 0, 0},
+	} {
+		img.SetNRGBA(i%4, i/4, c)
+	}
+	return img
+}
+
+// encodeTGA writes img as a true-color TGA with bpp bits per pixel and
+// descriptor desc, in the row and column order desc's origin bits ask for,
+// run-length encoded if rle is set.
+func encodeTGA(img *image.NRGBA, bpp int, desc byte, rle bool) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	hdr := make([]byte, 18)
+	hdr[2] = tgaTrueColor
+	if rle {
+		hdr[2] = tgaTrueColorRLE
+	}
+	binary.LittleEndian.PutUint16(hdr[12:], uint16(w))
+	binary.LittleEndian.PutUint16(hdr[14:], uint16(h))
+	hdr[16], hdr[17] = byte(bpp), desc
+
+	var pixels [][]byte
+	for i := 0; i < h; i++ {
+		y := h - 1 - i // bottom-left origin
+		if desc&0x20 != 0 {
+			y = i
+		}
+		for j := 0; j < w; j++ {
+			x := j
+			if desc&0x10 != 0 {
+				x = w - 1 - j
+			}
+			c := img.NRGBAAt(x, y)
+			switch bpp {
+			case 15, 16:
+				v := uint16(c.R>>3)<<10 | uint16(c.G>>3)<<5 | uint16(c.B>>3)
+				if c.A >= 128 {
+					v |= 0x8000
+				}
+				pixels = append(pixels, []byte{byte(v), byte(v >> 8)})
+			case 24:
+				pixels = append(pixels, []byte{c.B, c.G, c.R})
+			case 32:
+				pixels = append(pixels, []byte{c.B, c.G, c.R, c.A})
+			}
+		}
+	}
+
+	out := hdr
+	for i := 0; i < len(pixels); {
+		if !rle {
+			out = append(out, pixels[i]...)
+			i++
+			continue
+		}
+		// A run packet for repeated pixels, else a raw packet up to the next run
+		n := 1
+		for i+n < len(pixels) && n < 128 && bytes.Equal(pixels[i+n], pixels[i]) {
+			n++
+		}
+		if n > 1 {
+			out = append(out, 0x80|byte(n-1))
+			out = append(out, pixels[i]...)
+			i += n
+			continue
+		}
+		for i+n < len(pixels) && n < 128 && !bytes.Equal(pixels[i+n], pixels[i+n-1]) {
+			n++
+		}
+		if i+n < len(pixels) {
+			n-- // leave the run's first pixel for the run packet
+		}
+		n = max(n, 1)
+		out = append(out, byte(n-1))
+		for _, p := range pixels[i : i+n] {
+			out = append(out, p...)
+		}
+		i += n
+	}
+	return out
+}
+
+// wantTGA returns img as decodeTGA should read it back at bpp bits per
+// pixel with descriptor desc. img's alpha must be 0 or 255, so a 1-bit alpha
+// keeps it.
+func wantTGA(img *image.NRGBA, bpp int, desc byte) *image.NRGBA {
+	out := image.NewNRGBA(img.Bounds())
+	copy(out.Pix, img.Pix)
+	if bpp == 32 || (bpp == 16 && desc&0x0f != 0) {
+		return out
+	}
+	for i := 3; i < len(out.Pix); i += 4 {
+		out.Pix[i] = 255
+	}
+	return out
+}
+
+func TestDecodeTGA(t *testing.T) {
+	src := tgaTestImage()
+	for _, c := range []struct {
+		bpp  int
+		desc byte
+	}{
+		{24, 0x00},
+		{32, 0x08},
+		{32, 0x00}, // alpha bits unset: MU still keeps the 4th byte
+		{24, 0x08}, // alpha bits on a 24-bit image, which tga.Decode rejects
+		{16, 0x01},
+		{16, 0x00}, // no alpha bit declared: the top bit is ignored
+		{15, 0x00},
+	} {
+		for _, rle := range []bool{false, true} {
+			name := fmt.Sprintf("%d-bit desc %#02x rle %v", c.bpp, c.desc, rle)
+			got, err := decodeTGA(encodeTGA(src, c.bpp, c.desc, rle))
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			if want := wantTGA(src, c.bpp, c.desc); !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("%s:\n got %v\nwant %v", name, got.Pix, want.Pix)
+			}
+		}
+	}
+}
+
+// TestTGAOrigin writes the same image with each origin: both decode paths
+// must return it top-left first.
+func TestTGAOrigin(t *testing.T) {
+	src := tgaTestImage()
+	want := wantTGA(src, 24, 0)
+	for _, desc := range []byte{0x00, 0x10, 0x20, 0x30} {
+		data := encodeTGA(src, 24, desc, false)
+		if got, err := decodeTGA(data); err != nil || !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("decodeTGA desc %#02x: %v\n got %v\nwant %v", desc, err, got, want.Pix)
+		}
+		// tga.Decode, behind LoadTexture
+		got, err := decodeTexture("x.ozt", append([]byte{0, 0, 0, 0}, data...))
+		if err != nil {
+			t.Errorf("OZT desc %#02x: %v", desc, err)
+			continue
+		}
+		if !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("OZT desc %#02x:\n got %v\nwant %v", desc, got.Pix, want.Pix)
+		}
+	}
+}
+
+func TestDecodeTGAErrors(t *testing.T) {
+	valid := encodeTGA(tgaTestImage(), 24, 0, false)
+	huge := bytes.Clone(valid)
+	binary.LittleEndian.PutUint16(huge[12:], 1<<15)
+	binary.LittleEndian.PutUint16(huge[14:], 1<<15)
+	paletted := bytes.Clone(valid)
+	paletted[2] = 1
+	bpp8 := bytes.Clone(valid)
+	bpp8[16] = 8
+	rle := encodeTGA(tgaTestImage(), 24, 0, true)
+
+	for name, data := range map[string][]byte{
+		"short header":    valid[:10],
+		"truncated":       valid[:len(valid)-1],
+		"huge size":       huge,
+		"paletted":        paletted,
+		"8-bit":           bpp8,
+		"truncated RLE":   rle[:len(rle)-1],
+		"RLE header only": rle[:18],
+	} {
+		if _, err := decodeTGA(data); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package texture
+
+import "sync"
+
+// Warm resolves the named textures concurrently, so a model's textures
+// decode side by side instead of one after another as its meshes are drawn.
+// Names are BMD texture references (e.g. "sword04.jpg"); the extension picks
+// OZJ vs OZT the same way Resolve does. Names that resolve to the same file
+// are decoded once. Returns the number of distinct textures warmed.
+//
+// A Cache with a limit (NewCacheWithLimit) is left alone: warming could drop
+// the model's first textures again before its meshes draw them.
+func Warm(cache PathResolver, stems []string, workers int) int {
+	if c, ok := cache.(*Cache); ok && c.maxBytes > 0 {
+		return 0
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range stems {
+		path, ok := cache.ResolvePath(s)
+		if !ok || seen[path] {
+			continue
+		}
+		seen[path] = true
+		names = append(names, s)
+	}
+
+	ch := make(chan string, workers*2)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range ch {
+				cache.Resolve(name)
+			}
+		}()
+	}
+	for _, name := range names {
+		ch <- name
+	}
+	close(ch)
+	wg.Wait()
+
+	return len(names)
+}
@@ -0,0 +1,87 @@
+package texture
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingResolver resolves names by stem and records each Resolve call,
+// holding every call until two have been in flight at once (or a timeout)
+// so overlap shows.
+type countingResolver struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	inFlight int
+	overlap  int // most calls seen in flight at once
+	two      chan struct{}
+	once     sync.Once
+}
+
+func (r *countingResolver) ResolvePath(name string) (string, bool) {
+	stem := strings.ToLower(strings.TrimSuffix(name, name[strings.LastIndex(name, "."):]))
+	return stem, stem != "missing"
+}
+
+func (r *countingResolver) Resolve(name string) *image.NRGBA {
+	path, _ := r.ResolvePath(name)
+	r.mu.Lock()
+	r.calls[path]++
+	r.inFlight++
+	r.overlap = max(r.overlap, r.inFlight)
+	if r.inFlight == 2 {
+		r.once.Do(func() { close(r.two) })
+	}
+	r.mu.Unlock()
+	select {
+	case <-r.two:
+	case <-time.After(2 * time.Second):
+	}
+	r.mu.Lock()
+	r.inFlight--
+	r.mu.Unlock()
+	return nil
+}
+
+// TestWarm warms names where several spell the same file: each file is
+// resolved exactly once, on several workers at a time, and missing names
+// are skipped.
+func TestWarm(t *testing.T) {
+	r := &countingResolver{calls: map[string]int{}, two: make(chan struct{})}
+	n := Warm(r, []string{"sword.jpg", "SWORD.tga", "hilt.jpg", "missing.jpg", "gem.tga", "hilt.JPG"}, 4)
+	if n != 3 {
+		t.Errorf("warmed %d, want 3 distinct textures", n)
+	}
+	for _, path := range []string{"sword", "hilt", "gem"} {
+		if r.calls[path] != 1 {
+			t.Errorf("%s resolved %d times, want once", path, r.calls[path])
+		}
+	}
+	if len(r.calls) != 3 {
+		t.Errorf("resolved %v", r.calls)
+	}
+	if r.overlap < 2 {
+		t.Error("no two textures resolved at the same time")
+	}
+}
+
+// TestWarmCache warms a real cache, which then holds every texture, and a
+// limited one, which Warm leaves alone.
+func TestWarmCache(t *testing.T) {
+	idx := writeTextures(t, map[string]*image.NRGBA{
+		"a": solid(16, 16, color.NRGBA{200, 0, 0, 255}),
+		"b": solid(16, 16, color.NRGBA{0, 200, 0, 255}),
+	})
+	c := NewCache(idx)
+	if n := Warm(c, []string{"a.tga", "b.jpg", "A.jpg"}, 2); n != 2 || c.MemoryUsage() != 2*16*16*4 {
+		t.Errorf("warmed %d, cache holds %d bytes; want 2 textures", n, c.MemoryUsage())
+	}
+
+	limited := NewCacheWithLimit(idx, 1<<20)
+	if n := Warm(limited, []string{"a.tga", "b.jpg"}, 2); n != 0 || limited.MemoryUsage() != 0 {
+		t.Errorf("limited cache: warmed %d, holds %d bytes; want it skipped", n, limited.MemoryUsage())
+	}
+}
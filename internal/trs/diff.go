@@ -0,0 +1,32 @@
+package trs
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangedKeys returns the (section, index) keys whose resolved entry differs
+// between before and after, including keys present in only one of them,
+// sorted by section then index. Comparing resolved entries rather than raw
+// JSON means a changed preset, category, or section default reports exactly
+// the items that inherit it.
+func ChangedKeys(before, after Data) [][2]int {
+	var keys [][2]int
+	for k, a := range after {
+		if b, ok := before[k]; !ok || !reflect.DeepEqual(*a, *b) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
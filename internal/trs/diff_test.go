@@ -0,0 +1,35 @@
+package trs
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestChangedKeys edits a preset, a section default and an item between two
+// custom_trs.json versions: exactly the items resolving through them are
+// reported.
+func TestChangedKeys(t *testing.T) {
+	before := loadCustom(t, `{
+		"presets": {"blade": {"fill_ratio": 0.7}},
+		"items": {"0_0": "blade", "0_1": {"fill_ratio": 0.5}},
+		"sections": {"7": {"scale": 2}},
+		"categories": {"wing": {"scale": 3}}
+	}`)
+	after := loadCustom(t, `{
+		"presets": {"blade": {"fill_ratio": 0.8}},
+		"items": {"0_0": "blade"},
+		"sections": {"7": {"scale": 2.5}},
+		"categories": {"wing": {"scale": 3}}
+	}`)
+
+	want := [][2]int{{0, 0}, {0, 1}, {7, 0}} // preset edited, item removed, section edited
+	if got := ChangedKeys(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("changed keys %v, want %v", got, want)
+	}
+	if got := ChangedKeys(after, before); !reflect.DeepEqual(got, want) {
+		t.Errorf("reversed: changed keys %v, want %v", got, want)
+	}
+	if got := ChangedKeys(after, after); len(got) != 0 {
+		t.Errorf("same data: changed keys %v, want none", got)
+	}
+}
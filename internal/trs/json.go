@@ -0,0 +1,167 @@
+package trs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// entryJSON is Entry in custom_trs.json form. Overrides at their zero value
+// are omitted, so the output reads like a hand-written entry.
+type entryJSON struct {
+	Source           string            `json:"source"`
+	RotX             float64           `json:"rotX"`
+	RotY             float64           `json:"rotY"`
+	RotZ             float64           `json:"rotZ"`
+	Scale            float64           `json:"scale"`
+	Bones            *bool             `json:"bones,omitempty"`
+	Standardize      *bool             `json:"standardize,omitempty"`
+	DisplayAngle     any               `json:"display_angle"`
+	FillRatio        float64           `json:"fill_ratio"`
+	Flip             bool              `json:"flip,omitempty"`
+	NoAutoFlip       *bool             `json:"no_auto_flip,omitempty"`
+	Camera           string            `json:"camera,omitempty"`
+	Perspective      bool              `json:"perspective,omitempty"`
+	FOV              float64           `json:"fov,omitempty"`
+	CamHeight        float64           `json:"cam_height,omitempty"`
+	Projection       string            `json:"projection,omitempty"`
+	KeepAllMeshes    bool              `json:"keep_all_meshes,omitempty"`
+	EffectsAsGlow    bool              `json:"render_effects_as_glow,omitempty"`
+	FlipCanvas       bool              `json:"flip_canvas,omitempty"`
+	BoneFlip         bool              `json:"bone_flip,omitempty"`
+	MirrorPair       bool              `json:"mirror_pair,omitempty"`
+	AdditiveTextures []string          `json:"additive_textures,omitempty"`
+	AdditiveOnTop    bool              `json:"additive_on_top,omitempty"`
+	AdditiveFloor    int               `json:"additive_floor,omitempty"`
+	ExcludeTextures  []string          `json:"exclude_textures,omitempty"`
+	Tint             *[3]float64       `json:"tint,omitempty"`
+	TintTextures     []string          `json:"tint_textures,omitempty"`
+	RenderWidth      int               `json:"render_width,omitempty"`
+	RenderHeight     int               `json:"render_height,omitempty"`
+	PostRotate2D     *float64          `json:"post_rotate,omitempty"`
+	MergeMeshes      bool              `json:"merge_meshes,omitempty"`
+	AbsoluteScale    bool              `json:"absolute_scale,omitempty"`
+	KeepComponents   int               `json:"keep_components,omitempty"`
+	HideMeshIndices  []int             `json:"hide_meshes,omitempty"`
+	LayFlat          bool              `json:"lay_flat,omitempty"`
+	TexBrightness    float64           `json:"tex_brightness,omitempty"`
+	TexContrast      float64           `json:"tex_contrast,omitempty"`
+	TexGamma         float64           `json:"tex_gamma,omitempty"`
+	BodyBones        []int             `json:"body_bones,omitempty"`
+	Promote          *bool             `json:"promote,omitempty"`
+	Pivot            string            `json:"pivot,omitempty"`
+	BlendOverrides   map[string]string `json:"blend_overrides,omitempty"`
+	Lighting         *Lighting         `json:"lighting,omitempty"`
+}
+
+// MarshalJSON encodes e with the keys custom_trs.json uses, plus "source".
+// display_angle is "auto" for AutoDisplayAngle entries (the canonical
+// direction is then the loader default again when read back).
+func (e *Entry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// toJSON returns e in custom_trs.json form (see MarshalJSON).
+func (e *Entry) toJSON() entryJSON {
+	j := entryJSON{
+		Source:           e.Source,
+		RotX:             e.RotX,
+		RotY:             e.RotY,
+		RotZ:             e.RotZ,
+		Scale:            e.Scale,
+		Bones:            e.UseBones,
+		Standardize:      e.Standardize,
+		DisplayAngle:     e.DisplayAngle,
+		FillRatio:        e.FillRatio,
+		Flip:             e.Flip,
+		NoAutoFlip:       e.NoAutoFlip,
+		Camera:           e.Camera,
+		Perspective:      e.Perspective,
+		FOV:              e.FOV,
+		CamHeight:        e.CamHeight,
+		Projection:       e.Projection,
+		KeepAllMeshes:    e.KeepAllMeshes,
+		EffectsAsGlow:    e.EffectsAsGlow,
+		FlipCanvas:       e.FlipCanvas,
+		BoneFlip:         e.BoneFlip,
+		MirrorPair:       e.MirrorPair,
+		AdditiveTextures: e.AdditiveTextures,
+		AdditiveOnTop:    e.AdditiveOnTop,
+		AdditiveFloor:    e.AdditiveFloor,
+		ExcludeTextures:  e.ExcludeTextures,
+		TintTextures:     e.TintTextures,
+		RenderWidth:      e.RenderWidth,
+		RenderHeight:     e.RenderHeight,
+		PostRotate2D:     e.PostRotate2D,
+		MergeMeshes:      e.MergeMeshes,
+		AbsoluteScale:    e.AbsoluteScale,
+		KeepComponents:   e.KeepComponents,
+		HideMeshIndices:  e.HideMeshIndices,
+		LayFlat:          e.LayFlat,
+		TexBrightness:    e.TexBrightness,
+		TexContrast:      e.TexContrast,
+		TexGamma:         e.TexGamma,
+		BodyBones:        e.BodyBones,
+		Promote:          e.Promote,
+		Pivot:            e.Pivot,
+		BlendOverrides:   e.BlendOverrides,
+		Lighting:         e.Lighting,
+	}
+	if e.AutoDisplayAngle {
+		j.DisplayAngle = "auto"
+	}
+	if e.Tint != [3]float64{} {
+		j.Tint = &e.Tint
+	}
+	return j
+}
+
+// exportEntry is one element of Export's array: the item, then its entry's
+// MarshalJSON keys.
+type exportEntry struct {
+	Section int `json:"section"`
+	Index   int `json:"index"`
+	entryJSON
+}
+
+// Export writes data to w as an indented JSON array of its entries, sorted
+// by section then index, each with "section" and "index" ahead of the
+// MarshalJSON keys: a flat table of what every item renders with, for
+// diffing the effect of a custom_trs.json change or flattening a config.
+func Export(data Data, w io.Writer) error {
+	keys := make([][2]int, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	entries := make([]exportEntry, len(keys))
+	for i, k := range keys {
+		entries[i] = exportEntry{Section: k[0], Index: k[1], entryJSON: data[k].toJSON()}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// Hash returns a short hash of e's MarshalJSON form: equal for entries that
+// render alike, and stable across runs (struct fields and map keys encode in
+// a fixed order). A nil entry hashes to "".
+func (e *Entry) Hash() string {
+	if e == nil {
+		return ""
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
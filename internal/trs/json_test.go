@@ -0,0 +1,89 @@
+package trs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestExport exports loaded data: entries come in section, index order
+// (12 after 7, not as strings), with section defaults merged into binary
+// entries and item overrides in place.
+func TestExport(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"sections": {"0": {"fill_ratio": 0.6, "camera": "side"}, "7": {"scale": 2}, "12": {"merge": true, "fov": 30}},
+		"items": {"0_1": {"fill_ratio": 0.3, "display_angle": "auto", "tint": [1, 0.5, 0.5]}}
+	}`, [2]int{12, 0})
+	var buf bytes.Buffer
+	if err := Export(data, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("%v:\n%s", err, buf.String())
+	}
+
+	var keys []string
+	byKey := map[string]map[string]any{}
+	for _, e := range got {
+		k := fmt.Sprintf("%v_%v", e["section"], e["index"])
+		keys = append(keys, k)
+		byKey[k] = e
+	}
+	if want := []string{"0_0", "0_1", "7_0", "12_0"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("exported %v, want %v", keys, want)
+	}
+	for _, c := range []struct {
+		key, field string
+		want       any
+	}{
+		{"0_0", "fill_ratio", 0.6},
+		{"0_0", "camera", "side"},
+		{"0_1", "fill_ratio", 0.3},
+		{"0_1", "display_angle", "auto"},
+		{"0_1", "tint", []any{1.0, 0.5, 0.5}},
+		{"7_0", "scale", 2.0},
+		{"12_0", "source", "binary"},
+		{"12_0", "scale", 1.0},
+		{"12_0", "fov", 30.0},
+	} {
+		if v := byKey[c.key][c.field]; !reflect.DeepEqual(v, c.want) {
+			t.Errorf("%s %s: %v, want %v", c.key, c.field, v, c.want)
+		}
+	}
+	if _, ok := byKey["0_0"]["tint"]; ok {
+		t.Error("0_0: zero tint exported")
+	}
+}
+
+// TestMarshalRoundTrip pastes each exported entry back as an item override:
+// the reloaded entry must be the same apart from its source.
+func TestMarshalRoundTrip(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"sections": {"12": {"merge": true, "fov": 30, "pivot": "centroid"}},
+		"items": {"0_1": {"fill_ratio": 0.3, "post_rotate": 90, "hide_meshes": [2], "lay_flat": true}}
+	}`, [2]int{12, 0})
+	for key, e := range data {
+		raw, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k := fmt.Sprintf("%d_%d", key[0], key[1])
+		again := entry(t, loadCustom(t, fmt.Sprintf(`{"items": {%q: %s}}`, k, raw)), key[0], key[1])
+		again.Source = e.Source
+		if again.Hash() != e.Hash() {
+			t.Errorf("%s: %s reloads as %s", k, raw, mustMarshal(t, again))
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, e *Entry) []byte {
+	t.Helper()
+	raw, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
@@ -3,6 +3,7 @@ package trs
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -11,11 +12,36 @@ import (
 	"strings"
 
 	"mu-bmd-renderer/internal/crypto"
+	"mu-bmd-renderer/internal/itemclass"
 	"mu-bmd-renderer/internal/itemlist"
+	"mu-bmd-renderer/internal/logging"
 )
 
+// Options adjusts LoadWith.
+type Options struct {
+	// Strict makes a missing, unreadable, or malformed custom_trs.json (or
+	// OverrideDir file) an error, for deployments whose renders depend on
+	// the overrides. By default a missing custom_trs.json is skipped
+	// silently and a malformed one with a warning, leaving the binary TRS
+	// alone.
+	Strict bool
+
+	// OverrideDir is a directory of per-item override files applied after
+	// custom_trs.json, "" for none. Each <section>_<index>.json (or a range,
+	// 14_72-77.json) holds one entry as written under "items" (an inline
+	// entry or a custom_trs.json preset name) and replaces the item's entry
+	// the same way, so teams can keep overrides in separate files. A missing
+	// directory or a malformed file is skipped with a warning.
+	OverrideDir string
+}
+
 // Load reads ItemTRSData.bmd and merges custom_trs.json overrides.
 func Load(bmdPath, customJSONPath, itemListXMLPath string) (Data, error) {
+	return LoadWith(bmdPath, customJSONPath, itemListXMLPath, Options{})
+}
+
+// LoadWith is Load with options.
+func LoadWith(bmdPath, customJSONPath, itemListXMLPath string, opts Options) (Data, error) {
 	data := make(Data)
 
 	// Binary TRS
@@ -50,8 +76,36 @@ func Load(bmdPath, customJSONPath, itemListXMLPath string) (Data, error) {
 	}
 
 	// Custom TRS overrides
-	mergeCustomTRS(data, customJSONPath, itemListXMLPath)
+	var file customTRSFile
+	raw, err := os.ReadFile(customJSONPath)
+	if err != nil {
+		if opts.Strict {
+			return nil, fmt.Errorf("custom_trs.json: %w", err)
+		}
+	} else if raw, err = customTRSJSON(customJSONPath, raw); err != nil {
+		if opts.Strict {
+			return nil, err
+		}
+		logging.Default().Warnf("%v", err)
+	} else if file, err = mergeCustomTRS(data, raw, itemListXMLPath); err != nil {
+		if opts.Strict {
+			return nil, err
+		}
+		logging.Default().Warnf("%v", err)
+	}
 
+	// Per-item override files
+	if opts.OverrideDir != "" {
+		errs := mergeOverrideDir(data, opts.OverrideDir, file)
+		if opts.Strict && len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		for _, err := range errs {
+			logging.Default().Warnf("%v", err)
+		}
+	}
+
+	data.applyScaleMults()
 	return data, nil
 }
 
@@ -59,6 +113,7 @@ func Load(bmdPath, customJSONPath, itemListXMLPath string) (Data, error) {
 type customTRSFile struct {
 	Resolution map[string]resolutionEntry `json:"resolution"`
 	Presets    map[string]json.RawMessage `json:"presets"`
+	Categories map[string]json.RawMessage `json:"categories"`
 	Sections   map[string]json.RawMessage `json:"sections"`
 	Models     map[string]json.RawMessage `json:"models"`
 	Items      map[string]json.RawMessage `json:"items"`
@@ -74,17 +129,21 @@ type customTRSEntry struct {
 	RotY         *float64 `json:"rotY"`
 	RotZ         *float64 `json:"rotZ"`
 	Scale        *float64 `json:"scale"`
+	ScaleMult    *scaleMult `json:"scale_mult"`
 	Bones        *bool    `json:"bones"`
 	Override     *bool    `json:"override"`
 	Standardize  *bool    `json:"standardize"`
-	DisplayAngle *float64 `json:"display_angle"`
+	DisplayAngle *angleSetting `json:"display_angle"`
 	FillRatio    *float64 `json:"fill_ratio"`
 	Flip         *bool    `json:"flip"`
+	NoAutoFlip   *bool    `json:"no_auto_flip"`
 	Camera       *string  `json:"camera"`
 	Perspective    *bool    `json:"perspective"`
 	FOV            *float64 `json:"fov"`
 	CamHeight      *float64 `json:"cam_height"`
+	Projection     *projectionSetting `json:"projection"`
 	KeepAllMeshes    *bool             `json:"keep_all_meshes"`
+	EffectsAsGlow    *bool             `json:"render_effects_as_glow"`
 	FlipCanvas       *bool             `json:"flip_canvas"`
 	BoneFlip         *bool             `json:"bone_flip"`
 	MirrorPair       *bool             `json:"mirror_pair"`
@@ -94,14 +153,181 @@ type customTRSEntry struct {
 	ExcludeTextures  []string          `json:"exclude_textures"`
 	Tint             []float64         `json:"tint"`
 	TintTextures     []string          `json:"tint_textures"`
+	RenderSize       *int              `json:"render_size"` // square shorthand for render_width and render_height
 	RenderWidth      *int              `json:"render_width"`
 	RenderHeight     *int              `json:"render_height"`
+	PostRotate2D     *float64          `json:"post_rotate"`
+	MergeMeshes      *bool             `json:"merge_meshes"`
+	AbsoluteScale    *bool             `json:"absolute_scale"`
+	KeepComponents   *int              `json:"keep_components"`
+	HideMeshIndices  []int             `json:"hide_meshes"`
+	LayFlat          *bool             `json:"lay_flat"`
+	TexBrightness    *float64          `json:"tex_brightness"`
+	TexContrast      *float64          `json:"tex_contrast"`
+	TexGamma         *float64          `json:"tex_gamma"`
+	BodyBones        []int             `json:"body_bones"`
+	Promote          *bool             `json:"promote"`
+	Pivot            *pivotSetting     `json:"pivot"`
+	BlendOverrides   blendOverrides    `json:"blend_overrides"`
+	Lighting         *lightingSetting  `json:"lighting"`
 	Resolution       *string           `json:"resolution"`
 	Merge            *bool             `json:"merge"`
 }
 
-// parseItemKeys parses "section_index" or "section_start-end" into key pairs.
-func parseItemKeys(keyStr string) [][2]int {
+// angleSetting is display_angle in custom_trs.json: degrees, or "auto".
+type angleSetting struct {
+	deg  float64
+	auto bool
+}
+
+func (a *angleSetting) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		if s != "auto" {
+			return fmt.Errorf(`display_angle: want a number or "auto", got %q`, s)
+		}
+		a.auto = true
+		return nil
+	}
+	return json.Unmarshal(b, &a.deg)
+}
+
+// apply sets e's display angle. "auto" keeps the current angle as the
+// canonical direction (DefaultDisplayAngle unless set elsewhere).
+func (a *angleSetting) apply(e *Entry) {
+	e.AutoDisplayAngle = a.auto
+	if !a.auto {
+		e.DisplayAngle = a.deg
+	}
+}
+
+// pivotSetting is pivot in custom_trs.json: "bbox", "centroid", or
+// "bone:" followed by a bone name or index.
+type pivotSetting string
+
+func (p *pivotSetting) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("pivot: %w", err)
+	}
+	if s != "bbox" && s != "centroid" && (!strings.HasPrefix(s, "bone:") || len(s) == len("bone:")) {
+		return fmt.Errorf(`pivot: want "bbox", "centroid", or "bone:<name>", got %q`, s)
+	}
+	if s == "bbox" {
+		s = ""
+	}
+	*p = pivotSetting(s)
+	return nil
+}
+
+// projectionSetting is projection in custom_trs.json: "ortho" (or
+// "orthographic") or "perspective".
+type projectionSetting string
+
+func (p *projectionSetting) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("projection: %w", err)
+	}
+	if s == "orthographic" {
+		s = "ortho"
+	}
+	if s != "ortho" && s != "perspective" {
+		return fmt.Errorf(`projection: want "ortho" or "perspective", got %q`, s)
+	}
+	*p = projectionSetting(s)
+	return nil
+}
+
+// scaleMult is scale_mult in custom_trs.json: a positive factor on the
+// resolved scale (omitted = 1).
+type scaleMult float64
+
+func (m *scaleMult) UnmarshalJSON(b []byte) error {
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("scale_mult: %w", err)
+	}
+	if f <= 0 {
+		return fmt.Errorf("scale_mult: want a positive factor, got %g", f)
+	}
+	*m = scaleMult(f)
+	return nil
+}
+
+// blendOverrides is blend_overrides in custom_trs.json: texture stem →
+// "opaque", "alpha", or "additive". Stems are stored lowercase, as they
+// match case-insensitively.
+type blendOverrides map[string]string
+
+func (o *blendOverrides) UnmarshalJSON(b []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("blend_overrides: %w", err)
+	}
+	*o = make(blendOverrides, len(m))
+	for stem, mode := range m {
+		if mode != "opaque" && mode != "alpha" && mode != "additive" {
+			return fmt.Errorf(`blend_overrides: %s: want "opaque", "alpha", or "additive", got %q`, stem, mode)
+		}
+		(*o)[strings.ToLower(stem)] = mode
+	}
+	return nil
+}
+
+// lightingSetting is lighting in custom_trs.json: any of ambient, direct,
+// rim, exposure, spec_int, and spec_pow, none negative (spec_pow positive).
+type lightingSetting Lighting
+
+func (l *lightingSetting) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*Lighting)(l)); err != nil {
+		return fmt.Errorf("lighting: %w", err)
+	}
+	for _, f := range []struct {
+		name string
+		v    *float64
+	}{{"ambient", l.Ambient}, {"direct", l.Direct}, {"rim", l.Rim}, {"exposure", l.Exposure}, {"spec_int", l.SpecInt}} {
+		if f.v != nil && *f.v < 0 {
+			return fmt.Errorf("lighting: %s must not be negative, got %g", f.name, *f.v)
+		}
+	}
+	if l.SpecPow != nil && *l.SpecPow <= 0 {
+		return fmt.Errorf("lighting: spec_pow must be positive, got %g", *l.SpecPow)
+	}
+	return nil
+}
+
+// merge returns a copy of base (nil = all defaults) with the fields l sets,
+// so a merge entry can change one knob and keep the rest.
+func (l *lightingSetting) merge(base *Lighting) *Lighting {
+	var out Lighting
+	if base != nil {
+		out = *base
+	}
+	if l.Ambient != nil {
+		out.Ambient = l.Ambient
+	}
+	if l.Direct != nil {
+		out.Direct = l.Direct
+	}
+	if l.Rim != nil {
+		out.Rim = l.Rim
+	}
+	if l.Exposure != nil {
+		out.Exposure = l.Exposure
+	}
+	if l.SpecInt != nil {
+		out.SpecInt = l.SpecInt
+	}
+	if l.SpecPow != nil {
+		out.SpecPow = l.SpecPow
+	}
+	return &out
+}
+
+// ParseItemKeys parses "section_index" or "section_start-end" into key pairs.
+// It returns nil if keyStr is malformed.
+func ParseItemKeys(keyStr string) [][2]int {
 	parts := strings.SplitN(keyStr, "_", 2)
 	if len(parts) != 2 {
 		return nil
@@ -150,6 +376,9 @@ func makeEntry(c customTRSEntry) *Entry {
 	if c.Scale != nil {
 		e.Scale = *c.Scale
 	}
+	if c.ScaleMult != nil {
+		e.multiplyScale(float64(*c.ScaleMult))
+	}
 	if c.Bones != nil {
 		e.UseBones = c.Bones
 	}
@@ -157,7 +386,7 @@ func makeEntry(c customTRSEntry) *Entry {
 		e.Standardize = c.Standardize
 	}
 	if c.DisplayAngle != nil {
-		e.DisplayAngle = *c.DisplayAngle
+		c.DisplayAngle.apply(e)
 	}
 	if c.FillRatio != nil {
 		e.FillRatio = *c.FillRatio
@@ -165,6 +394,9 @@ func makeEntry(c customTRSEntry) *Entry {
 	if c.Flip != nil {
 		e.Flip = *c.Flip
 	}
+	if c.NoAutoFlip != nil {
+		e.NoAutoFlip = c.NoAutoFlip
+	}
 	if c.Camera != nil {
 		e.Camera = *c.Camera
 	}
@@ -177,9 +409,15 @@ func makeEntry(c customTRSEntry) *Entry {
 	if c.CamHeight != nil {
 		e.CamHeight = *c.CamHeight
 	}
+	if c.Projection != nil {
+		e.Projection = string(*c.Projection)
+	}
 	if c.KeepAllMeshes != nil {
 		e.KeepAllMeshes = *c.KeepAllMeshes
 	}
+	if c.EffectsAsGlow != nil {
+		e.EffectsAsGlow = *c.EffectsAsGlow
+	}
 	if c.FlipCanvas != nil {
 		e.FlipCanvas = *c.FlipCanvas
 	}
@@ -213,6 +451,48 @@ func makeEntry(c customTRSEntry) *Entry {
 	if c.RenderHeight != nil {
 		e.RenderHeight = *c.RenderHeight
 	}
+	if c.PostRotate2D != nil {
+		e.PostRotate2D = c.PostRotate2D
+	}
+	if c.MergeMeshes != nil {
+		e.MergeMeshes = *c.MergeMeshes
+	}
+	if c.AbsoluteScale != nil {
+		e.AbsoluteScale = *c.AbsoluteScale
+	}
+	if c.KeepComponents != nil {
+		e.KeepComponents = *c.KeepComponents
+	}
+	if len(c.HideMeshIndices) > 0 {
+		e.HideMeshIndices = c.HideMeshIndices
+	}
+	if c.LayFlat != nil {
+		e.LayFlat = *c.LayFlat
+	}
+	if c.TexBrightness != nil {
+		e.TexBrightness = *c.TexBrightness
+	}
+	if c.TexContrast != nil {
+		e.TexContrast = *c.TexContrast
+	}
+	if c.TexGamma != nil {
+		e.TexGamma = *c.TexGamma
+	}
+	if len(c.BodyBones) > 0 {
+		e.BodyBones = c.BodyBones
+	}
+	if c.Promote != nil {
+		e.Promote = c.Promote
+	}
+	if c.Pivot != nil {
+		e.Pivot = string(*c.Pivot)
+	}
+	if len(c.BlendOverrides) > 0 {
+		e.BlendOverrides = c.BlendOverrides
+	}
+	if c.Lighting != nil {
+		e.Lighting = c.Lighting.merge(nil)
+	}
 	return e
 }
 
@@ -231,6 +511,11 @@ func mergeEntryFields(existing *Entry, c customTRSEntry) {
 	if c.Scale != nil {
 		existing.Scale = *c.Scale
 	}
+	// Joins the factors already there (an earlier category's), all applied
+	// to the scale the entry ends up with (see applyScaleMults)
+	if c.ScaleMult != nil {
+		existing.multiplyScale(float64(*c.ScaleMult))
+	}
 	if c.Bones != nil {
 		existing.UseBones = c.Bones
 	}
@@ -238,7 +523,7 @@ func mergeEntryFields(existing *Entry, c customTRSEntry) {
 		existing.Standardize = c.Standardize
 	}
 	if c.DisplayAngle != nil {
-		existing.DisplayAngle = *c.DisplayAngle
+		c.DisplayAngle.apply(existing)
 	}
 	if c.FillRatio != nil {
 		existing.FillRatio = *c.FillRatio
@@ -246,6 +531,9 @@ func mergeEntryFields(existing *Entry, c customTRSEntry) {
 	if c.Flip != nil {
 		existing.Flip = *c.Flip
 	}
+	if c.NoAutoFlip != nil {
+		existing.NoAutoFlip = c.NoAutoFlip
+	}
 	if c.Camera != nil {
 		existing.Camera = *c.Camera
 	}
@@ -258,9 +546,15 @@ func mergeEntryFields(existing *Entry, c customTRSEntry) {
 	if c.CamHeight != nil {
 		existing.CamHeight = *c.CamHeight
 	}
+	if c.Projection != nil {
+		existing.Projection = string(*c.Projection)
+	}
 	if c.KeepAllMeshes != nil {
 		existing.KeepAllMeshes = *c.KeepAllMeshes
 	}
+	if c.EffectsAsGlow != nil {
+		existing.EffectsAsGlow = *c.EffectsAsGlow
+	}
 	if c.FlipCanvas != nil {
 		existing.FlipCanvas = *c.FlipCanvas
 	}
@@ -294,6 +588,48 @@ func mergeEntryFields(existing *Entry, c customTRSEntry) {
 	if c.RenderHeight != nil {
 		existing.RenderHeight = *c.RenderHeight
 	}
+	if c.PostRotate2D != nil {
+		existing.PostRotate2D = c.PostRotate2D
+	}
+	if c.MergeMeshes != nil {
+		existing.MergeMeshes = *c.MergeMeshes
+	}
+	if c.AbsoluteScale != nil {
+		existing.AbsoluteScale = *c.AbsoluteScale
+	}
+	if c.KeepComponents != nil {
+		existing.KeepComponents = *c.KeepComponents
+	}
+	if len(c.HideMeshIndices) > 0 {
+		existing.HideMeshIndices = c.HideMeshIndices
+	}
+	if c.LayFlat != nil {
+		existing.LayFlat = *c.LayFlat
+	}
+	if c.TexBrightness != nil {
+		existing.TexBrightness = *c.TexBrightness
+	}
+	if c.TexContrast != nil {
+		existing.TexContrast = *c.TexContrast
+	}
+	if c.TexGamma != nil {
+		existing.TexGamma = *c.TexGamma
+	}
+	if len(c.BodyBones) > 0 {
+		existing.BodyBones = c.BodyBones
+	}
+	if c.Promote != nil {
+		existing.Promote = c.Promote
+	}
+	if c.Pivot != nil {
+		existing.Pivot = string(*c.Pivot)
+	}
+	if len(c.BlendOverrides) > 0 {
+		existing.BlendOverrides = c.BlendOverrides
+	}
+	if c.Lighting != nil {
+		existing.Lighting = c.Lighting.merge(existing.Lighting)
+	}
 }
 
 // resolveEntry resolves a json.RawMessage that is either a preset name (string)
@@ -312,6 +648,7 @@ func resolveEntry(raw json.RawMessage, presets map[string]json.RawMessage, resol
 	if err := json.Unmarshal(raw, &c); err != nil {
 		return nil, err
 	}
+	c.expandRenderSize()
 	// Resolve render_preset: pull render_width/render_height from resolution group
 	if c.Resolution != nil {
 		if res, ok := resolutions[*c.Resolution]; ok {
@@ -328,24 +665,105 @@ func resolveEntry(raw json.RawMessage, presets map[string]json.RawMessage, resol
 	return &c, nil
 }
 
-func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
-	raw, err := os.ReadFile(jsonPath)
-	if err != nil {
+// expandRenderSize sets whichever of render_width and render_height c leaves
+// unset to its render_size, so the shorthand loses to an explicit side and
+// beats a resolution group.
+func (c *customTRSEntry) expandRenderSize() {
+	if c.RenderSize == nil {
 		return
 	}
+	if c.RenderWidth == nil {
+		c.RenderWidth = c.RenderSize
+	}
+	if c.RenderHeight == nil {
+		c.RenderHeight = c.RenderSize
+	}
+}
+
+// applyGroupEntry applies a section or category config to every matching item:
+// items without an entry get a copy of it if create is set; existing entries
+// are left alone unless the config sets "merge" (only specified fields) or
+// "override" (replace entirely).
+func applyGroupEntry(data Data, items []itemlist.ItemDef, c customTRSEntry, match func(itemlist.ItemDef) bool, create bool) {
+	override := c.Override != nil && *c.Override
+	merge := c.Merge != nil && *c.Merge
+	entry := makeEntry(c)
+
+	for _, item := range items {
+		if !match(item) {
+			continue
+		}
+		key := [2]int{item.Section, item.Index}
+		existing := data[key]
+
+		if existing == nil {
+			if !create {
+				continue
+			}
+			// No entry yet: create from group config
+			entryCopy := *entry
+			data[key] = &entryCopy
+		} else if merge {
+			// Merge: only override specified (non-nil) fields
+			mergeEntryFields(existing, c)
+		} else if override {
+			// Override: replace entirely
+			entryCopy := *entry
+			data[key] = &entryCopy
+		}
+	}
+}
+
+// fillGroupEntry gives each matching item that has no entry yet a copy of
+// the group config c.
+func fillGroupEntry(data Data, items []itemlist.ItemDef, c customTRSEntry, match func(itemlist.ItemDef) bool) {
+	entry := makeEntry(c)
+	for _, item := range items {
+		key := [2]int{item.Section, item.Index}
+		if match(item) && data[key] == nil {
+			entryCopy := *entry
+			data[key] = &entryCopy
+		}
+	}
+}
 
+// mergeCustomTRS applies custom_trs.json (raw) to data and returns the parsed
+// file. It returns an error, leaving data unchanged, only if the file doesn't
+// parse; entries that don't resolve are skipped.
+func mergeCustomTRS(data Data, raw []byte, xmlPath string) (customTRSFile, error) {
 	var file customTRSFile
 	if err := json.Unmarshal(raw, &file); err != nil {
-		fmt.Printf("Warning: custom_trs.json parse error: %v\n", err)
-		return
+		return customTRSFile{}, fmt.Errorf("custom_trs.json parse error: %w", err)
 	}
 
 	// Parse itemlist once for sections and models lookups
 	var items []itemlist.ItemDef
-	if len(file.Sections) > 0 || len(file.Models) > 0 {
+	if len(file.Sections) > 0 || len(file.Models) > 0 || len(file.Categories) > 0 {
 		items, _ = itemlist.Parse(xmlPath)
 	}
 
+	// Category defaults (from the model file name) rank below sections:
+	// merge/override touch existing (binary) entries before sections do, and
+	// items still without an entry after sections get the category's
+	type categoryEntry struct {
+		c     customTRSEntry
+		match func(itemlist.ItemDef) bool
+	}
+	var categories []categoryEntry
+	for catStr, rawEntry := range file.Categories {
+		cat := itemclass.Category(strings.ToLower(catStr))
+		c, err := resolveEntry(rawEntry, file.Presets, file.Resolution)
+		if err != nil {
+			continue
+		}
+		categories = append(categories, categoryEntry{*c, func(item itemlist.ItemDef) bool {
+			return item.Category == cat
+		}})
+	}
+	for _, ce := range categories {
+		applyGroupEntry(data, items, ce.c, ce.match, false)
+	}
+
 	// Section defaults/overrides
 	for secStr, rawEntry := range file.Sections {
 		sec, err := strconv.Atoi(secStr)
@@ -356,30 +774,12 @@ func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
 		if err != nil {
 			continue
 		}
-		override := c.Override != nil && *c.Override
-		merge := c.Merge != nil && *c.Merge
-		entry := makeEntry(*c)
-
-		for _, item := range items {
-			if item.Section != sec {
-				continue
-			}
-			key := [2]int{sec, item.Index}
-			existing := data[key]
-
-			if existing == nil {
-				// No binary TRS: create from section config
-				entryCopy := *entry
-				data[key] = &entryCopy
-			} else if merge {
-				// Merge: only override specified (non-nil) fields
-				mergeEntryFields(existing, *c)
-			} else if override {
-				// Override: replace entirely
-				entryCopy := *entry
-				data[key] = &entryCopy
-			}
-		}
+		applyGroupEntry(data, items, *c, func(item itemlist.ItemDef) bool {
+			return item.Section == sec
+		}, true)
+	}
+	for _, ce := range categories {
+		fillGroupEntry(data, items, ce.c, ce.match)
 	}
 
 	// Model overrides (override sections and binary)
@@ -398,6 +798,7 @@ func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
 			if json.Unmarshal(presetRaw, &c) != nil {
 				continue
 			}
+			c.expandRenderSize()
 			entry := makeEntry(c)
 			for _, mf := range modelFiles {
 				for _, item := range items {
@@ -424,28 +825,7 @@ func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
 		}
 	}
 
-	// Build section render dimensions map for inheritance
-	sectionRenderDims := make(map[int][2]int) // sec → [width, height]
-	for secStr, rawEntry := range file.Sections {
-		sec, err := strconv.Atoi(secStr)
-		if err != nil {
-			continue
-		}
-		c, err := resolveEntry(rawEntry, file.Presets, file.Resolution)
-		if err != nil {
-			continue
-		}
-		var w, h int
-		if c.RenderWidth != nil {
-			w = *c.RenderWidth
-		}
-		if c.RenderHeight != nil {
-			h = *c.RenderHeight
-		}
-		if w > 0 || h > 0 {
-			sectionRenderDims[sec] = [2]int{w, h}
-		}
-	}
+	secs := sectionDefaults(file)
 
 	// Per-item overrides (always win)
 	// Keys support range syntax: "14_72-77" expands to 14_72, 14_73, ... 14_77
@@ -457,7 +837,7 @@ func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
 	}
 	itemEntries := make([]itemEntry, 0, len(file.Items))
 	for keyStr, rawEntry := range file.Items {
-		keys := parseItemKeys(keyStr)
+		keys := ParseItemKeys(keyStr)
 		if keys == nil {
 			continue
 		}
@@ -467,23 +847,100 @@ func mergeCustomTRS(data Data, jsonPath, xmlPath string) {
 		return itemEntries[i].size > itemEntries[j].size
 	})
 	for _, ie := range itemEntries {
-		keys := parseItemKeys(ie.keyStr)
+		keys := ParseItemKeys(ie.keyStr)
 		c, err := resolveEntry(ie.raw, file.Presets, file.Resolution)
 		if err != nil {
 			continue
 		}
-		for _, key := range keys {
-			entry := makeEntry(*c)
-			// Inherit render dimensions from section if not set by per-item config
-			if dims, ok := sectionRenderDims[key[0]]; ok {
-				if c.RenderWidth == nil && dims[0] > 0 {
-					entry.RenderWidth = dims[0]
-				}
-				if c.RenderHeight == nil && dims[1] > 0 {
-					entry.RenderHeight = dims[1]
+		applyItemEntry(data, keys, *c, secs)
+	}
+	return file, nil
+}
+
+// sectionDefault is what a section of custom_trs.json passes on to the
+// per-item entries that replace its items' entries: its render size (0 =
+// unset), its scale_mult (0 = none), and its lighting (nil = none).
+type sectionDefault struct {
+	width, height int
+	scaleMult     float64
+	lighting      *lightingSetting
+}
+
+// sectionDefaults returns the sectionDefault of each section of file that
+// sets any of it.
+func sectionDefaults(file customTRSFile) map[int]sectionDefault {
+	defaults := make(map[int]sectionDefault)
+	for secStr, rawEntry := range file.Sections {
+		sec, err := strconv.Atoi(secStr)
+		if err != nil {
+			continue
+		}
+		c, err := resolveEntry(rawEntry, file.Presets, file.Resolution)
+		if err != nil {
+			continue
+		}
+		var d sectionDefault
+		if c.RenderWidth != nil {
+			d.width = *c.RenderWidth
+		}
+		if c.RenderHeight != nil {
+			d.height = *c.RenderHeight
+		}
+		if c.ScaleMult != nil {
+			d.scaleMult = float64(*c.ScaleMult)
+		}
+		d.lighting = c.Lighting
+		if d != (sectionDefault{}) {
+			defaults[sec] = d
+		}
+	}
+	return defaults
+}
+
+// applyItemEntry replaces the entries of keys with c, inheriting the section's
+// render dimensions where c doesn't set its own, the section's scale_mult on
+// top of c's, and the section's lighting fields c's lighting leaves unset.
+func applyItemEntry(data Data, keys [][2]int, c customTRSEntry, secs map[int]sectionDefault) {
+	for _, key := range keys {
+		entry := makeEntry(c)
+		if d, ok := secs[key[0]]; ok {
+			if c.RenderWidth == nil && d.width > 0 {
+				entry.RenderWidth = d.width
+			}
+			if c.RenderHeight == nil && d.height > 0 {
+				entry.RenderHeight = d.height
+			}
+			if d.scaleMult > 0 {
+				entry.multiplyScale(d.scaleMult)
+			}
+			if d.lighting != nil {
+				entry.Lighting = d.lighting.merge(nil)
+				if c.Lighting != nil {
+					entry.Lighting = c.Lighting.merge(entry.Lighting)
 				}
 			}
-			data[key] = entry
 		}
+		data[key] = entry
+	}
+}
+
+// multiplyScale adds a scale_mult factor to e's, applied once loading is
+// done (see applyScaleMults).
+func (e *Entry) multiplyScale(f float64) {
+	if e.scaleMult == 0 {
+		e.scaleMult = 1
+	}
+	e.scaleMult *= f
+}
+
+// applyScaleMults multiplies each entry's scale by the scale_mult factors
+// its layers (preset, category, section, item) collected, now that the
+// scale itself is final. An unset scale (0, auto-fit) stays unset.
+func (d Data) applyScaleMults() {
+	for _, e := range d {
+		if e.scaleMult != 0 && e.Scale > 0 {
+			e.Scale *= e.scaleMult
+		}
+		e.scaleMult = 0
 	}
 }
@@ -0,0 +1,538 @@
+package trs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/crypto"
+	"mu-bmd-renderer/internal/logging"
+)
+
+// testItemList is the ItemList.xml loadCustom hands to Load: one item in a
+// few sections and categories.
+const testItemList = `<ItemList>
+  <Section Index="0"><Item Index="0" Name="Kris" ModelFile="Sword01.bmd"/><Item Index="1" Name="Blade" ModelFile="Sword02.bmd"/></Section>
+  <Section Index="7"><Item Index="0" Name="Bronze Helm" ModelFile="HelmMale01.bmd"/></Section>
+  <Section Index="12"><Item Index="0" Name="Wings of Elf" ModelFile="Wing01.bmd"/></Section>
+</ItemList>`
+
+// loadCustom loads custom_trs.json content custom (with no binary TRS) over
+// testItemList.
+func loadCustom(t *testing.T, custom string) Data {
+	return loadWithBinary(t, custom)
+}
+
+// loadWithBinary is loadCustom with an ItemTRSData.bmd holding an entry
+// (scale 1, no rotation) for each of keys.
+func loadWithBinary(t *testing.T, custom string, keys ...[2]int) Data {
+	t.Helper()
+	bmdPath, customPath, xmlPath := writeTRSFiles(t, custom, keys...)
+	data, err := LoadWith(bmdPath, customPath, xmlPath, Options{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// writeTRSFiles writes the files loadWithBinary loads to a temporary
+// directory and returns their paths.
+func writeTRSFiles(t *testing.T, custom string, keys ...[2]int) (bmdPath, customPath, xmlPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	bmdPath = filepath.Join(dir, "ItemTRSData.bmd")
+	customPath = filepath.Join(dir, "custom_trs.json")
+	xmlPath = filepath.Join(dir, "ItemList.xml")
+	if len(keys) > 0 {
+		raw := binary.LittleEndian.AppendUint32(nil, uint32(len(keys)))
+		for _, k := range keys {
+			rec := make([]byte, 32)
+			binary.LittleEndian.PutUint32(rec, uint32(k[0]*512+k[1]))
+			binary.LittleEndian.PutUint32(rec[28:], math.Float32bits(1))
+			raw = append(raw, crypto.DecryptTRS(rec)...) // XOR: its own inverse
+		}
+		if err := os.WriteFile(bmdPath, raw, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(customPath, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(xmlPath, []byte(testItemList), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// entry returns data's entry for section_index, failing if there is none.
+func entry(t *testing.T, data Data, section, index int) *Entry {
+	t.Helper()
+	e := data[[2]int{section, index}]
+	if e == nil {
+		t.Fatalf("no entry for %d_%d", section, index)
+	}
+	return e
+}
+
+func TestPostRotateField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"post_rotate": 90}, "0_1": {"fill_ratio": 0.5}}}`)
+	if e := entry(t, data, 0, 0); e.PostRotate2D == nil || *e.PostRotate2D != 90 {
+		t.Errorf("0_0: PostRotate2D = %v, want 90", e.PostRotate2D)
+	}
+	if e := entry(t, data, 0, 1); e.PostRotate2D != nil {
+		t.Errorf("0_1: PostRotate2D = %v, want nil (PCA)", *e.PostRotate2D)
+	}
+}
+
+func TestNoAutoFlipField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"no_auto_flip": true}, "0_1": {"flip": true}}}`)
+	if e := entry(t, data, 0, 0); e.NoAutoFlip == nil || !*e.NoAutoFlip {
+		t.Errorf("0_0: NoAutoFlip = %v, want true", e.NoAutoFlip)
+	}
+	if e := entry(t, data, 0, 1); e.NoAutoFlip != nil || !e.Flip {
+		t.Errorf("0_1: NoAutoFlip = %v, Flip = %v; want nil (auto), true", e.NoAutoFlip, e.Flip)
+	}
+}
+
+func TestAbsoluteScaleField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"absolute_scale": true, "scale": 0.004}}}`)
+	if e := entry(t, data, 0, 0); !e.AbsoluteScale || e.Scale != 0.004 {
+		t.Errorf("AbsoluteScale %v, Scale %v; want true, 0.004", e.AbsoluteScale, e.Scale)
+	}
+}
+
+// TestScaleMult checks that scale_mult factors from every layer multiply
+// together and onto the scale the entry ends up with, and never onto an
+// unset one.
+func TestScaleMult(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"presets": {"nudge": {"merge": true, "scale_mult": 1.25}},
+		"categories": {"misc": {"merge": true, "scale_mult": 2}},
+		"sections": {
+			"0": {"scale_mult": 1.5},
+			"7": {"merge": true, "scale": 3, "scale_mult": 0.5},
+			"12": "nudge"
+		},
+		"items": {
+			"0_0": {"scale": 2, "scale_mult": 2},
+			"0_1": {"scale_mult": 2, "fill_ratio": 0.5}
+		}
+	}`, [2]int{7, 0}, [2]int{12, 0})
+	for _, c := range []struct {
+		section, index int
+		scale          float64
+		why            string
+	}{
+		{0, 0, 6, "item scale × item and section factors"},
+		{0, 1, 0, "no scale set: stays auto-fit"},
+		{7, 0, 3, "section merge sets the scale; category and section factors apply to it"},
+		{12, 0, 1.25, "preset's merge factor on the binary scale"},
+	} {
+		if e := entry(t, data, c.section, c.index); e.Scale != c.scale {
+			t.Errorf("%d_%d: Scale %v, want %v (%s)", c.section, c.index, e.Scale, c.scale, c.why)
+		}
+	}
+
+	// A section's own entry for an item without one
+	data = loadCustom(t, `{"sections": {"7": {"scale": 2, "scale_mult": 1.5}}}`)
+	if e := entry(t, data, 7, 0); e.Scale != 3 {
+		t.Errorf("section only: 7_0 Scale %v, want 3", e.Scale)
+	}
+}
+
+// TestProjectionField checks projection is kept over perspective and
+// cam_height from every layer: an item's own, and a category's under a
+// section merge. It doesn't clear them; viewmatrix reads projection first.
+func TestProjectionField(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"categories": {"misc": {"merge": true, "perspective": true, "cam_height": 200}},
+		"sections": {
+			"0": {"perspective": true, "cam_height": 300},
+			"7": {"merge": true, "projection": "orthographic"}
+		},
+		"items": {"0_0": {"projection": "orthographic", "perspective": true}}
+	}`, [2]int{7, 0})
+	for _, c := range []struct {
+		section, index int
+		projection     string
+		perspective    bool
+		camHeight      float64
+	}{
+		{0, 0, "ortho", true, 0},
+		{0, 1, "", true, 300},
+		{7, 0, "ortho", true, 200},
+	} {
+		e := entry(t, data, c.section, c.index)
+		if e.Projection != c.projection || e.Perspective != c.perspective || e.CamHeight != c.camHeight {
+			t.Errorf("%d_%d: projection %q, perspective %v, cam_height %v; want %q, %v, %v",
+				c.section, c.index, e.Projection, e.Perspective, e.CamHeight, c.projection, c.perspective, c.camHeight)
+		}
+	}
+
+	var p projectionSetting
+	if err := p.UnmarshalJSON([]byte(`"fisheye"`)); err == nil {
+		t.Error(`projection "fisheye": no error`)
+	}
+}
+
+// TestLightingMerge checks lighting merges field by field down the layers:
+// an item's over its section's, and a section merge's over a category's and
+// the binary entry. Fields no layer sets stay nil (the renderer defaults).
+func TestLightingMerge(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"categories": {"misc": {"merge": true, "lighting": {"ambient": 0.3, "direct": 1.2}}},
+		"sections": {
+			"0": {"lighting": {"ambient": 0.4, "rim": 0.2}},
+			"7": {"merge": true, "lighting": {"direct": 1.8}}
+		},
+		"items": {
+			"0_0": {"lighting": {"rim": 0.5, "exposure": 1.3}},
+			"12_0": {"lighting": {"spec_pow": 20}}
+		}
+	}`, [2]int{7, 0})
+	f := func(v float64) *float64 { return &v }
+	for _, c := range []struct {
+		section, index int
+		want           *Lighting
+	}{
+		{0, 0, &Lighting{Ambient: f(0.4), Rim: f(0.5), Exposure: f(1.3)}},
+		{0, 1, &Lighting{Ambient: f(0.4), Rim: f(0.2)}},
+		{7, 0, &Lighting{Ambient: f(0.3), Direct: f(1.8)}},
+		{12, 0, &Lighting{SpecPow: f(20)}},
+	} {
+		if e := entry(t, data, c.section, c.index); !reflect.DeepEqual(e.Lighting, c.want) {
+			t.Errorf("%d_%d: lighting %s, want %s", c.section, c.index, lightingString(e.Lighting), lightingString(c.want))
+		}
+	}
+}
+
+// lightingString formats l's set fields for test messages.
+func lightingString(l *Lighting) string {
+	if l == nil {
+		return "nil"
+	}
+	var b strings.Builder
+	for _, f := range []struct {
+		name string
+		v    *float64
+	}{{"ambient", l.Ambient}, {"direct", l.Direct}, {"rim", l.Rim}, {"exposure", l.Exposure}, {"spec_int", l.SpecInt}, {"spec_pow", l.SpecPow}} {
+		if f.v != nil {
+			fmt.Fprintf(&b, "%s=%g ", f.name, *f.v)
+		}
+	}
+	return "{" + strings.TrimSpace(b.String()) + "}"
+}
+
+// TestRenderSizeField checks render_size sets both sides, loses to an
+// explicit side, beats a resolution group, and is inherited from a section
+// like render_width and render_height.
+func TestRenderSizeField(t *testing.T) {
+	data := loadCustom(t, `{
+		"resolution": {"wide": {"render_width": 512, "render_height": 256}},
+		"sections": {"12": {"render_size": 384}},
+		"items": {
+			"0_0": {"render_size": 128},
+			"0_1": {"render_size": 128, "render_height": 64, "resolution": "wide"},
+			"12_0": {"fill_ratio": 0.5}
+		}
+	}`)
+	for _, c := range []struct {
+		section, index int
+		w, h           int
+	}{
+		{0, 0, 128, 128},
+		{0, 1, 128, 64},
+		{7, 0, 0, 0},
+		{12, 0, 384, 384},
+	} {
+		e := data[[2]int{c.section, c.index}]
+		var w, h int
+		if e != nil {
+			w, h = e.RenderWidth, e.RenderHeight
+		}
+		if w != c.w || h != c.h {
+			t.Errorf("%d_%d: %dx%d, want %dx%d", c.section, c.index, w, h, c.w, c.h)
+		}
+	}
+}
+
+func TestKeepComponentsField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"keep_components": 2}}}`)
+	if e := entry(t, data, 0, 0); e.KeepComponents != 2 {
+		t.Errorf("KeepComponents = %d, want 2", e.KeepComponents)
+	}
+}
+
+// TestCategoryPrecedence checks that category defaults rank below sections:
+// a plain section default beats the category for items with no entry, and
+// category merges reach binary entries before section merges do.
+func TestCategoryPrecedence(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"categories": {
+			"misc": {"fill_ratio": 0.9},
+			"Wing": {"merge": true, "fill_ratio": 0.9, "fov": 30}
+		},
+		"sections": {
+			"0": {"fill_ratio": 0.6},
+			"12": {"merge": true, "fill_ratio": 0.6}
+		},
+		"items": {"0_1": {"fill_ratio": 0.3}}
+	}`, [2]int{12, 0})
+
+	for _, c := range []struct {
+		section, index int
+		fill           float64
+		why            string
+	}{
+		{0, 0, 0.6, "section default over category"},
+		{0, 1, 0.3, "item over section and category"},
+		{7, 0, 0.9, "category default with no section config"},
+		{12, 0, 0.6, "section merge after category merge"},
+	} {
+		if e := entry(t, data, c.section, c.index); e.FillRatio != c.fill {
+			t.Errorf("%d_%d: FillRatio %v, want %v (%s)", c.section, c.index, e.FillRatio, c.fill, c.why)
+		}
+	}
+	e := entry(t, data, 12, 0)
+	if e.FOV != 30 || e.Scale != 1 || e.Source != "binary" {
+		t.Errorf("12_0: FOV %v, Scale %v, Source %q; want the category's 30 merged into the binary entry", e.FOV, e.Scale, e.Source)
+	}
+}
+
+// TestCategoryLeavesBinaryEntries checks that a category default without
+// merge or override does not replace an item's binary entry.
+func TestCategoryLeavesBinaryEntries(t *testing.T) {
+	data := loadWithBinary(t, `{"categories": {"wing": {"fill_ratio": 0.9, "scale": 3}}}`, [2]int{12, 0})
+	if e := entry(t, data, 12, 0); e.Scale != 1 || e.FillRatio != DefaultFillRatio {
+		t.Errorf("12_0: Scale %v, FillRatio %v; want the binary entry's 1, %v", e.Scale, e.FillRatio, DefaultFillRatio)
+	}
+}
+
+func TestParseItemKeys(t *testing.T) {
+	for _, c := range []struct {
+		key  string
+		want [][2]int
+	}{
+		{"12_40", [][2]int{{12, 40}}},
+		{"14_72-74", [][2]int{{14, 72}, {14, 73}, {14, 74}}},
+		{"0_5-5", [][2]int{{0, 5}}},
+		{"12", nil},
+		{"12_", nil},
+		{"x_1", nil},
+		{"12_9-4", nil},
+		{"12_1-x", nil},
+	} {
+		if got := ParseItemKeys(c.key); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseItemKeys(%q) = %v, want %v", c.key, got, c.want)
+		}
+	}
+}
+
+func TestDisplayAngleAuto(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"categories": {"wing": {"merge": true, "display_angle": 30}},
+		"sections": {"12": {"merge": true, "display_angle": "auto"}},
+		"items": {
+			"0_0": {"display_angle": "auto"},
+			"0_1": {"display_angle": -60},
+			"7_0": {"display_angle": "left"}
+		}
+	}`, [2]int{12, 0})
+	for _, c := range []struct {
+		section, index int
+		auto           bool
+		angle          float64
+	}{
+		{0, 0, true, DefaultDisplayAngle},
+		{0, 1, false, -60},
+		{12, 0, true, 30}, // auto around the angle merged before it
+	} {
+		if e := entry(t, data, c.section, c.index); e.AutoDisplayAngle != c.auto || e.DisplayAngle != c.angle {
+			t.Errorf("%d_%d: AutoDisplayAngle %v, DisplayAngle %v; want %v, %v", c.section, c.index, e.AutoDisplayAngle, e.DisplayAngle, c.auto, c.angle)
+		}
+	}
+	if e := data[[2]int{7, 0}]; e != nil {
+		t.Errorf(`7_0: display_angle "left" gave an entry (angle %v), want it rejected`, e.DisplayAngle)
+	}
+}
+
+func TestHideMeshesField(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"sections": {"12": {"merge": true, "hide_meshes": [2, 3]}},
+		"items": {"0_0": {"hide_meshes": [1]}}
+	}`, [2]int{12, 0})
+	if e := entry(t, data, 0, 0); !reflect.DeepEqual(e.HideMeshIndices, []int{1}) {
+		t.Errorf("0_0: HideMeshIndices %v, want [1]", e.HideMeshIndices)
+	}
+	if e := entry(t, data, 12, 0); !reflect.DeepEqual(e.HideMeshIndices, []int{2, 3}) {
+		t.Errorf("12_0: HideMeshIndices %v, want [2 3] merged into the binary entry", e.HideMeshIndices)
+	}
+}
+
+func TestLayFlatField(t *testing.T) {
+	data := loadCustom(t, `{"sections": {"0": {"lay_flat": true}}, "items": {"0_1": {"fill_ratio": 0.5}}}`)
+	if e := entry(t, data, 0, 0); !e.LayFlat {
+		t.Error("0_0: LayFlat false, want the section's true")
+	}
+	if e := entry(t, data, 0, 1); e.LayFlat {
+		t.Error("0_1: LayFlat true, want false")
+	}
+}
+
+func TestTexAdjustFields(t *testing.T) {
+	data := loadCustom(t, `{"sections": {"0": {"tex_brightness": 1.4, "tex_gamma": 1.2}}, "items": {"0_1": {"tex_contrast": 1.3}}}`)
+	if e := entry(t, data, 0, 0); e.TexBrightness != 1.4 || e.TexContrast != 0 || e.TexGamma != 1.2 {
+		t.Errorf("0_0: brightness %g, contrast %g, gamma %g; want 1.4, 0, 1.2", e.TexBrightness, e.TexContrast, e.TexGamma)
+	}
+	if e := entry(t, data, 0, 1); e.TexBrightness != 0 || e.TexContrast != 1.3 {
+		t.Errorf("0_1: brightness %g, contrast %g; want 0, 1.3", e.TexBrightness, e.TexContrast)
+	}
+}
+
+func TestBodyBonesField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"body_bones": [2, 5]}, "0_1": {"fill_ratio": 0.5}}}`)
+	if e := entry(t, data, 0, 0); !reflect.DeepEqual(e.BodyBones, []int{2, 5}) {
+		t.Errorf("0_0: BodyBones = %v, want [2 5]", e.BodyBones)
+	}
+	if e := entry(t, data, 0, 1); e.BodyBones != nil {
+		t.Errorf("0_1: BodyBones = %v, want nil", e.BodyBones)
+	}
+}
+
+func TestPromoteField(t *testing.T) {
+	data := loadCustom(t, `{"items": {"0_0": {"promote": false}, "0_1": {"fill_ratio": 0.5}}}`)
+	if e := entry(t, data, 0, 0); e.Promote == nil || *e.Promote {
+		t.Errorf("0_0: Promote = %v, want false", e.Promote)
+	}
+	if e := entry(t, data, 0, 1); e.Promote != nil {
+		t.Errorf("0_1: Promote = %v, want nil (run default)", *e.Promote)
+	}
+}
+
+func TestBlendOverridesField(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"sections": {"12": {"merge": true, "blend_overrides": {"Wing01_R": "alpha"}}},
+		"items": {"0_0": {"blend_overrides": {"Cloth": "opaque", "GLOW01": "additive"}}}
+	}`, [2]int{12, 0})
+	if e := entry(t, data, 0, 0); !reflect.DeepEqual(e.BlendOverrides, map[string]string{"cloth": "opaque", "glow01": "additive"}) {
+		t.Errorf("0_0: BlendOverrides = %v, want lowercase stems", e.BlendOverrides)
+	}
+	if e := entry(t, data, 12, 0); e.Source != "binary" || e.BlendOverrides["wing01_r"] != "alpha" {
+		t.Errorf("12_0: Source %q, BlendOverrides %v; want the section's merged into the binary entry", e.Source, e.BlendOverrides)
+	}
+
+	// An unknown mode drops the entry on load, which Validate reports
+	bmdPath, customPath, xmlPath := writeTRSFiles(t, `{"items": {"0_0": {"blend_overrides": {"cloth": "screen"}}}}`)
+	data, err := LoadWith(bmdPath, customPath, xmlPath, Options{Strict: true})
+	if err != nil || data[[2]int{0, 0}] != nil {
+		t.Errorf("unknown mode: entry %+v, error %v; want the entry skipped", data[[2]int{0, 0}], err)
+	}
+	if errs := Validate(customPath, xmlPath); len(errs) != 1 || !strings.Contains(errs[0].Error(), `blend_overrides: cloth: want "opaque"`) {
+		t.Errorf("unknown mode: Validate %v", errs)
+	}
+}
+
+// TestWarningsUseDefaultLogger loads a malformed custom_trs.json with a
+// capturing logging.Default: the parse error is a warning printed through
+// it, and the binary entries still load.
+func TestWarningsUseDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	prev := logging.Default()
+	logging.SetDefault(logging.New(&buf, &buf, logging.LevelWarn))
+	defer logging.SetDefault(prev)
+
+	bmdPath, customPath, xmlPath := writeTRSFiles(t, `{"items": {"0_0": `, [2]int{0, 0})
+	data, err := LoadWith(bmdPath, customPath, xmlPath, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Warning: custom_trs.json parse error") {
+		t.Errorf("logged %q, want the parse error warning", buf.String())
+	}
+	entry(t, data, 0, 0)
+}
+
+// TestStrict loads a malformed and a missing custom_trs.json: strict mode
+// fails with the error, lenient mode keeps the binary entries.
+func TestStrict(t *testing.T) {
+	prev := logging.Default()
+	logging.SetDefault(logging.Discard)
+	defer logging.SetDefault(prev)
+
+	bmdPath, malformed, xmlPath := writeTRSFiles(t, `{"items": [`, [2]int{0, 0})
+	missing := filepath.Join(t.TempDir(), "custom_trs.json")
+	for name, customPath := range map[string]string{"malformed": malformed, "missing": missing} {
+		if _, err := LoadWith(bmdPath, customPath, xmlPath, Options{Strict: true}); err == nil {
+			t.Errorf("%s, strict: no error", name)
+		}
+		data, err := LoadWith(bmdPath, customPath, xmlPath, Options{})
+		if err != nil {
+			t.Errorf("%s, lenient: %v", name, err)
+		} else if e := entry(t, data, 0, 0); e.Source != "binary" {
+			t.Errorf("%s, lenient: 0_0 from %q, want the binary entry", name, e.Source)
+		}
+	}
+}
+
+// TestOverrideDir loads per-item override files over binary entries and
+// custom_trs.json: each file replaces its item's entry, an item's own file
+// wins over a range covering it, presets resolve from custom_trs.json, and
+// a bad file is skipped without stopping the others (or, strict, fails).
+func TestOverrideDir(t *testing.T) {
+	bmdPath, customPath, xmlPath := writeTRSFiles(t, `{
+		"presets": {"big": {"scale": 4}},
+		"items": {"0_1": {"fill_ratio": 0.3}, "7_0": {"fill_ratio": 0.2}}
+	}`, [2]int{0, 0}, [2]int{0, 1}, [2]int{12, 0})
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"0_0.json":   `{"scale": 2}`,
+		"0_0-1.json": `{"fill_ratio": 0.5}`,
+		"12_0.json":  `"big"`,
+		"notes.json": `{"scale": 9}`,
+		"0_1.txt":    `{"scale": 9}`,
+		"7_0.json":   `{"scale": `,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var warnings bytes.Buffer
+	prev := logging.Default()
+	logging.SetDefault(logging.New(&warnings, &warnings, logging.LevelWarn))
+	defer logging.SetDefault(prev)
+	data, err := LoadWith(bmdPath, customPath, xmlPath, Options{OverrideDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		section, index int
+		scale, fill    float64
+		source         string
+		why            string
+	}{
+		{0, 0, 2, DefaultFillRatio, "custom", "own file over the range and the binary entry"},
+		{0, 1, 0, 0.5, "custom", "range file over custom_trs.json"},
+		{12, 0, 4, DefaultFillRatio, "custom", "preset from custom_trs.json"},
+		{7, 0, 0, 0.2, "custom", "malformed file skipped"},
+	} {
+		e := entry(t, data, c.section, c.index)
+		if e.Scale != c.scale || e.FillRatio != c.fill || e.Source != c.source {
+			t.Errorf("%d_%d: Scale %v, FillRatio %v, Source %q; want %v, %v, %q (%s)",
+				c.section, c.index, e.Scale, e.FillRatio, e.Source, c.scale, c.fill, c.source, c.why)
+		}
+	}
+	if n := strings.Count(warnings.String(), "Warning: trs overrides:"); n != 2 {
+		t.Errorf("%d warnings, want one each for notes.json and 7_0.json:\n%s", n, warnings.String())
+	}
+
+	if _, err := LoadWith(bmdPath, customPath, xmlPath, Options{OverrideDir: dir, Strict: true}); err == nil {
+		t.Error("strict: no error for the bad files")
+	}
+}
@@ -0,0 +1,61 @@
+package trs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// mergeOverrideDir applies the per-item override files in dir (see
+// Options.OverrideDir) to data, after custom_trs.json (file, for presets,
+// resolution groups and what sections pass on to items). Ranges go first so a single
+// item's file wins over a range covering it, as in "items". Files that can't
+// be read, named, or resolved are skipped and returned as errors, one each;
+// the rest still apply.
+func mergeOverrideDir(data Data, dir string, file customTRSFile) []error {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return []error{fmt.Errorf("trs overrides: %w", err)}
+	}
+
+	type override struct {
+		keys [][2]int
+		c    customTRSEntry
+	}
+	var overrides []override
+	var errs []error
+	for _, de := range des {
+		name := de.Name()
+		if de.IsDir() || !strings.EqualFold(filepath.Ext(name), ".json") {
+			continue
+		}
+		keys := ParseItemKeys(strings.TrimSuffix(name, filepath.Ext(name)))
+		if keys == nil {
+			errs = append(errs, fmt.Errorf("trs overrides: %s: name is not <section>_<index>.json", name))
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("trs overrides: %w", err))
+			continue
+		}
+		c, err := resolveEntry(raw, file.Presets, file.Resolution)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("trs overrides: %s: %w", name, err))
+			continue
+		}
+		overrides = append(overrides, override{keys, *c})
+	}
+	// ReadDir sorts by name, so equal-size overlaps resolve the same way every run
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return len(overrides[i].keys) > len(overrides[j].keys)
+	})
+
+	secs := sectionDefaults(file)
+	for _, o := range overrides {
+		applyItemEntry(data, o.keys, o.c, secs)
+	}
+	return errs
+}
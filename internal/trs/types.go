@@ -11,13 +11,17 @@ type Entry struct {
 	UseBones     *bool   // nil = auto, true/false = forced
 	Standardize  *bool   // nil = true (default), false = skip PCA rotation
 	DisplayAngle float64 // PCA target angle in degrees (default -45)
+	AutoDisplayAngle bool // display_angle "auto": angle from the item's shape, DisplayAngle is the canonical direction
 	FillRatio    float64 // canvas fill fraction (default 0.70)
 	Flip         bool    // invert auto-orientation detection
+	NoAutoFlip   *bool   // nil/false = auto-detect 180° orientation, true = keep PCA rotation only
 	Camera       string  // "", "noflip", "correction", "fallback"
 	Perspective    bool    // enable perspective projection
 	FOV            float64 // field of view in degrees (default 75)
 	CamHeight      float64 // positioned camera: height offset as fraction of model Y-span (0 = disabled)
+	Projection     string  // "" (from Perspective/CamHeight), "ortho", or "perspective"; beats Perspective and CamHeight
 	KeepAllMeshes    bool              // skip effect mesh filtering
+	EffectsAsGlow    bool              // keep effect meshes, drawn additively (black vanishes), instead of filtering them
 	FlipCanvas       bool              // mirror final image horizontally
 	BoneFlip         bool              // prefix root bone matrices with Rx(-90°) to match BMD-viewer group inheritance
 	MirrorPair       bool              // duplicate + mirror to create a pair (e.g. single boot → pair)
@@ -29,11 +33,53 @@ type Entry struct {
 	TintTextures     []string          // apply tint only to these texture stems (empty = all meshes)
 	RenderWidth      int               // per-item output width override (0 = use global config)
 	RenderHeight     int               // per-item output height override (0 = use global config)
+	PostRotate2D     *float64          // fixed 2D rotation in degrees (CCW) instead of PCA alignment (nil = use PCA)
+	MergeMeshes      bool              // merge same-texture meshes within each blend pass
+	AbsoluteScale    bool              // true = render at scale × output px per model unit, no auto-fit or fill_ratio rescale
+	KeepComponents   int               // keep only the N largest connected pieces after cleanup (0 = off)
+	HideMeshIndices  []int             // hide these meshes by BMD index (0-based), before any other filtering
+	LayFlat          bool              // rotate the flattest 3D axis toward the camera (scrolls, books)
+	TexBrightness    float64           // texture RGB multiplier before lighting (0 = unset, 1 = unchanged)
+	TexContrast      float64           // texture contrast around mid-gray (0 = unset, 1 = unchanged)
+	TexGamma         float64           // texture gamma, >1 lifts shadows (0 = unset, 1 = unchanged)
+	BodyBones        []int             // cull meshes mostly bound to these bone indices (body parts under equipment)
+	Promote          *bool             // promote a glow/overlay mesh to opaque when none is (nil = global setting)
+	Pivot            string            // render center: "" (bbox center), "centroid", or "bone:<name or index>"
+	BlendOverrides   map[string]string // lowercase texture stem → "opaque", "alpha", or "additive", ahead of the pass heuristics
+	Lighting         *Lighting         // per-item lighting overrides (nil = the renderer's defaults)
+
+	scaleMult float64 // scale_mult factors collected while loading, applied to Scale at the end (0 = none)
+}
+
+// Lighting overrides the renderer's light settings for an item; nil fields
+// keep the defaults (see raster.DefaultLightConfig).
+type Lighting struct {
+	Ambient  *float64 `json:"ambient,omitempty"`
+	Direct   *float64 `json:"direct,omitempty"`
+	Rim      *float64 `json:"rim,omitempty"`
+	Exposure *float64 `json:"exposure,omitempty"`
+	SpecInt  *float64 `json:"spec_int,omitempty"`
+	SpecPow  *float64 `json:"spec_pow,omitempty"`
 }
 
 // Data maps (section, index) to an Entry.
 type Data map[[2]int]*Entry
 
+// AddRotation adds a rotation offset in degrees to every entry, as if the
+// TRS data had been authored that way: camera auto-routing by rotY sees the
+// offset too. Items without an entry keep the default camera. Every entry is
+// its own copy (see mergeCustomTRS), so each is offset exactly once.
+func (d Data) AddRotation(rot [3]float64) {
+	if rot == [3]float64{} {
+		return
+	}
+	for _, e := range d {
+		e.RotX += rot[0]
+		e.RotY += rot[1]
+		e.RotZ += rot[2]
+	}
+}
+
 // DefaultDisplayAngle is the default PCA target angle.
 const DefaultDisplayAngle = -45.0
 
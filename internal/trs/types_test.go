@@ -0,0 +1,33 @@
+package trs
+
+import "testing"
+
+// TestAddRotation offsets entries from the binary file, a section default
+// shared by two items, and a category: each must move exactly once.
+func TestAddRotation(t *testing.T) {
+	data := loadWithBinary(t, `{
+		"categories": {"wing": {"merge": true, "rotX": 10}},
+		"sections": {"0": {"rotY": 20}},
+		"items": {"7_0": {"rotZ": 30}}
+	}`, [2]int{12, 0})
+	data.AddRotation([3]float64{1, 2, 3})
+	for _, c := range []struct {
+		section, index int
+		want           [3]float64
+	}{
+		{0, 0, [3]float64{1, 22, 3}},
+		{0, 1, [3]float64{1, 22, 3}},
+		{7, 0, [3]float64{1, 2, 33}},
+		{12, 0, [3]float64{11, 2, 3}},
+	} {
+		e := entry(t, data, c.section, c.index)
+		if got := [3]float64{e.RotX, e.RotY, e.RotZ}; got != c.want {
+			t.Errorf("%d_%d: rotation %v, want %v", c.section, c.index, got, c.want)
+		}
+	}
+
+	data.AddRotation([3]float64{})
+	if e := entry(t, data, 7, 0); e.RotZ != 33 {
+		t.Errorf("zero offset changed rotZ to %v", e.RotZ)
+	}
+}
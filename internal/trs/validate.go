@@ -0,0 +1,193 @@
+package trs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mu-bmd-renderer/internal/itemclass"
+	"mu-bmd-renderer/internal/itemlist"
+)
+
+// Validate checks the custom TRS file at path (JSON, or YAML; see
+// customTRSJSON) against the item list at xmlPath and returns every problem
+// that loading would skip over silently: unknown keys, section and item keys
+// that don't parse (including ranges whose start is after their end),
+// references to presets or resolution groups that don't exist, entries that
+// don't decode, and sections, models, and items that match nothing in
+// ItemList.xml. Problems are returned in file-part then key order; nil means
+// the file is clean.
+func Validate(path, xmlPath string) []error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	name := filepath.Base(path)
+	if raw, err = customTRSJSON(path, raw); err != nil {
+		return []error{err}
+	}
+	var file customTRSFile
+	if err := decodeStrict(raw, &file); err != nil {
+		return []error{fmt.Errorf("%s: %w", name, err)}
+	}
+
+	v := validator{file: file}
+	items, err := itemlist.Parse(xmlPath)
+	if err != nil {
+		v.addf("%s: %v (items not checked against it)", filepath.Base(xmlPath), err)
+	} else {
+		v.items = make(map[[2]int]bool, len(items))
+		v.sections = make(map[int]bool)
+		v.models = make(map[string]bool)
+		for _, it := range items {
+			v.items[[2]int{it.Section, it.Index}] = true
+			v.sections[it.Section] = true
+			v.models[strings.ToLower(it.ModelFile)] = true
+		}
+	}
+
+	for _, k := range sortedKeys(file.Presets) {
+		v.inline(fmt.Sprintf("presets[%q]", k), file.Presets[k])
+	}
+	for _, k := range sortedKeys(file.Categories) {
+		where := fmt.Sprintf("categories[%q]", k)
+		if !slices.Contains(itemclass.Categories, itemclass.Category(strings.ToLower(k))) {
+			v.addf("%s: unknown category (use one of %v)", where, itemclass.Categories)
+		}
+		v.entry(where, file.Categories[k])
+	}
+	for _, k := range sortedKeys(file.Sections) {
+		where := fmt.Sprintf("sections[%q]", k)
+		if sec, err := strconv.Atoi(k); err != nil {
+			v.addf("%s: key is not a section number", where)
+		} else if v.sections != nil && !v.sections[sec] {
+			v.addf("%s: no items in this section", where)
+		}
+		v.entry(where, file.Sections[k])
+	}
+	for _, k := range sortedKeys(file.Models) {
+		v.model(k, file.Models[k])
+	}
+	for _, k := range sortedKeys(file.Items) {
+		where := fmt.Sprintf("items[%q]", k)
+		if keys, problem := parseItemKeys(k); problem != "" {
+			v.addf("%s: %s", where, problem)
+		} else if v.items != nil && !slices.ContainsFunc(keys, func(key [2]int) bool { return v.items[key] }) {
+			v.addf("%s: no such item in ItemList.xml", where)
+		}
+		v.entry(where, file.Items[k])
+	}
+	return v.errs
+}
+
+// validator collects Validate's problems; items, sections, and models (lower
+// case) are what ItemList.xml has, nil if it didn't load.
+type validator struct {
+	file     customTRSFile
+	items    map[[2]int]bool
+	sections map[int]bool
+	models   map[string]bool
+	errs     []error
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+// entry checks raw, a preset name or an inline entry, as resolveEntry would
+// resolve it. A named preset's own fields are checked under "presets".
+func (v *validator) entry(where string, raw json.RawMessage) {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		if _, ok := v.file.Presets[name]; !ok {
+			v.addf("%s: preset %q not found", where, name)
+		}
+		return
+	}
+	v.inline(where, raw)
+}
+
+// inline checks raw as an inline entry: known keys with valid values, and a
+// "resolution" naming an existing group.
+func (v *validator) inline(where string, raw json.RawMessage) {
+	var c customTRSEntry
+	if err := decodeStrict(raw, &c); err != nil {
+		v.addf("%s: %v", where, err)
+		return
+	}
+	if c.Resolution != nil {
+		if _, ok := v.file.Resolution[*c.Resolution]; !ok {
+			v.addf("%s: resolution %q not found", where, *c.Resolution)
+		}
+	}
+}
+
+// model checks a "models" entry in either of its forms: a model file mapped
+// to an entry, or a preset name mapped to a list of model files.
+func (v *validator) model(key string, raw json.RawMessage) {
+	where := fmt.Sprintf("models[%q]", key)
+	var files []string
+	if json.Unmarshal(raw, &files) == nil && len(files) > 0 {
+		if _, ok := v.file.Presets[key]; !ok {
+			v.addf("%s: preset %q not found", where, key)
+		}
+		for _, mf := range files {
+			if v.models != nil && !v.models[strings.ToLower(mf)] {
+				v.addf("%s: no item uses model %q", where, mf)
+			}
+		}
+		return
+	}
+	if v.models != nil && !v.models[strings.ToLower(key)] {
+		v.addf("%s: no item uses this model", where)
+	}
+	v.entry(where, raw)
+}
+
+// parseItemKeys is ParseItemKeys with the reason a key doesn't parse.
+func parseItemKeys(key string) ([][2]int, string) {
+	if keys := ParseItemKeys(key); keys != nil {
+		return keys, ""
+	}
+	sec, idx, ok := strings.Cut(key, "_")
+	if !ok {
+		return nil, `key is not "<section>_<index>" or "<section>_<start>-<end>"`
+	}
+	if _, err := strconv.Atoi(sec); err != nil {
+		return nil, fmt.Sprintf("section %q is not a number", sec)
+	}
+	if start, end, ok := strings.Cut(idx, "-"); ok {
+		s, err1 := strconv.Atoi(start)
+		e, err2 := strconv.Atoi(end)
+		if err1 == nil && err2 == nil && s > e {
+			return nil, fmt.Sprintf("range start %d is after its end %d", s, e)
+		}
+		return nil, fmt.Sprintf("range %q is not <start>-<end>", idx)
+	}
+	return nil, fmt.Sprintf("index %q is not a number", idx)
+}
+
+// decodeStrict unmarshals raw into v, failing on keys v has no field for
+// (a misspelled "rotYY" would otherwise be dropped without a word).
+func decodeStrict(raw []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// sortedKeys returns m's keys in order, so problems are reported in the same
+// order every run.
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+package trs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestValidate validates one custom TRS file per problem against
+// testItemList: each must give exactly that problem, and a clean file none.
+func TestValidate(t *testing.T) {
+	for _, c := range []struct {
+		name, custom, want string
+	}{
+		{"clean", `{
+			"resolution": {"wide": {"render_width": 256, "render_height": 128}},
+			"presets": {"blade": {"fill_ratio": 0.7}},
+			"sections": {"7": "blade"},
+			"models": {"Wing01.bmd": {"scale": 2}, "blade": ["Sword02.bmd"]},
+			"items": {"0_0": {"resolution": "wide"}, "0_1-5": {"rotY": 90}}
+		}`, ""},
+		{"unknown top-level key", `{"itemz": {}}`, `unknown field "itemz"`},
+		{"unknown entry key", `{"items": {"0_0": {"rotYY": 90}}}`, `items["0_0"]: json: unknown field "rotYY"`},
+		{"missing preset", `{"items": {"0_0": "blade"}}`, `items["0_0"]: preset "blade" not found`},
+		{"missing model preset", `{"models": {"blade": ["Sword01.bmd"]}}`, `models["blade"]: preset "blade" not found`},
+		{"missing resolution", `{"items": {"0_0": {"resolution": "wide"}}}`, `items["0_0"]: resolution "wide" not found`},
+		{"non-numeric section", `{"sections": {"swords": {"scale": 2}}}`, `sections["swords"]: key is not a section number`},
+		{"range start after end", `{"items": {"0_5-1": {"scale": 2}}}`, `items["0_5-1"]: range start 5 is after its end 1`},
+		{"malformed item key", `{"items": {"0-1": {"scale": 2}}}`, `items["0-1"]: key is not "<section>_<index>"`},
+		{"section not in ItemList", `{"sections": {"5": {"scale": 2}}}`, `sections["5"]: no items in this section`},
+		{"model not in ItemList", `{"models": {"Axe01.bmd": {"scale": 2}}}`, `models["Axe01.bmd"]: no item uses this model`},
+		{"item not in ItemList", `{"items": {"7_3": {"scale": 2}}}`, `items["7_3"]: no such item in ItemList.xml`},
+		{"range not in ItemList", `{"items": {"12_1-4": {"scale": 2}}}`, `items["12_1-4"]: no such item in ItemList.xml`},
+	} {
+		_, customPath, xmlPath := writeTRSFiles(t, c.custom)
+		errs := Validate(customPath, xmlPath)
+		if c.want == "" {
+			if errs != nil {
+				t.Errorf("%s: %v, want nil", c.name, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), c.want) {
+			t.Errorf("%s: %v, want one problem containing %q", c.name, errs, c.want)
+		}
+	}
+}
+
+// TestParseItemKeysProblem checks the reason parseItemKeys gives for each
+// way a key can fail to parse, and none for the keys ParseItemKeys takes.
+func TestParseItemKeysProblem(t *testing.T) {
+	for _, c := range []struct {
+		key     string
+		keys    [][2]int
+		problem string
+	}{
+		{"9_1", [][2]int{{9, 1}}, ""},
+		{"12_3-5", [][2]int{{12, 3}, {12, 4}, {12, 5}}, ""},
+		{"12_4-4", [][2]int{{12, 4}}, ""},
+		{"12", nil, `key is not "<section>_<index>" or "<section>_<start>-<end>"`},
+		{"a_1", nil, `section "a" is not a number`},
+		{"12_x", nil, `index "x" is not a number`},
+		{"12_9-4", nil, "range start 9 is after its end 4"},
+		{"12_1-x", nil, `range "1-x" is not <start>-<end>`},
+	} {
+		keys, problem := parseItemKeys(c.key)
+		if !reflect.DeepEqual(keys, c.keys) || problem != c.problem {
+			t.Errorf("parseItemKeys(%q) = %v, %q; want %v, %q", c.key, keys, problem, c.keys, c.problem)
+		}
+	}
+}
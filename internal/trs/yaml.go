@@ -0,0 +1,25 @@
+package trs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"mu-bmd-renderer/internal/yamljson"
+)
+
+// customTRSJSON returns raw, the contents of the custom TRS file at path, as
+// JSON. A .yaml or .yml file is converted, so comments and unquoted keys are
+// allowed there while the json tags (and the validating UnmarshalJSON types)
+// stay the one schema.
+func customTRSJSON(path string, raw []byte) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		out, err := yamljson.Convert(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		return out, nil
+	}
+	return raw, nil
+}
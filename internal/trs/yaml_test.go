@@ -0,0 +1,85 @@
+package trs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// yamlItemList holds the items the YAML and JSON twins below configure.
+const yamlItemList = `<ItemList>
+  <Section Index="7"><Item Index="0" Name="Bronze Helm" ModelFile="HelmMale01.bmd"/></Section>
+  <Section Index="9"><Item Index="0" Name="Bronze Pants" ModelFile="PantMale01.bmd"/><Item Index="1" Name="Dragon Pants" ModelFile="PantMale02.bmd"/><Item Index="2" Name="Pad Pants" ModelFile="PantMale03.bmd"/><Item Index="3" Name="Legendary Pants" ModelFile="PantMale04.bmd"/></Section>
+</ItemList>`
+
+const customJSON = `{
+  "presets": {"armor": {"fill_ratio": 0.6, "camera": "side"}},
+  "sections": {"7": "armor"},
+  "items": {
+    "9_1": {"rotY": 45, "exclude_textures": ["cape"]},
+    "9_2-3": {"scale": 2, "fov": 30}
+  }
+}`
+
+const customYAML = `# Same settings as customJSON
+presets:
+  armor: {fill_ratio: 0.6, camera: side}
+sections:
+  7: armor
+items:
+  9_1:            # stays the key "9_1", not the number 91
+    rotY: 45
+    exclude_textures: [cape]
+  9_2-3: {scale: 2, fov: 30}
+`
+
+// TestCustomTRSYAML loads the same custom TRS as .json, .yaml and .yml:
+// each must give the same Data, including the item key 9_1 and the range.
+func TestCustomTRSYAML(t *testing.T) {
+	write := func(dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	load := func(name, content string) Data {
+		t.Helper()
+		dir := t.TempDir()
+		customPath, xmlPath := write(dir, name, content), write(dir, "ItemList.xml", yamlItemList)
+		data, err := LoadWith(filepath.Join(dir, "ItemTRSData.bmd"), customPath, xmlPath, Options{Strict: true})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return data
+	}
+
+	// The JSON twin is clean, so no setting is dropped on either side
+	dir := t.TempDir()
+	if errs := Validate(write(dir, "custom_trs.json", customJSON), write(dir, "ItemList.xml", yamlItemList)); errs != nil {
+		t.Fatalf("JSON twin: %v", errs)
+	}
+	want := load("custom_trs.json", customJSON)
+	for _, k := range [][2]int{{7, 0}, {9, 1}, {9, 2}, {9, 3}} {
+		if want[k] == nil {
+			t.Fatalf("JSON: no entry for %d_%d", k[0], k[1])
+		}
+	}
+	if e := want[[2]int{9, 1}]; e.RotY != 45 {
+		t.Errorf("JSON: 9_1 rotY %v, want 45", e.RotY)
+	}
+	if e := want[[2]int{7, 0}]; e.FillRatio != 0.6 || e.Camera != "side" {
+		t.Errorf("JSON: 7_0 fill ratio %v, camera %q; want the preset's 0.6, side", e.FillRatio, e.Camera)
+	}
+	if want[[2]int{9, 0}] != nil {
+		t.Error("JSON: entry for 9_0")
+	}
+
+	for _, name := range []string{"custom_trs.yaml", "custom_trs.YML"} {
+		if got := load(name, customYAML); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s loaded differently from its JSON twin:\n%v\nwant\n%v", name, got, want)
+		}
+	}
+}
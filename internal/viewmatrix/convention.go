@@ -0,0 +1,50 @@
+package viewmatrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"mu-bmd-renderer/internal/mathutil"
+)
+
+// Conventions maps a coordinate convention name to the matrix that converts
+// a data set's vertex coordinates into MU BMD space (left-handed, Z-up), which
+// every camera matrix (ViewFallback, TRSCorrection, NoflipCam) expects.
+// It is applied after bone transforms, so skinned and raw meshes agree.
+var Conventions = map[string]mathutil.Mat3{
+	// Official client data: no conversion
+	"mu-default": mathutil.Mat3Identity(),
+	// Right-handed exports: X mirrored, so items come out mirrored left-right
+	"mirrored": mathutil.MirrorX,
+	// Y-up exports: items come out lying on their back; Rx(+90°) takes +Y to +Z
+	"y-up": mathutil.RotX(math.Pi / 2),
+}
+
+// Convention returns the matrix of the named coordinate convention for
+// ComputeViewMatrix: nil for mu-default ("" included), which needs none.
+func Convention(name string) (*mathutil.Mat3, error) {
+	if name == "" || name == "mu-default" {
+		return nil, nil
+	}
+	m, ok := Conventions[name]
+	if !ok {
+		names := make([]string, 0, len(Conventions))
+		for n := range Conventions {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown coordinate convention %q (use %s)", name, strings.Join(names, ", "))
+	}
+	return &m, nil
+}
+
+// applyConvention returns view with convention c applied to the data side
+// (view @ C). A nil c (mu-default) returns view unchanged.
+func applyConvention(view mathutil.Mat3, c *mathutil.Mat3) mathutil.Mat3 {
+	if c != nil {
+		return mathutil.Mat3Mul(view, *c)
+	}
+	return view
+}
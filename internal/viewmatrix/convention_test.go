@@ -0,0 +1,27 @@
+package viewmatrix
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"mu-bmd-renderer/internal/mathutil"
+)
+
+func TestConventionNames(t *testing.T) {
+	for _, name := range []string{"", "mu-default"} {
+		if m, err := Convention(name); m != nil || err != nil {
+			t.Errorf("%q: %v, %v; want no matrix", name, m, err)
+		}
+	}
+	m, err := Convention("y-up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up := m.MulVec3(mathutil.Vec3{0, 1, 0}); math.Abs(up[2]-1) > 1e-9 {
+		t.Errorf("y-up takes +Y to %v, want +Z", up)
+	}
+	if _, err := Convention("z-down"); err == nil || !strings.Contains(err.Error(), "mirrored, mu-default, y-up") {
+		t.Errorf("unknown convention: %v, want the known names listed", err)
+	}
+}
@@ -0,0 +1,43 @@
+package viewmatrix
+
+import (
+	"math"
+	"testing"
+
+	"mu-bmd-renderer/internal/bmd"
+	"mu-bmd-renderer/internal/mathutil"
+)
+
+// slab returns a mesh of the corners of a w×h×d box, turned by rot.
+func slab(w, h, d float64, rot mathutil.Mat3) bmd.Mesh {
+	var m bmd.Mesh
+	for _, x := range []float64{-w / 2, w / 2} {
+		for _, y := range []float64{-h / 2, h / 2} {
+			for _, z := range []float64{-d / 2, d / 2} {
+				v := rot.MulVec3(mathutil.Vec3{x, y, z})
+				m.Verts = append(m.Verts, [3]float32{float32(v[0]), float32(v[1]), float32(v[2])})
+			}
+		}
+	}
+	return m
+}
+
+func TestLayFlat(t *testing.T) {
+	tilt := mathutil.Mat3Mul(mathutil.RotX(0.7), mathutil.RotY(-0.3))
+	view := mathutil.RotX(mathutil.Deg2Rad(-90))
+
+	// A thin slab: its normal (tilt's z axis) must end up along the view axis
+	r := LayFlat([]bmd.Mesh{slab(40, 30, 2, tilt)}, view)
+	n := r.MulVec3(tilt.MulVec3(mathutil.Vec3{0, 0, 1}))
+	if math.Abs(math.Abs(n[2])-1) > 1e-6 {
+		t.Errorf("slab normal viewed as %v, want along the view axis", n)
+	}
+	if d := r.Det(); math.Abs(d-view.Det()) > 1e-9 {
+		t.Errorf("det %v, want %v: LayFlat may only rotate", d, view.Det())
+	}
+
+	// A cube has no flattest side: the view is kept
+	if r := LayFlat([]bmd.Mesh{slab(20, 20, 20, tilt)}, view); r != view {
+		t.Errorf("cube: view changed to %v", r)
+	}
+}
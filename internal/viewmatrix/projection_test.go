@@ -0,0 +1,41 @@
+package viewmatrix
+
+import (
+	"reflect"
+	"testing"
+
+	"mu-bmd-renderer/internal/mathutil"
+	"mu-bmd-renderer/internal/trs"
+)
+
+// TestProjectionOrtho projects two points that differ only in depth: with
+// perspective they land apart, and projection "ortho" puts them back on
+// one spot, as a plain entry does, whatever perspective and cam_height say.
+func TestProjectionOrtho(t *testing.T) {
+	verts := [][3]float32{{20, 10, -50}, {20, 10, 50}, {-20, -10, 0}}
+	project := func(e *trs.Entry) []float64 {
+		px, py, _ := ProjectVertices(verts, mathutil.RotX(0), [3]float64{}, 1, 64, 64, e, nil)
+		return append(px, py...)
+	}
+
+	section := &trs.Entry{Perspective: true, FOV: 40, CamHeight: 300}
+	if !IsPerspective(section) || !UsesPosCamera(section) {
+		t.Fatal("perspective and cam_height: want perspective through the positioned camera")
+	}
+	if p := project(section); p[0] == p[1] {
+		t.Errorf("perspective: near and far points both at x %v", p[0])
+	}
+
+	ortho := *section
+	ortho.Projection = "ortho"
+	if IsPerspective(&ortho) || UsesPosCamera(&ortho) {
+		t.Error(`projection "ortho": still perspective or positioned camera`)
+	}
+	if got, want := project(&ortho), project(&trs.Entry{}); !reflect.DeepEqual(got, want) {
+		t.Errorf(`projection "ortho": projected %v, want %v`, got, want)
+	}
+
+	if !IsPerspective(&trs.Entry{Projection: "perspective"}) {
+		t.Error(`projection "perspective": not perspective`)
+	}
+}
@@ -35,24 +35,36 @@ func TRSViewMatrix(e *trs.Entry) mathutil.Mat3 {
 	rz := mathutil.Deg2Rad(e.RotZ)
 	trsRot := mathutil.Mat3Mul(mathutil.Mat3Mul(mathutil.RotZ(rz), mathutil.RotY(ry)), mathutil.RotX(rx))
 
-	// Custom camera override
-	switch e.Camera {
-	case "noflip":
-		return mathutil.Mat3Mul(mathutil.NoflipCam, trsRot)
+	switch CameraPath(e) {
 	case "correction":
 		return mathutil.Mat3Mul(mathutil.TRSCorrection, trsRot)
 	case "fallback":
 		return mathutil.ViewFallback
 	}
+	return mathutil.Mat3Mul(mathutil.NoflipCam, trsRot)
+}
+
+// CameraPath returns the camera TRSViewMatrix uses for e: "noflip",
+// "correction", or "fallback". A nil entry renders with VIEW_FALLBACK.
+func CameraPath(e *trs.Entry) string {
+	if e == nil {
+		return "fallback"
+	}
+
+	// Custom camera override
+	switch e.Camera {
+	case "noflip", "correction", "fallback":
+		return e.Camera
+	}
 
 	// Auto-routing by rotY
 	rotY := e.RotY
 	if mathutil.AngleDist(rotY, 270) <= 45 {
-		return mathutil.Mat3Mul(mathutil.TRSCorrection, trsRot)
+		return "correction"
 	} else if mathutil.AngleDist(rotY, 90) <= 45 {
-		return mathutil.ViewFallback
+		return "fallback"
 	}
-	return mathutil.Mat3Mul(mathutil.NoflipCam, trsRot)
+	return "noflip"
 }
 
 // IsFallbackPath returns true if this TRS entry routes to VIEW_FALLBACK.
@@ -66,9 +78,28 @@ func IsFallbackPath(e *trs.Entry) bool {
 	return mathutil.AngleDist(e.RotY, 90) <= 45 && mathutil.AngleDist(e.RotY, 270) > 45
 }
 
+// IsPerspective reports whether e renders with perspective projection:
+// projection "perspective", or perspective set with no projection.
+func IsPerspective(e *trs.Entry) bool {
+	if e == nil {
+		return false
+	}
+	if e.Projection != "" {
+		return e.Projection == "perspective"
+	}
+	return e.Perspective
+}
+
+// UsesPosCamera reports whether e renders through the positioned camera
+// (cam_height set); projection "ortho" turns it off.
+func UsesPosCamera(e *trs.Entry) bool {
+	return e != nil && e.CamHeight != 0 && e.Projection != "ortho"
+}
+
 // ComputeViewMatrix applies component filtering and returns the view matrix + filtered body meshes.
+// The view matrix includes convention, from Convention (nil = mu-default).
 // Effect mesh filtering is done earlier in the pipeline (before bone transforms).
-func ComputeViewMatrix(meshes []bmd.Mesh, entry *trs.Entry) (mathutil.Mat3, []bmd.Mesh) {
+func ComputeViewMatrix(meshes []bmd.Mesh, entry *trs.Entry, convention *mathutil.Mat3) (mathutil.Mat3, []bmd.Mesh) {
 	var bodyMeshes []bmd.Mesh
 	for i := range meshes {
 		// Skip FilterComponents for force-additive meshes — their duplicated
@@ -87,9 +118,9 @@ func ComputeViewMatrix(meshes []bmd.Mesh, entry *trs.Entry) (mathutil.Mat3, []bm
 	}
 
 	if entry != nil {
-		return TRSViewMatrix(entry), bodyMeshes
+		return applyConvention(TRSViewMatrix(entry), convention), bodyMeshes
 	}
-	return mathutil.ViewFallback, bodyMeshes
+	return applyConvention(mathutil.ViewFallback, convention), bodyMeshes
 }
 
 // ShouldUseBones determines whether bone transforms should be applied.
@@ -260,8 +291,11 @@ func ProjectVertices(verts [][3]float32, R mathutil.Mat3, center [3]float64, sca
 	py := make([]float64, n)
 	pz := make([]float64, n)
 
-	halfW := float64(renderW) / 2
-	halfH := float64(renderH) / 2
+	// Rasterizers sample pixel (x, y) at integer coordinates, but the canvas
+	// center lies between the middle pixels' centers (x+0.5); offset by half a
+	// pixel so geometry centered in view lands centered on the canvas.
+	halfW := float64(renderW)/2 - 0.5
+	halfH := float64(renderH)/2 - 0.5
 
 	// Positioned camera path: full parallax perspective from an elevated camera
 	if posCamera != nil {
@@ -278,7 +312,7 @@ func ProjectVertices(verts [][3]float32, R mathutil.Mat3, center [3]float64, sca
 	}
 
 	// Standard perspective setup
-	usePersp := entry != nil && entry.Perspective
+	usePersp := IsPerspective(entry)
 	var perspCamDist, perspZCenter float64
 	if usePersp {
 		fov := entry.FOV
@@ -333,3 +367,46 @@ func ProjectVertices(verts [][3]float32, R mathutil.Mat3, center [3]float64, sca
 
 	return px, py, pz
 }
+
+// LayFlat rotates view R so the meshes' flattest direction (the smallest-
+// variance axis of the viewed vertices, a 3D analogue of the 2D standardize
+// PCA) points along the view axis, showing flat items such as scrolls and
+// books face-on. The rotation is the smallest one that does this, so the face
+// already toward the camera stays toward it. Near-spherical vertex clouds
+// (no clearly flattest axis) return R unchanged.
+func LayFlat(meshes []bmd.Mesh, R mathutil.Mat3) mathutil.Mat3 {
+	var mean mathutil.Vec3
+	n := 0
+	for _, m := range meshes {
+		for _, v := range m.Verts {
+			mean = mean.Add(R.MulVec3(mathutil.Vec3{float64(v[0]), float64(v[1]), float64(v[2])}))
+			n++
+		}
+	}
+	if n < 3 {
+		return R
+	}
+	mean = mean.Scale(1 / float64(n))
+
+	var cov mathutil.Mat3
+	for _, m := range meshes {
+		for _, v := range m.Verts {
+			d := R.MulVec3(mathutil.Vec3{float64(v[0]), float64(v[1]), float64(v[2])}).Sub(mean)
+			for r := 0; r < 3; r++ {
+				for c := 0; c < 3; c++ {
+					cov[r*3+c] += d[r] * d[c]
+				}
+			}
+		}
+	}
+
+	evals, evecs := mathutil.EigenSym3(cov)
+	if evals[2] > 0.5*evals[1] {
+		return R
+	}
+	normal := evecs[2]
+	if normal[2] < 0 {
+		normal = normal.Scale(-1)
+	}
+	return mathutil.Mat3Mul(mathutil.RotateOnto(normal, mathutil.Vec3{0, 0, 1}), R)
+}
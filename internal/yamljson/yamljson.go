@@ -0,0 +1,84 @@
+// Package yamljson converts YAML documents to JSON, so a YAML file can be
+// read through the same json struct tags (and validating UnmarshalJSON
+// methods) as its JSON twin.
+package yamljson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Convert returns the YAML document raw as JSON. An empty (or all-comment)
+// document is "{}". Mapping keys are taken as written, and plain scalars
+// with an underscore stay strings as in YAML 1.2: yaml.v3 would otherwise
+// read an item key like 9_1 as the number 91.
+func Convert(raw []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []byte("{}"), nil
+	}
+	v, err := value(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// value decodes a YAML node into the value encoding/json would have decoded
+// from the equivalent JSON.
+func value(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return value(n.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Kind == yaml.ScalarNode && k.Tag == "!!merge" {
+				// "<<: *preset" copies the keys of another mapping
+				merged, err := value(v)
+				if err != nil {
+					return nil, err
+				}
+				if mm, ok := merged.(map[string]any); ok {
+					for mk, mv := range mm {
+						if _, set := m[mk]; !set {
+							m[mk] = mv
+						}
+					}
+				}
+				continue
+			}
+			val, err := value(v)
+			if err != nil {
+				return nil, err
+			}
+			m[k.Value] = val
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		a := make([]any, len(n.Content))
+		for i, e := range n.Content {
+			v, err := value(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = v
+		}
+		return a, nil
+	}
+	if n.Style == 0 && (n.Tag == "!!int" || n.Tag == "!!float") && strings.Contains(n.Value, "_") {
+		return n.Value, nil
+	}
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return nil, fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	return v, nil
+}
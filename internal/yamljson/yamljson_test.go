@@ -0,0 +1,41 @@
+package yamljson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	for _, c := range []struct {
+		yaml string
+		want string
+	}{
+		{"", `{}`},
+		{"# only a comment\n", `{}`},
+		{"items:\n  9_1: {scale: 1.5}\n", `{"items": {"9_1": {"scale": 1.5}}}`},
+		{"keys: [9_1, 12, 0.5, true, null, ~]\n", `{"keys": ["9_1", 12, 0.5, true, null, null]}`},
+		{"name: \"12\"\nflag: yes\n", `{"name": "12", "flag": "yes"}`},
+	} {
+		raw, err := Convert([]byte(c.yaml))
+		if err != nil {
+			t.Errorf("%q: %v", c.yaml, err)
+			continue
+		}
+		var got, want any
+		if err := json.Unmarshal(raw, &got); err != nil {
+			t.Errorf("%q: invalid JSON %s: %v", c.yaml, raw, err)
+			continue
+		}
+		json.Unmarshal([]byte(c.want), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %s, want %s", c.yaml, raw, c.want)
+		}
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	if _, err := Convert([]byte("a: [1, 2\n")); err == nil {
+		t.Error("unterminated flow sequence: want an error")
+	}
+}